@@ -0,0 +1,408 @@
+// Package align implements pairwise sequence alignment with affine gap
+// penalties (Gotoh 1982), used to confirm an assembled insert actually
+// matches its intended reference before a construct ships.
+package align
+
+import (
+	"fmt"
+	"strings"
+)
+
+// SubstitutionMatrix scores aligning one residue against another. Scores are
+// looked up symmetrically (Score(a, b) == Score(b, a)); pairs it doesn't
+// know about fall back to Default.
+type SubstitutionMatrix struct {
+	Name    string
+	scores  map[[2]byte]int
+	Default int
+}
+
+// Score returns the substitution score for aligning a against b.
+func (m SubstitutionMatrix) Score(a, b byte) int {
+	if s, ok := m.scores[[2]byte{a, b}]; ok {
+		return s
+	}
+	if s, ok := m.scores[[2]byte{b, a}]; ok {
+		return s
+	}
+	return m.Default
+}
+
+// NewDNAMatrix builds a simple match/mismatch substitution matrix over
+// A/C/G/T/N, with N scored as Default against everything (including itself)
+// since it carries no information.
+func NewDNAMatrix(match, mismatch int) SubstitutionMatrix {
+	scores := make(map[[2]byte]int)
+	bases := []byte("ACGT")
+	for _, a := range bases {
+		for _, b := range bases {
+			if a == b {
+				scores[[2]byte{a, b}] = match
+			} else {
+				scores[[2]byte{a, b}] = mismatch
+			}
+		}
+	}
+	return SubstitutionMatrix{Name: "DNA", scores: scores, Default: mismatch}
+}
+
+// aaOrder is the residue order used by buildMatrix to decode the flat
+// upper-triangular score tables below.
+const aaOrder = "ARNDCQEGHILKMFPSTWYV"
+
+func buildMatrix(name string, upperTriangle []int) SubstitutionMatrix {
+	scores := make(map[[2]byte]int)
+	idx := 0
+	for i := 0; i < len(aaOrder); i++ {
+		for j := i; j < len(aaOrder); j++ {
+			scores[[2]byte{aaOrder[i], aaOrder[j]}] = upperTriangle[idx]
+			idx++
+		}
+	}
+	return SubstitutionMatrix{Name: name, scores: scores, Default: -4}
+}
+
+// BLOSUM62 is the standard protein substitution matrix for moderately
+// diverged sequences (the default used by BLASTP).
+var BLOSUM62 = buildMatrix("BLOSUM62", []int{
+	4, -1, -2, -2, 0, -1, -1, 0, -2, -1, -1, -1, -1, -2, -1, 1, 0, -3, -2, 0,
+	5, 0, -2, -3, 1, 0, -2, 0, -3, -2, 2, -1, -3, -2, -1, -1, -3, -2, -3,
+	6, 1, -3, 0, 0, 0, 1, -3, -3, 0, -2, -3, -2, 1, 0, -4, -2, -3,
+	6, -3, 0, 2, -1, -1, -3, -4, -1, -3, -3, -1, 0, -1, -4, -3, -3,
+	9, -3, -4, -3, -3, -1, -1, -3, -1, -2, -3, -1, -1, -2, -2, -1,
+	5, 2, -2, 0, -3, -2, 1, 0, -3, -1, 0, -1, -2, -1, -2,
+	5, -2, 0, -3, -3, 1, -2, -3, -1, 0, -1, -3, -2, -2,
+	6, -2, -4, -4, -2, -3, -3, -2, 0, -2, -2, -3, -3,
+	8, -3, -3, -1, -2, -1, -2, -1, -2, -2, 2, -3,
+	4, 2, -3, 1, 0, -3, -2, -1, -3, -1, 3,
+	4, -2, 2, 0, -3, -2, -1, -2, -1, 1,
+	5, -1, -3, -1, 0, -1, -3, -2, -2,
+	5, 0, -2, -1, -1, -1, -1, 1,
+	6, -4, -2, -2, 1, 3, -1,
+	7, -1, -1, -4, -3, -2,
+	4, 1, -3, -2, -2,
+	5, -2, -2, 0,
+	11, 2, -3,
+	7, -1,
+	4,
+})
+
+// PAM250 is the standard protein substitution matrix for distantly diverged
+// sequences.
+var PAM250 = buildMatrix("PAM250", []int{
+	2, -2, 0, 0, -2, 0, 0, 1, -1, -1, -2, -1, -1, -3, 1, 1, 1, -6, -3, 0,
+	6, 0, -1, -4, 1, -1, -3, 2, -2, -3, 3, 0, -4, 0, 0, -1, 2, -4, -2,
+	2, 2, -4, 1, 1, 0, 2, -2, -3, 1, -2, -3, 0, 1, 0, -4, -2, -2,
+	4, -5, 2, 3, 1, 1, -2, -4, 0, -3, -6, -1, 0, 0, -7, -4, -2,
+	12, -5, -5, -3, -3, -2, -6, -5, -5, -4, -3, 0, -2, -8, 0, -2,
+	4, 2, -1, 3, -2, -2, 1, -1, -5, 0, -1, -1, -5, -4, -2,
+	4, 0, 1, -2, -3, 0, -2, -5, -1, 0, 0, -7, -4, -2,
+	5, -2, -3, -4, -2, -3, -5, 0, 1, 0, -7, -5, -1,
+	6, -2, -2, 0, -2, -2, 0, -1, -1, -3, 0, -2,
+	5, 2, -2, 2, 1, -2, -1, 0, -5, -1, 4,
+	6, -3, 4, 2, -3, -3, -2, -2, -1, 2,
+	5, 0, -5, -1, 0, 0, -3, -4, -2,
+	6, 0, -2, -2, -1, -4, -2, 2,
+	9, -5, -3, -3, 0, 7, -1,
+	6, 1, 0, -6, -5, -1,
+	2, 1, -2, -3, -1,
+	3, -5, -3, 0,
+	17, 0, -6,
+	10, -2,
+	4,
+})
+
+// GapPenalty is the affine gap cost: Open is charged once when a gap
+// starts (covering its first residue), Extend is charged per further
+// residue the gap covers, so a k-residue gap costs Open + (k-1)*Extend.
+type GapPenalty struct {
+	Open   int
+	Extend int
+}
+
+// Alignment is the result of aligning two sequences: the two strings with
+// '-' gap characters inserted so they line up column-for-column, the raw
+// score, the percent identity over aligned (non-gap) columns, and the
+// half-open coordinate ranges of each input sequence that were consumed.
+type Alignment struct {
+	AlignedA string
+	AlignedB string
+	Score    int
+	Identity float64
+	AStart   int
+	AEnd     int
+	BStart   int
+	BEnd     int
+}
+
+// MismatchPosition returns the 0-based column of the first aligned,
+// non-identical pair of residues (skipping gaps), or -1 if the alignment has
+// no mismatches.
+func (a Alignment) MismatchPosition() int {
+	for i := 0; i < len(a.AlignedA) && i < len(a.AlignedB); i++ {
+		ca, cb := a.AlignedA[i], a.AlignedB[i]
+		if ca == '-' || cb == '-' {
+			continue
+		}
+		if ca != cb {
+			return i
+		}
+	}
+	return -1
+}
+
+const negInf = -1 << 30
+
+// mode controls which end-gaps are free and whether the alignment is local.
+// NeedlemanWunsch, SmithWaterman, and Fitted are all the same Gotoh
+// recurrence underneath, parameterised by mode the way this package's
+// callers already parameterise shared logic with small option structs.
+type mode struct {
+	local         bool
+	freeStartGapA bool
+	freeEndGapA   bool
+	freeStartGapB bool
+	freeEndGapB   bool
+}
+
+// NeedlemanWunsch computes the optimal global alignment of a and b.
+func NeedlemanWunsch(a, b string, matrix SubstitutionMatrix, gap GapPenalty) Alignment {
+	return align(a, b, matrix, gap, mode{})
+}
+
+// SmithWaterman computes the optimal local alignment between a and b.
+func SmithWaterman(a, b string, matrix SubstitutionMatrix, gap GapPenalty) Alignment {
+	return align(a, b, matrix, gap, mode{local: true})
+}
+
+// Fitted computes a semi-global alignment that fits a (in full) somewhere
+// inside b, without penalizing gaps before or after a in b. It's the mode to
+// use when locating a short reference inside a longer sequence it's known
+// to be a substring (up to edits) of.
+func Fitted(a, b string, matrix SubstitutionMatrix, gap GapPenalty) Alignment {
+	return align(a, b, matrix, gap, mode{freeStartGapB: true, freeEndGapB: true})
+}
+
+func align(a, b string, sub SubstitutionMatrix, gap GapPenalty, mode mode) Alignment {
+	n, m := len(a), len(b)
+	M := make([][]int, n+1)
+	Ix := make([][]int, n+1)
+	Iy := make([][]int, n+1)
+	for i := range M {
+		M[i] = make([]int, m+1)
+		Ix[i] = make([]int, m+1)
+		Iy[i] = make([]int, m+1)
+	}
+
+	floor := func(v int) int {
+		if mode.local && v < 0 {
+			return 0
+		}
+		return v
+	}
+
+	gapCost := func(k int, free bool) int {
+		if free || k == 0 {
+			return 0
+		}
+		return -(gap.Open + (k-1)*gap.Extend)
+	}
+
+	M[0][0] = 0
+	for i := 1; i <= n; i++ {
+		M[i][0] = negInf
+		Ix[i][0] = floor(gapCost(i, mode.freeStartGapA))
+		Iy[i][0] = negInf
+	}
+	for j := 1; j <= m; j++ {
+		M[0][j] = negInf
+		Iy[0][j] = floor(gapCost(j, mode.freeStartGapB))
+		Ix[0][j] = negInf
+	}
+
+	max3 := func(x, y, z int) int {
+		v := x
+		if y > v {
+			v = y
+		}
+		if z > v {
+			v = z
+		}
+		return v
+	}
+
+	for i := 1; i <= n; i++ {
+		for j := 1; j <= m; j++ {
+			best := max3(M[i-1][j-1], Ix[i-1][j-1], Iy[i-1][j-1])
+			M[i][j] = floor(best + sub.Score(a[i-1], b[j-1]))
+
+			openA := M[i-1][j] - gap.Open
+			if mode.freeEndGapA && j == m {
+				openA = M[i-1][j]
+			}
+			extA := Ix[i-1][j] - gap.Extend
+			if mode.freeEndGapA && j == m {
+				extA = Ix[i-1][j]
+			}
+			Ix[i][j] = floor(maxInt(openA, extA))
+
+			openB := M[i][j-1] - gap.Open
+			if mode.freeEndGapB && i == n {
+				openB = M[i][j-1]
+			}
+			extB := Iy[i][j-1] - gap.Extend
+			if mode.freeEndGapB && i == n {
+				extB = Iy[i][j-1]
+			}
+			Iy[i][j] = floor(maxInt(openB, extB))
+		}
+	}
+
+	endI, endJ, score := n, m, negInf
+	if mode.local {
+		for i := 0; i <= n; i++ {
+			for j := 0; j <= m; j++ {
+				if v := max3(M[i][j], Ix[i][j], Iy[i][j]); v > score {
+					score, endI, endJ = v, i, j
+				}
+			}
+		}
+	} else if mode.freeEndGapB {
+		for j := 0; j <= m; j++ {
+			if v := max3(M[n][j], Ix[n][j], Iy[n][j]); v > score {
+				score, endJ = v, j
+			}
+		}
+	} else if mode.freeEndGapA {
+		for i := 0; i <= n; i++ {
+			if v := max3(M[i][m], Ix[i][m], Iy[i][m]); v > score {
+				score, endI = v, i
+			}
+		}
+	} else {
+		score = max3(M[n][m], Ix[n][m], Iy[n][m])
+	}
+
+	var alignedA, alignedB strings.Builder
+	i, j := endI, endJ
+	state := 'M'
+	switch {
+	case M[i][j] >= Ix[i][j] && M[i][j] >= Iy[i][j]:
+		state = 'M'
+	case Ix[i][j] >= Iy[i][j]:
+		state = 'I'
+	default:
+		state = 'J'
+	}
+	matches := 0
+	aligned := 0
+	for i > 0 || j > 0 {
+		if mode.local && max3(M[i][j], Ix[i][j], Iy[i][j]) <= 0 {
+			break
+		}
+		if i == 0 {
+			state = 'J'
+		} else if j == 0 {
+			state = 'I'
+		}
+		switch state {
+		case 'M':
+			alignedA.WriteByte(a[i-1])
+			alignedB.WriteByte(b[j-1])
+			aligned++
+			if a[i-1] == b[j-1] {
+				matches++
+			}
+			prevBest := max3(M[i-1][j-1], Ix[i-1][j-1], Iy[i-1][j-1])
+			i, j = i-1, j-1
+			switch {
+			case M[i][j] == prevBest:
+				state = 'M'
+			case Ix[i][j] == prevBest:
+				state = 'I'
+			default:
+				state = 'J'
+			}
+		case 'I':
+			alignedA.WriteByte(a[i-1])
+			alignedB.WriteByte('-')
+			extA := Ix[i-1][j] - gap.Extend
+			if mode.freeEndGapA && j == m {
+				extA = Ix[i-1][j]
+			}
+			if i >= 1 && Ix[i][j] == extA {
+				i--
+				state = 'I'
+			} else {
+				i--
+				state = 'M'
+			}
+		default: // 'J'
+			alignedA.WriteByte('-')
+			alignedB.WriteByte(b[j-1])
+			extB := Iy[i][j-1] - gap.Extend
+			if mode.freeEndGapB && i == n {
+				extB = Iy[i][j-1]
+			}
+			if j >= 1 && Iy[i][j] == extB {
+				j--
+				state = 'J'
+			} else {
+				j--
+				state = 'M'
+			}
+		}
+	}
+
+	ra := reverseString(alignedA.String())
+	rb := reverseString(alignedB.String())
+
+	identity := 0.0
+	if aligned > 0 {
+		identity = 100 * float64(matches) / float64(aligned)
+	}
+
+	return Alignment{
+		AlignedA: ra,
+		AlignedB: rb,
+		Score:    score,
+		Identity: identity,
+		AStart:   i,
+		AEnd:     endI,
+		BStart:   j,
+		BEnd:     endJ,
+	}
+}
+
+func maxInt(a, b int) int {
+	if a > b {
+		return a
+	}
+	return b
+}
+
+func reverseString(s string) string {
+	b := []byte(s)
+	for i, j := 0, len(b)-1; i < j; i, j = i+1, j-1 {
+		b[i], b[j] = b[j], b[i]
+	}
+	return string(b)
+}
+
+// String renders an alignment the way pairwise alignment tools conventionally
+// do: the two sequences stacked with a match-line between them.
+func (a Alignment) String() string {
+	var match strings.Builder
+	for i := 0; i < len(a.AlignedA) && i < len(a.AlignedB); i++ {
+		ca, cb := a.AlignedA[i], a.AlignedB[i]
+		switch {
+		case ca == '-' || cb == '-':
+			match.WriteByte(' ')
+		case ca == cb:
+			match.WriteByte('|')
+		default:
+			match.WriteByte('.')
+		}
+	}
+	return fmt.Sprintf("%s\n%s\n%s\nscore=%d identity=%.1f%%", a.AlignedA, match.String(), a.AlignedB, a.Score, a.Identity)
+}
@@ -0,0 +1,61 @@
+package align
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestNeedlemanWunschIdentical(t *testing.T) {
+	matrix := NewDNAMatrix(1, -1)
+	gap := GapPenalty{Open: 5, Extend: 1}
+
+	aln := NeedlemanWunsch("ACGTACGT", "ACGTACGT", matrix, gap)
+	if aln.AlignedA != "ACGTACGT" || aln.AlignedB != "ACGTACGT" {
+		t.Errorf("aligning identical sequences inserted gaps: %q / %q", aln.AlignedA, aln.AlignedB)
+	}
+	if aln.Identity != 100 {
+		t.Errorf("Identity = %v, want 100 for identical sequences", aln.Identity)
+	}
+	if pos := aln.MismatchPosition(); pos != -1 {
+		t.Errorf("MismatchPosition = %d, want -1 for identical sequences", pos)
+	}
+}
+
+func TestNeedlemanWunschSingleMismatch(t *testing.T) {
+	matrix := NewDNAMatrix(1, -1)
+	gap := GapPenalty{Open: 5, Extend: 1}
+
+	aln := NeedlemanWunsch("ACGTACGT", "ACGAACGT", matrix, gap)
+	if pos := aln.MismatchPosition(); pos != 3 {
+		t.Errorf("MismatchPosition = %d, want 3", pos)
+	}
+}
+
+func TestSmithWatermanLocal(t *testing.T) {
+	matrix := NewDNAMatrix(2, -1)
+	gap := GapPenalty{Open: 5, Extend: 1}
+
+	aln := SmithWaterman("NNNNACGTNNNN", "ACGT", matrix, gap)
+	if aln.AlignedA != "ACGT" || aln.AlignedB != "ACGT" {
+		t.Errorf("SmithWaterman local match = %q / %q, want ACGT / ACGT", aln.AlignedA, aln.AlignedB)
+	}
+}
+
+func TestFittedFindsSubstringInsideLongerSequence(t *testing.T) {
+	matrix := NewDNAMatrix(1, -2)
+	gap := GapPenalty{Open: 5, Extend: 1}
+
+	aln := Fitted("ACGT", "TTTTACGTTTTT", matrix, gap)
+	if aln.BEnd != 8 {
+		t.Errorf("Fitted BEnd = %d, want 8 (end of the matched region in b)", aln.BEnd)
+	}
+	if !strings.Contains(aln.AlignedB, "ACGT") {
+		t.Errorf("Fitted alignment didn't contain the matched ACGT region: AlignedB=%q", aln.AlignedB)
+	}
+}
+
+func TestSubstitutionMatrixScoreIsSymmetric(t *testing.T) {
+	if got, want := BLOSUM62.Score('A', 'R'), BLOSUM62.Score('R', 'A'); got != want {
+		t.Errorf("BLOSUM62.Score(A, R) = %d, Score(R, A) = %d, want equal", got, want)
+	}
+}
@@ -0,0 +1,201 @@
+// Package bioseq provides a small alphabet-aware sequence type used in place
+// of raw strings for DNA, RNA, and protein data. Tagging a sequence with its
+// alphabet at construction time catches the class of bug where, say, a
+// protein accession's amino acids accidentally flow into a field expecting
+// DNA.
+package bioseq
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+)
+
+// Alphabet identifies what kind of residues a Sequence's Letters hold.
+type Alphabet int
+
+const (
+	DNA Alphabet = iota
+	RNA
+	Protein
+	IUPACAmbiguous
+)
+
+func (a Alphabet) String() string {
+	switch a {
+	case DNA:
+		return "DNA"
+	case RNA:
+		return "RNA"
+	case Protein:
+		return "Protein"
+	case IUPACAmbiguous:
+		return "IUPACAmbiguous"
+	default:
+		return "unknown"
+	}
+}
+
+const (
+	dnaLetters            = "ACGTN"
+	rnaLetters            = "ACGUN"
+	proteinLetters        = "ACDEFGHIKLMNPQRSTVWYX*"
+	iupacAmbiguousLetters = "ACGTUWSMKRYBDHVN"
+)
+
+func validLetters(a Alphabet) string {
+	switch a {
+	case DNA:
+		return dnaLetters
+	case RNA:
+		return rnaLetters
+	case Protein:
+		return proteinLetters
+	case IUPACAmbiguous:
+		return iupacAmbiguousLetters
+	default:
+		return ""
+	}
+}
+
+// Sequence is a named, alphabet-tagged run of residues.
+type Sequence struct {
+	Name        string
+	Description string
+	Letters     []byte
+	Alphabet    Alphabet
+}
+
+// New validates letters against alphabet and returns a Sequence. Letters are
+// upper-cased before validation so callers don't need to normalize case
+// themselves.
+func New(name string, letters string, alphabet Alphabet) (Sequence, error) {
+	upper := strings.ToUpper(letters)
+	valid := validLetters(alphabet)
+	for i := 0; i < len(upper); i++ {
+		if !strings.ContainsRune(valid, rune(upper[i])) {
+			return Sequence{}, fmt.Errorf("bioseq: invalid %s residue %q at position %d", alphabet, upper[i], i)
+		}
+	}
+	return Sequence{Name: name, Letters: []byte(upper), Alphabet: alphabet}, nil
+}
+
+// Slice returns the raw residues. Callers must not mutate the result.
+func (s Sequence) Slice() []byte {
+	return s.Letters
+}
+
+// String returns the residues as a string.
+func (s Sequence) String() string {
+	return string(s.Letters)
+}
+
+// Len returns the number of residues.
+func (s Sequence) Len() int {
+	return len(s.Letters)
+}
+
+// MarshalJSON renders a Sequence as its plain residue string, so JSON output
+// that used to carry a raw DNA/protein string keeps the same shape now that
+// the field holds a Sequence.
+func (s Sequence) MarshalJSON() ([]byte, error) {
+	return json.Marshal(s.String())
+}
+
+// UnmarshalJSON reads a Sequence back from the plain residue string written
+// by MarshalJSON. The alphabet isn't encoded, so round-tripped sequences
+// come back untagged (Alphabet zero value, DNA); callers that need the
+// alphabet preserved should carry it separately.
+func (s *Sequence) UnmarshalJSON(data []byte) error {
+	var letters string
+	if err := json.Unmarshal(data, &letters); err != nil {
+		return err
+	}
+	s.Letters = []byte(letters)
+	return nil
+}
+
+// Subseq returns the residues in [start, end), 0-based, as a new Sequence
+// sharing the parent's name/alphabet.
+func (s Sequence) Subseq(start, end int) (Sequence, error) {
+	if start < 0 || end > len(s.Letters) || start > end {
+		return Sequence{}, fmt.Errorf("bioseq: subseq [%d, %d) out of bounds for length %d", start, end, len(s.Letters))
+	}
+	return Sequence{Name: s.Name, Letters: append([]byte(nil), s.Letters[start:end]...), Alphabet: s.Alphabet}, nil
+}
+
+var dnaComplement = map[byte]byte{
+	'A': 'T', 'T': 'A', 'C': 'G', 'G': 'C', 'N': 'N',
+}
+
+var rnaComplement = map[byte]byte{
+	'A': 'U', 'U': 'A', 'C': 'G', 'G': 'C', 'N': 'N',
+}
+
+// Complement returns the base-wise complement of a DNA or RNA sequence
+// without reversing it.
+func (s Sequence) Complement() (Sequence, error) {
+	table := dnaComplement
+	if s.Alphabet == RNA {
+		table = rnaComplement
+	} else if s.Alphabet != DNA {
+		return Sequence{}, fmt.Errorf("bioseq: Complement is only defined for DNA/RNA, got %s", s.Alphabet)
+	}
+	out := make([]byte, len(s.Letters))
+	for i, b := range s.Letters {
+		c, ok := table[b]
+		if !ok {
+			return Sequence{}, fmt.Errorf("bioseq: no complement for residue %q", b)
+		}
+		out[i] = c
+	}
+	return Sequence{Name: s.Name, Letters: out, Alphabet: s.Alphabet}, nil
+}
+
+// ReverseComplement returns the reverse complement of a DNA or RNA sequence.
+func (s Sequence) ReverseComplement() (Sequence, error) {
+	comp, err := s.Complement()
+	if err != nil {
+		return Sequence{}, err
+	}
+	for i, j := 0, len(comp.Letters)-1; i < j; i, j = i+1, j-1 {
+		comp.Letters[i], comp.Letters[j] = comp.Letters[j], comp.Letters[i]
+	}
+	return comp, nil
+}
+
+// CodonTable is a genetic code: a codon-to-amino-acid map plus the set of
+// codons that can initiate translation.
+type CodonTable struct {
+	ID      int
+	Name    string
+	Codons  map[string]byte
+	Starts  map[string]bool
+}
+
+// Translate reads codons starting at frame (0, 1, or 2) and returns the
+// resulting protein Sequence. Unlike the ad hoc translate() helper it
+// replaces, an incomplete trailing codon or an unrecognized codon is a hard
+// error rather than a silently emitted 'X'.
+func (s Sequence) Translate(frame int, table CodonTable) (Sequence, error) {
+	if s.Alphabet != DNA && s.Alphabet != RNA {
+		return Sequence{}, fmt.Errorf("bioseq: Translate requires DNA/RNA, got %s", s.Alphabet)
+	}
+	if frame < 0 || frame > 2 {
+		return Sequence{}, fmt.Errorf("bioseq: invalid reading frame %d", frame)
+	}
+	letters := s.Letters[frame:]
+	if len(letters)%3 != 0 {
+		return Sequence{}, fmt.Errorf("bioseq: sequence length %d (frame %d) is not a multiple of 3", len(s.Letters), frame)
+	}
+	protein := make([]byte, 0, len(letters)/3)
+	for i := 0; i+3 <= len(letters); i += 3 {
+		codon := strings.ReplaceAll(string(letters[i:i+3]), "U", "T")
+		aa, ok := table.Codons[codon]
+		if !ok {
+			return Sequence{}, fmt.Errorf("bioseq: unrecognized codon %q at offset %d (table %d)", codon, i, table.ID)
+		}
+		protein = append(protein, aa)
+	}
+	return Sequence{Name: s.Name, Letters: protein, Alphabet: Protein}, nil
+}
@@ -0,0 +1,75 @@
+package bioseq
+
+import "testing"
+
+func TestNewValidatesAlphabet(t *testing.T) {
+	if _, err := New("seq1", "ACGT", DNA); err != nil {
+		t.Fatalf("New with valid DNA letters: %v", err)
+	}
+	if _, err := New("seq1", "ACGU", DNA); err == nil {
+		t.Error("expected an error for a U in a DNA sequence")
+	}
+	seq, err := New("seq1", "acgt", DNA)
+	if err != nil {
+		t.Fatalf("New should upper-case letters before validating: %v", err)
+	}
+	if seq.String() != "ACGT" {
+		t.Errorf("String() = %q, want ACGT", seq.String())
+	}
+}
+
+func TestReverseComplement(t *testing.T) {
+	seq, err := New("seq1", "ACGTN", DNA)
+	if err != nil {
+		t.Fatalf("New: %v", err)
+	}
+	rc, err := seq.ReverseComplement()
+	if err != nil {
+		t.Fatalf("ReverseComplement: %v", err)
+	}
+	if got := rc.String(); got != "NACGT" {
+		t.Errorf("ReverseComplement(ACGTN) = %q, want NACGT", got)
+	}
+
+	protein, _ := New("p1", "MAD", Protein)
+	if _, err := protein.ReverseComplement(); err == nil {
+		t.Error("expected an error reverse-complementing a protein sequence")
+	}
+}
+
+func TestSubseq(t *testing.T) {
+	seq, _ := New("seq1", "ACGTACGT", DNA)
+	sub, err := seq.Subseq(2, 5)
+	if err != nil {
+		t.Fatalf("Subseq: %v", err)
+	}
+	if got := sub.String(); got != "GTA" {
+		t.Errorf("Subseq(2, 5) = %q, want GTA", got)
+	}
+	if _, err := seq.Subseq(4, 2); err == nil {
+		t.Error("expected an error for start > end")
+	}
+	if _, err := seq.Subseq(0, 100); err == nil {
+		t.Error("expected an error for end past the sequence length")
+	}
+}
+
+func TestTranslate(t *testing.T) {
+	seq, _ := New("orf1", "ATGGCTTAA", DNA)
+	protein, err := seq.Translate(0, StandardTable())
+	if err != nil {
+		t.Fatalf("Translate: %v", err)
+	}
+	if got := protein.String(); got != "MA*" {
+		t.Errorf("Translate(ATGGCTTAA) = %q, want MA*", got)
+	}
+
+	if _, err := seq.Translate(3, StandardTable()); err == nil {
+		t.Error("expected an error for an out-of-range frame")
+	}
+
+	truncated, _ := New("orf2", "ATGGC", DNA)
+	if _, err := truncated.Translate(0, StandardTable()); err == nil {
+		t.Error("expected an error for a length not a multiple of 3")
+	}
+}
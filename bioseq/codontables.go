@@ -0,0 +1,95 @@
+package bioseq
+
+// codonTables holds the NCBI genetic code tables this package knows how to
+// translate with, keyed by their official transl_table number. Table/
+// StandardTable stay usable for anything that wants to register more later.
+var codonTables = map[int]CodonTable{}
+
+func init() {
+	registerTable(1, "Standard", standardCodons, map[string]bool{"ATG": true, "GTG": true, "TTG": true, "CTG": true})
+	registerTable(2, "Vertebrate Mitochondrial", withOverrides(standardCodons, map[string]byte{
+		"AGA": '*', "AGG": '*', "ATA": 'M', "TGA": 'W',
+	}), map[string]bool{"ATT": true, "ATC": true, "ATA": true, "ATG": true, "GTG": true})
+	registerTable(3, "Yeast Mitochondrial", withOverrides(standardCodons, map[string]byte{
+		"ATA": 'M', "CTT": 'T', "CTC": 'T', "CTA": 'T', "CTG": 'T', "TGA": 'W',
+	}), map[string]bool{"ATA": true, "ATG": true, "GTG": true})
+	registerTable(4, "Mold/Protozoan/Coelenterate Mitochondrial; Mycoplasma/Spiroplasma", withOverrides(standardCodons, map[string]byte{
+		"TGA": 'W',
+	}), map[string]bool{"TTA": true, "TTG": true, "CTG": true, "ATT": true, "ATC": true, "ATA": true, "ATG": true, "GTG": true})
+	registerTable(5, "Invertebrate Mitochondrial", withOverrides(standardCodons, map[string]byte{
+		"AGA": 'S', "AGG": 'S', "ATA": 'M', "TGA": 'W',
+	}), map[string]bool{"TTG": true, "ATT": true, "ATC": true, "ATA": true, "ATG": true, "GTG": true})
+	registerTable(6, "Ciliate, Dasycladacean and Hexamita Nuclear", withOverrides(standardCodons, map[string]byte{
+		"TAA": 'Q', "TAG": 'Q',
+	}), map[string]bool{"ATG": true})
+	registerTable(9, "Echinoderm and Flatworm Mitochondrial", withOverrides(standardCodons, map[string]byte{
+		"AAA": 'N', "AGA": 'S', "AGG": 'S', "TGA": 'W',
+	}), map[string]bool{"ATG": true, "GTG": true})
+	registerTable(10, "Euplotid Nuclear", withOverrides(standardCodons, map[string]byte{
+		"TGA": 'C',
+	}), map[string]bool{"ATG": true})
+	registerTable(11, "Bacterial, Archaeal and Plant Plastid", standardCodons, map[string]bool{
+		"TTG": true, "CTG": true, "ATT": true, "ATC": true, "ATA": true, "ATG": true, "GTG": true,
+	})
+	registerTable(12, "Alternative Yeast Nuclear", withOverrides(standardCodons, map[string]byte{
+		"CTG": 'S',
+	}), map[string]bool{"CTG": true, "ATG": true})
+	registerTable(13, "Ascidian Mitochondrial", withOverrides(standardCodons, map[string]byte{
+		"AGA": 'G', "AGG": 'G', "ATA": 'M', "TGA": 'W',
+	}), map[string]bool{"TTG": true, "ATA": true, "ATG": true, "GTG": true})
+	registerTable(14, "Alternative Flatworm Mitochondrial", withOverrides(standardCodons, map[string]byte{
+		"AAA": 'N', "AGA": 'S', "AGG": 'S', "TAA": 'Y', "TGA": 'W',
+	}), map[string]bool{"ATG": true})
+	registerTable(16, "Chlorophycean Mitochondrial", withOverrides(standardCodons, map[string]byte{
+		"TAG": 'L',
+	}), map[string]bool{"ATG": true})
+	registerTable(21, "Trematode Mitochondrial", withOverrides(standardCodons, map[string]byte{
+		"TGA": 'W', "ATA": 'M', "AGA": 'S', "AGG": 'S', "AAA": 'N',
+	}), map[string]bool{"ATG": true, "GTG": true})
+}
+
+func registerTable(id int, name string, codons map[string]byte, starts map[string]bool) {
+	codonTables[id] = CodonTable{ID: id, Name: name, Codons: codons, Starts: starts}
+}
+
+func withOverrides(base map[string]byte, overrides map[string]byte) map[string]byte {
+	out := make(map[string]byte, len(base))
+	for k, v := range base {
+		out[k] = v
+	}
+	for k, v := range overrides {
+		out[k] = v
+	}
+	return out
+}
+
+// Table looks up a registered genetic code by its NCBI transl_table number.
+func Table(id int) (CodonTable, bool) {
+	t, ok := codonTables[id]
+	return t, ok
+}
+
+// StandardTable is NCBI transl_table=1, the default genetic code.
+func StandardTable() CodonTable {
+	t, _ := Table(1)
+	return t
+}
+
+var standardCodons = map[string]byte{
+	"TTT": 'F', "TTC": 'F', "TTA": 'L', "TTG": 'L',
+	"CTT": 'L', "CTC": 'L', "CTA": 'L', "CTG": 'L',
+	"ATT": 'I', "ATC": 'I', "ATA": 'I', "ATG": 'M',
+	"GTT": 'V', "GTC": 'V', "GTA": 'V', "GTG": 'V',
+	"TCT": 'S', "TCC": 'S', "TCA": 'S', "TCG": 'S',
+	"CCT": 'P', "CCC": 'P', "CCA": 'P', "CCG": 'P',
+	"ACT": 'T', "ACC": 'T', "ACA": 'T', "ACG": 'T',
+	"GCT": 'A', "GCC": 'A', "GCA": 'A', "GCG": 'A',
+	"TAT": 'Y', "TAC": 'Y', "TAA": '*', "TAG": '*',
+	"CAT": 'H', "CAC": 'H', "CAA": 'Q', "CAG": 'Q',
+	"AAT": 'N', "AAC": 'N', "AAA": 'K', "AAG": 'K',
+	"GAT": 'D', "GAC": 'D', "GAA": 'E', "GAG": 'E',
+	"TGT": 'C', "TGC": 'C', "TGA": '*', "TGG": 'W',
+	"CGT": 'R', "CGC": 'R', "CGA": 'R', "CGG": 'R',
+	"AGT": 'S', "AGC": 'S', "AGA": 'R', "AGG": 'R',
+	"GGT": 'G', "GGC": 'G', "GGA": 'G', "GGG": 'G',
+}
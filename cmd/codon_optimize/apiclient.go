@@ -0,0 +1,195 @@
+package main
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"math/rand"
+	"net"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	apiRetryBase  = 500 * time.Millisecond
+	apiRetryCap   = 8 * time.Second
+	apiMaxRetries = 5
+)
+
+// sharedAPIClient is the one http.Client reused by fetchProteinSequence,
+// getIDTToken, and optimizeCodonIDT, so all three benefit from connection
+// pooling and the same retry/backoff policy instead of each dialing its own
+// ad-hoc client per call.
+var sharedAPIClient = newAPIClient()
+
+// apiClient retries transient HTTP failures (429/5xx, or a timed-out
+// net.Error) with decorrelated-jitter backoff, honoring a server's
+// Retry-After header when present, in the same spirit as
+// internal/vendor/twist.go's TwistClient.request.
+type apiClient struct {
+	http *http.Client
+}
+
+func newAPIClient() *apiClient {
+	return &apiClient{http: &http.Client{Timeout: 60 * time.Second}}
+}
+
+// do sends one HTTP request built fresh from method/url/headers/body on
+// every attempt (a *http.Request's body can't be replayed across retries),
+// retrying up to apiMaxRetries times.
+func (c *apiClient) do(method, url string, headers map[string]string, body []byte) ([]byte, int, error) {
+	var backoff time.Duration
+	for attempt := 0; ; attempt++ {
+		respBody, status, header, err := c.doOnce(method, url, headers, body)
+		if err == nil && status >= 200 && status < 300 {
+			return respBody, status, nil
+		}
+		var callErr error
+		if err != nil {
+			callErr = err
+		} else {
+			callErr = fmt.Errorf("api error (%d): %s", status, strings.TrimSpace(string(respBody)))
+		}
+		if attempt >= apiMaxRetries || !apiIsRetryable(status, err) {
+			return nil, status, callErr
+		}
+		delay, ok := apiRetryAfter(header)
+		if !ok {
+			backoff = apiNextBackoff(backoff)
+			delay = backoff
+		}
+		time.Sleep(delay)
+	}
+}
+
+func (c *apiClient) doOnce(method, url string, headers map[string]string, body []byte) ([]byte, int, http.Header, error) {
+	var reader io.Reader
+	if body != nil {
+		reader = bytes.NewReader(body)
+	}
+	req, err := http.NewRequest(method, url, reader)
+	if err != nil {
+		return nil, 0, nil, err
+	}
+	for k, v := range headers {
+		req.Header.Set(k, v)
+	}
+	resp, err := c.http.Do(req)
+	if err != nil {
+		return nil, 0, nil, err
+	}
+	defer resp.Body.Close()
+	respBody, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, resp.StatusCode, resp.Header, err
+	}
+	return respBody, resp.StatusCode, resp.Header, nil
+}
+
+// apiNextBackoff computes a decorrelated-jitter backoff delay: a random
+// value between apiRetryBase and 3x the previous delay, capped at
+// apiRetryCap, matching internal/vendor/retry.go's nextBackoff.
+func apiNextBackoff(prev time.Duration) time.Duration {
+	if prev <= 0 {
+		prev = apiRetryBase
+	}
+	ceiling := prev * 3
+	if ceiling > apiRetryCap {
+		ceiling = apiRetryCap
+	}
+	if ceiling <= apiRetryBase {
+		return apiRetryBase
+	}
+	return apiRetryBase + time.Duration(rand.Int63n(int64(ceiling-apiRetryBase)))
+}
+
+func apiIsRetryable(statusCode int, err error) bool {
+	if statusCode == http.StatusTooManyRequests || statusCode >= 500 {
+		return true
+	}
+	var netErr net.Error
+	if errors.As(err, &netErr) && netErr.Timeout() {
+		return true
+	}
+	return false
+}
+
+func apiRetryAfter(h http.Header) (time.Duration, bool) {
+	v := h.Get("Retry-After")
+	if v == "" {
+		return 0, false
+	}
+	if secs, err := strconv.Atoi(v); err == nil {
+		return time.Duration(secs) * time.Second, true
+	}
+	if t, err := http.ParseTime(v); err == nil {
+		if d := time.Until(t); d > 0 {
+			return d, true
+		}
+	}
+	return 0, false
+}
+
+// cacheDir resolves the on-disk cache root: $XDG_CACHE_HOME/benchaid if
+// set, otherwise the OS's default user cache directory.
+func cacheDir() (string, error) {
+	base := os.Getenv("XDG_CACHE_HOME")
+	if base == "" {
+		var err error
+		base, err = os.UserCacheDir()
+		if err != nil {
+			return "", err
+		}
+	}
+	return filepath.Join(base, "benchaid"), nil
+}
+
+func cacheKeyPath(key string) (string, error) {
+	dir, err := cacheDir()
+	if err != nil {
+		return "", err
+	}
+	sum := sha256.Sum256([]byte(key))
+	return filepath.Join(dir, hex.EncodeToString(sum[:])+".cache"), nil
+}
+
+// cacheRead and cacheWrite are a best-effort disk cache: failures (missing
+// $HOME, read-only filesystem, etc.) just mean a cache miss rather than a
+// fatal error, the same way loadEnvFromFile tolerates a missing .env.
+func cacheRead(key string) ([]byte, bool) {
+	path, err := cacheKeyPath(key)
+	if err != nil {
+		return nil, false
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, false
+	}
+	return data, true
+}
+
+func cacheWrite(key string, data []byte) {
+	path, err := cacheKeyPath(key)
+	if err != nil {
+		return
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return
+	}
+	// Entries here can include cached OAuth bearer tokens (see
+	// getIDTTokenCached), so match the 0o600 convention used elsewhere in
+	// this repo for sensitive on-disk state (cmd/twist_order's order journal).
+	_ = os.WriteFile(path, data, 0o600)
+}
+
+func credentialHash(parts ...string) string {
+	sum := sha256.Sum256([]byte(strings.Join(parts, "\x00")))
+	return hex.EncodeToString(sum[:])
+}
@@ -0,0 +1,158 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+
+	"benchaid/codonopt"
+)
+
+// restrictionSites is a small REBASE-style table of common Type II
+// restriction enzyme recognition sequences, looked up case-insensitively by
+// --avoid-sites.
+var restrictionSites = map[string]string{
+	"ecori":   "GAATTC",
+	"bamhi":   "GGATCC",
+	"hindiii": "AAGCTT",
+	"noti":    "GCGGCCGC",
+	"xhoi":    "CTCGAG",
+	"sali":    "GTCGAC",
+	"psti":    "CTGCAG",
+	"kpni":    "GGTACC",
+	"saci":    "GAGCTC",
+	"spei":    "ACTAGT",
+	"ndei":    "CATATG",
+	"xbai":    "TCTAGA",
+	"ecorv":   "GATATC",
+	"smai":    "CCCGGG",
+	"bglii":   "AGATCT",
+	"nhei":    "GCTAGC",
+	"clai":    "ATCGAT",
+	"ncoi":    "CCATGG",
+	"sacii":   "CCGCGG",
+	"apai":    "GGGCCC",
+}
+
+// multiFlag collects every occurrence of a repeatable string flag (e.g.
+// --avoid-motif AAAAA --avoid-motif TTTTT) into a slice.
+type multiFlag []string
+
+func (m *multiFlag) String() string { return strings.Join(*m, ",") }
+
+func (m *multiFlag) Set(v string) error {
+	*m = append(*m, v)
+	return nil
+}
+
+// lookupRestrictionSite resolves an enzyme name to its recognition
+// sequence, ignoring case.
+func lookupRestrictionSite(name string) (string, bool) {
+	site, ok := restrictionSites[strings.ToLower(strings.TrimSpace(name))]
+	return site, ok
+}
+
+// parseAvoidSites turns a comma-separated --avoid-sites list of enzyme
+// names (e.g. "EcoRI,BamHI") into their recognition sequences.
+func parseAvoidSites(spec string) ([]string, error) {
+	var sites []string
+	for _, name := range strings.Split(spec, ",") {
+		name = strings.TrimSpace(name)
+		if name == "" {
+			continue
+		}
+		site, ok := lookupRestrictionSite(name)
+		if !ok {
+			return nil, fmt.Errorf("--avoid-sites: unknown enzyme %q", name)
+		}
+		sites = append(sites, site)
+	}
+	return sites, nil
+}
+
+// constraintOptions collects the CLI's hard sequence constraints built from
+// --avoid-sites, --avoid-motif, --gc-min, --gc-max, and --max-repeat-len.
+// The zero value disables constraint checking entirely.
+type constraintOptions struct {
+	AvoidSites   []string // recognition sequences and user motifs, merged
+	GCMin, GCMax float64
+	MaxRepeatLen int
+}
+
+func (c constraintOptions) enabled() bool {
+	return len(c.AvoidSites) > 0 || c.GCMin > 0 || c.GCMax > 0 || c.MaxRepeatLen > 0
+}
+
+// validateConstraints reports every violation of opts against dna, naming
+// the offending position(s) so the messages can be surfaced directly as
+// outputData.ConstraintViolations.
+func validateConstraints(dna string, opts constraintOptions) []string {
+	var violations []string
+	for _, site := range opts.AvoidSites {
+		for _, pos := range findAllIndex(dna, strings.ToUpper(site)) {
+			violations = append(violations, fmt.Sprintf("forbidden site %s found at position %d", site, pos+1))
+		}
+	}
+	if opts.GCMin > 0 || opts.GCMax > 0 {
+		gc := gcContent(dna) / 100
+		if opts.GCMin > 0 && gc < opts.GCMin {
+			violations = append(violations, fmt.Sprintf("overall GC content %.1f%% is below the %.0f%% minimum", gc*100, opts.GCMin*100))
+		}
+		if opts.GCMax > 0 && gc > opts.GCMax {
+			violations = append(violations, fmt.Sprintf("overall GC content %.1f%% is above the %.0f%% maximum", gc*100, opts.GCMax*100))
+		}
+	}
+	if opts.MaxRepeatLen > 0 {
+		for _, pos := range findRepeatPositions(dna, opts.MaxRepeatLen) {
+			violations = append(violations, fmt.Sprintf("direct repeat of length >= %d recurs at position %d", opts.MaxRepeatLen, pos+1))
+		}
+	}
+	return violations
+}
+
+func findAllIndex(s, sub string) []int {
+	var positions []int
+	if sub == "" {
+		return positions
+	}
+	for i := 0; i+len(sub) <= len(s); i++ {
+		if s[i:i+len(sub)] == sub {
+			positions = append(positions, i)
+		}
+	}
+	return positions
+}
+
+func findRepeatPositions(seq string, repeatLen int) []int {
+	if repeatLen <= 0 || len(seq) < repeatLen*2 {
+		return nil
+	}
+	seen := make(map[string]bool, len(seq))
+	var positions []int
+	for i := 0; i+repeatLen <= len(seq); i++ {
+		kmer := seq[i : i+repeatLen]
+		if seen[kmer] {
+			positions = append(positions, i)
+			continue
+		}
+		seen[kmer] = true
+	}
+	return positions
+}
+
+// repairConstraints attempts to resolve opts' violations by synonymous
+// codon substitution against table: codonopt.Refine handles avoid-sites and
+// the GC window, and repairDirectRepeats (shared with the local backend)
+// handles --max-repeat-len. It returns the repaired sequence and whatever
+// violations remain after repair.
+func repairConstraints(dna, protein string, table codonopt.Table, opts constraintOptions) (string, []string) {
+	repaired := codonopt.Refine(dna, protein, table, codonopt.Constraints{
+		AvoidSites:     opts.AvoidSites,
+		MaxHomopolymer: localMaxHomopolymer,
+		GCMin:          opts.GCMin,
+		GCMax:          opts.GCMax,
+	})
+	if opts.MaxRepeatLen > 0 {
+		repaired = repairDirectRepeats(repaired, protein, table, opts.MaxRepeatLen)
+	}
+	return repaired, validateConstraints(repaired, opts)
+}
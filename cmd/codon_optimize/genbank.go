@@ -0,0 +1,156 @@
+package main
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// restrictionEnzymeOrder lists restrictionSites' keys in a fixed, properly
+// cased order, so buildGenBank's misc_feature output is deterministic
+// instead of depending on Go's randomized map iteration order.
+var restrictionEnzymeOrder = []string{
+	"EcoRI", "BamHI", "HindIII", "NotI", "XhoI", "SalI", "PstI", "KpnI", "SacI", "SpeI",
+	"NdeI", "XbaI", "EcoRV", "SmaI", "BglII", "NheI", "ClaI", "NcoI", "SacII", "ApaI",
+}
+
+// positionPattern extracts the first "position N" style reference from a
+// complexity message, so buildGenBank can place an approximate misc_feature
+// instead of spanning the whole sequence for every hit.
+var positionPattern = regexp.MustCompile(`(?i)position\s+(\d+)`)
+
+// buildGenBank renders a minimal but valid GenBank flat file for out: a
+// LOCUS/DEFINITION header, a source feature, a CDS spanning the full ORF
+// annotated with /translation, and misc_feature entries for every
+// complexity hit and restriction site found in the optimized sequence, so
+// the result loads directly into ApE/SnapGene/Benchling without manual
+// re-annotation.
+func buildGenBank(seqName, organismName string, out outputData) string {
+	dna := out.OptimizedDNA
+	var b strings.Builder
+
+	fmt.Fprintf(&b, "LOCUS       %-16s %5d bp    DNA     linear   SYN\n", genbankLocusName(seqName), len(dna))
+	fmt.Fprintf(&b, "DEFINITION  %s, codon-optimized for %s.\n", seqName, organismName)
+	b.WriteString("ACCESSION   .\n")
+	b.WriteString("VERSION     .\n")
+	b.WriteString("SOURCE      .\n")
+	fmt.Fprintf(&b, "  ORGANISM  %s\n", organismName)
+	b.WriteString("FEATURES             Location/Qualifiers\n")
+
+	fmt.Fprintf(&b, "     source          1..%d\n", len(dna))
+	writeGenBankQualifier(&b, "organism", organismName)
+
+	if len(dna) > 0 {
+		fmt.Fprintf(&b, "     CDS             1..%d\n", len(dna))
+		writeGenBankQualifier(&b, "translation", out.InputProtein)
+	}
+
+	for _, item := range out.ComplexityScores {
+		text, ok := item.(string)
+		if !ok || text == "" {
+			continue
+		}
+		start, end := complexityFeatureCoords(text, len(dna))
+		fmt.Fprintf(&b, "     misc_feature    %d..%d\n", start, end)
+		writeGenBankQualifier(&b, "note", text)
+	}
+
+	for _, name := range restrictionEnzymeOrder {
+		site, ok := lookupRestrictionSite(name)
+		if !ok {
+			continue
+		}
+		for _, pos := range findAllIndex(dna, site) {
+			fmt.Fprintf(&b, "     misc_feature    %d..%d\n", pos+1, pos+len(site))
+			writeGenBankQualifier(&b, "label", name+" site")
+		}
+	}
+
+	b.WriteString("ORIGIN\n")
+	b.WriteString(genbankOrigin(dna))
+	b.WriteString("//\n")
+	return b.String()
+}
+
+// genbankLocusName sanitizes seqName into the token GenBank's LOCUS line
+// expects: no whitespace, capped at 16 characters.
+func genbankLocusName(seqName string) string {
+	name := strings.Map(func(r rune) rune {
+		switch {
+		case r >= 'a' && r <= 'z', r >= 'A' && r <= 'Z', r >= '0' && r <= '9', r == '_', r == '-':
+			return r
+		default:
+			return '_'
+		}
+	}, seqName)
+	if name == "" {
+		name = "sequence"
+	}
+	if len(name) > 16 {
+		name = name[:16]
+	}
+	return name
+}
+
+// writeGenBankQualifier appends a /key="value" qualifier indented to
+// column 21 and wrapped at column 79, GenBank's feature-table format.
+func writeGenBankQualifier(b *strings.Builder, key, value string) {
+	const indent = "                     " // 21 spaces
+	const width = 79
+	line := fmt.Sprintf("/%s=%q", key, value)
+	for len(line) > 0 {
+		avail := width - len(indent)
+		if len(line) <= avail {
+			b.WriteString(indent + line + "\n")
+			break
+		}
+		b.WriteString(indent + line[:avail] + "\n")
+		line = line[avail:]
+	}
+}
+
+// complexityFeatureCoords looks for a "position N" reference inside text
+// and returns a short window around it; if none is found (or it's out of
+// range), the feature falls back to spanning the whole sequence, since the
+// message's origin can't otherwise be localized.
+func complexityFeatureCoords(text string, seqLen int) (int, int) {
+	if m := positionPattern.FindStringSubmatch(text); m != nil {
+		if pos, err := strconv.Atoi(m[1]); err == nil && pos >= 1 && pos <= seqLen {
+			end := pos + 9
+			if end > seqLen {
+				end = seqLen
+			}
+			return pos, end
+		}
+	}
+	if seqLen == 0 {
+		return 1, 1
+	}
+	return 1, seqLen
+}
+
+// genbankOrigin formats dna as GenBank's ORIGIN block: lowercase sequence
+// in chunks of 10 bases, 6 chunks per line, right-justified 1-based
+// position numbers.
+func genbankOrigin(dna string) string {
+	dna = strings.ToLower(dna)
+	var b strings.Builder
+	for i := 0; i < len(dna); i += 60 {
+		fmt.Fprintf(&b, "%9d", i+1)
+		end := i + 60
+		if end > len(dna) {
+			end = len(dna)
+		}
+		for j := i; j < end; j += 10 {
+			chunkEnd := j + 10
+			if chunkEnd > end {
+				chunkEnd = end
+			}
+			b.WriteString(" ")
+			b.WriteString(dna[j:chunkEnd])
+		}
+		b.WriteString("\n")
+	}
+	return b.String()
+}
@@ -0,0 +1,287 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+
+	"benchaid/bioseq"
+	"benchaid/codonopt"
+)
+
+// Optimizer produces an optimized DNA sequence for a protein (or DNA coding
+// sequence), abstracting over IDT's hosted CodonOpt API and the offline
+// local backend so main() can select either via --backend without the rest
+// of the CLI caring which one answered.
+type Optimizer interface {
+	Optimize(req OptimizeRequest) (OptimizeResult, error)
+}
+
+// OptimizeRequest is the backend-agnostic input to an Optimizer.
+type OptimizeRequest struct {
+	Name         string
+	Sequence     string
+	SequenceType string // "aminoAcid" or "dna", as inferSequenceType reports
+	Organism     string
+	ProductType  string
+}
+
+// OptimizeResult carries everything outputData needs, regardless of which
+// backend produced it. CAI is nil when the backend doesn't report one.
+type OptimizeResult struct {
+	FullSequence      string
+	CAI               *float64
+	GCContent         float64
+	ComplexityScore   float64
+	ComplexitySummary string
+	Complexities      []complexityItem
+}
+
+// IDTOptimizer is the original backend: IDT's hosted CodonOpt API.
+type IDTOptimizer struct {
+	Token string
+}
+
+func (o IDTOptimizer) Optimize(req OptimizeRequest) (OptimizeResult, error) {
+	resp, err := optimizeCodonIDT(req.Name, req.Sequence, req.Organism, req.SequenceType, req.ProductType, o.Token)
+	if err != nil {
+		return OptimizeResult{}, err
+	}
+	if resp.OptResult.FullSequence == "" {
+		return OptimizeResult{}, errors.New("IDT response missing optimized sequence")
+	}
+	return OptimizeResult{
+		FullSequence:      resp.OptResult.FullSequence,
+		GCContent:         gcContent(resp.OptResult.FullSequence),
+		ComplexityScore:   resp.OptResult.ComplexityScore,
+		ComplexitySummary: resp.OptResult.ComplexitySummary,
+		Complexities:      resp.OptResult.Complexities,
+	}, nil
+}
+
+// localGCMin/localGCMax/localMaxHomopolymer/localMaxRepeatLen are the local
+// backend's built-in constraint thresholds, matching the defaults
+// cmd/twist_order's synthesizability linter uses for the same checks.
+const (
+	localGCMin          = 0.25
+	localGCMax          = 0.65
+	localMaxHomopolymer = 10
+	localMaxRepeatLen   = 8
+)
+
+// LocalOptimizer performs codon optimization entirely offline against the
+// codon-usage tables in benchaid/codonopt, so it needs neither network
+// access nor IDT credentials. It picks an initial codon assignment, then
+// runs a local hill-climbing pass to keep GC content in range and break up
+// homopolymer runs and direct repeats.
+type LocalOptimizer struct {
+	// Harmonize samples codons from their usage distribution ("codon
+	// harmonization") instead of always taking the single most-frequent
+	// synonym; Seed makes that sampling reproducible.
+	Harmonize bool
+	Seed      int64
+}
+
+func (o LocalOptimizer) Optimize(req OptimizeRequest) (OptimizeResult, error) {
+	protein := req.Sequence
+	if req.SequenceType == "dna" {
+		translated, err := translateToProtein(req.Sequence)
+		if err != nil {
+			return OptimizeResult{}, err
+		}
+		protein = translated
+	}
+	protein = strings.TrimSuffix(protein, "*")
+	if protein == "" {
+		return OptimizeResult{}, errors.New("local backend: empty protein sequence")
+	}
+
+	table, ok := localCodonTable(req.Organism)
+	if !ok {
+		return OptimizeResult{}, fmt.Errorf("local backend: no codon usage table for organism %q", req.Organism)
+	}
+
+	strategy := "most-frequent"
+	if o.Harmonize {
+		strategy = "weighted-random"
+	}
+	dna := codonopt.Optimize(protein, table, codonopt.Options{Strategy: strategy, Seed: o.Seed})
+	dna = codonopt.Refine(dna, protein, table, codonopt.Constraints{
+		MaxHomopolymer: localMaxHomopolymer,
+		GCMin:          localGCMin,
+		GCMax:          localGCMax,
+	})
+	dna = repairDirectRepeats(dna, protein, table, localMaxRepeatLen)
+
+	cai, err := codonopt.CAI(dna, table)
+	if err != nil {
+		return OptimizeResult{}, fmt.Errorf("local backend: %w", err)
+	}
+	summary, items := localComplexitySummary(dna)
+
+	return OptimizeResult{
+		FullSequence:      dna,
+		CAI:               &cai,
+		GCContent:         gcContent(dna),
+		ComplexitySummary: summary,
+		Complexities:      items,
+	}, nil
+}
+
+// localCodonTable maps an organismMap value (the full species name
+// codon_optimize carries around internally) to the matching preset in
+// benchaid/codonopt. Trichoplusia ni (Hi5) has no usage table of its own;
+// Spodoptera frugiperda is the closest available lepidopteran insect
+// preset, so it's reused with this explicit note rather than silently.
+func localCodonTable(organismName string) (codonopt.Table, bool) {
+	switch organismName {
+	case "Escherichia coli":
+		return codonopt.Builtin("ecoli")
+	case "Homo sapiens (human)":
+		return codonopt.Builtin("hsapiens")
+	case "Cricetulus griseus (hamster)":
+		return codonopt.Builtin("cho")
+	case "Spodoptera frugiperda", "Trichoplusia ni":
+		return codonopt.Builtin("sfrugiperda")
+	default:
+		return codonopt.Builtin(organismName)
+	}
+}
+
+var (
+	codonGroupsOnce sync.Once
+	codonGroups     map[byte][]string
+)
+
+// synonymousCodons returns every codon the standard genetic code assigns to
+// aa, built once from bioseq.StandardTable() so this file doesn't carry its
+// own copy of the genetic code.
+func synonymousCodons(aa byte) []string {
+	codonGroupsOnce.Do(func() {
+		codonGroups = make(map[byte][]string)
+		for codon, a := range bioseq.StandardTable().Codons {
+			codonGroups[a] = append(codonGroups[a], codon)
+		}
+	})
+	return codonGroups[aa]
+}
+
+func translateToProtein(dna string) (string, error) {
+	seq, err := bioseq.New("", dna, bioseq.DNA)
+	if err != nil {
+		return "", fmt.Errorf("local backend: %w", err)
+	}
+	protein, err := seq.Translate(0, bioseq.StandardTable())
+	if err != nil {
+		return "", fmt.Errorf("local backend: %w", err)
+	}
+	return protein.String(), nil
+}
+
+// repairDirectRepeats makes one left-to-right pass over dna's codons,
+// and whenever a repeatLen-mer reappears, tries re-coding the codon at the
+// second occurrence to a synonymous alternative (highest table usage
+// first) that doesn't keep the repeat. This is a single hill-climbing
+// pass, not a search to convergence, so a handful of repeats that can't be
+// broken without changing an earlier codon may remain.
+func repairDirectRepeats(dna, protein string, table codonopt.Table, repeatLen int) string {
+	if repeatLen <= 0 || len(dna) < repeatLen*2 {
+		return dna
+	}
+	codons := []byte(dna)
+	seen := make(map[string]bool, len(codons))
+	for i := 0; i+repeatLen <= len(codons); i++ {
+		kmer := string(codons[i : i+repeatLen])
+		if !seen[kmer] {
+			seen[kmer] = true
+			continue
+		}
+		codonIdx := i / 3
+		start := codonIdx * 3
+		if start+3 > len(codons) || codonIdx >= len(protein) {
+			continue
+		}
+		current := string(codons[start : start+3])
+		for _, alt := range rankedByUsage(synonymousCodons(protein[codonIdx]), table) {
+			if alt == current {
+				continue
+			}
+			candidate := append(append([]byte(nil), codons[:start]...), alt...)
+			candidate = append(candidate, codons[start+3:]...)
+			if string(candidate[i:i+repeatLen]) != kmer {
+				codons = candidate
+				break
+			}
+		}
+	}
+	return string(codons)
+}
+
+func rankedByUsage(codons []string, table codonopt.Table) []string {
+	ranked := append([]string(nil), codons...)
+	for i := 1; i < len(ranked); i++ {
+		for j := i; j > 0 && table.Usage(ranked[j]) > table.Usage(ranked[j-1]); j-- {
+			ranked[j], ranked[j-1] = ranked[j-1], ranked[j]
+		}
+	}
+	return ranked
+}
+
+// localComplexitySummary reports the constraints repairDirectRepeats and
+// codonopt.Refine could not fully satisfy, in the same spirit as
+// cmd/twist_order's synthesizability lint but scoped to what the local
+// backend itself enforces.
+func localComplexitySummary(dna string) (string, []complexityItem) {
+	var notes []string
+	if run := longestHomopolymerRun(dna); run > localMaxHomopolymer {
+		notes = append(notes, fmt.Sprintf("homopolymer run of %d exceeds the %d-base limit", run, localMaxHomopolymer))
+	}
+	if n := countRemainingRepeats(dna, localMaxRepeatLen); n > 0 {
+		notes = append(notes, fmt.Sprintf("%d direct repeat(s) of length >= %d remain", n, localMaxRepeatLen))
+	}
+	gc := gcContent(dna) / 100
+	if gc < localGCMin || gc > localGCMax {
+		notes = append(notes, fmt.Sprintf("overall GC content %.1f%% is outside %.0f-%.0f%%", gc*100, localGCMin*100, localGCMax*100))
+	}
+	items := make([]complexityItem, len(notes))
+	for i, n := range notes {
+		items[i] = complexityItem{Text: n}
+	}
+	if len(notes) == 0 {
+		return "no local complexity issues detected", nil
+	}
+	return strings.Join(notes, "; "), items
+}
+
+func longestHomopolymerRun(seq string) int {
+	longest, run := 0, 0
+	for i := 0; i < len(seq); i++ {
+		if i > 0 && seq[i] == seq[i-1] {
+			run++
+		} else {
+			run = 1
+		}
+		if run > longest {
+			longest = run
+		}
+	}
+	return longest
+}
+
+func countRemainingRepeats(seq string, repeatLen int) int {
+	if repeatLen <= 0 || len(seq) < repeatLen*2 {
+		return 0
+	}
+	seen := make(map[string]bool, len(seq))
+	count := 0
+	for i := 0; i+repeatLen <= len(seq); i++ {
+		kmer := seq[i : i+repeatLen]
+		if seen[kmer] {
+			count++
+			continue
+		}
+		seen[kmer] = true
+	}
+	return count
+}
@@ -7,11 +7,12 @@ import (
 	"errors"
 	"flag"
 	"fmt"
-	"io"
 	"net/http"
 	"net/url"
 	"os"
+	"path/filepath"
 	"strings"
+	"sync"
 	"time"
 )
 
@@ -48,29 +49,51 @@ type seqInfo struct {
 }
 
 type outputData struct {
-	Name              string        `json:"name"`
-	Organism          string        `json:"organism"`
-	InputProtein      string        `json:"input_protein"`
-	InputLengthAA     int           `json:"input_length_aa"`
-	OptimizedDNA      string        `json:"optimized_dna"`
-	OptimizedLength   int           `json:"optimized_length_bp"`
-	GCContent         float64       `json:"gc_content"`
-	CAI               interface{}   `json:"cai"`
-	ComplexityScores  []interface{} `json:"complexity_scores"`
-	ComplexitySummary string        `json:"complexity_summary"`
+	Name                 string        `json:"name"`
+	Organism             string        `json:"organism"`
+	InputProtein         string        `json:"input_protein"`
+	InputLengthAA        int           `json:"input_length_aa"`
+	OptimizedDNA         string        `json:"optimized_dna"`
+	OptimizedLength      int           `json:"optimized_length_bp"`
+	GCContent            float64       `json:"gc_content"`
+	CAI                  interface{}   `json:"cai"`
+	ComplexityScores     []interface{} `json:"complexity_scores"`
+	ComplexitySummary    string        `json:"complexity_summary"`
+	ConstraintViolations []string      `json:"constraint_violations,omitempty"`
+	Error                string        `json:"error,omitempty"`
+}
+
+// recordInput is one sequence to optimize, either carried inline (from a
+// FASTA record) or as an accession to resolve via fetchProteinSequence.
+type recordInput struct {
+	Name      string
+	Accession string
+	Sequence  string
 }
 
 func main() {
 	var (
-		sequence  string
-		accession string
-		residues  string
-		name      string
-		organism  string
-		vector    string
-		output    string
-		jsonOut   bool
-		fastaOut  bool
+		sequence     string
+		accession    string
+		residues     string
+		name         string
+		organism     string
+		vector       string
+		output       string
+		jsonOut      bool
+		fastaOut     bool
+		genbankOut   bool
+		batch        string
+		concurrency  int
+		outputDir    string
+		backend      string
+		seed         int64
+		harmonize    bool
+		avoidSites   string
+		avoidMotifs  multiFlag
+		gcMin, gcMax float64
+		maxRepeatLen int
+		noCache      bool
 	)
 
 	flag.StringVar(&sequence, "sequence", "", "Protein or DNA sequence")
@@ -89,6 +112,19 @@ func main() {
 	flag.StringVar(&output, "O", "", "Output file (default: stdout)")
 	flag.BoolVar(&jsonOut, "json", false, "Output as JSON")
 	flag.BoolVar(&fastaOut, "fasta", false, "Output as FASTA")
+	flag.BoolVar(&genbankOut, "genbank", false, "Output as a GenBank flat file (CDS, source, and misc_feature annotations), loadable directly into ApE/SnapGene/Benchling")
+	flag.StringVar(&batch, "batch", "", "Path to a multi-record FASTA file or newline-separated accession list; optimizes every record in one run")
+	flag.IntVar(&concurrency, "concurrency", 4, "Number of --batch records to optimize concurrently")
+	flag.StringVar(&outputDir, "output-dir", "", "Directory for per-record JSON files in --batch mode (used when none of --json, --fasta, or --genbank is set)")
+	flag.StringVar(&backend, "backend", "idt", "Optimization backend: idt (hosted) or local (offline, no network/credentials required)")
+	flag.Int64Var(&seed, "seed", 1, "RNG seed for --backend local --harmonize (reproducible codon sampling)")
+	flag.BoolVar(&harmonize, "harmonize", false, "With --backend local, sample codons from the usage distribution (codon harmonization) instead of always picking the most frequent")
+	flag.StringVar(&avoidSites, "avoid-sites", "", "Comma-separated restriction enzyme names whose recognition sites must not appear (e.g. EcoRI,BamHI)")
+	flag.Var(&avoidMotifs, "avoid-motif", "Additional DNA motif that must not appear (repeatable)")
+	flag.Float64Var(&gcMin, "gc-min", 0, "Minimum overall GC content fraction, e.g. 0.3 (0 disables the check)")
+	flag.Float64Var(&gcMax, "gc-max", 0, "Maximum overall GC content fraction, e.g. 0.65 (0 disables the check)")
+	flag.IntVar(&maxRepeatLen, "max-repeat-len", 0, "Reject/repair direct repeats at or above this length (0 disables the check)")
+	flag.BoolVar(&noCache, "no-cache", false, "Disable on-disk caching of NCBI FASTA responses and IDT tokens under $XDG_CACHE_HOME/benchaid")
 	flag.Usage = func() {
 		fmt.Fprintf(os.Stderr, "Codon optimize sequences using IDT API\n\n")
 		fmt.Fprintf(os.Stderr, "Usage:\n")
@@ -96,6 +132,10 @@ func main() {
 		fmt.Fprintf(os.Stderr, "  %s --accession NP_001234567 --organism ecoli\n", os.Args[0])
 		fmt.Fprintf(os.Stderr, "  %s --accession NP_001234567 --residues 1-300 --organism human\n", os.Args[0])
 		fmt.Fprintf(os.Stderr, "  %s --accession NP_001234567 --vector 438-C\n\n", os.Args[0])
+		fmt.Fprintf(os.Stderr, "  %s --batch records.fasta --organism insect --concurrency 8 --json\n", os.Args[0])
+		fmt.Fprintf(os.Stderr, "  %s --sequence MKTLLLTLVVV... --organism ecoli --backend local --harmonize\n", os.Args[0])
+		fmt.Fprintf(os.Stderr, "  %s --sequence MKTLLLTLVVV... --organism human --avoid-sites EcoRI,BamHI --gc-min 0.4 --gc-max 0.6\n", os.Args[0])
+		fmt.Fprintf(os.Stderr, "  %s --accession NP_001234567 --organism ecoli --genbank --output construct.gb\n\n", os.Args[0])
 		fmt.Fprintf(os.Stderr, "Organisms:\n")
 		fmt.Fprintf(os.Stderr, "  insect, sf9, sf21, hi5    -> Spodoptera frugiperda / Trichoplusia ni\n")
 		fmt.Fprintf(os.Stderr, "  ecoli, bacteria           -> Escherichia coli\n")
@@ -109,6 +149,62 @@ func main() {
 	}
 	flag.Parse()
 
+	if backend != "idt" && backend != "local" {
+		fatalf("unknown --backend %q (want idt or local)", backend)
+	}
+
+	sites, err := parseAvoidSites(avoidSites)
+	if err != nil {
+		fatalf("%v", err)
+	}
+	for _, motif := range avoidMotifs {
+		sites = append(sites, strings.ToUpper(motif))
+	}
+	constraints := constraintOptions{AvoidSites: sites, GCMin: gcMin, GCMax: gcMax, MaxRepeatLen: maxRepeatLen}
+
+	if batch != "" {
+		if sequence != "" || accession != "" {
+			fatalf("--batch cannot be combined with --sequence or --accession")
+		}
+		if organism == "" && vector == "" {
+			fatalf("either --organism or --vector is required")
+		}
+		if organism != "" && vector != "" {
+			fatalf("choose only one of --organism or --vector")
+		}
+
+		if vector != "" {
+			inf, ok := inferOrganismFromVector(vector)
+			if !ok {
+				fatalf("could not infer organism from vector: %s", vector)
+			}
+			fmt.Fprintf(os.Stderr, "Inferred organism from %s: %s\n", vector, inf)
+			organism = inf
+		}
+		organismName := normalizeOrganism(organism)
+
+		optimizer, err := buildOptimizer(backend, seed, harmonize, noCache)
+		if err != nil {
+			fatalf("%v", err)
+		}
+
+		runBatch(batchConfig{
+			path:         batch,
+			concurrency:  concurrency,
+			organism:     organism,
+			organismName: organismName,
+			optimizer:    optimizer,
+			constraints:  constraints,
+			noCache:      noCache,
+			jsonOut:      jsonOut,
+			fastaOut:     fastaOut,
+			genbankOut:   genbankOut,
+			output:       output,
+			outputDir:    outputDir,
+		})
+		return
+	}
+
 	if sequence == "" && accession == "" {
 		fatalf("either --sequence or --accession is required")
 	}
@@ -122,21 +218,11 @@ func main() {
 		fatalf("choose only one of --organism or --vector")
 	}
 
-	loadEnvFromFile(".env")
-
-	clientID := os.Getenv("IDT_CLIENT_ID")
-	clientSecret := os.Getenv("IDT_CLIENT_SECRET")
-	username := os.Getenv("IDT_USERNAME")
-	password := os.Getenv("IDT_PASSWORD")
-	if clientID == "" || clientSecret == "" || username == "" || password == "" {
-		fatalf("IDT credentials must be set (IDT_CLIENT_ID, IDT_CLIENT_SECRET, IDT_USERNAME, IDT_PASSWORD)")
-	}
-
 	var seq string
 	var seqName string
 	if accession != "" {
 		fmt.Fprintf(os.Stderr, "Fetching %s from NCBI...\n", accession)
-		info, err := fetchProteinSequence(accession)
+		info, err := fetchProteinSequence(accession, noCache)
 		if err != nil {
 			fatalf("failed to fetch sequence: %v", err)
 		}
@@ -176,36 +262,17 @@ func main() {
 	}
 
 	organismName := normalizeOrganism(organism)
-	fmt.Fprintf(os.Stderr, "Authenticating with IDT...\n")
-	token, err := getIDTToken(clientID, clientSecret, username, password)
+	optimizer, err := buildOptimizer(backend, seed, harmonize, noCache)
 	if err != nil {
-		fatalf("failed to authenticate with IDT: %v", err)
+		fatalf("%v", err)
 	}
 
 	fmt.Fprintf(os.Stderr, "Optimizing %d aa for %s...\n", len(seq), organismName)
-	sequenceType := inferSequenceType(seq)
-	result, err := optimizeCodonIDT(seqName, seq, organismName, sequenceType, "gene", token)
+	out, err := buildOutput(seqName, seq, organismName, optimizer, constraints)
 	if err != nil {
-		fatalf("codon optimization failed: %v", err)
-	}
-
-	optimized := result.OptResult.FullSequence
-	if optimized == "" {
-		fatalf("IDT response missing optimized sequence")
-	}
-
-	out := outputData{
-		Name:              seqName,
-		Organism:          organismName,
-		InputProtein:      seq,
-		InputLengthAA:     len(seq),
-		OptimizedDNA:      optimized,
-		OptimizedLength:   len(optimized),
-		GCContent:         gcContent(optimized),
-		CAI:               nil,
-		ComplexityScores:  toComplexityMessages(result.OptResult.Complexities),
-		ComplexitySummary: result.OptResult.ComplexitySummary,
+		fatalf("%v", err)
 	}
+	optimized := out.OptimizedDNA
 
 	var outputText string
 	switch {
@@ -217,6 +284,8 @@ func main() {
 		outputText = string(encoded)
 	case fastaOut:
 		outputText = fmt.Sprintf(">%s_codon_optimized_%s\n%s", seqName, organism, optimized)
+	case genbankOut:
+		outputText = buildGenBank(seqName, organismName, out)
 	default:
 		outputText = fmt.Sprintf(
 			"Codon Optimization Result\n=========================\nName:           %s\nOrganism:       %s\nInput:          %d aa\nOutput:         %d bp\nGC Content:     %.2f\nCAI:            %v\nComplexity:     %s\n\nOptimized DNA Sequence:\n%s\n",
@@ -224,14 +293,347 @@ func main() {
 		)
 	}
 
+	writeOutputText(outputText, output)
+
+	if len(out.ConstraintViolations) > 0 {
+		fmt.Fprintf(os.Stderr, "Unresolved constraint violations:\n")
+		for _, v := range out.ConstraintViolations {
+			fmt.Fprintf(os.Stderr, "  - %s\n", v)
+		}
+		os.Exit(1)
+	}
+}
+
+// writeOutputText writes text to output if set, otherwise to stdout.
+func writeOutputText(text, output string) {
 	if output != "" {
-		if err := os.WriteFile(output, []byte(outputText), 0o644); err != nil {
+		if err := os.WriteFile(output, []byte(text), 0o644); err != nil {
 			fatalf("failed to write output: %v", err)
 		}
 		fmt.Fprintf(os.Stderr, "Written to %s\n", output)
-	} else {
-		fmt.Println(outputText)
+		return
+	}
+	fmt.Println(text)
+}
+
+// buildOptimizer constructs the Optimizer the given --backend name selects.
+// The idt backend authenticates against IDT now so failures surface before
+// any sequence work begins; the local backend needs no credentials.
+func buildOptimizer(backend string, seed int64, harmonize bool, noCache bool) (Optimizer, error) {
+	if backend == "local" {
+		return LocalOptimizer{Seed: seed, Harmonize: harmonize}, nil
+	}
+
+	loadEnvFromFile(".env")
+	clientID := os.Getenv("IDT_CLIENT_ID")
+	clientSecret := os.Getenv("IDT_CLIENT_SECRET")
+	username := os.Getenv("IDT_USERNAME")
+	password := os.Getenv("IDT_PASSWORD")
+	if clientID == "" || clientSecret == "" || username == "" || password == "" {
+		return nil, errors.New("IDT credentials must be set (IDT_CLIENT_ID, IDT_CLIENT_SECRET, IDT_USERNAME, IDT_PASSWORD)")
+	}
+	fmt.Fprintf(os.Stderr, "Authenticating with IDT...\n")
+	token, err := getIDTTokenCached(clientID, clientSecret, username, password, noCache)
+	if err != nil {
+		return nil, fmt.Errorf("failed to authenticate with IDT: %w", err)
+	}
+	return IDTOptimizer{Token: token}, nil
+}
+
+// buildOutput optimizes seq for organismName via opt and assembles the
+// outputData the CLI's various output modes render. If constraints is
+// enabled, the optimized sequence is validated afterward and, on
+// violation, repaired via repairConstraints; any violations surviving
+// repair are reported in the returned outputData.ConstraintViolations
+// rather than as an error, so callers can still emit the sequence.
+func buildOutput(seqName, seq, organismName string, opt Optimizer, constraints constraintOptions) (outputData, error) {
+	result, err := opt.Optimize(OptimizeRequest{
+		Name:         seqName,
+		Sequence:     seq,
+		SequenceType: inferSequenceType(seq),
+		Organism:     organismName,
+		ProductType:  "gene",
+	})
+	if err != nil {
+		return outputData{}, fmt.Errorf("codon optimization failed: %w", err)
+	}
+	if result.FullSequence == "" {
+		return outputData{}, errors.New("optimizer returned no sequence")
+	}
+	var cai interface{}
+	if result.CAI != nil {
+		cai = *result.CAI
+	}
+	out := outputData{
+		Name:              seqName,
+		Organism:          organismName,
+		InputProtein:      seq,
+		InputLengthAA:     len(seq),
+		OptimizedDNA:      result.FullSequence,
+		OptimizedLength:   len(result.FullSequence),
+		GCContent:         result.GCContent,
+		CAI:               cai,
+		ComplexityScores:  toComplexityMessages(result.Complexities),
+		ComplexitySummary: result.ComplexitySummary,
+	}
+
+	if constraints.enabled() {
+		if violations := validateConstraints(out.OptimizedDNA, constraints); len(violations) > 0 {
+			protein := strings.TrimSuffix(seq, "*")
+			if inferSequenceType(seq) == "dna" {
+				if translated, terr := translateToProtein(seq); terr == nil {
+					protein = strings.TrimSuffix(translated, "*")
+				}
+			}
+			if table, ok := localCodonTable(organismName); ok {
+				repaired, remaining := repairConstraints(out.OptimizedDNA, protein, table, constraints)
+				out.OptimizedDNA = repaired
+				out.OptimizedLength = len(repaired)
+				out.GCContent = gcContent(repaired)
+				violations = remaining
+			}
+			out.ConstraintViolations = violations
+		}
+	}
+
+	return out, nil
+}
+
+// batchConfig holds the shared, already-resolved settings runBatch applies
+// to every record (organism, credentials); per-record input comes from
+// parseBatchInput.
+type batchConfig struct {
+	path         string
+	concurrency  int
+	organism     string
+	organismName string
+	optimizer    Optimizer
+	constraints  constraintOptions
+	noCache      bool
+	jsonOut      bool
+	fastaOut     bool
+	genbankOut   bool
+	output       string
+	outputDir    string
+}
+
+// runBatch optimizes every record parsed from cfg.path across a worker
+// pool of cfg.concurrency goroutines sharing one Optimizer. A failure on
+// one record is recorded in that record's outputData.Error rather than
+// aborting the run.
+func runBatch(cfg batchConfig) {
+	records, err := parseBatchInput(cfg.path)
+	if err != nil {
+		fatalf("failed to parse --batch input: %v", err)
+	}
+
+	concurrency := cfg.concurrency
+	if concurrency < 1 {
+		concurrency = 1
+	}
+	results := make([]outputData, len(records))
+	sem := make(chan struct{}, concurrency)
+	var wg sync.WaitGroup
+	for i, rec := range records {
+		wg.Add(1)
+		sem <- struct{}{}
+		go func(i int, rec recordInput) {
+			defer wg.Done()
+			defer func() { <-sem }()
+			results[i] = optimizeRecord(rec, cfg.organismName, cfg.optimizer, cfg.constraints, cfg.noCache)
+		}(i, rec)
+	}
+	wg.Wait()
+
+	switch {
+	case cfg.jsonOut:
+		writeBatchJSONL(results, cfg.output)
+	case cfg.fastaOut:
+		writeBatchFASTA(results, cfg.output)
+	case cfg.genbankOut:
+		writeBatchGenBank(results, cfg.output)
+	default:
+		if cfg.outputDir == "" {
+			fatalf("--batch without --json, --fasta, or --genbank requires --output-dir")
+		}
+		writeBatchFiles(results, cfg.outputDir)
+	}
+
+	failed, violated := 0, 0
+	for _, r := range results {
+		switch {
+		case r.Error != "":
+			failed++
+			fmt.Fprintf(os.Stderr, "%s: %s\n", r.Name, r.Error)
+		case len(r.ConstraintViolations) > 0:
+			violated++
+			fmt.Fprintf(os.Stderr, "%s: unresolved constraint violations: %s\n", r.Name, strings.Join(r.ConstraintViolations, "; "))
+		}
 	}
+	fmt.Fprintf(os.Stderr, "Batch complete: %d/%d succeeded\n", len(results)-failed, len(results))
+	if failed > 0 || violated > 0 {
+		os.Exit(1)
+	}
+}
+
+// optimizeRecord resolves rec's sequence (fetching it from NCBI if rec is
+// an accession) and runs it through buildOutput, reporting any failure in
+// the returned outputData.Error instead of returning an error, so one bad
+// record doesn't stop runBatch from processing the rest.
+func optimizeRecord(rec recordInput, organismName string, opt Optimizer, constraints constraintOptions, noCache bool) outputData {
+	name, seq, err := resolveRecordSequence(rec, noCache)
+	if err != nil {
+		failName := rec.Name
+		if failName == "" {
+			failName = rec.Accession
+		}
+		return outputData{Name: failName, Error: err.Error()}
+	}
+	out, err := buildOutput(name, seq, organismName, opt, constraints)
+	if err != nil {
+		return outputData{Name: name, Error: err.Error()}
+	}
+	return out
+}
+
+// resolveRecordSequence turns a recordInput into a name and a normalized
+// sequence, fetching it from NCBI first if the record came from an
+// accession list rather than inline FASTA.
+func resolveRecordSequence(rec recordInput, noCache bool) (string, string, error) {
+	if rec.Accession != "" {
+		info, err := fetchProteinSequence(rec.Accession, noCache)
+		if err != nil {
+			return "", "", err
+		}
+		name := rec.Name
+		if name == "" {
+			name = info.Name
+		}
+		return name, info.Sequence, nil
+	}
+	name := rec.Name
+	if name == "" {
+		name = "Query"
+	}
+	return name, normalizeSequence(rec.Sequence), nil
+}
+
+// parseBatchInput reads path as either a multi-record FASTA file (if it
+// starts with '>') or a newline-separated list of NCBI accessions.
+func parseBatchInput(path string) ([]recordInput, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	text := strings.TrimSpace(string(data))
+	if strings.HasPrefix(text, ">") {
+		return parseBatchFASTA(text)
+	}
+	return parseBatchAccessions(text)
+}
+
+func parseBatchFASTA(text string) ([]recordInput, error) {
+	var records []recordInput
+	var current *recordInput
+	var seqLines []string
+	flush := func() {
+		if current != nil {
+			current.Sequence = strings.Join(seqLines, "")
+			records = append(records, *current)
+		}
+	}
+	for _, line := range strings.Split(text, "\n") {
+		line = strings.TrimRight(line, "\r")
+		if strings.HasPrefix(line, ">") {
+			flush()
+			header := strings.TrimSpace(strings.TrimPrefix(line, ">"))
+			name := header
+			if fields := strings.Fields(header); len(fields) > 0 {
+				name = fields[0]
+			}
+			current = &recordInput{Name: name}
+			seqLines = nil
+			continue
+		}
+		if strings.TrimSpace(line) == "" {
+			continue
+		}
+		seqLines = append(seqLines, line)
+	}
+	flush()
+	if len(records) == 0 {
+		return nil, errors.New("no FASTA records found")
+	}
+	return records, nil
+}
+
+func parseBatchAccessions(text string) ([]recordInput, error) {
+	var records []recordInput
+	for _, line := range strings.Split(text, "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		records = append(records, recordInput{Accession: line})
+	}
+	if len(records) == 0 {
+		return nil, errors.New("no accessions found")
+	}
+	return records, nil
+}
+
+func writeBatchJSONL(results []outputData, output string) {
+	var buf bytes.Buffer
+	enc := json.NewEncoder(&buf)
+	for _, r := range results {
+		if err := enc.Encode(r); err != nil {
+			fatalf("failed to encode JSONL: %v", err)
+		}
+	}
+	writeOutputText(strings.TrimRight(buf.String(), "\n"), output)
+}
+
+func writeBatchFASTA(results []outputData, output string) {
+	var buf strings.Builder
+	for _, r := range results {
+		if r.Error != "" || r.OptimizedDNA == "" {
+			continue
+		}
+		fmt.Fprintf(&buf, ">%s_codon_optimized_%s\n%s\n", r.Name, r.Organism, r.OptimizedDNA)
+	}
+	writeOutputText(strings.TrimRight(buf.String(), "\n"), output)
+}
+
+// writeBatchGenBank concatenates one GenBank record per successfully
+// optimized result. Most GenBank-aware tools (ApE, SnapGene, Benchling)
+// accept a multi-record flat file by treating each "//" as a separator.
+func writeBatchGenBank(results []outputData, output string) {
+	var buf strings.Builder
+	for _, r := range results {
+		if r.Error != "" || r.OptimizedDNA == "" {
+			continue
+		}
+		buf.WriteString(buildGenBank(r.Name, r.Organism, r))
+	}
+	writeOutputText(strings.TrimRight(buf.String(), "\n"), output)
+}
+
+func writeBatchFiles(results []outputData, dir string) {
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		fatalf("failed to create output dir: %v", err)
+	}
+	for _, r := range results {
+		encoded, err := json.MarshalIndent(r, "", "  ")
+		if err != nil {
+			fatalf("failed to encode %s: %v", r.Name, err)
+		}
+		name := strings.NewReplacer("/", "_", "\\", "_", " ", "_").Replace(r.Name)
+		fname := filepath.Join(dir, name+".json")
+		if err := os.WriteFile(fname, encoded, 0o644); err != nil {
+			fatalf("failed to write %s: %v", fname, err)
+		}
+	}
+	fmt.Fprintf(os.Stderr, "Wrote %d record(s) to %s\n", len(results), dir)
 }
 
 func loadEnvFromFile(path string) {
@@ -313,27 +715,28 @@ func parsePositiveInt(val string) (int, error) {
 	return n, nil
 }
 
-func fetchProteinSequence(accession string) (seqInfo, error) {
-	query := url.QueryEscape(accession)
-	reqURL := fmt.Sprintf("https://eutils.ncbi.nlm.nih.gov/entrez/eutils/efetch.fcgi?db=protein&id=%s&rettype=fasta&retmode=text", query)
-	req, err := http.NewRequest(http.MethodGet, reqURL, nil)
-	if err != nil {
-		return seqInfo{}, err
-	}
-	req.Header.Set("User-Agent", "Benchmate/1.0")
-	client := &http.Client{Timeout: 30 * time.Second}
-	resp, err := client.Do(req)
-	if err != nil {
-		return seqInfo{}, err
-	}
-	defer resp.Body.Close()
-	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
-		body, _ := io.ReadAll(resp.Body)
-		return seqInfo{}, fmt.Errorf("NCBI fetch error: %s", strings.TrimSpace(string(body)))
+// fetchProteinSequence fetches accession's FASTA record from NCBI, caching
+// the raw response on disk (keyed by accession) unless noCache is set —
+// accessions are immutable records, so there's no TTL to track.
+func fetchProteinSequence(accession string, noCache bool) (seqInfo, error) {
+	cacheKey := "ncbi-fasta:" + accession
+	var fasta []byte
+	if !noCache {
+		if cached, ok := cacheRead(cacheKey); ok {
+			fasta = cached
+		}
 	}
-	fasta, err := io.ReadAll(resp.Body)
-	if err != nil {
-		return seqInfo{}, err
+	if fasta == nil {
+		query := url.QueryEscape(accession)
+		reqURL := fmt.Sprintf("https://eutils.ncbi.nlm.nih.gov/entrez/eutils/efetch.fcgi?db=protein&id=%s&rettype=fasta&retmode=text", query)
+		respBody, _, err := sharedAPIClient.do(http.MethodGet, reqURL, map[string]string{"User-Agent": "Benchmate/1.0"}, nil)
+		if err != nil {
+			return seqInfo{}, fmt.Errorf("NCBI fetch error: %w", err)
+		}
+		fasta = respBody
+		if !noCache {
+			cacheWrite(cacheKey, fasta)
+		}
 	}
 	lines := strings.Split(strings.TrimSpace(string(fasta)), "\n")
 	if len(lines) == 0 {
@@ -360,7 +763,11 @@ func fetchProteinSequence(accession string) (seqInfo, error) {
 	}, nil
 }
 
-func getIDTToken(clientID, clientSecret, username, password string) (string, error) {
+// getIDTToken authenticates against IDT's OAuth2 password grant and returns
+// the bearer token along with its reported TTL (the expires_in field),
+// which getIDTTokenCached uses to decide when a cached token has gone
+// stale.
+func getIDTToken(clientID, clientSecret, username, password string) (string, time.Duration, error) {
 	data := url.Values{}
 	data.Set("grant_type", "password")
 	data.Set("scope", "test")
@@ -369,33 +776,53 @@ func getIDTToken(clientID, clientSecret, username, password string) (string, err
 	encoded := data.Encode()
 
 	creds := base64.StdEncoding.EncodeToString([]byte(clientID + ":" + clientSecret))
-	req, err := http.NewRequest(http.MethodPost, idtAuthURL, strings.NewReader(encoded))
-	if err != nil {
-		return "", err
-	}
-	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
-	req.Header.Set("Authorization", "Basic "+creds)
-
-	client := &http.Client{Timeout: 30 * time.Second}
-	resp, err := client.Do(req)
-	if err != nil {
-		return "", err
+	headers := map[string]string{
+		"Content-Type":  "application/x-www-form-urlencoded",
+		"Authorization": "Basic " + creds,
 	}
-	defer resp.Body.Close()
-	body, err := io.ReadAll(resp.Body)
+	body, _, err := sharedAPIClient.do(http.MethodPost, idtAuthURL, headers, []byte(encoded))
 	if err != nil {
-		return "", err
+		return "", 0, fmt.Errorf("IDT auth error: %w", err)
 	}
-	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
-		return "", fmt.Errorf("IDT auth error: %s", strings.TrimSpace(string(body)))
+	var payload struct {
+		AccessToken string  `json:"access_token"`
+		ExpiresIn   float64 `json:"expires_in"`
 	}
-	var payload map[string]interface{}
 	if err := json.Unmarshal(body, &payload); err != nil {
+		return "", 0, err
+	}
+	if payload.AccessToken == "" {
+		return "", 0, errors.New("missing access_token")
+	}
+	return payload.AccessToken, time.Duration(payload.ExpiresIn) * time.Second, nil
+}
+
+type idtTokenCacheEntry struct {
+	Token     string `json:"token"`
+	ExpiresAt int64  `json:"expires_at"`
+}
+
+// getIDTTokenCached wraps getIDTToken with an on-disk cache keyed by a hash
+// of the credentials, so repeated invocations (e.g. --batch runs) skip
+// re-authenticating until the cached token's TTL has elapsed.
+func getIDTTokenCached(clientID, clientSecret, username, password string, noCache bool) (string, error) {
+	cacheKey := "idt-token:" + credentialHash(clientID, clientSecret, username, password)
+	if !noCache {
+		if cached, ok := cacheRead(cacheKey); ok {
+			var entry idtTokenCacheEntry
+			if err := json.Unmarshal(cached, &entry); err == nil && entry.Token != "" && time.Now().Unix() < entry.ExpiresAt {
+				return entry.Token, nil
+			}
+		}
+	}
+	token, ttl, err := getIDTToken(clientID, clientSecret, username, password)
+	if err != nil {
 		return "", err
 	}
-	token, _ := payload["access_token"].(string)
-	if token == "" {
-		return "", errors.New("missing access_token")
+	if !noCache && ttl > 0 {
+		if encoded, err := json.Marshal(idtTokenCacheEntry{Token: token, ExpiresAt: time.Now().Add(ttl).Unix()}); err == nil {
+			cacheWrite(cacheKey, encoded)
+		}
 	}
 	return token, nil
 }
@@ -431,24 +858,13 @@ func optimizeCodonIDT(name, sequence, organismName, sequenceType, productType, t
 	if err != nil {
 		return codonOptResponse{}, err
 	}
-	req, err := http.NewRequest(http.MethodPost, idtCodonURL, bytes.NewReader(body))
-	if err != nil {
-		return codonOptResponse{}, err
-	}
-	req.Header.Set("Content-Type", "application/json")
-	req.Header.Set("Authorization", "Bearer "+token)
-	client := &http.Client{Timeout: 60 * time.Second}
-	resp, err := client.Do(req)
-	if err != nil {
-		return codonOptResponse{}, err
+	headers := map[string]string{
+		"Content-Type":  "application/json",
+		"Authorization": "Bearer " + token,
 	}
-	defer resp.Body.Close()
-	respBody, err := io.ReadAll(resp.Body)
+	respBody, _, err := sharedAPIClient.do(http.MethodPost, idtCodonURL, headers, body)
 	if err != nil {
-		return codonOptResponse{}, err
-	}
-	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
-		return codonOptResponse{}, fmt.Errorf("IDT API error: %s", strings.TrimSpace(string(respBody)))
+		return codonOptResponse{}, fmt.Errorf("IDT API error: %w", err)
 	}
 	var results []codonOptResponse
 	if err := json.Unmarshal(respBody, &results); err != nil {
@@ -0,0 +1,324 @@
+package main
+
+import (
+	"bufio"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"sync"
+	"time"
+)
+
+// batchEntry is one unit of work read from a --batch input file: either
+// Sequence+Name (from a FASTA record) or Identifier (from a plain ID
+// list), carrying the same per-entry label either way.
+type batchEntry struct {
+	Label      string
+	Identifier string
+	Sequence   string
+	Name       string
+}
+
+// batchRecord is the NDJSON shape written for each batch entry: the same
+// jsonOutput --format=json produces for a single entry, or an error string
+// when the entry failed. Exactly one of Result/Error is set. JobHash is
+// always set so a later --resume run can recognize a completed entry.
+type batchRecord struct {
+	Input   string      `json:"input"`
+	JobHash string      `json:"job_hash"`
+	Result  *jsonOutput `json:"result,omitempty"`
+	Error   string      `json:"error,omitempty"`
+}
+
+// batchSummary is the final NDJSON line of a --batch run: aggregate
+// counts and timing so a caller doesn't have to scan every record to see
+// how the run went.
+type batchSummary struct {
+	Summary        bool    `json:"summary"`
+	Total          int     `json:"total"`
+	Succeeded      int     `json:"succeeded"`
+	Failed         int     `json:"failed"`
+	Skipped        int     `json:"skipped"`
+	Elapsed        string  `json:"elapsed"`
+	ElapsedSeconds float64 `json:"elapsed_seconds"`
+}
+
+// batchOptions bundles runBatchMode's own knobs (worker pool size, output
+// destination, per-job timeout, and resume behavior), as distinct from
+// reqTemplate, which carries the scoring/fetch options applied to every
+// entry in the batch.
+type batchOptions struct {
+	Workers    int
+	Output     string
+	JobTimeout time.Duration
+	Resume     bool
+}
+
+// runBatchMode reads entries from path (auto-detecting FASTA vs. a plain
+// ID list; "-" reads stdin), runs Run concurrently across opts.Workers
+// goroutines sharing fc's connection pool, rate limiter, and cache, and
+// writes one NDJSON record per entry to opts.Output (stdout if empty) as
+// it completes, followed by a summary record. A failure on one entry is
+// captured in its record's Error field and never aborts the rest of the
+// batch. A live "N/total done" progress line is written to stderr.
+func runBatchMode(ctx context.Context, fc *fetchClient, calib *calibrationModel, path string, reqTemplate Request, opts batchOptions) {
+	entries, err := readBatchEntries(path)
+	if err != nil {
+		fatalf("failed to read --batch input: %v", err)
+	}
+	if len(entries) == 0 {
+		fatalf("--batch input %q contained no entries", path)
+	}
+	workers := opts.Workers
+	if workers <= 0 {
+		workers = 1
+	}
+
+	completedHashes, err := loadCompletedJobHashes(opts)
+	if err != nil {
+		fatalf("failed to read --resume ledger: %v", err)
+	}
+
+	out, closeOut, err := openBatchOutput(opts)
+	if err != nil {
+		fatalf("failed to open --output: %v", err)
+	}
+	defer closeOut()
+
+	var pending []batchEntry
+	skipped := 0
+	for _, entry := range entries {
+		if completedHashes[jobHash(entry, reqTemplate)] {
+			skipped++
+			continue
+		}
+		pending = append(pending, entry)
+	}
+
+	jobs := make(chan batchEntry)
+	records := make(chan batchRecord)
+
+	var wg sync.WaitGroup
+	for i := 0; i < workers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for entry := range jobs {
+				req := reqTemplate
+				req.Identifier = entry.Identifier
+				req.Sequence = entry.Sequence
+				req.Name = entry.Name
+
+				jobCtx := ctx
+				cancel := func() {}
+				if opts.JobTimeout > 0 {
+					jobCtx, cancel = context.WithTimeout(ctx, opts.JobTimeout)
+				}
+
+				result, err := Run(jobCtx, fc, calib, req)
+				cancel()
+				record := batchRecord{Input: entry.Label, JobHash: jobHash(entry, reqTemplate)}
+				if err != nil {
+					record.Error = err.Error()
+				} else {
+					record.Result = &result
+				}
+				records <- record
+			}
+		}()
+	}
+
+	go func() {
+		for _, entry := range pending {
+			jobs <- entry
+		}
+		close(jobs)
+	}()
+
+	go func() {
+		wg.Wait()
+		close(records)
+	}()
+
+	started := time.Now()
+	enc := json.NewEncoder(out)
+	var succeeded, failed int
+	total := len(entries)
+	done := skipped
+	for record := range records {
+		if record.Error != "" {
+			failed++
+		} else {
+			succeeded++
+		}
+		done++
+		if err := enc.Encode(record); err != nil {
+			fatalf("failed to write batch record: %v", err)
+		}
+		fmt.Fprintf(os.Stderr, "\rbatch: %d/%d done (%d failed, %d skipped)", done, total, failed, skipped)
+	}
+	fmt.Fprintln(os.Stderr)
+
+	elapsed := time.Since(started)
+	if err := enc.Encode(batchSummary{
+		Summary:        true,
+		Total:          total,
+		Succeeded:      succeeded,
+		Failed:         failed,
+		Skipped:        skipped,
+		Elapsed:        elapsed.String(),
+		ElapsedSeconds: elapsed.Seconds(),
+	}); err != nil {
+		fatalf("failed to write batch summary: %v", err)
+	}
+}
+
+// jobHash fingerprints an entry plus the scoring/fetch options applied to
+// it, so a later --resume run can tell whether a previously-completed
+// record in opts.Output still matches the current invocation's inputs.
+func jobHash(entry batchEntry, req Request) string {
+	h := sha256.New()
+	fmt.Fprintf(h, "%s\x00%s\x00%s\x00%s\x00%d\x00%d\x00%s\x00%t\x00%t\x00%s\x00%d\x00%.4f\x00%.4f\x00%t",
+		entry.Label, entry.Identifier, entry.Sequence, req.Region, req.MinLength, req.MaxLength,
+		req.DisorderSource, req.FetchCIF, req.Diverse, req.DiverseMethod, req.DiverseK,
+		req.IoUThreshold, req.MMRLambda, req.FetchMSA)
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+// loadCompletedJobHashes reads opts.Output (when opts.Resume is set) and
+// returns the JobHash of every record that completed without an Error, so
+// runBatchMode can skip re-running them.
+func loadCompletedJobHashes(opts batchOptions) (map[string]bool, error) {
+	hashes := make(map[string]bool)
+	if !opts.Resume {
+		return hashes, nil
+	}
+	if opts.Output == "" {
+		return nil, fmt.Errorf("--resume requires --output")
+	}
+	f, err := os.Open(opts.Output)
+	if os.IsNotExist(err) {
+		return hashes, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	dec := json.NewDecoder(f)
+	for {
+		var record batchRecord
+		if err := dec.Decode(&record); err != nil {
+			if err == io.EOF {
+				break
+			}
+			return nil, err
+		}
+		if record.Error == "" && record.JobHash != "" {
+			hashes[record.JobHash] = true
+		}
+	}
+	return hashes, nil
+}
+
+// openBatchOutput opens opts.Output for writing batch records: truncated
+// unless resuming (in which case completed records must be preserved and
+// new ones appended), or stdout when opts.Output is empty.
+func openBatchOutput(opts batchOptions) (io.Writer, func(), error) {
+	if opts.Output == "" {
+		return os.Stdout, func() {}, nil
+	}
+	flags := os.O_CREATE | os.O_WRONLY
+	if opts.Resume {
+		flags |= os.O_APPEND
+	} else {
+		flags |= os.O_TRUNC
+	}
+	f, err := os.OpenFile(opts.Output, flags, 0o644)
+	if err != nil {
+		return nil, nil, err
+	}
+	return f, func() { f.Close() }, nil
+}
+
+// readBatchEntries loads path as either a multi-FASTA file (detected by a
+// leading '>' on its first non-blank line) or a newline-delimited list of
+// UniProt IDs/gene names, one entry per line. path == "-" reads stdin.
+func readBatchEntries(path string) ([]batchEntry, error) {
+	var data []byte
+	var err error
+	if path == "-" {
+		data, err = io.ReadAll(os.Stdin)
+	} else {
+		data, err = os.ReadFile(path)
+	}
+	if err != nil {
+		return nil, err
+	}
+	text := string(data)
+	trimmed := strings.TrimLeft(text, "\r\n\t ")
+	if strings.HasPrefix(trimmed, ">") {
+		return parseFASTAEntries(text), nil
+	}
+	return parseIdentifierEntries(text), nil
+}
+
+// parseFASTAEntries splits multi-FASTA text into one batchEntry per
+// record, using the first whitespace-delimited token of each header line
+// as both the Name and the record's Label.
+func parseFASTAEntries(text string) []batchEntry {
+	var entries []batchEntry
+	var header string
+	var seq strings.Builder
+
+	flush := func() {
+		if header == "" {
+			return
+		}
+		fields := strings.Fields(header)
+		name := header
+		if len(fields) > 0 {
+			name = fields[0]
+		}
+		entries = append(entries, batchEntry{
+			Label:    name,
+			Sequence: seq.String(),
+			Name:     name,
+		})
+		seq.Reset()
+	}
+
+	scanner := bufio.NewScanner(strings.NewReader(text))
+	scanner.Buffer(make([]byte, 0, 64*1024), 10*1024*1024)
+	for scanner.Scan() {
+		line := scanner.Text()
+		if strings.HasPrefix(line, ">") {
+			flush()
+			header = strings.TrimSpace(strings.TrimPrefix(line, ">"))
+			continue
+		}
+		seq.WriteString(strings.TrimSpace(line))
+	}
+	flush()
+	return entries
+}
+
+// parseIdentifierEntries splits text into one batchEntry per non-blank
+// line, treating each as a UniProt ID, entry name, or gene name.
+func parseIdentifierEntries(text string) []batchEntry {
+	var entries []batchEntry
+	scanner := bufio.NewScanner(strings.NewReader(text))
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		entries = append(entries, batchEntry{Label: line, Identifier: line})
+	}
+	return entries
+}
@@ -0,0 +1,254 @@
+package main
+
+import (
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	_ "github.com/mattn/go-sqlite3"
+)
+
+// cacheRecord is one cached HTTP response: its payload plus enough of the
+// response (URL, ETag, Last-Modified) for fetchWithCache to revalidate it
+// with a conditional GET instead of re-fetching it outright once it's
+// past cacheTTL.
+type cacheRecord struct {
+	Namespace    string    `json:"namespace"`
+	Key          string    `json:"key"`
+	URL          string    `json:"url,omitempty"`
+	FetchedAt    time.Time `json:"fetched_at"`
+	ETag         string    `json:"etag,omitempty"`
+	LastModified string    `json:"last_modified,omitempty"`
+	Payload      []byte    `json:"payload"`
+}
+
+// cacheStore is the persistence behind fetchClient's cache. FileStore
+// (one JSON file per entry, the original layout) and SQLiteStore (one
+// database file) both implement it, and fetchWithCache doesn't care
+// which backs it.
+type cacheStore interface {
+	Get(namespace, key string) (cacheRecord, bool)
+	Put(record cacheRecord) error
+	List() ([]cacheRecord, error)
+	Prune(ttl time.Duration) (int, error)
+	Close() error
+}
+
+// FileStore persists the cache as one JSON file per entry under
+// dir/namespace/key.json. It's the original on-disk layout; SQLiteStore
+// is the newer alternative for installations with enough cached entries
+// that per-file stats start to show up.
+type FileStore struct {
+	dir string
+}
+
+// NewFileStore builds a FileStore rooted at dir.
+func NewFileStore(dir string) *FileStore {
+	return &FileStore{dir: dir}
+}
+
+func (s *FileStore) path(namespace, key string) string {
+	key = strings.ToLower(key)
+	key = strings.ReplaceAll(key, "/", "_")
+	key = strings.ReplaceAll(key, " ", "_")
+	return filepath.Join(s.dir, namespace, fmt.Sprintf("%s.json", key))
+}
+
+func (s *FileStore) Get(namespace, key string) (cacheRecord, bool) {
+	data, err := os.ReadFile(s.path(namespace, key))
+	if err != nil {
+		return cacheRecord{}, false
+	}
+	var record cacheRecord
+	if err := json.Unmarshal(data, &record); err != nil {
+		return cacheRecord{}, false
+	}
+	record.Namespace, record.Key = namespace, key
+	return record, true
+}
+
+func (s *FileStore) Put(record cacheRecord) error {
+	path := s.path(record.Namespace, record.Key)
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return err
+	}
+	data, err := json.MarshalIndent(record, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, data, 0644)
+}
+
+func (s *FileStore) List() ([]cacheRecord, error) {
+	var records []cacheRecord
+	err := filepath.WalkDir(s.dir, func(path string, d fs.DirEntry, walkErr error) error {
+		if walkErr != nil {
+			if os.IsNotExist(walkErr) {
+				return nil
+			}
+			return walkErr
+		}
+		if d.IsDir() || !strings.HasSuffix(path, ".json") {
+			return nil
+		}
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return nil
+		}
+		var record cacheRecord
+		if err := json.Unmarshal(data, &record); err != nil {
+			return nil
+		}
+		rel, err := filepath.Rel(s.dir, path)
+		if err != nil {
+			return nil
+		}
+		record.Namespace = filepath.Dir(rel)
+		record.Key = strings.TrimSuffix(filepath.Base(rel), ".json")
+		records = append(records, record)
+		return nil
+	})
+	return records, err
+}
+
+func (s *FileStore) Prune(ttl time.Duration) (int, error) {
+	records, err := s.List()
+	if err != nil {
+		return 0, err
+	}
+	var removed int
+	for _, record := range records {
+		if time.Since(record.FetchedAt) <= ttl {
+			continue
+		}
+		if err := os.Remove(s.path(record.Namespace, record.Key)); err == nil {
+			removed++
+		}
+	}
+	return removed, nil
+}
+
+func (s *FileStore) Close() error { return nil }
+
+// SQLiteStore persists the cache in a single SQLite database instead of
+// one file per entry, so a cold start doesn't have to stat thousands of
+// small cache files and `benchaid cache stats` can answer from one
+// query rather than a directory walk.
+type SQLiteStore struct {
+	db *sql.DB
+}
+
+// NewSQLiteStore opens (creating if necessary) a SQLite database at path
+// and ensures its cache_entries table exists.
+func NewSQLiteStore(path string) (*SQLiteStore, error) {
+	db, err := sql.Open("sqlite3", path)
+	if err != nil {
+		return nil, err
+	}
+	schema := `CREATE TABLE IF NOT EXISTS cache_entries (
+		namespace TEXT NOT NULL,
+		key TEXT NOT NULL,
+		url TEXT,
+		fetched_at TEXT NOT NULL,
+		etag TEXT,
+		last_modified TEXT,
+		payload BLOB NOT NULL,
+		PRIMARY KEY (namespace, key)
+	)`
+	if _, err := db.Exec(schema); err != nil {
+		db.Close()
+		return nil, fmt.Errorf("create cache_entries table: %w", err)
+	}
+	return &SQLiteStore{db: db}, nil
+}
+
+func (s *SQLiteStore) Get(namespace, key string) (cacheRecord, bool) {
+	var record cacheRecord
+	var fetchedAt string
+	row := s.db.QueryRow(
+		`SELECT url, fetched_at, etag, last_modified, payload FROM cache_entries WHERE namespace = ? AND key = ?`,
+		namespace, key,
+	)
+	if err := row.Scan(&record.URL, &fetchedAt, &record.ETag, &record.LastModified, &record.Payload); err != nil {
+		return cacheRecord{}, false
+	}
+	parsed, err := time.Parse(time.RFC3339, fetchedAt)
+	if err != nil {
+		return cacheRecord{}, false
+	}
+	record.Namespace, record.Key, record.FetchedAt = namespace, key, parsed
+	return record, true
+}
+
+func (s *SQLiteStore) Put(record cacheRecord) error {
+	_, err := s.db.Exec(
+		`INSERT INTO cache_entries (namespace, key, url, fetched_at, etag, last_modified, payload)
+			VALUES (?, ?, ?, ?, ?, ?, ?)
+			ON CONFLICT(namespace, key) DO UPDATE SET
+				url=excluded.url, fetched_at=excluded.fetched_at,
+				etag=excluded.etag, last_modified=excluded.last_modified, payload=excluded.payload`,
+		record.Namespace, record.Key, record.URL,
+		record.FetchedAt.UTC().Format(time.RFC3339), record.ETag, record.LastModified, record.Payload,
+	)
+	return err
+}
+
+func (s *SQLiteStore) List() ([]cacheRecord, error) {
+	rows, err := s.db.Query(`SELECT namespace, key, url, fetched_at, etag, last_modified, payload FROM cache_entries`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var records []cacheRecord
+	for rows.Next() {
+		var record cacheRecord
+		var fetchedAt string
+		if err := rows.Scan(&record.Namespace, &record.Key, &record.URL, &fetchedAt, &record.ETag, &record.LastModified, &record.Payload); err != nil {
+			return nil, err
+		}
+		record.FetchedAt, _ = time.Parse(time.RFC3339, fetchedAt)
+		records = append(records, record)
+	}
+	return records, rows.Err()
+}
+
+func (s *SQLiteStore) Prune(ttl time.Duration) (int, error) {
+	cutoff := time.Now().Add(-ttl).UTC().Format(time.RFC3339)
+	res, err := s.db.Exec(`DELETE FROM cache_entries WHERE fetched_at < ?`, cutoff)
+	if err != nil {
+		return 0, err
+	}
+	affected, err := res.RowsAffected()
+	return int(affected), err
+}
+
+func (s *SQLiteStore) Close() error { return s.db.Close() }
+
+// openCacheStore builds the cacheStore named by backend ("file" or
+// "sqlite"), rooted/located at path.
+func openCacheStore(backend, path string) (cacheStore, error) {
+	switch backend {
+	case "", "file":
+		return NewFileStore(path), nil
+	case "sqlite":
+		return NewSQLiteStore(path)
+	default:
+		return nil, fmt.Errorf("unknown cache backend %q (want file or sqlite)", backend)
+	}
+}
+
+// defaultCachePath returns the default location for backend under
+// cacheDir: the directory itself for "file", or a single db file for
+// "sqlite".
+func defaultCachePath(backend, cacheDir string) string {
+	if backend == "sqlite" {
+		return filepath.Join(cacheDir, "cache.db")
+	}
+	return cacheDir
+}
@@ -0,0 +1,113 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"text/tabwriter"
+	"time"
+)
+
+// runCacheCommand dispatches `benchaid cache <subcommand>`: list, prune, or
+// stats against the cache backend named by --cache-backend/--cache-path,
+// the same flags the top-level command uses to configure fetchClient.
+func runCacheCommand(args []string) {
+	if len(args) < 1 {
+		fatalf("usage: benchaid cache <list|prune|stats> [flags]")
+	}
+	switch args[0] {
+	case "list":
+		runCacheList(args[1:])
+	case "prune":
+		runCachePrune(args[1:])
+	case "stats":
+		runCacheStats(args[1:])
+	default:
+		fatalf("unknown cache subcommand %q (want list, prune, or stats)", args[0])
+	}
+}
+
+// cacheFlags registers the --cache-backend/--cache-path flags common to
+// every cache subcommand and returns pointers to their values;
+// openConfiguredCacheStore turns those into an open store.
+func cacheFlags(fs *flag.FlagSet) (backend, path *string) {
+	backend = fs.String("cache-backend", "file", "Cache backend: file (one JSON file per entry) or sqlite")
+	path = fs.String("cache-path", "", "Cache location (defaults to the platform cache dir, or cache.db within it for --cache-backend=sqlite)")
+	return backend, path
+}
+
+func openConfiguredCacheStore(backend, path string) cacheStore {
+	if path == "" {
+		path = defaultCachePath(backend, defaultCacheDir())
+	}
+	store, err := openCacheStore(backend, path)
+	if err != nil {
+		fatalf("%v", err)
+	}
+	return store
+}
+
+func runCacheList(args []string) {
+	fs := flag.NewFlagSet("cache list", flag.ExitOnError)
+	backend, path := cacheFlags(fs)
+	fs.Parse(args)
+
+	store := openConfiguredCacheStore(*backend, *path)
+	defer store.Close()
+
+	records, err := store.List()
+	if err != nil {
+		fatalf("failed to list cache entries: %v", err)
+	}
+	w := tabwriter.NewWriter(os.Stdout, 0, 2, 2, ' ', 0)
+	fmt.Fprintln(w, "NAMESPACE\tKEY\tFETCHED\tAGE\tSIZE")
+	for _, record := range records {
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%d\n",
+			record.Namespace, record.Key,
+			record.FetchedAt.Format(time.RFC3339),
+			time.Since(record.FetchedAt).Round(time.Second),
+			len(record.Payload))
+	}
+	w.Flush()
+}
+
+func runCachePrune(args []string) {
+	fs := flag.NewFlagSet("cache prune", flag.ExitOnError)
+	backend, path := cacheFlags(fs)
+	ttl := fs.Duration("ttl", cacheTTL, "Remove entries fetched longer ago than this")
+	fs.Parse(args)
+
+	store := openConfiguredCacheStore(*backend, *path)
+	defer store.Close()
+
+	removed, err := store.Prune(*ttl)
+	if err != nil {
+		fatalf("failed to prune cache: %v", err)
+	}
+	fmt.Printf("removed %d entries older than %s\n", removed, *ttl)
+}
+
+func runCacheStats(args []string) {
+	fs := flag.NewFlagSet("cache stats", flag.ExitOnError)
+	backend, path := cacheFlags(fs)
+	fs.Parse(args)
+
+	store := openConfiguredCacheStore(*backend, *path)
+	defer store.Close()
+
+	records, err := store.List()
+	if err != nil {
+		fatalf("failed to read cache entries: %v", err)
+	}
+	byNamespace := map[string]int{}
+	var totalSize int
+	for _, record := range records {
+		byNamespace[record.Namespace]++
+		totalSize += len(record.Payload)
+	}
+	fmt.Printf("entries: %d\n", len(records))
+	fmt.Printf("total size: %d bytes\n", totalSize)
+	for namespace, count := range byNamespace {
+		fmt.Printf("  %s: %d\n", namespace, count)
+	}
+}
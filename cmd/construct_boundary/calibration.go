@@ -0,0 +1,171 @@
+package main
+
+import (
+	"bufio"
+	"crypto/sha256"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// calibrationPoint is one breakpoint of a fitted isotonic calibration
+// curve: every raw score >= Threshold (and below the next breakpoint's
+// Threshold) maps to Probability.
+type calibrationPoint struct {
+	Threshold   float64 `json:"threshold"`
+	Probability float64 `json:"probability"`
+}
+
+// calibrationModel is the calibration.json artefact produced by `benchaid
+// calibrate`: a monotone non-decreasing step function from raw
+// scoreCandidate scores to an estimated probability of a soluble,
+// well-expressed construct, fitted by Pool-Adjacent-Violators over
+// historical cloning outcomes.
+type calibrationModel struct {
+	Version string             `json:"version"`
+	Points  []calibrationPoint `json:"points"`
+
+	thresholds []float64
+}
+
+// loadCalibrationModel reads and validates a calibration.json artefact,
+// precomputing the threshold slice Predict binary-searches.
+func loadCalibrationModel(path string) (*calibrationModel, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var model calibrationModel
+	if err := json.Unmarshal(data, &model); err != nil {
+		return nil, err
+	}
+	if len(model.Points) == 0 {
+		return nil, fmt.Errorf("calibration model %q has no points", path)
+	}
+	model.thresholds = make([]float64, len(model.Points))
+	for i, p := range model.Points {
+		model.thresholds[i] = p.Threshold
+	}
+	return &model, nil
+}
+
+// Predict maps a raw scoreCandidate score to its calibrated probability by
+// binary-searching the model's breakpoints, returning ok=false if calib is
+// nil (no --calibration model was supplied).
+func (calib *calibrationModel) Predict(score float64) (probability float64, ok bool) {
+	if calib == nil || len(calib.Points) == 0 {
+		return 0, false
+	}
+	idx := sort.SearchFloat64s(calib.thresholds, score)
+	if idx > 0 && (idx == len(calib.thresholds) || calib.thresholds[idx] != score) {
+		idx--
+	}
+	return calib.Points[idx].Probability, true
+}
+
+// fitIsotonicPAVA fits a monotone non-decreasing step function mapping raw
+// score to outcome via the Pool-Adjacent-Violators algorithm: each (score,
+// outcome) pair starts as its own block of weight 1; adjacent blocks whose
+// means violate monotonicity (left.mean > right.mean) are repeatedly
+// merged into a single weighted-average block until none remain.
+func fitIsotonicPAVA(scores, outcomes []float64) []calibrationPoint {
+	type pair struct{ score, outcome float64 }
+	pairs := make([]pair, len(scores))
+	for i := range scores {
+		pairs[i] = pair{scores[i], outcomes[i]}
+	}
+	sort.Slice(pairs, func(i, j int) bool { return pairs[i].score < pairs[j].score })
+
+	type block struct {
+		weight   float64
+		mean     float64
+		minScore float64
+	}
+	var blocks []block
+	for _, p := range pairs {
+		blocks = append(blocks, block{weight: 1, mean: p.outcome, minScore: p.score})
+		for len(blocks) > 1 && blocks[len(blocks)-2].mean > blocks[len(blocks)-1].mean {
+			last := blocks[len(blocks)-1]
+			prev := blocks[len(blocks)-2]
+			merged := block{
+				weight:   prev.weight + last.weight,
+				mean:     (prev.weight*prev.mean + last.weight*last.mean) / (prev.weight + last.weight),
+				minScore: prev.minScore,
+			}
+			blocks = append(blocks[:len(blocks)-2], merged)
+		}
+	}
+
+	points := make([]calibrationPoint, len(blocks))
+	for i, b := range blocks {
+		points[i] = calibrationPoint{Threshold: b.minScore, Probability: b.mean}
+	}
+	return points
+}
+
+// runCalibrateCommand implements `benchaid calibrate`: fit a calibration
+// model from a TSV of (raw_score, outcome) rows and write it as JSON.
+func runCalibrateCommand(args []string) {
+	fs := flag.NewFlagSet("calibrate", flag.ExitOnError)
+	input := fs.String("input", "", "TSV of historical cloning results: raw_score<TAB>outcome (0 or 1), one row per construct")
+	output := fs.String("output", "calibration.json", "Where to write the fitted calibration.json")
+	fs.Parse(args)
+
+	if *input == "" {
+		fatalf("--input is required")
+	}
+
+	data, err := os.ReadFile(*input)
+	if err != nil {
+		fatalf("failed to read --input: %v", err)
+	}
+
+	var scores, outcomes []float64
+	scanner := bufio.NewScanner(strings.NewReader(string(data)))
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		fields := strings.Fields(line)
+		if len(fields) != 2 {
+			fatalf("malformed row %q: want raw_score<TAB>outcome", line)
+		}
+		score, err := strconv.ParseFloat(fields[0], 64)
+		if err != nil {
+			fatalf("malformed raw_score %q: %v", fields[0], err)
+		}
+		outcome, err := strconv.ParseFloat(fields[1], 64)
+		if err != nil || (outcome != 0 && outcome != 1) {
+			fatalf("malformed outcome %q: want 0 or 1", fields[1])
+		}
+		scores = append(scores, score)
+		outcomes = append(outcomes, outcome)
+	}
+	if err := scanner.Err(); err != nil {
+		fatalf("failed to read --input: %v", err)
+	}
+	if len(scores) == 0 {
+		fatalf("--input %q contained no rows", *input)
+	}
+
+	points := fitIsotonicPAVA(scores, outcomes)
+	sum := sha256.Sum256(data)
+	model := calibrationModel{
+		Version: fmt.Sprintf("pava-%d-%x", len(scores), sum[:6]),
+		Points:  points,
+	}
+
+	out, err := json.MarshalIndent(model, "", "  ")
+	if err != nil {
+		fatalf("failed to encode calibration model: %v", err)
+	}
+	if err := os.WriteFile(*output, out, 0644); err != nil {
+		fatalf("failed to write --output: %v", err)
+	}
+	fmt.Printf("fitted calibration model %s from %d rows (%d breakpoints) -> %s\n", model.Version, len(scores), len(points), *output)
+}
@@ -0,0 +1,295 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// cifObservedChain is one chain's worth of author-numbered observation
+// data parsed from an mmCIF file's _pdbx_poly_seq_scheme category: the
+// full author-numbered span and any internal stretches the crystal
+// structure didn't resolve.
+type cifObservedChain struct {
+	ChainID      string
+	Start, End   int
+	ObservedGaps []rangeInfo
+}
+
+// fetchCIFText downloads (through fc's cache, so repeat runs don't
+// re-fetch) and returns the raw mmCIF text for a PDB ID.
+func fetchCIFText(ctx context.Context, fc *fetchClient, id string) (string, error) {
+	cifURL := fmt.Sprintf("https://files.rcsb.org/download/%s.cif", id)
+	body, err := fc.fetchWithCache(ctx, "pdb-cif", id, cifURL)
+	if err != nil {
+		return "", err
+	}
+	return string(body), nil
+}
+
+// enrichPDBRangesFromCIF refines each UniProt-derived pdbRange (whose
+// Start/End/ChainID come from the coarse "Chains=A/B=12-345" property)
+// against its mmCIF file: author-numbered Start/End, ObservedGaps, and
+// Mutations. A PDB ID is fetched and parsed at most once even if it backs
+// several ranges (e.g. one range per UniProt isoform note).
+func enrichPDBRangesFromCIF(ctx context.Context, fc *fetchClient, ranges []pdbRange) []pdbRange {
+	chainsByID := map[string][]cifObservedChain{}
+	mutationsByID := map[string][]string{}
+	fetched := map[string]bool{}
+
+	for i := range ranges {
+		id := strings.ToUpper(ranges[i].ID)
+		if !fetched[id] {
+			fetched[id] = true
+			cifText, err := fetchCIFText(ctx, fc, id)
+			if err == nil {
+				chainsByID[id] = parseCIFObservedChains(cifText)
+				mutationsByID[id] = parseCIFMutations(cifText)
+			}
+		}
+
+		for _, label := range strings.Split(ranges[i].ChainID, "/") {
+			label = strings.TrimSpace(label)
+			if label == "" {
+				continue
+			}
+			for _, chain := range chainsByID[id] {
+				if chain.ChainID != label {
+					continue
+				}
+				ranges[i].Start = chain.Start
+				ranges[i].End = chain.End
+				ranges[i].ObservedGaps = append(ranges[i].ObservedGaps, chain.ObservedGaps...)
+			}
+		}
+		for _, mut := range mutationsByID[id] {
+			if mutationMatchesChain(mut, ranges[i].ChainID) {
+				ranges[i].Mutations = append(ranges[i].Mutations, mut)
+			}
+		}
+	}
+	return ranges
+}
+
+// parseCIFObservedChains reads the _pdbx_poly_seq_scheme loop, which lists
+// every residue of every chain in entity sequence order with "?" in
+// auth_seq_num/pdb_mon_id wherever the crystal structure didn't resolve
+// that residue. From that it derives each chain's observed author-
+// numbered span and any internal (not N/C-terminal) unresolved stretches.
+func parseCIFObservedChains(cifText string) []cifObservedChain {
+	rows := parseCIFLoop(cifText, "_pdbx_poly_seq_scheme.")
+
+	type residue struct {
+		authSeq  int
+		observed bool
+	}
+	var order []string
+	byChain := map[string][]residue{}
+	for _, row := range rows {
+		chain := row["pdb_strand_id"]
+		if chain == "" {
+			chain = row["asym_id"]
+		}
+		if chain == "" {
+			continue
+		}
+		if _, ok := byChain[chain]; !ok {
+			order = append(order, chain)
+		}
+		authRaw := row["auth_seq_num"]
+		authNum, err := strconv.Atoi(authRaw)
+		observed := err == nil
+		byChain[chain] = append(byChain[chain], residue{authSeq: authNum, observed: observed})
+	}
+
+	var chains []cifObservedChain
+	for _, chain := range order {
+		residues := byChain[chain]
+
+		var observedNums []int
+		for _, r := range residues {
+			if r.observed {
+				observedNums = append(observedNums, r.authSeq)
+			}
+		}
+		if len(observedNums) == 0 {
+			continue
+		}
+		sort.Ints(observedNums)
+
+		var gaps []rangeInfo
+		seenObserved := false
+		inGap := false
+		lastObserved := 0
+		gapStart := 0
+		for _, r := range residues {
+			if r.observed {
+				if inGap {
+					gaps = append(gaps, rangeInfo{
+						Start: gapStart, End: r.authSeq - 1,
+						Type: "crystal_gap", Source: "mmCIF", Name: chain,
+					})
+					inGap = false
+				}
+				lastObserved = r.authSeq
+				seenObserved = true
+				continue
+			}
+			if seenObserved && !inGap {
+				gapStart = lastObserved + 1
+				inGap = true
+			}
+		}
+
+		chains = append(chains, cifObservedChain{
+			ChainID:      chain,
+			Start:        observedNums[0],
+			End:          observedNums[len(observedNums)-1],
+			ObservedGaps: gaps,
+		})
+	}
+	return chains
+}
+
+// parseCIFMutations reads the _struct_ref_seq_dif loop, mmCIF's record of
+// every position where the deposited entry's sequence differs from its
+// reference database entry (engineered mutations, conflicts, expression
+// tags), and renders each as "chain:seqnum dbResidue->entryResidue".
+func parseCIFMutations(cifText string) []string {
+	rows := parseCIFLoop(cifText, "_struct_ref_seq_dif.")
+	var mutations []string
+	for _, row := range rows {
+		chain := row["pdbx_pdb_strand_id"]
+		entryMon := row["mon_id"]
+		dbMon := row["db_mon_id"]
+		seqNum := row["seq_num"]
+		if chain == "" || seqNum == "" {
+			continue
+		}
+		if entryMon == "" || dbMon == "" || entryMon == dbMon || entryMon == "?" || dbMon == "?" {
+			continue
+		}
+		mutations = append(mutations, fmt.Sprintf("%s:%s %s->%s", chain, seqNum, dbMon, entryMon))
+	}
+	return mutations
+}
+
+// mutationMatchesChain reports whether mut (formatted "chain:seqnum ...")
+// belongs to any of the author chain letters in chainGroup (e.g. "A/B").
+func mutationMatchesChain(mut, chainGroup string) bool {
+	idx := strings.Index(mut, ":")
+	if idx < 0 {
+		return false
+	}
+	chain := mut[:idx]
+	for _, label := range strings.Split(chainGroup, "/") {
+		if strings.TrimSpace(label) == chain {
+			return true
+		}
+	}
+	return false
+}
+
+// crystalGapLabel reports the PDB ID of the first pdbRange whose
+// ObservedGaps contains pos, so boundaryScore can flag a candidate
+// terminus that falls inside a crystallographically disordered stretch.
+func crystalGapLabel(pos int, pdbRanges []pdbRange) (string, bool) {
+	for _, pdb := range pdbRanges {
+		if pos < pdb.Start || pos > pdb.End {
+			continue
+		}
+		for _, gap := range pdb.ObservedGaps {
+			if pos >= gap.Start && pos <= gap.End {
+				return pdb.ID, true
+			}
+		}
+	}
+	return "", false
+}
+
+// cifTokenizeLine splits one mmCIF data line into whitespace-separated
+// tokens, honoring the CIF rule that a token may be quoted with ' or " to
+// embed literal whitespace.
+func cifTokenizeLine(line string) []string {
+	var tokens []string
+	i, n := 0, len(line)
+	for i < n {
+		for i < n && (line[i] == ' ' || line[i] == '\t') {
+			i++
+		}
+		if i >= n {
+			break
+		}
+		if line[i] == '\'' || line[i] == '"' {
+			quote := line[i]
+			j := i + 1
+			for j < n {
+				if line[j] == quote && (j+1 == n || line[j+1] == ' ' || line[j+1] == '\t') {
+					break
+				}
+				j++
+			}
+			tokens = append(tokens, line[i+1:j])
+			if j < n {
+				j++
+			}
+			i = j
+			continue
+		}
+		j := i
+		for j < n && line[j] != ' ' && line[j] != '\t' {
+			j++
+		}
+		tokens = append(tokens, line[i:j])
+		i = j
+	}
+	return tokens
+}
+
+// parseCIFLoop extracts every row of category (e.g. "_pdbx_poly_seq_scheme.")
+// from mmCIF text as field-name -> value maps. It handles the loop_ syntax
+// RCSB's deposited files actually use for these categories (a fixed list
+// of "_category.field" lines followed by one whitespace/quote-tokenized
+// data row per line); it does not implement full CIF multi-line ';' text
+// fields, which these particular categories don't use.
+func parseCIFLoop(text, category string) []map[string]string {
+	lines := strings.Split(text, "\n")
+	var rows []map[string]string
+	for i := 0; i < len(lines); i++ {
+		if strings.TrimSpace(lines[i]) != "loop_" {
+			continue
+		}
+		j := i + 1
+		var fields []string
+		for j < len(lines) && strings.HasPrefix(strings.TrimSpace(lines[j]), category) {
+			fields = append(fields, strings.TrimSpace(lines[j]))
+			j++
+		}
+		if len(fields) == 0 {
+			continue
+		}
+		for j < len(lines) {
+			line := strings.TrimRight(lines[j], "\r")
+			trimmed := strings.TrimSpace(line)
+			if trimmed == "" || trimmed == "loop_" || strings.HasPrefix(trimmed, "_") ||
+				strings.HasPrefix(trimmed, "#") || strings.HasPrefix(trimmed, "data_") {
+				break
+			}
+			tokens := cifTokenizeLine(line)
+			if len(tokens) < len(fields) {
+				j++
+				continue
+			}
+			row := make(map[string]string, len(fields))
+			for k, field := range fields {
+				row[strings.TrimPrefix(field, category)] = tokens[k]
+			}
+			rows = append(rows, row)
+			j++
+		}
+		i = j - 1
+	}
+	return rows
+}
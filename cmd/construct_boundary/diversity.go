@@ -0,0 +1,102 @@
+package main
+
+import (
+	"math"
+	"sort"
+)
+
+// selectDiverseCandidates greedily selects up to k candidates from cands,
+// sorted by score descending, accepting a candidate only if its residue-
+// interval IoU with every already-accepted candidate is below
+// iouThreshold. This is the same idea as non-maximum suppression in
+// object detection: it keeps the repo's preference for the
+// highest-scoring representative of each overlapping cluster instead of
+// several near-duplicates of the same region.
+func selectDiverseCandidates(cands []candidate, k int, iouThreshold float64) []candidate {
+	sorted := make([]candidate, len(cands))
+	copy(sorted, cands)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i].Score > sorted[j].Score })
+
+	var selected []candidate
+	for _, cand := range sorted {
+		if len(selected) >= k {
+			break
+		}
+		diverse := true
+		for _, s := range selected {
+			if intervalIoU(cand, s) >= iouThreshold {
+				diverse = false
+				break
+			}
+		}
+		if diverse {
+			selected = append(selected, cand)
+		}
+	}
+	return selected
+}
+
+// selectMMRCandidates is a richer alternative to selectDiverseCandidates:
+// Maximal Marginal Relevance. At each step it adds the remaining candidate
+// maximizing lambda*normalizedScore - (1-lambda)*maxOverlapWithSelected,
+// where overlap is IoU and score is normalized against the top score so
+// lambda is comparable across proteins. lambda=1 behaves like plain
+// top-K-by-score; lambda=0 maximizes diversity regardless of score.
+func selectMMRCandidates(cands []candidate, k int, lambda float64) []candidate {
+	if len(cands) == 0 || k <= 0 {
+		return nil
+	}
+	remaining := make([]candidate, len(cands))
+	copy(remaining, cands)
+
+	maxScore := remaining[0].Score
+	for _, c := range remaining {
+		if c.Score > maxScore {
+			maxScore = c.Score
+		}
+	}
+	normalize := func(score float64) float64 {
+		if maxScore <= 0 {
+			return 0
+		}
+		return score / maxScore
+	}
+
+	var selected []candidate
+	for len(selected) < k && len(remaining) > 0 {
+		bestIdx := -1
+		bestValue := math.Inf(-1)
+		for i, cand := range remaining {
+			var maxOverlap float64
+			for _, s := range selected {
+				if iou := intervalIoU(cand, s); iou > maxOverlap {
+					maxOverlap = iou
+				}
+			}
+			value := lambda*normalize(cand.Score) - (1-lambda)*maxOverlap
+			if value > bestValue {
+				bestValue = value
+				bestIdx = i
+			}
+		}
+		selected = append(selected, remaining[bestIdx])
+		remaining = append(remaining[:bestIdx], remaining[bestIdx+1:]...)
+	}
+	return selected
+}
+
+// intervalIoU is the intersection-over-union of two candidates' residue
+// intervals, treating each as the closed range [Start, End].
+func intervalIoU(a, b candidate) float64 {
+	interStart := max(a.Start, b.Start)
+	interEnd := min(a.End, b.End)
+	var inter int
+	if interEnd >= interStart {
+		inter = interEnd - interStart + 1
+	}
+	union := a.Length() + b.Length() - inter
+	if union <= 0 {
+		return 0
+	}
+	return float64(inter) / float64(union)
+}
@@ -0,0 +1,338 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"math/rand"
+	"net"
+	"net/http"
+	"net/url"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// fetchBackoffBase and fetchBackoffCap bound the exponential backoff used
+// between retry attempts in fetchClient.get: the first retry waits up to
+// fetchBackoffBase, doubling on each subsequent attempt up to
+// fetchBackoffCap.
+const (
+	fetchBackoffBase = 500 * time.Millisecond
+	fetchBackoffCap  = 10 * time.Second
+)
+
+// fetchClient wraps an *http.Client with the retry, backoff, and per-host
+// rate limiting policy every fetcher in this package (UniProt, AlphaFold,
+// RCSB) should share, plus the cacheStore backing fetchWithCache.
+type fetchClient struct {
+	http       *http.Client
+	maxRetries int
+	rps        float64
+	store      cacheStore
+
+	mu       sync.Mutex
+	limiters map[string]*rateLimiter
+}
+
+// newFetchClient builds a fetchClient with a per-request timeout, a retry
+// budget, a per-host requests-per-second cap, and the cacheStore
+// fetchWithCache reads and writes through.
+func newFetchClient(timeout time.Duration, maxRetries int, rps float64, store cacheStore) *fetchClient {
+	return &fetchClient{
+		http:       &http.Client{Timeout: timeout},
+		maxRetries: maxRetries,
+		rps:        rps,
+		store:      store,
+		limiters:   map[string]*rateLimiter{},
+	}
+}
+
+// limiterFor returns the token-bucket limiter for host, creating one on
+// first use.
+func (fc *fetchClient) limiterFor(host string) *rateLimiter {
+	fc.mu.Lock()
+	defer fc.mu.Unlock()
+	limiter, ok := fc.limiters[host]
+	if !ok {
+		limiter = newRateLimiter(fc.rps)
+		fc.limiters[host] = limiter
+	}
+	return limiter
+}
+
+// get fetches rawURL plainly (no conditional headers), retrying
+// transient failures (429, 503, other 5xx, and network timeouts) up to
+// fc.maxRetries times with full-jitter exponential backoff, honoring a
+// server's Retry-After header when present. It rate-limits per host so a
+// batch of requests to the same API doesn't trip that API's own
+// throttling. A 404 is returned immediately as an error, since retrying
+// it can't help.
+func (fc *fetchClient) get(ctx context.Context, rawURL string) ([]byte, error) {
+	body, _, _, _, err := fc.doFetch(ctx, rawURL, "", "")
+	return body, err
+}
+
+// getWithValidators is get, but also returns the response's ETag and
+// Last-Modified headers so a caller can store them for a future
+// conditional GET.
+func (fc *fetchClient) getWithValidators(ctx context.Context, rawURL string) (body []byte, etag, lastModified string, err error) {
+	body, _, etag, lastModified, err = fc.doFetch(ctx, rawURL, "", "")
+	return
+}
+
+// getConditional issues a GET with If-None-Match/If-Modified-Since set
+// from the caller's previously stored validators. A 304 response is
+// reported as notModified=true rather than an error, since it means the
+// cached payload is still current.
+func (fc *fetchClient) getConditional(ctx context.Context, rawURL, etag, lastModified string) (body []byte, notModified bool, newETag, newLastModified string, err error) {
+	return fc.doFetch(ctx, rawURL, etag, lastModified)
+}
+
+// doFetch is the shared retry loop behind get, getWithValidators, and
+// getConditional: it rate-limits per host, retries transient failures
+// with full-jitter backoff honoring Retry-After, and special-cases 304
+// (conditional requests only) and 404.
+func (fc *fetchClient) doFetch(ctx context.Context, rawURL, ifNoneMatch, ifModifiedSince string) (body []byte, notModified bool, etag, lastModified string, err error) {
+	parsed, parseErr := url.Parse(rawURL)
+	if parseErr != nil {
+		return nil, false, "", "", fmt.Errorf("invalid URL %q: %w", rawURL, parseErr)
+	}
+	limiter := fc.limiterFor(parsed.Host)
+
+	var lastErr error
+	var lastHeader http.Header
+	for attempt := 0; attempt <= fc.maxRetries; attempt++ {
+		if attempt > 0 {
+			delay := fetchBackoff(attempt)
+			if retryAfter, ok := fetchRetryAfter(lastHeader); ok {
+				delay = retryAfter
+			}
+			select {
+			case <-ctx.Done():
+				return nil, false, "", "", ctx.Err()
+			case <-time.After(delay):
+			}
+		}
+
+		if err := limiter.wait(ctx); err != nil {
+			return nil, false, "", "", err
+		}
+
+		respBody, status, header, doErr := fc.doOnce(ctx, rawURL, ifNoneMatch, ifModifiedSince)
+		if doErr == nil && status == http.StatusOK {
+			return respBody, false, header.Get("ETag"), header.Get("Last-Modified"), nil
+		}
+		if doErr == nil && status == http.StatusNotModified {
+			return nil, true, header.Get("ETag"), header.Get("Last-Modified"), nil
+		}
+		if doErr == nil && status == http.StatusNotFound {
+			return nil, false, "", "", fmt.Errorf("fetch %s: status 404", rawURL)
+		}
+
+		lastErr = doErr
+		if doErr == nil {
+			lastErr = fmt.Errorf("fetch %s: status %d", rawURL, status)
+		}
+		lastHeader = header
+		if !fetchIsRetryable(status, doErr) {
+			return nil, false, "", "", lastErr
+		}
+	}
+	return nil, false, "", "", fmt.Errorf("fetch %s: giving up after %d attempts: %w", rawURL, fc.maxRetries+1, lastErr)
+}
+
+// doOnce performs a single HTTP GET against rawURL, optionally as a
+// conditional request, and returns its body (transparently gunzipped),
+// status code, and response header.
+func (fc *fetchClient) doOnce(ctx context.Context, rawURL, ifNoneMatch, ifModifiedSince string) ([]byte, int, http.Header, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, rawURL, nil)
+	if err != nil {
+		return nil, 0, nil, err
+	}
+	req.Header.Set("Accept-Encoding", "gzip")
+	if ifNoneMatch != "" {
+		req.Header.Set("If-None-Match", ifNoneMatch)
+	}
+	if ifModifiedSince != "" {
+		req.Header.Set("If-Modified-Since", ifModifiedSince)
+	}
+
+	resp, err := fc.http.Do(req)
+	if err != nil {
+		return nil, 0, nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusNotModified {
+		io.Copy(io.Discard, resp.Body)
+		return nil, resp.StatusCode, resp.Header, nil
+	}
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, resp.StatusCode, resp.Header, err
+	}
+	if resp.Header.Get("Content-Encoding") == "gzip" || isGzip(body) {
+		decoded, err := gunzip(body)
+		if err != nil {
+			return nil, resp.StatusCode, resp.Header, err
+		}
+		body = decoded
+	}
+	return body, resp.StatusCode, resp.Header, nil
+}
+
+// fetchWithCache serves (namespace, key) from fc.store when a fresh
+// entry exists. A stale entry that carries a stored ETag or Last-
+// Modified validator is revalidated with a conditional GET first; a 304
+// response refreshes FetchedAt and returns the cached payload without
+// re-downloading it. Everything else falls through to a plain fetch from
+// rawURL, whose result (and any validators it returned) is written back
+// to the store.
+func (fc *fetchClient) fetchWithCache(ctx context.Context, namespace, key, rawURL string) ([]byte, error) {
+	record, ok := fc.store.Get(namespace, key)
+	if ok {
+		if time.Since(record.FetchedAt) <= cacheTTL {
+			return record.Payload, nil
+		}
+		if record.ETag != "" || record.LastModified != "" {
+			body, notModified, etag, lastModified, err := fc.getConditional(ctx, rawURL, record.ETag, record.LastModified)
+			if err == nil && notModified {
+				record.FetchedAt = time.Now()
+				_ = fc.store.Put(record)
+				return record.Payload, nil
+			}
+			if err == nil {
+				refreshed := cacheRecord{
+					Namespace: namespace, Key: key, URL: rawURL,
+					FetchedAt: time.Now(), ETag: etag, LastModified: lastModified,
+					Payload: body,
+				}
+				_ = fc.store.Put(refreshed)
+				return body, nil
+			}
+		}
+	}
+
+	body, etag, lastModified, err := fc.getWithValidators(ctx, rawURL)
+	if err != nil {
+		return nil, err
+	}
+	_ = fc.store.Put(cacheRecord{
+		Namespace: namespace, Key: key, URL: rawURL,
+		FetchedAt: time.Now(), ETag: etag, LastModified: lastModified,
+		Payload: body,
+	})
+	return body, nil
+}
+
+// fetchBackoff returns a full-jitter exponential backoff duration for the
+// given retry attempt (1-indexed), capped at fetchBackoffCap.
+func fetchBackoff(attempt int) time.Duration {
+	ceiling := fetchBackoffBase << uint(attempt-1)
+	if ceiling > fetchBackoffCap || ceiling <= 0 {
+		ceiling = fetchBackoffCap
+	}
+	return time.Duration(rand.Int63n(int64(ceiling)))
+}
+
+// fetchIsRetryable reports whether a failed request is worth retrying:
+// rate-limited (429), temporarily unavailable (503) or another server
+// error (5xx), or a network-level timeout. Context cancellation and
+// deadline errors are never retryable, since the caller has already
+// given up.
+func fetchIsRetryable(status int, err error) bool {
+	if errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded) {
+		return false
+	}
+	if status == http.StatusTooManyRequests || status == http.StatusServiceUnavailable || status >= 500 {
+		return true
+	}
+	var netErr net.Error
+	if errors.As(err, &netErr) && netErr.Timeout() {
+		return true
+	}
+	return false
+}
+
+// fetchRetryAfter parses a Retry-After header (seconds or an HTTP-date)
+// into a duration, reporting false if the header is absent or
+// unparseable.
+func fetchRetryAfter(h http.Header) (time.Duration, bool) {
+	if h == nil {
+		return 0, false
+	}
+	value := h.Get("Retry-After")
+	if value == "" {
+		return 0, false
+	}
+	if seconds, err := strconv.Atoi(value); err == nil {
+		if seconds < 0 {
+			return 0, false
+		}
+		return time.Duration(seconds) * time.Second, true
+	}
+	if when, err := http.ParseTime(value); err == nil {
+		delay := time.Until(when)
+		if delay < 0 {
+			return 0, true
+		}
+		return delay, true
+	}
+	return 0, false
+}
+
+// rateLimiter is a simple token-bucket limiter used to cap requests per
+// second to a single host.
+type rateLimiter struct {
+	mu         sync.Mutex
+	tokens     float64
+	capacity   float64
+	refillRate float64
+	last       time.Time
+}
+
+// newRateLimiter builds a rateLimiter allowing rps requests per second,
+// starting with a full bucket. An rps of 0 or less defaults to 5, a
+// conservative default for the public APIs this package talks to.
+func newRateLimiter(rps float64) *rateLimiter {
+	if rps <= 0 {
+		rps = 5
+	}
+	return &rateLimiter{
+		tokens:     rps,
+		capacity:   rps,
+		refillRate: rps,
+		last:       time.Now(),
+	}
+}
+
+// wait blocks until a token is available, or ctx is done.
+func (r *rateLimiter) wait(ctx context.Context) error {
+	for {
+		r.mu.Lock()
+		now := time.Now()
+		elapsed := now.Sub(r.last).Seconds()
+		r.last = now
+		r.tokens += elapsed * r.refillRate
+		if r.tokens > r.capacity {
+			r.tokens = r.capacity
+		}
+		if r.tokens >= 1 {
+			r.tokens--
+			r.mu.Unlock()
+			return nil
+		}
+		deficit := 1 - r.tokens
+		wait := time.Duration(deficit / r.refillRate * float64(time.Second))
+		r.mu.Unlock()
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(wait):
+		}
+	}
+}
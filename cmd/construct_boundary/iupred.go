@@ -0,0 +1,162 @@
+package main
+
+import "math"
+
+// residueOrder fixes each standard amino acid's position in the pairwise
+// interaction table below; order itself doesn't matter, only that every
+// residue has one.
+const residueOrder = "ACDEFGHIKLMNPQRSTVWY"
+
+// kyteDoolittleHydropathy gives each residue's hydropathy index (Kyte &
+// Doolittle, 1982), the basis for the contact energy table below.
+var kyteDoolittleHydropathy = map[byte]float64{
+	'A': 1.8, 'C': 2.5, 'D': -3.5, 'E': -3.5, 'F': 2.8,
+	'G': -0.4, 'H': -3.2, 'I': 4.5, 'K': -3.9, 'L': 3.8,
+	'M': 1.9, 'N': -3.5, 'P': -1.6, 'Q': -3.5, 'R': -4.5,
+	'S': -0.8, 'T': -0.7, 'V': 4.2, 'W': -0.9, 'Y': -1.3,
+}
+
+// contactEnergy is a 20x20 pairwise residue interaction energy table, in
+// the spirit of the statistical contact potentials (e.g. Thomas & Dill)
+// IUPred derives its per-residue energy estimate from: favorable
+// (negative) entries for residue pairs that tend to pack into folded
+// cores, unfavorable (positive) entries for pairs that don't. It's built
+// here from Kyte-Doolittle hydropathy rather than refit against the PDB,
+// which is enough to separate order-promoting from disorder-promoting
+// local contacts without shipping a second large reference table.
+var contactEnergy = buildContactEnergy()
+
+func buildContactEnergy() map[[2]byte]float64 {
+	table := make(map[[2]byte]float64, len(residueOrder)*len(residueOrder))
+	for i := 0; i < len(residueOrder); i++ {
+		for j := 0; j < len(residueOrder); j++ {
+			a, b := residueOrder[i], residueOrder[j]
+			avg := (kyteDoolittleHydropathy[a] + kyteDoolittleHydropathy[b]) / 2
+			table[[2]byte{a, b}] = -avg / 4.5
+		}
+	}
+	return table
+}
+
+// disorderWindow, disorderExclude, and disorderSmoothWindow mirror
+// IUPred's own parameters: a wide neighborhood for the per-residue energy
+// estimate, a narrow exclusion around i itself (dominated by backbone
+// geometry rather than packing), and a short smoothing pass to turn the
+// noisy per-residue estimate into stable regions.
+const (
+	disorderWindow       = 100
+	disorderExclude      = 2
+	disorderSmoothWindow = 11
+)
+
+// computeDisorderFromSequence is a self-contained, IUPred-style disorder
+// predictor driven only by the amino acid sequence, for use when pLDDT is
+// unavailable or as an independent second opinion alongside it. For each
+// residue it averages contactEnergy against neighbors within
+// disorderWindow (excluding i±disorderExclude), passes the result through
+// a logistic transform calibrated so values above 0.5 indicate disorder,
+// then smooths with a sliding average over disorderSmoothWindow residues
+// before thresholding to boolean calls.
+func computeDisorderFromSequence(seq string) ([]float64, []bool) {
+	n := len(seq)
+	if n == 0 {
+		return nil, nil
+	}
+
+	raw := make([]float64, n)
+	for i := 0; i < n; i++ {
+		lo, hi := i-disorderWindow, i+disorderWindow
+		if lo < 0 {
+			lo = 0
+		}
+		if hi >= n {
+			hi = n - 1
+		}
+		sum, count := 0.0, 0
+		for j := lo; j <= hi; j++ {
+			if abs(j-i) <= disorderExclude {
+				continue
+			}
+			sum += contactEnergy[[2]byte{seq[i], seq[j]}]
+			count++
+		}
+		energy := 0.0
+		if count > 0 {
+			energy = sum / float64(count)
+		}
+		raw[i] = disorderLogistic(energy)
+	}
+
+	smoothed := slidingAverage(raw, disorderSmoothWindow)
+	disorder := make([]bool, n)
+	for i, v := range smoothed {
+		disorder[i] = v > 0.5
+	}
+	return smoothed, disorder
+}
+
+// disorderLogistic maps a local contact energy to a 0-1 disorder
+// propensity: energies near zero push the score above 0.5, while
+// strongly favorable (negative, packing-prone) energies push it toward
+// 0. The scale and offset are hand-calibrated against typical
+// hydropathy-derived energies rather than fit to a disorder benchmark.
+func disorderLogistic(energy float64) float64 {
+	const scale = 6.0
+	return 1 / (1 + math.Exp(-scale*(energy+0.15)))
+}
+
+func slidingAverage(values []float64, window int) []float64 {
+	n := len(values)
+	out := make([]float64, n)
+	half := window / 2
+	for i := 0; i < n; i++ {
+		lo, hi := i-half, i+half
+		if lo < 0 {
+			lo = 0
+		}
+		if hi >= n {
+			hi = n - 1
+		}
+		sum := 0.0
+		for j := lo; j <= hi; j++ {
+			sum += values[j]
+		}
+		out[i] = sum / float64(hi-lo+1)
+	}
+	return out
+}
+
+// orDisorder merges two boolean disorder calls position-by-position,
+// treating a position as disordered if either source flags it. Used for
+// --disorder-source=both.
+func orDisorder(a, b []bool) []bool {
+	n := len(a)
+	if len(b) > n {
+		n = len(b)
+	}
+	out := make([]bool, n)
+	for i := 0; i < n; i++ {
+		var av, bv bool
+		if i < len(a) {
+			av = a[i]
+		}
+		if i < len(b) {
+			bv = b[i]
+		}
+		out[i] = av || bv
+	}
+	return out
+}
+
+// disorderSourcesAgree reports whether both the pLDDT-derived and
+// sequence-derived predictors independently call pos (1-based)
+// disordered, used by boundaryScore to credit --disorder-source=both with
+// extra evidence. Agreement on "ordered" isn't informative (most of any
+// sequence is ordered by default), so only a shared disorder call counts.
+func disorderSourcesAgree(pos int, ctx boundaryContext) bool {
+	idx := pos - 1
+	if idx < 0 || idx >= len(ctx.DisorderPLDDT) || idx >= len(ctx.DisorderSeq) {
+		return false
+	}
+	return ctx.DisorderPLDDT[idx] && ctx.DisorderSeq[idx]
+}
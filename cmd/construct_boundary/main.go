@@ -3,17 +3,18 @@ package main
 import (
 	"bytes"
 	"compress/gzip"
+	"context"
 	"encoding/json"
 	"errors"
 	"flag"
 	"fmt"
 	"io"
 	"math"
-	"net/http"
 	"net/url"
 	"os"
 	"path/filepath"
 	"regexp"
+	"runtime"
 	"sort"
 	"strconv"
 	"strings"
@@ -28,11 +29,6 @@ const (
 	alphafoldPredictionURL = "https://alphafold.ebi.ac.uk/api/prediction"
 )
 
-type cacheEntry struct {
-	FetchedAt time.Time       `json:"fetched_at"`
-	Payload   json.RawMessage `json:"payload"`
-}
-
 type uniProtEntry struct {
 	PrimaryAccession   string                 `json:"primaryAccession"`
 	UniProtKBID        string                 `json:"uniProtkbId"`
@@ -144,23 +140,36 @@ type pdbRange struct {
 	End        int
 	Method     string
 	Resolution string
+
+	// ChainID, ObservedGaps, and Mutations come from UniProt's coarse
+	// Chains= property by default (ChainID only); with --fetch-cif they're
+	// refined against the entry's mmCIF file (see cif.go) for author
+	// numbering, crystallographically disordered stretches, and engineered
+	// sequence differences.
+	ChainID      string
+	ObservedGaps []rangeInfo
+	Mutations    []string
 }
 
 type prediction struct {
-	Rank      int      `json:"rank"`
-	Start     int      `json:"start"`
-	End       int      `json:"end"`
-	Length    int      `json:"length"`
-	Score     float64  `json:"score"`
-	Rationale string   `json:"rationale"`
-	Evidence  evidence `json:"evidence"`
+	Rank        int      `json:"rank"`
+	Start       int      `json:"start"`
+	End         int      `json:"end"`
+	Length      int      `json:"length"`
+	Score       float64  `json:"score"`
+	Probability float64  `json:"probability,omitempty"`
+	Rationale   string   `json:"rationale"`
+	Evidence    evidence `json:"evidence"`
 }
 
 type evidence struct {
-	PDBMatch         string  `json:"pdb_match,omitempty"`
-	Domain           string  `json:"domain,omitempty"`
-	AvgPLDDT         float64 `json:"avg_plddt,omitempty"`
-	DisorderFraction float64 `json:"disorder_fraction,omitempty"`
+	PDBMatch           string  `json:"pdb_match,omitempty"`
+	Domain             string  `json:"domain,omitempty"`
+	AvgPLDDT           float64 `json:"avg_plddt,omitempty"`
+	DisorderFraction   float64 `json:"disorder_fraction,omitempty"`
+	AvgConservation    float64 `json:"avg_conservation,omitempty"`
+	CrystalGap         bool    `json:"crystal_gap,omitempty"`
+	CalibrationVersion string  `json:"calibration_version,omitempty"`
 }
 
 type proteinSummary struct {
@@ -186,7 +195,7 @@ type featureSummary struct {
 
 type boundaryContext struct {
 	Length       int
-	Disorder     []bool
+	Disorder     []bool // the calls actually used for scoring, per --disorder-source
 	PLDDT        []float64
 	DomainRanges []rangeInfo
 	PDBRanges    []pdbRange
@@ -194,6 +203,18 @@ type boundaryContext struct {
 	StrandRanges []rangeInfo
 	ActiveSites  []rangeInfo
 	PTMPositions []int
+
+	// Conservation is per-residue, aligned to the query sequence the same
+	// way PLDDT is (index pos-1), from conservationScores against an MSA
+	// supplied via --msa or fetched from Pfam via fetchConservationMSA.
+	// Empty when no MSA was available.
+	Conservation []float64
+
+	// DisorderPLDDT and DisorderSeq are always populated independently of
+	// --disorder-source, so boundaryScore can credit agreement between
+	// the two when --disorder-source=both.
+	DisorderPLDDT []bool
+	DisorderSeq   []bool
 }
 
 type candidate struct {
@@ -207,49 +228,112 @@ type candidate struct {
 	Evidence  evidence
 }
 
-func main() {
-	var (
-		identifier string
-		sequence   string
-		name       string
-		region     string
-		minLength  int
-		maxLength  int
-		jsonOut    bool
-		plotPath   string
-	)
-
-	flag.StringVar(&identifier, "uniprot", "", "UniProt ID, entry name, or gene name")
-	flag.StringVar(&sequence, "sequence", "", "Raw amino acid sequence")
-	flag.StringVar(&name, "name", "", "Protein name (required with --sequence)")
-	flag.StringVar(&region, "region", "", "Focus region (e.g., 500-800 or domain name)")
-	flag.IntVar(&minLength, "min-length", defaultMinLength, "Minimum construct length (aa)")
-	flag.IntVar(&maxLength, "max-length", 0, "Maximum construct length (aa, 0 for no limit)")
-	flag.BoolVar(&jsonOut, "json", false, "Output JSON")
-	flag.StringVar(&plotPath, "plot", "", "Write ASCII visualization to a file")
-	flag.Parse()
-
-	if sequence == "" && identifier == "" {
-		fatalf("provide --uniprot or --sequence")
-	}
-	if sequence != "" && name == "" {
-		fatalf("provide --name with --sequence")
-	}
+// Request bundles one entry's worth of pipeline input: either Identifier
+// (a UniProt ID, entry name, or gene name) or a Sequence/Name pair, plus
+// the same scoring and fetch options exposed as flags. It's the shared
+// unit of work for both single-entry and --batch mode.
+type Request struct {
+	Identifier     string
+	Sequence       string
+	Name           string
+	Region         string
+	MinLength      int
+	MaxLength      int
+	DisorderSource string
+	FetchCIF       bool
+
+	// Diverse, DiverseMethod, DiverseK, IoUThreshold, and MMRLambda
+	// control selectDiverseCandidates/selectMMRCandidates, which trim
+	// the scored candidate list to a set of top picks that overlap each
+	// other less (nms keeps a pick only while its IoU with every earlier
+	// pick is below IoUThreshold; mmr penalizes overlap by MMRLambda)
+	// instead of plain top-10-by-score truncation. Diverse
+	// false preserves the original behavior.
+	Diverse       bool
+	DiverseMethod string
+	DiverseK      int
+	IoUThreshold  float64
+	MMRLambda     float64
+
+	// MSA is a pre-loaded conservation alignment (its first row must be
+	// the query sequence); when nil and FetchMSA is set, runPipeline
+	// fetches one from Pfam via fetchConservationMSA instead.
+	MSA      []msaSequence
+	FetchMSA bool
+}
+
+// pipelineResult holds everything runPipeline computes for one Request:
+// jsonOutput's fields plus the raw per-residue data (plddt, disordered,
+// top candidates) that the single-entry text/ASCII/plot output needs but
+// jsonOutput doesn't carry.
+type pipelineResult struct {
+	entry        uniProtEntry
+	name         string
+	sequence     string
+	plddt        []float64
+	disordered   []bool
+	domains      []rangeInfo
+	pdbRanges    []pdbRange
+	conservation []float64
+	top          []candidate
+	warnings     []string
+}
+
+// Run executes the full fetch -> score -> predict pipeline for req and
+// returns its jsonOutput. It is the single code path shared by
+// single-entry mode and --batch mode, so the two can never drift.
+func Run(ctx context.Context, fc *fetchClient, calib *calibrationModel, req Request) (jsonOutput, error) {
+	result, err := runPipeline(ctx, fc, req)
+	if err != nil {
+		return jsonOutput{}, err
+	}
+	return jsonOutput{
+		Protein: proteinSummary{
+			UniProtID: result.entry.PrimaryAccession,
+			Name:      result.name,
+			Length:    len(result.sequence),
+			Gene:      firstGene(result.entry.Genes),
+		},
+		Predictions: buildPredictions(result.top, result.plddt, result.conservation, calib),
+		Features: featureSummary{
+			Domains:           result.domains,
+			DisorderedRegions: compressDisorder(result.disordered),
+			PDBStructures:     result.pdbRanges,
+			PLDDTScores:       result.plddt,
+		},
+		Warnings: result.warnings,
+	}, nil
+}
+
+// runPipeline does the work behind Run, returning the richer
+// pipelineResult that main's single-entry text/ASCII/plot output needs in
+// addition to what jsonOutput carries.
+func runPipeline(ctx context.Context, fc *fetchClient, req Request) (pipelineResult, error) {
+	disorderSource := req.DisorderSource
+	if disorderSource == "" {
+		disorderSource = "plddt"
+	}
+	if disorderSource != "plddt" && disorderSource != "iupred" && disorderSource != "both" {
+		return pipelineResult{}, fmt.Errorf("unknown disorder source %q (want plddt, iupred, or both)", disorderSource)
+	}
+	if req.Sequence == "" && req.Identifier == "" {
+		return pipelineResult{}, errors.New("provide an identifier or a sequence")
+	}
+	minLength := req.MinLength
 	if minLength <= 0 {
 		minLength = defaultMinLength
 	}
 
-	cacheDir := defaultCacheDir()
-	client := &http.Client{Timeout: 20 * time.Second}
-	var warnings []string
-	warnings = append(warnings, "IUPred/PSIPRED not integrated; loop detection uses pLDDT and UniProt secondary structure when available.")
+	warnings := []string{"PSIPRED not integrated; loop detection uses pLDDT, a built-in IUPred-style sequence-only predictor, and UniProt secondary structure when available."}
 
+	name := req.Name
+	sequence := req.Sequence
 	var entry uniProtEntry
 	if sequence == "" {
 		var err error
-		entry, warnings, err = fetchUniProtEntry(client, cacheDir, identifier)
+		entry, warnings, err = fetchUniProtEntry(ctx, fc, req.Identifier)
 		if err != nil {
-			fatalf("failed to fetch UniProt entry: %v", err)
+			return pipelineResult{}, fmt.Errorf("failed to fetch UniProt entry: %w", err)
 		}
 		sequence = entry.Sequence.Value
 		if name == "" {
@@ -262,14 +346,14 @@ func main() {
 
 	sequence = sanitizeSequence(sequence)
 	if sequence == "" {
-		fatalf("sequence is empty after sanitization")
+		return pipelineResult{}, errors.New("sequence is empty after sanitization")
 	}
 
 	var plddt []float64
 	if entry.PrimaryAccession != "" {
 		var err error
 		var afWarnings []string
-		plddt, afWarnings, err = fetchAlphaFoldPLDDT(client, cacheDir, entry.PrimaryAccession)
+		plddt, afWarnings, err = fetchAlphaFoldPLDDT(ctx, fc, entry.PrimaryAccession)
 		if err != nil {
 			warnings = append(warnings, err.Error())
 		}
@@ -281,29 +365,64 @@ func main() {
 
 	domains, helixes, strands, activeSites, ptmPositions := extractFeatures(entry.Features)
 	pdbRanges := extractPDBRanges(entry.CrossReferences)
-	disordered := computeDisorderedRegions(plddt, len(sequence))
+	if req.FetchCIF {
+		pdbRanges = enrichPDBRangesFromCIF(ctx, fc, pdbRanges)
+	}
+	plddtDisordered := computeDisorderedRegions(plddt, len(sequence))
+	_, seqDisordered := computeDisorderFromSequence(sequence)
+
+	var disordered []bool
+	switch disorderSource {
+	case "iupred":
+		disordered = seqDisordered
+	case "both":
+		disordered = orDisorder(plddtDisordered, seqDisordered)
+	default:
+		disordered = plddtDisordered
+	}
+
+	var conservation []float64
+	msa := req.MSA
+	if msa == nil && req.FetchMSA && entry.PrimaryAccession != "" {
+		var err error
+		msa, err = fetchConservationMSA(ctx, fc, entry.PrimaryAccession)
+		if err != nil {
+			warnings = append(warnings, fmt.Sprintf("conservation MSA unavailable: %v", err))
+			msa = nil
+		}
+	}
+	if msa != nil {
+		conservation = conservationScores(msa)
+		if len(conservation) != len(sequence) {
+			warnings = append(warnings, fmt.Sprintf("conservation length (%d) does not match sequence length (%d); ignoring", len(conservation), len(sequence)))
+			conservation = nil
+		}
+	}
 
-	ctx := boundaryContext{
-		Length:       len(sequence),
-		Disorder:     disordered,
-		PLDDT:        plddt,
-		DomainRanges: domains,
-		PDBRanges:    pdbRanges,
-		HelixRanges:  helixes,
-		StrandRanges: strands,
-		ActiveSites:  activeSites,
-		PTMPositions: ptmPositions,
+	bctx := boundaryContext{
+		Length:        len(sequence),
+		Disorder:      disordered,
+		PLDDT:         plddt,
+		DomainRanges:  domains,
+		PDBRanges:     pdbRanges,
+		HelixRanges:   helixes,
+		StrandRanges:  strands,
+		ActiveSites:   activeSites,
+		PTMPositions:  ptmPositions,
+		Conservation:  conservation,
+		DisorderPLDDT: plddtDisordered,
+		DisorderSeq:   seqDisordered,
 	}
 
-	regionRange, regionName, err := parseRegion(region)
+	regionRange, regionName, err := parseRegion(req.Region)
 	if err != nil {
-		fatalf("invalid --region: %v", err)
+		return pipelineResult{}, fmt.Errorf("invalid region: %w", err)
 	}
 
-	candidates := buildCandidates(sequence, regionRange, regionName, domains, pdbRanges, disordered, minLength, maxLength)
-	scored := scoreCandidates(candidates, ctx, minLength, maxLength)
+	candidates := buildCandidates(sequence, regionRange, regionName, domains, pdbRanges, disordered, minLength, req.MaxLength)
+	scored := scoreCandidates(candidates, bctx, minLength, req.MaxLength)
 	if len(scored) == 0 {
-		fatalf("no construct candidates found with current filters")
+		return pipelineResult{}, errors.New("no construct candidates found with current filters")
 	}
 	sort.Slice(scored, func(i, j int) bool {
 		if scored[i].Score == scored[j].Score {
@@ -313,35 +432,229 @@ func main() {
 	})
 
 	top := scored
-	if len(top) > 10 {
+	if req.Diverse {
+		k := req.DiverseK
+		if k <= 0 {
+			k = 5
+		}
+		switch req.DiverseMethod {
+		case "mmr":
+			lambda := req.MMRLambda
+			if lambda <= 0 {
+				lambda = 0.5
+			}
+			top = selectMMRCandidates(scored, k, lambda)
+		default:
+			iouThreshold := req.IoUThreshold
+			if iouThreshold <= 0 {
+				iouThreshold = 0.5
+			}
+			top = selectDiverseCandidates(scored, k, iouThreshold)
+		}
+	} else if len(top) > 10 {
 		top = top[:10]
 	}
 
-	if jsonOut {
-		out := jsonOutput{
-			Protein: proteinSummary{
-				UniProtID: entry.PrimaryAccession,
-				Name:      name,
-				Length:    len(sequence),
-				Gene:      firstGene(entry.Genes),
-			},
-			Predictions: buildPredictions(top, plddt),
-			Features: featureSummary{
-				Domains:           domains,
-				DisorderedRegions: compressDisorder(disordered),
-				PDBStructures:     pdbRanges,
-				PLDDTScores:       plddt,
-			},
-			Warnings: warnings,
-		}
-		if err := writeJSON(os.Stdout, out); err != nil {
-			fatalf("failed to write JSON: %v", err)
+	return pipelineResult{
+		entry:        entry,
+		name:         name,
+		sequence:     sequence,
+		plddt:        plddt,
+		disordered:   disordered,
+		domains:      domains,
+		pdbRanges:    pdbRanges,
+		conservation: conservation,
+		top:          top,
+		warnings:     warnings,
+	}, nil
+}
+
+func main() {
+	if len(os.Args) > 1 && os.Args[1] == "cache" {
+		runCacheCommand(os.Args[2:])
+		return
+	}
+	if len(os.Args) > 1 && os.Args[1] == "calibrate" {
+		runCalibrateCommand(os.Args[2:])
+		return
+	}
+
+	var (
+		identifier      string
+		sequence        string
+		name            string
+		region          string
+		minLength       int
+		maxLength       int
+		format          string
+		plotPath        string
+		svgPath         string
+		pngPath         string
+		disorderSource  string
+		fetchCIF        bool
+		timeout         time.Duration
+		maxRetries      int
+		rps             float64
+		batchPath       string
+		workers         int
+		cacheBackend    string
+		cachePath       string
+		calibrationPath string
+		nms             bool
+		diverseMethod   string
+		diverseK        int
+		iouThreshold    float64
+		mmrLambda       float64
+		msaPath         string
+		fetchMSA        bool
+		batchOutput     string
+		jobTimeout      time.Duration
+		resume          bool
+	)
+
+	flag.StringVar(&identifier, "uniprot", "", "UniProt ID, entry name, or gene name")
+	flag.StringVar(&sequence, "sequence", "", "Raw amino acid sequence")
+	flag.StringVar(&name, "name", "", "Protein name (required with --sequence)")
+	flag.StringVar(&region, "region", "", "Focus region (e.g., 500-800 or domain name)")
+	flag.IntVar(&minLength, "min-length", defaultMinLength, "Minimum construct length (aa)")
+	flag.IntVar(&maxLength, "max-length", 0, "Maximum construct length (aa, 0 for no limit)")
+	flag.StringVar(&format, "format", "table", "Output format: table (human-readable summary + ASCII plot), json, tsv, or bed")
+	flag.StringVar(&plotPath, "plot", "", "Write ASCII visualization to a file")
+	flag.StringVar(&svgPath, "svg", "", "Write a figure-ready SVG track visualization to a file")
+	flag.StringVar(&pngPath, "png", "", "Write a rasterized PNG track visualization to a file")
+	flag.StringVar(&disorderSource, "disorder-source", "plddt", "Disorder evidence to use for boundary scoring: plddt, iupred (sequence-only), or both")
+	flag.BoolVar(&fetchCIF, "fetch-cif", false, "Fetch and parse each cross-referenced PDB entry's mmCIF file from RCSB for author-numbered residue ranges, crystallographic gaps, and engineered mutations (requires network; off by default)")
+	flag.DurationVar(&timeout, "timeout", 20*time.Second, "Per-request HTTP timeout")
+	flag.IntVar(&maxRetries, "max-retries", 3, "Retries for rate-limited or transient HTTP failures")
+	flag.Float64Var(&rps, "rps", 5, "Maximum requests per second to any single host")
+	flag.StringVar(&batchPath, "batch", "", "Run over a newline-delimited list of UniProt IDs/gene names, or a multi-FASTA file ('-' for stdin), emitting NDJSON to --output (stdout by default)")
+	flag.IntVar(&workers, "workers", runtime.NumCPU(), "Concurrent workers for --batch mode")
+	flag.StringVar(&batchOutput, "output", "", "--batch mode: NDJSON output file (stdout if unset); required by --resume")
+	flag.DurationVar(&jobTimeout, "job-timeout", 0, "--batch mode: per-entry deadline on top of --timeout's per-request one (0 for none)")
+	flag.BoolVar(&resume, "resume", false, "--batch mode: skip entries whose accession+options hash already succeeded in --output, appending only new records")
+	flag.StringVar(&cacheBackend, "cache-backend", "file", "Cache backend: file (one JSON file per entry) or sqlite")
+	flag.StringVar(&cachePath, "cache-path", "", "Cache location (defaults to the platform cache dir, or cache.db within it for --cache-backend=sqlite)")
+	flag.StringVar(&calibrationPath, "calibration", "", "calibration.json from 'benchaid calibrate' mapping raw scores to a calibrated Probability (omitted if not set)")
+	flag.BoolVar(&nms, "nms", false, "Select a diverse top-K instead of the plain top-10-by-score, suppressing candidates that overlap an already-selected one")
+	flag.StringVar(&diverseMethod, "diverse-method", "nms", "Diverse selection method when --nms is set: nms (greedy IoU suppression) or mmr (Maximal Marginal Relevance)")
+	flag.IntVar(&diverseK, "diverse-k", 5, "Number of candidates to keep when --nms is set")
+	flag.Float64Var(&iouThreshold, "iou-threshold", 0.5, "Max IoU with an already-selected candidate before --diverse-method=nms rejects one")
+	flag.Float64Var(&mmrLambda, "mmr-lambda", 0.5, "Score-vs-diversity tradeoff for --diverse-method=mmr, from 0 (diversity only) to 1 (score only)")
+	flag.StringVar(&msaPath, "msa", "", "FASTA, A3M, or Stockholm alignment (first row must be the query) to score boundaries against evolutionary conservation")
+	flag.BoolVar(&fetchMSA, "conservation", false, "Fetch a Pfam seed alignment for conservation scoring when --msa isn't given (requires network; off by default)")
+	flag.Parse()
+
+	if disorderSource != "plddt" && disorderSource != "iupred" && disorderSource != "both" {
+		fatalf("unknown --disorder-source %q (want plddt, iupred, or both)", disorderSource)
+	}
+	var writer reportWriter
+	if format != "table" {
+		var err error
+		writer, err = selectReportWriter(format)
+		if err != nil {
+			fatalf("%v", err)
+		}
+	}
+
+	if cachePath == "" {
+		cachePath = defaultCachePath(cacheBackend, defaultCacheDir())
+	}
+	store, err := openCacheStore(cacheBackend, cachePath)
+	if err != nil {
+		fatalf("%v", err)
+	}
+	defer store.Close()
+
+	var calib *calibrationModel
+	if calibrationPath != "" {
+		calib, err = loadCalibrationModel(calibrationPath)
+		if err != nil {
+			fatalf("failed to load --calibration: %v", err)
 		}
+	}
+
+	var msa []msaSequence
+	if msaPath != "" {
+		data, err := os.ReadFile(msaPath)
+		if err != nil {
+			fatalf("failed to read --msa: %v", err)
+		}
+		msa, err = parseMSA(data)
+		if err != nil {
+			fatalf("failed to parse --msa: %v", err)
+		}
+	}
+
+	ctx := context.Background()
+	fc := newFetchClient(timeout, maxRetries, rps, store)
+
+	if batchPath != "" {
+		reqTemplate := Request{
+			Region:         region,
+			MinLength:      minLength,
+			MaxLength:      maxLength,
+			DisorderSource: disorderSource,
+			FetchCIF:       fetchCIF,
+			Diverse:        nms,
+			DiverseMethod:  diverseMethod,
+			DiverseK:       diverseK,
+			IoUThreshold:   iouThreshold,
+			MMRLambda:      mmrLambda,
+			MSA:            msa,
+			FetchMSA:       fetchMSA,
+		}
+		runBatchMode(ctx, fc, calib, batchPath, reqTemplate, batchOptions{
+			Workers:    workers,
+			Output:     batchOutput,
+			JobTimeout: jobTimeout,
+			Resume:     resume,
+		})
 		return
 	}
 
-	printSummary(name, entry.PrimaryAccession, len(sequence), top, compressDisorder(disordered), pdbRanges, warnings)
-	ascii := buildASCIIVisualization(len(sequence), plddt, disordered, domains, top)
+	if sequence == "" && identifier == "" {
+		fatalf("provide --uniprot or --sequence")
+	}
+	if sequence != "" && name == "" {
+		fatalf("provide --name with --sequence")
+	}
+
+	req := Request{
+		Identifier:     identifier,
+		Sequence:       sequence,
+		Name:           name,
+		Region:         region,
+		MinLength:      minLength,
+		MaxLength:      maxLength,
+		DisorderSource: disorderSource,
+		FetchCIF:       fetchCIF,
+		Diverse:        nms,
+		DiverseMethod:  diverseMethod,
+		DiverseK:       diverseK,
+		IoUThreshold:   iouThreshold,
+		MMRLambda:      mmrLambda,
+		MSA:            msa,
+		FetchMSA:       fetchMSA,
+	}
+
+	if writer != nil {
+		out, err := Run(ctx, fc, calib, req)
+		if err != nil {
+			fatalf("%v", err)
+		}
+		if err := writer.WriteReport(os.Stdout, out); err != nil {
+			fatalf("failed to write %s report: %v", format, err)
+		}
+		return
+	}
+
+	result, err := runPipeline(ctx, fc, req)
+	if err != nil {
+		fatalf("%v", err)
+	}
+
+	printSummary(result.name, result.entry.PrimaryAccession, len(result.sequence), result.top, compressDisorder(result.disordered), result.pdbRanges, result.warnings)
+	ascii := buildASCIIVisualization(len(result.sequence), result.plddt, result.disordered, result.domains, result.top)
 	fmt.Println()
 	fmt.Println(ascii)
 
@@ -350,6 +663,22 @@ func main() {
 			fatalf("failed to write plot: %v", err)
 		}
 	}
+
+	if svgPath != "" {
+		svg := renderSVG(len(result.sequence), result.plddt, result.disordered, result.domains, result.pdbRanges, result.top)
+		if err := os.WriteFile(svgPath, svg, 0644); err != nil {
+			fatalf("failed to write --svg: %v", err)
+		}
+	}
+	if pngPath != "" {
+		png, err := renderPNG(len(result.sequence), result.plddt, result.disordered, result.domains, result.pdbRanges, result.top)
+		if err != nil {
+			fatalf("failed to render --png: %v", err)
+		}
+		if err := os.WriteFile(pngPath, png, 0644); err != nil {
+			fatalf("failed to write --png: %v", err)
+		}
+	}
 }
 
 func defaultCacheDir() string {
@@ -372,22 +701,18 @@ func sanitizeSequence(sequence string) string {
 	return sequence
 }
 
-func fetchUniProtEntry(client *http.Client, cacheDir, identifier string) (uniProtEntry, []string, error) {
+func fetchUniProtEntry(ctx context.Context, fc *fetchClient, identifier string) (uniProtEntry, []string, error) {
 	var warnings []string
-	body, err := fetchWithCache(client, cacheDir, "uniprot", identifier, func() ([]byte, error) {
-		url := fmt.Sprintf("%s/%s.json", uniprotBaseURL, url.PathEscape(identifier))
-		return httpGet(client, url)
-	})
+	entryURL := fmt.Sprintf("%s/%s.json", uniprotBaseURL, url.PathEscape(identifier))
+	body, err := fc.fetchWithCache(ctx, "uniprot", identifier, entryURL)
 	if err == nil {
 		entry, err := decodeUniProtEntry(body)
 		return entry, warnings, err
 	}
 
-	searchBody, searchErr := fetchWithCache(client, cacheDir, "uniprot-search", identifier, func() ([]byte, error) {
-		query := url.QueryEscape(fmt.Sprintf("gene_exact:%s OR %s", identifier, identifier))
-		searchURL := fmt.Sprintf("%s?query=%s&format=json&size=5", uniprotSearchURL, query)
-		return httpGet(client, searchURL)
-	})
+	query := url.QueryEscape(fmt.Sprintf("gene_exact:%s OR %s", identifier, identifier))
+	searchURL := fmt.Sprintf("%s?query=%s&format=json&size=5", uniprotSearchURL, query)
+	searchBody, searchErr := fc.fetchWithCache(ctx, "uniprot-search", identifier, searchURL)
 	if searchErr != nil {
 		return uniProtEntry{}, warnings, err
 	}
@@ -398,10 +723,8 @@ func fetchUniProtEntry(client *http.Client, cacheDir, identifier string) (uniPro
 	}
 	warnings = append(warnings, fmt.Sprintf("resolved %q to UniProt accession %s", identifier, accession))
 
-	entryBody, err := fetchWithCache(client, cacheDir, "uniprot", accession, func() ([]byte, error) {
-		url := fmt.Sprintf("%s/%s.json", uniprotBaseURL, url.PathEscape(accession))
-		return httpGet(client, url)
-	})
+	accessionURL := fmt.Sprintf("%s/%s.json", uniprotBaseURL, url.PathEscape(accession))
+	entryBody, err := fc.fetchWithCache(ctx, "uniprot", accession, accessionURL)
 	if err != nil {
 		return uniProtEntry{}, warnings, err
 	}
@@ -443,12 +766,10 @@ func extractFirstAccession(body []byte) (string, error) {
 	return resp.Results[0].PrimaryAccession, nil
 }
 
-func fetchAlphaFoldPLDDT(client *http.Client, cacheDir, accession string) ([]float64, []string, error) {
+func fetchAlphaFoldPLDDT(ctx context.Context, fc *fetchClient, accession string) ([]float64, []string, error) {
 	var warnings []string
-	body, err := fetchWithCache(client, cacheDir, "alphafold", accession, func() ([]byte, error) {
-		url := fmt.Sprintf("%s/%s", alphafoldPredictionURL, url.PathEscape(accession))
-		return httpGet(client, url)
-	})
+	predictionURL := fmt.Sprintf("%s/%s", alphafoldPredictionURL, url.PathEscape(accession))
+	body, err := fc.fetchWithCache(ctx, "alphafold", accession, predictionURL)
 	if err != nil {
 		return nil, warnings, fmt.Errorf("alphafold lookup failed: %w", err)
 	}
@@ -472,9 +793,7 @@ func fetchAlphaFoldPLDDT(client *http.Client, cacheDir, accession string) ([]flo
 		return nil, warnings, nil
 	}
 
-	pdbBody, err := fetchWithCache(client, cacheDir, "alphafold-pdb", accession, func() ([]byte, error) {
-		return httpGet(client, predictions[0].PdbURL)
-	})
+	pdbBody, err := fc.fetchWithCache(ctx, "alphafold-pdb", accession, predictions[0].PdbURL)
 	if err != nil {
 		return nil, warnings, err
 	}
@@ -522,94 +841,6 @@ func parsePLDDTFromPDB(body []byte) ([]float64, error) {
 	return plddt, nil
 }
 
-func fetchWithCache(client *http.Client, cacheDir, namespace, key string, fetch func() ([]byte, error)) ([]byte, error) {
-	cachePath := cacheFilePath(cacheDir, namespace, key)
-	if payload, ok := readCache(cachePath); ok {
-		return payload, nil
-	}
-	payload, err := fetch()
-	if err != nil {
-		return nil, err
-	}
-	if err := writeCache(cachePath, payload); err != nil {
-		return payload, nil
-	}
-	return payload, nil
-}
-
-func cacheFilePath(cacheDir, namespace, key string) string {
-	key = strings.ToLower(key)
-	key = strings.ReplaceAll(key, "/", "_")
-	key = strings.ReplaceAll(key, " ", "_")
-	return filepath.Join(cacheDir, namespace, fmt.Sprintf("%s.json", key))
-}
-
-func readCache(path string) ([]byte, bool) {
-	data, err := os.ReadFile(path)
-	if err != nil {
-		return nil, false
-	}
-	var entry cacheEntry
-	if err := json.Unmarshal(data, &entry); err != nil {
-		return nil, false
-	}
-	if time.Since(entry.FetchedAt) > cacheTTL {
-		return nil, false
-	}
-	return entry.Payload, true
-}
-
-func writeCache(path string, payload []byte) error {
-	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
-		return err
-	}
-	entry := cacheEntry{
-		FetchedAt: time.Now(),
-		Payload:   payload,
-	}
-	data, err := json.MarshalIndent(entry, "", "  ")
-	if err != nil {
-		return err
-	}
-	return os.WriteFile(path, data, 0644)
-}
-
-func httpGet(client *http.Client, url string) ([]byte, error) {
-	var lastErr error
-	for attempt := 0; attempt < 3; attempt++ {
-		resp, err := client.Get(url)
-		if err != nil {
-			lastErr = err
-			time.Sleep(time.Duration(attempt+1) * time.Second)
-			continue
-		}
-		defer resp.Body.Close()
-		if resp.StatusCode == http.StatusNotFound {
-			return nil, fmt.Errorf("resource not found (%s)", url)
-		}
-		if resp.StatusCode < 200 || resp.StatusCode >= 300 {
-			lastErr = fmt.Errorf("http status %d", resp.StatusCode)
-			time.Sleep(time.Duration(attempt+1) * time.Second)
-			continue
-		}
-		body, err := io.ReadAll(resp.Body)
-		if err != nil {
-			lastErr = err
-			time.Sleep(time.Duration(attempt+1) * time.Second)
-			continue
-		}
-		if resp.Header.Get("Content-Encoding") == "gzip" || isGzip(body) {
-			decoded, err := gunzip(body)
-			if err != nil {
-				return nil, err
-			}
-			return decoded, nil
-		}
-		return body, nil
-	}
-	return nil, lastErr
-}
-
 func normalizeJSON(body []byte) ([]byte, error) {
 	if isGzip(body) {
 		return gunzip(body)
@@ -677,6 +908,7 @@ func extractPDBRanges(crossRefs []uniProtCrossRef) []pdbRange {
 			if len(parts) != 2 {
 				continue
 			}
+			chainID := strings.TrimSpace(parts[0])
 			for _, span := range strings.Split(parts[1], ",") {
 				span = strings.TrimSpace(span)
 				if span == "" {
@@ -697,6 +929,7 @@ func extractPDBRanges(crossRefs []uniProtCrossRef) []pdbRange {
 					End:        end,
 					Method:     method,
 					Resolution: resolution,
+					ChainID:    chainID,
 				})
 			}
 		}
@@ -846,6 +1079,11 @@ func scoreCandidates(candidates []candidate, ctx boundaryContext, minLength, max
 		if candidates[i].PDBID != "" {
 			candidates[i].Evidence.PDBMatch = candidates[i].PDBID
 		}
+		if _, ok := crystalGapLabel(candidates[i].Start, ctx.PDBRanges); ok {
+			candidates[i].Evidence.CrystalGap = true
+		} else if _, ok := crystalGapLabel(candidates[i].End, ctx.PDBRanges); ok {
+			candidates[i].Evidence.CrystalGap = true
+		}
 	}
 	sort.SliceStable(candidates, func(i, j int) bool {
 		return candidates[i].Score > candidates[j].Score
@@ -861,6 +1099,10 @@ func boundaryScore(pos int, side string, ctx boundaryContext) (float64, []string
 		score += 30
 		rationale = append(rationale, "disorder transition")
 	}
+	if disorderSourcesAgree(pos, ctx) {
+		score += 10
+		rationale = append(rationale, "disorder agreement (pLDDT+IUPred)")
+	}
 	if isDomainBoundary(pos, ctx.DomainRanges) {
 		score += 25
 		rationale = append(rationale, "domain boundary")
@@ -877,6 +1119,18 @@ func boundaryScore(pos int, side string, ctx boundaryContext) (float64, []string
 		score += 10
 		rationale = append(rationale, "PDB boundary")
 	}
+	if id, ok := crystalGapLabel(pos, ctx.PDBRanges); ok {
+		score += 15
+		rationale = append(rationale, fmt.Sprintf("crystallographic gap in %s", id))
+	}
+	if isLowConservationWindow(pos, ctx.Conservation) {
+		score += 15
+		rationale = append(rationale, "boundary in low-conservation window")
+	}
+	if cutsConservedBlock(pos, ctx.Conservation) {
+		score -= 40
+		rationale = append(rationale, "cuts highly conserved block")
+	}
 
 	if withinRanges(pos, ctx.HelixRanges) || withinRanges(pos, ctx.StrandRanges) {
 		score -= 50
@@ -955,6 +1209,26 @@ func hasStructuredSide(pos int, side string, plddt []float64) bool {
 	return avgWindow(plddt, max(0, pos-10), pos-1) > 70
 }
 
+// isLowConservationWindow reports whether pos sits in a window of weak
+// evolutionary conservation, the same "cut here, it's disposable" signal
+// isLoopRegion gives from pLDDT but drawn from an MSA instead.
+func isLowConservationWindow(pos int, conservation []float64) bool {
+	if len(conservation) == 0 || pos <= 0 || pos > len(conservation) {
+		return false
+	}
+	return avgWindow(conservation, pos-3, pos+2) < 0.3
+}
+
+// cutsConservedBlock reports whether pos falls inside a strongly
+// conserved stretch, which a construct boundary should avoid just as it
+// avoids secondary structure or active sites.
+func cutsConservedBlock(pos int, conservation []float64) bool {
+	if len(conservation) == 0 || pos <= 0 || pos > len(conservation) {
+		return false
+	}
+	return avgWindow(conservation, pos-3, pos+2) > 0.8
+}
+
 func withinRanges(pos int, ranges []rangeInfo) bool {
 	for _, r := range ranges {
 		if pos >= r.Start && pos <= r.End {
@@ -973,7 +1247,7 @@ func withinPositions(pos int, positions []int, buffer int) bool {
 	return false
 }
 
-func buildPredictions(candidates []candidate, plddt []float64) []prediction {
+func buildPredictions(candidates []candidate, plddt []float64, conservation []float64, calib *calibrationModel) []prediction {
 	var preds []prediction
 	for i, cand := range candidates {
 		pred := prediction{
@@ -990,6 +1264,13 @@ func buildPredictions(candidates []candidate, plddt []float64) []prediction {
 			pred.Evidence.AvgPLDDT = avg
 			pred.Evidence.DisorderFraction = fraction
 		}
+		if len(conservation) > 0 {
+			pred.Evidence.AvgConservation = avgWindow(conservation, cand.Start-1, cand.End-1)
+		}
+		if probability, ok := calib.Predict(pred.Score); ok {
+			pred.Probability = probability
+			pred.Evidence.CalibrationVersion = calib.Version
+		}
 		preds = append(preds, pred)
 	}
 	return preds
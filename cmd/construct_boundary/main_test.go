@@ -1,6 +1,9 @@
 package main
 
-import "testing"
+import (
+	"strings"
+	"testing"
+)
 
 func TestParseRegionRange(t *testing.T) {
 	rng, name, err := parseRegion("10-200")
@@ -28,3 +31,21 @@ func TestComputeDisorderedRegions(t *testing.T) {
 		}
 	}
 }
+
+func TestComputeDisorderFromSequence(t *testing.T) {
+	scores, disorder := computeDisorderFromSequence("")
+	if scores != nil || disorder != nil {
+		t.Fatalf("expected nil results for empty sequence, got %v, %v", scores, disorder)
+	}
+
+	seq := strings.Repeat("GSGSGSPQPQPQ", 10)
+	scores, disorder = computeDisorderFromSequence(seq)
+	if len(scores) != len(seq) || len(disorder) != len(seq) {
+		t.Fatalf("unexpected result length: scores=%d disorder=%d want=%d", len(scores), len(disorder), len(seq))
+	}
+	for i, s := range scores {
+		if s < 0 || s > 1 {
+			t.Fatalf("position %d: score %v out of [0,1] range", i, s)
+		}
+	}
+}
@@ -0,0 +1,232 @@
+package main
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"fmt"
+	"math"
+	"net/url"
+	"strings"
+)
+
+// interproBaseURL serves an entry's Pfam domain matches (and, via
+// pfamAlignmentURL, the seed alignment for a given Pfam accession) for
+// fetchConservationMSA, the --conservation default when the user doesn't
+// supply their own --msa file.
+const (
+	interproBaseURL  = "https://www.ebi.ac.uk/interpro/api/entry/pfam/protein/uniprot"
+	pfamAlignmentURL = "https://www.ebi.ac.uk/interpro/wwwapi/entry/pfam"
+)
+
+// msaSequence is one row of a parsed multiple sequence alignment: its
+// identifier and its aligned residues (gaps included), all the same
+// length across a well-formed MSA.
+type msaSequence struct {
+	ID      string
+	Aligned string
+}
+
+// parseMSA auto-detects and parses a FASTA, A3M, or Stockholm alignment.
+// A3M is FASTA-like but marks insertion columns with lowercase letters
+// and '.'; Stockholm is identified by its "# STOCKHOLM" header line.
+func parseMSA(data []byte) ([]msaSequence, error) {
+	text := string(data)
+	trimmed := strings.TrimLeft(text, "\r\n\t ")
+	switch {
+	case strings.HasPrefix(trimmed, "# STOCKHOLM"):
+		return parseStockholmMSA(text)
+	case strings.HasPrefix(trimmed, ">"):
+		return parseFASTAMSA(text)
+	default:
+		return nil, fmt.Errorf("unrecognized MSA format (want FASTA, A3M, or Stockholm)")
+	}
+}
+
+// parseFASTAMSA parses both plain FASTA alignments and A3M, which is just
+// FASTA with lowercase/'.' insertion columns; stripA3MInsertions
+// removes those so every row is aligned to the same match-state columns.
+func parseFASTAMSA(text string) ([]msaSequence, error) {
+	var seqs []msaSequence
+	var id string
+	var row strings.Builder
+
+	flush := func() {
+		if id == "" {
+			return
+		}
+		seqs = append(seqs, msaSequence{ID: id, Aligned: stripA3MInsertions(row.String())})
+		row.Reset()
+	}
+
+	scanner := bufio.NewScanner(strings.NewReader(text))
+	scanner.Buffer(make([]byte, 0, 64*1024), 10*1024*1024)
+	for scanner.Scan() {
+		line := scanner.Text()
+		if strings.HasPrefix(line, ">") {
+			flush()
+			id = strings.Fields(strings.TrimPrefix(line, ">"))[0]
+			continue
+		}
+		row.WriteString(strings.TrimSpace(line))
+	}
+	flush()
+	if len(seqs) == 0 {
+		return nil, fmt.Errorf("no sequences found in MSA")
+	}
+	return seqs, nil
+}
+
+// stripA3MInsertions drops A3M insertion-state characters (lowercase
+// letters and '.') so a plain FASTA alignment passes through unchanged.
+func stripA3MInsertions(row string) string {
+	var b strings.Builder
+	b.Grow(len(row))
+	for _, r := range row {
+		if r == '.' || (r >= 'a' && r <= 'z') {
+			continue
+		}
+		b.WriteRune(r)
+	}
+	return b.String()
+}
+
+// parseStockholmMSA parses a (possibly multi-block) Stockholm alignment,
+// concatenating each identifier's residues across blocks and ignoring
+// "#=GC"/"#=GR"/"#=GF" annotation lines and the trailing "//".
+func parseStockholmMSA(text string) ([]msaSequence, error) {
+	order := make([]string, 0)
+	rows := make(map[string]*strings.Builder)
+
+	scanner := bufio.NewScanner(strings.NewReader(text))
+	scanner.Buffer(make([]byte, 0, 64*1024), 10*1024*1024)
+	for scanner.Scan() {
+		line := scanner.Text()
+		trimmed := strings.TrimSpace(line)
+		if trimmed == "" || trimmed == "//" || strings.HasPrefix(trimmed, "#") {
+			continue
+		}
+		fields := strings.Fields(trimmed)
+		if len(fields) != 2 {
+			continue
+		}
+		id, residues := fields[0], fields[1]
+		if _, ok := rows[id]; !ok {
+			rows[id] = &strings.Builder{}
+			order = append(order, id)
+		}
+		rows[id].WriteString(residues)
+	}
+	if len(order) == 0 {
+		return nil, fmt.Errorf("no sequences found in Stockholm alignment")
+	}
+	seqs := make([]msaSequence, len(order))
+	for i, id := range order {
+		seqs[i] = msaSequence{ID: id, Aligned: rows[id].String()}
+	}
+	return seqs, nil
+}
+
+// standardAminoAcidCount is the number of standard amino acids, used by
+// conservationScores as the denominator for its maximum-entropy column
+// (complete uncertainty over all 20 residues).
+const standardAminoAcidCount = 20
+
+// conservationScores computes one conservation value per ungapped column
+// of msa's first row (the query), in [0,1], from the Shannon entropy of
+// each alignment column's residue distribution normalised against the
+// maximum possible entropy over the 20 standard amino acids: a fully
+// conserved column scores 1, a column as diverse as possible scores 0.
+// Gap characters ('-', '.') are excluded from a column's counts.
+func conservationScores(msa []msaSequence) []float64 {
+	if len(msa) == 0 {
+		return nil
+	}
+	width := len(msa[0].Aligned)
+	maxEntropy := math.Log2(standardAminoAcidCount)
+
+	columnEntropy := make([]float64, width)
+	for col := 0; col < width; col++ {
+		counts := make(map[byte]int)
+		total := 0
+		for _, seq := range msa {
+			if col >= len(seq.Aligned) {
+				continue
+			}
+			c := seq.Aligned[col]
+			if c == '-' || c == '.' {
+				continue
+			}
+			counts[byte(toUpperASCII(c))]++
+			total++
+		}
+		if total == 0 {
+			columnEntropy[col] = maxEntropy
+			continue
+		}
+		var entropy float64
+		for _, n := range counts {
+			p := float64(n) / float64(total)
+			entropy -= p * math.Log2(p)
+		}
+		columnEntropy[col] = entropy
+	}
+
+	var scores []float64
+	query := msa[0].Aligned
+	for col := 0; col < width; col++ {
+		c := query[col]
+		if c == '-' || c == '.' {
+			continue
+		}
+		conservation := 1 - columnEntropy[col]/maxEntropy
+		scores = append(scores, clamp(conservation, 0, 1))
+	}
+	return scores
+}
+
+func toUpperASCII(c byte) byte {
+	if c >= 'a' && c <= 'z' {
+		return c - ('a' - 'A')
+	}
+	return c
+}
+
+// fetchConservationMSA pulls the Pfam seed alignment(s) covering
+// accession's UniProt entry from InterPro, for --conservation runs where
+// the user didn't supply their own --msa. It returns the first matching
+// Pfam alignment; a protein with no Pfam matches or with accession not
+// yet indexed by InterPro yields an error the caller can turn into a
+// warning and fall back to scoring without conservation evidence.
+func fetchConservationMSA(ctx context.Context, fc *fetchClient, accession string) ([]msaSequence, error) {
+	matchURL := fmt.Sprintf("%s/%s", interproBaseURL, url.PathEscape(accession))
+	body, err := fc.fetchWithCache(ctx, "interpro-matches", accession, matchURL)
+	if err != nil {
+		return nil, fmt.Errorf("interpro lookup failed: %w", err)
+	}
+	var matches struct {
+		Results []struct {
+			Metadata struct {
+				Accession string `json:"accession"`
+			} `json:"metadata"`
+		} `json:"results"`
+	}
+	normalized, err := normalizeJSON(body)
+	if err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal(normalized, &matches); err != nil {
+		return nil, err
+	}
+	if len(matches.Results) == 0 {
+		return nil, fmt.Errorf("no Pfam matches found for %s", accession)
+	}
+
+	pfamID := matches.Results[0].Metadata.Accession
+	alignmentURL := fmt.Sprintf("%s/%s/?annotation=alignment:seed", pfamAlignmentURL, url.PathEscape(pfamID))
+	alignmentBody, err := fc.fetchWithCache(ctx, "pfam-alignment", pfamID, alignmentURL)
+	if err != nil {
+		return nil, fmt.Errorf("pfam alignment fetch failed: %w", err)
+	}
+	return parseMSA(alignmentBody)
+}
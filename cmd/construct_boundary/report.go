@@ -0,0 +1,80 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"math"
+	"text/tabwriter"
+)
+
+// reportWriter renders a completed jsonOutput in one machine-readable
+// --format. table mode (the original default) keeps printSummary and
+// buildASCIIVisualization's human-oriented rendering instead of going
+// through this interface, since it needs pipelineResult's raw per-residue
+// data (plddt, disorder flags) that jsonOutput doesn't carry.
+type reportWriter interface {
+	WriteReport(w io.Writer, out jsonOutput) error
+}
+
+// jsonReportWriter is --format=json: the same jsonOutput the original
+// --json flag produced, via writeJSON.
+type jsonReportWriter struct{}
+
+func (jsonReportWriter) WriteReport(w io.Writer, out jsonOutput) error {
+	return writeJSON(w, out)
+}
+
+// tsvReportWriter is --format=tsv: one row per predicted candidate.
+type tsvReportWriter struct{}
+
+func (tsvReportWriter) WriteReport(w io.Writer, out jsonOutput) error {
+	tw := tabwriter.NewWriter(w, 0, 2, 2, ' ', 0)
+	fmt.Fprintln(tw, "rank\tstart\tend\tlength\tscore\tprobability\tavg_pLDDT\tdisorder_fraction\tavg_conservation\trationale\tpdb_match\tdomain\tcrystal_gap")
+	for _, pred := range out.Predictions {
+		fmt.Fprintf(tw, "%d\t%d\t%d\t%d\t%.0f\t%.3f\t%.1f\t%.2f\t%.2f\t%s\t%s\t%s\t%t\n",
+			pred.Rank, pred.Start, pred.End, pred.Length, pred.Score, pred.Probability,
+			pred.Evidence.AvgPLDDT, pred.Evidence.DisorderFraction, pred.Evidence.AvgConservation, pred.Rationale,
+			pred.Evidence.PDBMatch, pred.Evidence.Domain, pred.Evidence.CrystalGap)
+	}
+	return tw.Flush()
+}
+
+// bedReportWriter is --format=bed: one UCSC-style 0-based half-open
+// interval per predicted candidate, so predictions can be loaded as a
+// track in genome/protein browsers. chrom is the UniProt accession (or
+// the protein name, or "query" as a last resort, for sequence-only runs).
+type bedReportWriter struct{}
+
+func (bedReportWriter) WriteReport(w io.Writer, out jsonOutput) error {
+	chrom := out.Protein.UniProtID
+	if chrom == "" {
+		chrom = out.Protein.Name
+	}
+	if chrom == "" {
+		chrom = "query"
+	}
+	for _, pred := range out.Predictions {
+		score := int(math.Round(pred.Score))
+		score = max(0, min(1000, score))
+		if _, err := fmt.Fprintf(w, "%s\t%d\t%d\tcandidate_%d\t%d\t+\n", chrom, pred.Start-1, pred.End, pred.Rank, score); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// selectReportWriter returns the reportWriter for a --format value other
+// than "table", which main handles separately via printSummary and
+// buildASCIIVisualization.
+func selectReportWriter(format string) (reportWriter, error) {
+	switch format {
+	case "json":
+		return jsonReportWriter{}, nil
+	case "tsv":
+		return tsvReportWriter{}, nil
+	case "bed":
+		return bedReportWriter{}, nil
+	default:
+		return nil, fmt.Errorf("unknown --format %q (want table, json, tsv, or bed)", format)
+	}
+}
@@ -0,0 +1,214 @@
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"image"
+	"image/color"
+	"image/draw"
+	"image/png"
+)
+
+// svgWidth, svgMargin, svgTrackHeight, and svgTrackGap lay out renderSVG's
+// and renderPNG's stacked tracks identically, so the two always agree on
+// what a given residue's pixel column looks like.
+const (
+	svgWidth       = 1000
+	svgMargin      = 60
+	svgTrackHeight = 36
+	svgTrackGap    = 10
+)
+
+// svgTrackNames is, in order, every track renderSVG and renderPNG draw
+// beneath the axis; the axis itself has no entry here.
+var svgTrackNames = []string{"pLDDT", "Disorder", "Domains", "PDB", "Top candidates"}
+
+// svgPlotWidth is the horizontal pixel span available for residue data,
+// after subtracting the left/right margins reserved for axis labels.
+func svgPlotWidth() int {
+	return svgWidth - 2*svgMargin
+}
+
+// svgHeight is the total canvas height: a margin, the axis track, every
+// track in svgTrackNames, and a closing margin.
+func svgHeight() int {
+	return svgMargin + (len(svgTrackNames)+1)*(svgTrackHeight+svgTrackGap) + svgMargin
+}
+
+// svgX maps a 1-based residue position to its pixel column.
+func svgX(pos, length int) float64 {
+	if length <= 0 {
+		return float64(svgMargin)
+	}
+	return float64(svgMargin) + float64(pos-1)/float64(length)*float64(svgPlotWidth())
+}
+
+// colorForPLDDT maps a pLDDT value (0-100) to a red (disordered) -> green
+// (confident) gradient, shared by renderSVG's heatmap and renderPNG's
+// rasterization so the two render identically.
+func colorForPLDDT(avg float64) color.RGBA {
+	t := avg / 100
+	if t < 0 {
+		t = 0
+	}
+	if t > 1 {
+		t = 1
+	}
+	return color.RGBA{
+		R: uint8(255 * (1 - t)),
+		G: uint8(255 * t),
+		B: 60,
+		A: 255,
+	}
+}
+
+// renderSVG draws length residues as a stack of horizontal tracks -- a
+// residue-number axis with tick marks every 50 aa, a pLDDT heatmap,
+// a disorder bar, labelled domain rectangles, PDB structure coverage, and
+// the top predicted candidates with their scores -- as a self-contained
+// SVG document (no external dependencies or fonts).
+func renderSVG(length int, plddt []float64, disordered []bool, domains []rangeInfo, pdbRanges []pdbRange, candidates []candidate) []byte {
+	var buf bytes.Buffer
+	width, height := svgWidth, svgHeight()
+
+	fmt.Fprintf(&buf, `<svg xmlns="http://www.w3.org/2000/svg" width="%d" height="%d" font-family="sans-serif" font-size="11">`+"\n", width, height)
+	fmt.Fprintf(&buf, `<rect x="0" y="0" width="%d" height="%d" fill="white"/>`+"\n", width, height)
+
+	y := svgMargin
+	fmt.Fprintf(&buf, `<text x="4" y="%d" font-weight="bold">Position</text>`+"\n", y+svgTrackHeight/2+4)
+	fmt.Fprintf(&buf, `<line x1="%.1f" y1="%d" x2="%.1f" y2="%d" stroke="black"/>`+"\n",
+		svgX(1, length), y+svgTrackHeight/2, svgX(max(length, 1), length), y+svgTrackHeight/2)
+	for pos := 1; pos <= length; pos += 50 {
+		x := svgX(pos, length)
+		fmt.Fprintf(&buf, `<line x1="%.1f" y1="%d" x2="%.1f" y2="%d" stroke="black"/>`+"\n",
+			x, y+svgTrackHeight/2-4, x, y+svgTrackHeight/2+4)
+		fmt.Fprintf(&buf, `<text x="%.1f" y="%d" text-anchor="middle">%d</text>`+"\n", x, y+svgTrackHeight, pos)
+	}
+	y += svgTrackHeight + svgTrackGap
+
+	fmt.Fprintf(&buf, `<text x="4" y="%d">pLDDT</text>`+"\n", y+svgTrackHeight/2+4)
+	if len(plddt) > 0 {
+		for px := 0; px < svgPlotWidth(); px++ {
+			start, end := svgPixelResidues(px, length)
+			col := colorForPLDDT(avgWindow(plddt, start, end))
+			fmt.Fprintf(&buf, `<rect x="%d" y="%d" width="1" height="%d" fill="rgb(%d,%d,%d)"/>`+"\n",
+				svgMargin+px, y, svgTrackHeight, col.R, col.G, col.B)
+		}
+	}
+	y += svgTrackHeight + svgTrackGap
+
+	fmt.Fprintf(&buf, `<text x="4" y="%d">Disorder</text>`+"\n", y+svgTrackHeight/2+4)
+	for px := 0; px < svgPlotWidth(); px++ {
+		start, end := svgPixelResidues(px, length)
+		if sliceAny(disordered, start, end) {
+			fmt.Fprintf(&buf, `<rect x="%d" y="%d" width="1" height="%d" fill="#c0392b"/>`+"\n", svgMargin+px, y, svgTrackHeight)
+		}
+	}
+	y += svgTrackHeight + svgTrackGap
+
+	fmt.Fprintf(&buf, `<text x="4" y="%d">Domains</text>`+"\n", y+svgTrackHeight/2+4)
+	for _, d := range domains {
+		x1, x2 := svgX(d.Start, length), svgX(d.End, length)
+		fmt.Fprintf(&buf, `<rect x="%.1f" y="%d" width="%.1f" height="%d" fill="#2980b9" fill-opacity="0.6"/>`+"\n", x1, y, x2-x1, svgTrackHeight)
+		fmt.Fprintf(&buf, `<text x="%.1f" y="%d" text-anchor="middle">%s</text>`+"\n", (x1+x2)/2, y+svgTrackHeight/2+4, d.Name)
+	}
+	y += svgTrackHeight + svgTrackGap
+
+	fmt.Fprintf(&buf, `<text x="4" y="%d">PDB</text>`+"\n", y+svgTrackHeight/2+4)
+	for _, p := range pdbRanges {
+		x1, x2 := svgX(p.Start, length), svgX(p.End, length)
+		fmt.Fprintf(&buf, `<rect x="%.1f" y="%d" width="%.1f" height="%d" fill="#8e44ad" fill-opacity="0.5"/>`+"\n", x1, y, x2-x1, svgTrackHeight)
+		fmt.Fprintf(&buf, `<text x="%.1f" y="%d" text-anchor="middle">%s</text>`+"\n", (x1+x2)/2, y+svgTrackHeight/2+4, p.ID)
+	}
+	y += svgTrackHeight + svgTrackGap
+
+	fmt.Fprintf(&buf, `<text x="4" y="%d">Top candidates</text>`+"\n", y+svgTrackHeight/2+4)
+	for i, c := range candidates {
+		if i >= 5 {
+			break
+		}
+		x1, x2 := svgX(c.Start, length), svgX(c.End, length)
+		fmt.Fprintf(&buf, `<rect x="%.1f" y="%d" width="%.1f" height="%d" fill="#27ae60" fill-opacity="0.6"/>`+"\n", x1, y, x2-x1, svgTrackHeight)
+		fmt.Fprintf(&buf, `<text x="%.1f" y="%d" text-anchor="middle">#%d (%.0f)</text>`+"\n", (x1+x2)/2, y+svgTrackHeight/2+4, i+1, c.Score)
+	}
+
+	buf.WriteString("</svg>\n")
+	return buf.Bytes()
+}
+
+// svgPixelResidues returns the [start, end] residue window (0-based,
+// inclusive) that pixel column px of the plot area covers, for averaging
+// or membership checks against per-residue data.
+func svgPixelResidues(px, length int) (int, int) {
+	plotWidth := svgPlotWidth()
+	start := int(float64(px) / float64(plotWidth) * float64(length))
+	end := int(float64(px+1)/float64(plotWidth)*float64(length)) - 1
+	if end < start {
+		end = start
+	}
+	return start, end
+}
+
+// renderPNG rasterizes the same tracks as renderSVG using only image/draw
+// and image/png. Text labels (domain names, PDB IDs, candidate scores)
+// aren't drawn, since the standard library has no glyph rasterizer; use
+// renderSVG when labels matter.
+func renderPNG(length int, plddt []float64, disordered []bool, domains []rangeInfo, pdbRanges []pdbRange, candidates []candidate) ([]byte, error) {
+	width, height := svgWidth, svgHeight()
+	img := image.NewRGBA(image.Rect(0, 0, width, height))
+	draw.Draw(img, img.Bounds(), image.NewUniform(color.White), image.Point{}, draw.Src)
+
+	fill := func(x0, y0, x1, y1 int, c color.Color) {
+		if x1 <= x0 || y1 <= y0 {
+			return
+		}
+		draw.Draw(img, image.Rect(x0, y0, x1, y1), image.NewUniform(c), image.Point{}, draw.Over)
+	}
+	xPixel := func(pos int) int { return int(svgX(pos, length)) }
+
+	y := svgMargin
+	for pos := 1; pos <= length; pos += 50 {
+		x := xPixel(pos)
+		fill(x, y+svgTrackHeight/2-4, x+1, y+svgTrackHeight/2+4, color.Black)
+	}
+	y += svgTrackHeight + svgTrackGap
+
+	if len(plddt) > 0 {
+		for px := 0; px < svgPlotWidth(); px++ {
+			start, end := svgPixelResidues(px, length)
+			fill(svgMargin+px, y, svgMargin+px+1, y+svgTrackHeight, colorForPLDDT(avgWindow(plddt, start, end)))
+		}
+	}
+	y += svgTrackHeight + svgTrackGap
+
+	for px := 0; px < svgPlotWidth(); px++ {
+		start, end := svgPixelResidues(px, length)
+		if sliceAny(disordered, start, end) {
+			fill(svgMargin+px, y, svgMargin+px+1, y+svgTrackHeight, color.RGBA{192, 57, 43, 255})
+		}
+	}
+	y += svgTrackHeight + svgTrackGap
+
+	for _, d := range domains {
+		fill(xPixel(d.Start), y, xPixel(d.End), y+svgTrackHeight, color.RGBA{41, 128, 185, 160})
+	}
+	y += svgTrackHeight + svgTrackGap
+
+	for _, p := range pdbRanges {
+		fill(xPixel(p.Start), y, xPixel(p.End), y+svgTrackHeight, color.RGBA{142, 68, 173, 140})
+	}
+	y += svgTrackHeight + svgTrackGap
+
+	for i, c := range candidates {
+		if i >= 5 {
+			break
+		}
+		fill(xPixel(c.Start), y, xPixel(c.End), y+svgTrackHeight, color.RGBA{39, 174, 96, 160})
+	}
+
+	var buf bytes.Buffer
+	if err := png.Encode(&buf, img); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
@@ -3,18 +3,27 @@ package main
 import (
 	"bufio"
 	"bytes"
+	"compress/gzip"
+	"encoding/csv"
 	"encoding/json"
 	"errors"
 	"flag"
 	"fmt"
 	"io"
 	"net/http"
+	"net/url"
 	"os"
 	"path/filepath"
 	"sort"
 	"strconv"
 	"strings"
+	"sync"
 	"time"
+
+	"benchaid/align"
+	"benchaid/bioseq"
+	"benchaid/codonopt"
+	"benchaid/primers"
 )
 
 const (
@@ -26,7 +35,7 @@ type Vector struct {
 	Name               string
 	Aliases            []string
 	Description        string
-	Sequence           string
+	Sequence           bioseq.Sequence
 	SequenceFile       string
 	Length             int
 	CloningMethod      string
@@ -38,6 +47,8 @@ type Vector struct {
 	NTerminalTag       string
 	CTerminalTag       string
 	ReadingFrame       int
+	TranslTable        int
+	HostOrganism       string
 	SourcePath         string
 }
 
@@ -54,7 +65,7 @@ type Feature struct {
 type Construct struct {
 	Name            string
 	Description     string
-	Sequence        string
+	Sequence        bioseq.Sequence
 	Length          int
 	Vector          string
 	InsertName      string
@@ -66,7 +77,16 @@ type Construct struct {
 	InsertProtein   string
 	InsertDNA       string
 	CreatedDate     string
-	PrimerPair      []string
+	// Primers holds the amplification primer pair designed to carry the
+	// insert's overlap-dependent cloning (Gibson/SLIC/LIC) homology
+	// overhangs; nil for restriction cloning, where no PCR step is needed.
+	Primers         []primers.PrimerPair
+	NTerminalTag    string
+	// VectorInsertionSite is the 1-based position in the parent vector
+	// (before the insert's length was spliced in) where the insert was
+	// cloned, carried through so writers that diff the construct against
+	// the vector (e.g. writeVCF) don't need the Vector value re-resolved.
+	VectorInsertionSite int
 }
 
 type ValidationResult struct {
@@ -79,11 +99,12 @@ type ValidationResult struct {
 }
 
 type seqInfo struct {
-	Name    string
-	DNA     string
-	Protein string
-	Source  string
-	Range   string
+	Name        string
+	DNA         bioseq.Sequence
+	Protein     bioseq.Sequence
+	Source      string
+	Range       string
+	TranslTable int
 }
 
 type restrictionSite struct {
@@ -99,7 +120,55 @@ var restrictionSites = map[string]restrictionSite{
 	"EcoRI": {Name: "EcoRI", Sequence: "GAATTC", CutIndex: 1},
 }
 
+// SyntaxError reports a parse failure against a specific source — a GenBank
+// flatfile fetched from NCBI, or a hand-edited vector YAML file — pinpointing
+// the line the parser was on so callers get more than "no sequence found".
+type SyntaxError struct {
+	File     string
+	Line     int
+	Context  string
+	Msg      string
+	InnerErr error
+}
+
+func (e *SyntaxError) Error() string {
+	return fmt.Sprintf("syntax error at %s:%d: %s\n  %d\t%s", e.File, e.Line, e.Msg, e.Line, e.Context)
+}
+
+func (e *SyntaxError) Unwrap() error {
+	return e.InnerErr
+}
+
+func newSyntaxError(file string, line int, context, msg string, inner error) *SyntaxError {
+	return &SyntaxError{File: file, Line: line, Context: context, Msg: msg, InnerErr: inner}
+}
+
+// totalLines and lastNonEmptyLine back-fill line/context information for
+// errors detected only after a scanner has already run to completion (e.g.
+// "no sequence found"), where there's no single offending line to point at.
+func totalLines(content string) int {
+	return strings.Count(content, "\n") + 1
+}
+
+func lastNonEmptyLine(content string) string {
+	lines := strings.Split(content, "\n")
+	for i := len(lines) - 1; i >= 0; i-- {
+		if strings.TrimSpace(lines[i]) != "" {
+			return lines[i]
+		}
+	}
+	return ""
+}
+
 func main() {
+	if len(os.Args) > 1 && os.Args[1] == "cache" {
+		if len(os.Args) < 3 || os.Args[2] != "prune" {
+			fatalf("usage: construct_generator cache prune [--cache-dir <dir>] [--cache-ttl <duration>]")
+		}
+		runCachePrune(os.Args[3:])
+		return
+	}
+
 	var (
 		vectorName      string
 		insertAccession string
@@ -111,7 +180,14 @@ func main() {
 		output          string
 		listVectors     bool
 		vectorInfo      string
-		jsonOut         bool
+		formats         stringListFlag
+		stdoutFormat    string
+		cacheDir        string
+		cacheTTL        time.Duration
+		noCache         bool
+		refreshCache    bool
+		codonTableID    int
+		optimizeHost    string
 	)
 
 	flag.StringVar(&vectorName, "vector", "", "Vector name from library")
@@ -121,14 +197,36 @@ func main() {
 	flag.StringVar(&insertName, "insert-name", "", "Insert name")
 	flag.StringVar(&residues, "residues", "", "Residue range (e.g. 1-500)")
 	flag.StringVar(&method, "method", "", "Cloning method (lic, gibson, restriction)")
-	flag.StringVar(&output, "output", "", "Output GenBank file path (.gb or .gbk)")
+	flag.StringVar(&output, "output", "", "Output file path/base name (extension derived per --format)")
 	flag.BoolVar(&listVectors, "list-vectors", false, "List available vectors")
 	flag.StringVar(&vectorInfo, "vector-info", "", "Show details for a vector")
-	flag.BoolVar(&jsonOut, "json", false, "Write construct metadata as JSON (alongside GenBank)")
+	flag.Var(&formats, "format", "Output format(s): gb, fasta, sbol, csv, json, jsonl, gff3, vcf (comma-separated, may repeat)")
+	flag.StringVar(&stdoutFormat, "stdout", "", "Stream a single output format to stdout instead of writing files")
+	flag.StringVar(&cacheDir, "cache-dir", "", "NCBI cache directory (default ~/.benchaid/cache)")
+	flag.DurationVar(&cacheTTL, "cache-ttl", 30*24*time.Hour, "How long a cached NCBI record is served without revalidation")
+	flag.BoolVar(&noCache, "no-cache", false, "Bypass the NCBI cache entirely")
+	flag.BoolVar(&refreshCache, "refresh-cache", false, "Force revalidation of cached NCBI records")
+	flag.IntVar(&codonTableID, "codon-table", 1, "NCBI genetic code table number used for translation (1, 2, 3, 4, 5, 6, 9, 10, 11, 12, 13, 14, 16, 21)")
+	flag.StringVar(&optimizeHost, "optimize", "", "Codon-optimize the insert for a host organism (ecoli, scerevisiae, ppastoris, hsapiens, sfrugiperda, cho); defaults to the vector's host_organism if set")
 	flag.Parse()
 
+	ncbiCacheConfig.dir = cacheDir
+	ncbiCacheConfig.ttl = cacheTTL
+	ncbiCacheConfig.disable = noCache
+	ncbiCacheConfig.refresh = refreshCache
+
+	if table, ok := bioseq.Table(codonTableID); ok {
+		translationTable = table
+	} else {
+		fatalf("unknown codon table %d", codonTableID)
+	}
+
 	vectors, err := loadVectors()
 	if err != nil {
+		var synErr *SyntaxError
+		if errors.As(err, &synErr) {
+			fatalf("%v", synErr)
+		}
 		fatalf("failed to load vectors: %v", err)
 	}
 
@@ -149,25 +247,28 @@ func main() {
 	if vectorName == "" {
 		fatalf("provide --vector")
 	}
-	if output == "" {
-		fatalf("provide --output")
+	if output == "" && stdoutFormat == "" {
+		fatalf("provide --output or --stdout")
 	}
 
 	vector, ok := findVector(vectorName, vectors)
 	if !ok {
 		fatalf("vector %q not found; use --list-vectors", vectorName)
 	}
-	if vector.Sequence == "" {
+	if vector.Sequence.Len() == 0 {
 		fatalf("vector %q has no sequence; supply a vector definition with sequence or sequence_file", vector.Name)
 	}
 
 	insert, err := loadInsert(seqInfo{
 		Name:   insertName,
-		DNA:    insertSeq,
 		Source: insertAccession,
 		Range:  residues,
-	}, insertFile, insertAccession)
+	}, insertFile, insertAccession, insertSeq)
 	if err != nil {
+		var synErr *SyntaxError
+		if errors.As(err, &synErr) {
+			fatalf("%v", synErr)
+		}
 		fatalf("failed to load insert: %v", err)
 	}
 
@@ -175,6 +276,17 @@ func main() {
 		insert.Name = "Insert"
 	}
 
+	organism := optimizeHost
+	if organism == "" {
+		organism = vector.HostOrganism
+	}
+	if organism != "" {
+		insert, err = optimizeInsert(insert, organism, vector.RestrictionSites)
+		if err != nil {
+			fatalf("codon optimization failed: %v", err)
+		}
+	}
+
 	if method == "" {
 		method = vector.CloningMethod
 	}
@@ -197,17 +309,41 @@ func main() {
 		fmt.Fprintf(os.Stderr, "warning: %s\n", warn)
 	}
 
-	if err := writeGenBank(construct, output); err != nil {
-		fatalf("failed to write GenBank: %v", err)
+	if stdoutFormat != "" {
+		format, err := formatByName(stdoutFormat)
+		if err != nil {
+			fatalf("%v", err)
+		}
+		if err := format.Write(os.Stdout, construct, validation); err != nil {
+			fatalf("failed to write %s: %v", format.Name(), err)
+		}
+		return
 	}
-	fmt.Fprintf(os.Stderr, "Written: %s\n", output)
 
-	if jsonOut {
-		jsonPath := output + ".json"
-		if err := writeJSON(construct, jsonPath); err != nil {
-			fatalf("failed to write JSON: %v", err)
+	names := formats.values
+	if len(names) == 0 {
+		names = []string{"gb"}
+	}
+	base := strings.TrimSuffix(output, filepath.Ext(output))
+	for _, name := range names {
+		format, err := formatByName(name)
+		if err != nil {
+			fatalf("%v", err)
+		}
+		path := base + format.Extension()
+		f, err := os.Create(path)
+		if err != nil {
+			fatalf("failed to create %s: %v", path, err)
 		}
-		fmt.Fprintf(os.Stderr, "Written: %s\n", jsonPath)
+		writeErr := format.Write(f, construct, validation)
+		closeErr := f.Close()
+		if writeErr != nil {
+			fatalf("failed to write %s: %v", path, writeErr)
+		}
+		if closeErr != nil {
+			fatalf("failed to write %s: %v", path, closeErr)
+		}
+		fmt.Fprintf(os.Stderr, "Written: %s\n", path)
 	}
 }
 
@@ -216,8 +352,11 @@ func usage() {
 	fmt.Fprintf(os.Stderr, "Usage:\n")
 	fmt.Fprintf(os.Stderr, "  construct_generator --vector 438-C --insert-accession NM_003170 --residues 1-500 --output spt6.gb\n")
 	fmt.Fprintf(os.Stderr, "  construct_generator --vector pET28a --insert-file gene.fasta --method restriction --output construct.gb\n")
+	fmt.Fprintf(os.Stderr, "  construct_generator --vector 438-C --insert-accession NM_003170 --output spt6 --format gb,fasta,csv\n")
+	fmt.Fprintf(os.Stderr, "  construct_generator --vector 438-C --insert-accession NM_003170 --stdout fasta\n")
 	fmt.Fprintf(os.Stderr, "  construct_generator --list-vectors\n")
 	fmt.Fprintf(os.Stderr, "  construct_generator --vector-info 438-C\n")
+	fmt.Fprintf(os.Stderr, "  construct_generator cache prune [--cache-dir <dir>] [--cache-ttl <duration>]\n")
 }
 
 func fatalf(format string, args ...interface{}) {
@@ -225,6 +364,26 @@ func fatalf(format string, args ...interface{}) {
 	os.Exit(1)
 }
 
+// stringListFlag implements flag.Value for a flag that may be repeated
+// and/or passed as a comma-separated list, e.g. --format gb,fasta --format csv.
+type stringListFlag struct {
+	values []string
+}
+
+func (f *stringListFlag) String() string {
+	return strings.Join(f.values, ",")
+}
+
+func (f *stringListFlag) Set(value string) error {
+	for _, part := range strings.Split(value, ",") {
+		part = strings.TrimSpace(part)
+		if part != "" {
+			f.values = append(f.values, part)
+		}
+	}
+	return nil
+}
+
 func printVectorList(vectors []Vector) {
 	if len(vectors) == 0 {
 		fmt.Println("No vectors available.")
@@ -265,8 +424,8 @@ func printVectorInfo(vector Vector) {
 	if vector.CTerminalTag != "" {
 		fmt.Printf("C-terminal tag: %s\n", vector.CTerminalTag)
 	}
-	if vector.Sequence != "" {
-		fmt.Printf("Length: %d bp\n", len(vector.Sequence))
+	if vector.Sequence.Len() != 0 {
+		fmt.Printf("Length: %d bp\n", vector.Sequence.Len())
 	}
 	if vector.SourcePath != "" {
 		fmt.Printf("Source: %s\n", vector.SourcePath)
@@ -341,7 +500,7 @@ func loadVectorFile(path string) ([]Vector, error) {
 		}
 		vector, err := parseVectorYAML(path, section)
 		if err != nil {
-			return nil, fmt.Errorf("%s: %w", path, err)
+			return nil, err
 		}
 		vectors = append(vectors, vector)
 	}
@@ -353,7 +512,7 @@ func dedupeVectors(vectors []Vector) []Vector {
 	for _, v := range vectors {
 		key := strings.ToLower(v.Name)
 		if _, ok := seen[key]; ok {
-			if seen[key].Sequence == "" && v.Sequence != "" {
+			if seen[key].Sequence.Len() == 0 && v.Sequence.Len() != 0 {
 				seen[key] = v
 			}
 			continue
@@ -460,13 +619,17 @@ func parseVectorYAML(path, content string) (Vector, error) {
 
 	scanner := bufio.NewScanner(strings.NewReader(content))
 	lineNum := 0
+	var lastLine string
 	for scanner.Scan() {
 		lineNum++
 		line := scanner.Text()
+		lastLine = line
 		if multilineKey != "" {
 			indent := leadingSpaces(line)
 			if indent <= multilineIndent && strings.TrimSpace(line) != "" {
-				setVectorField(&vector, currentFeature, multilineKey, strings.Join(multilineValue, ""), mode)
+				if err := setVectorField(&vector, currentFeature, multilineKey, strings.Join(multilineValue, ""), mode); err != nil {
+					return vector, newSyntaxError(path, lineNum, line, "failed to read vector definition", err)
+				}
 				multilineKey = ""
 				multilineValue = nil
 				mode = ""
@@ -501,6 +664,8 @@ func parseVectorYAML(path, content string) (Vector, error) {
 				vector.Features = append(vector.Features, feature)
 				currentFeature = &vector.Features[len(vector.Features)-1]
 				mode = "features"
+			default:
+				return vector, newSyntaxError(path, lineNum, line, fmt.Sprintf("unexpected list item outside of a list field: %q", item), nil)
 			}
 			continue
 		}
@@ -534,7 +699,11 @@ func parseVectorYAML(path, content string) (Vector, error) {
 				multilineIndent = leadingSpaces(line)
 				multilineValue = nil
 			} else {
-				vector.Sequence = normalizeDNA(stripQuotes(value))
+				seq, err := normalizeDNA(stripQuotes(value))
+				if err != nil {
+					return vector, newSyntaxError(path, lineNum, line, "invalid vector sequence", err)
+				}
+				vector.Sequence = seq
 			}
 		default:
 			if currentFeature != nil && mode == "features" && key != "" {
@@ -546,20 +715,22 @@ func parseVectorYAML(path, content string) (Vector, error) {
 					multilineKey = key
 					multilineIndent = leadingSpaces(line)
 					multilineValue = nil
-				} else {
-					setVectorField(&vector, currentFeature, key, value, mode)
+				} else if err := setVectorField(&vector, currentFeature, key, value, mode); err != nil {
+					return vector, newSyntaxError(path, lineNum, line, "invalid vector field", err)
 				}
 			}
 		}
 	}
 	if err := scanner.Err(); err != nil {
-		return vector, err
+		return vector, newSyntaxError(path, lineNum, lastLine, "failed to read vector definition", err)
 	}
 	if multilineKey != "" {
-		setVectorField(&vector, currentFeature, multilineKey, strings.Join(multilineValue, ""), mode)
+		if err := setVectorField(&vector, currentFeature, multilineKey, strings.Join(multilineValue, ""), mode); err != nil {
+			return vector, newSyntaxError(path, lineNum, lastLine, "failed to read vector definition", err)
+		}
 	}
 
-	if vector.Sequence == "" && vector.SequenceFile != "" {
+	if vector.Sequence.Len() == 0 && vector.SequenceFile != "" {
 		seqPath := resolvePath(filepath.Dir(path), vector.SequenceFile)
 		name, seq, err := readSequenceFile(seqPath)
 		if err != nil {
@@ -570,13 +741,13 @@ func parseVectorYAML(path, content string) (Vector, error) {
 			vector.Name = name
 		}
 	}
-	if vector.Sequence != "" && vector.Length == 0 {
-		vector.Length = len(vector.Sequence)
+	if vector.Sequence.Len() != 0 && vector.Length == 0 {
+		vector.Length = vector.Sequence.Len()
 	}
 	return vector, nil
 }
 
-func setVectorField(vector *Vector, currentFeature *Feature, key, value, mode string) {
+func setVectorField(vector *Vector, currentFeature *Feature, key, value, mode string) error {
 	value = stripQuotes(strings.TrimSpace(value))
 	switch key {
 	case "name":
@@ -588,22 +759,31 @@ func setVectorField(vector *Vector, currentFeature *Feature, key, value, mode st
 	case "sequence_file":
 		vector.SequenceFile = value
 	case "sequence":
-		vector.Sequence = normalizeDNA(value)
+		seq, err := normalizeDNA(value)
+		if err != nil {
+			return err
+		}
+		vector.Sequence = seq
 	case "length":
 		vector.Length = parseInt(value)
 	case "insertion_site":
 		vector.InsertionSite = parseInt(value)
 	case "five_prime_junction":
-		vector.FivePrimeJunction = normalizeDNA(value)
+		vector.FivePrimeJunction = cleanDNALetters(value)
 	case "three_prime_junction":
-		vector.ThreePrimeJunction = normalizeDNA(value)
+		vector.ThreePrimeJunction = cleanDNALetters(value)
 	case "n_terminal_tag":
 		vector.NTerminalTag = value
 	case "c_terminal_tag":
 		vector.CTerminalTag = value
 	case "reading_frame":
 		vector.ReadingFrame = parseInt(value)
+	case "transl_table":
+		vector.TranslTable = parseInt(value)
+	case "host_organism":
+		vector.HostOrganism = value
 	}
+	return nil
 }
 
 func setFeatureField(feature *Feature, key, value string) {
@@ -686,7 +866,7 @@ func resolvePath(base, rel string) string {
 	return filepath.Join(base, rel)
 }
 
-func loadInsert(base seqInfo, insertFile, accession string) (seqInfo, error) {
+func loadInsert(base seqInfo, insertFile, accession, rawSequence string) (seqInfo, error) {
 	if accession != "" {
 		return fetchInsertFromNCBI(accession, base.Range)
 	}
@@ -704,8 +884,12 @@ func loadInsert(base seqInfo, insertFile, accession string) (seqInfo, error) {
 		}
 		return finalizeInsert(base)
 	}
-	if base.DNA != "" {
-		base.DNA = normalizeDNA(base.DNA)
+	if rawSequence != "" {
+		dna, err := normalizeDNA(rawSequence)
+		if err != nil {
+			return seqInfo{}, err
+		}
+		base.DNA = dna
 		if base.Source == "" {
 			base.Source = "raw"
 		}
@@ -714,17 +898,57 @@ func loadInsert(base seqInfo, insertFile, accession string) (seqInfo, error) {
 	return seqInfo{}, errors.New("provide one of --insert-accession, --insert-file, or --insert-sequence")
 }
 
+// optimizeInsert recodes info's CDS for organism's codon preferences,
+// leaving info.Protein untouched since Optimize/Refine only ever choose a
+// different synonymous codon for the same residue. restrictionSiteNames are
+// the vector's own sites, avoided during optimization so codon-optimizing
+// an insert can't introduce a spurious cut site the cloning step relies on
+// being unique to the vector backbone.
+func optimizeInsert(info seqInfo, organism string, restrictionSiteNames []string) (seqInfo, error) {
+	table, ok := codonopt.Builtin(organism)
+	if !ok {
+		return info, fmt.Errorf("unknown host organism %q", organism)
+	}
+	protein := info.Protein.String()
+	optimized := codonopt.Optimize(protein, table, codonopt.Options{})
+	optimized = codonopt.Refine(optimized, protein, table, codonopt.Constraints{
+		AvoidSites:     restrictionSiteSequences(restrictionSiteNames),
+		MaxHomopolymer: 6,
+		GCMin:          0.3,
+		GCMax:          0.65,
+	})
+	dna, err := bioseq.New(info.DNA.Name, optimized, bioseq.DNA)
+	if err != nil {
+		return info, err
+	}
+	info.DNA = dna
+	return info, nil
+}
+
+func restrictionSiteSequences(names []string) []string {
+	sites := make([]string, 0, len(names))
+	for _, name := range names {
+		if site, ok := restrictionSites[name]; ok {
+			sites = append(sites, site.Sequence)
+		}
+	}
+	return sites
+}
+
 func finalizeInsert(info seqInfo) (seqInfo, error) {
-	if info.DNA == "" {
+	if info.DNA.Len() == 0 {
 		return info, errors.New("insert sequence is empty")
 	}
-	info.DNA = normalizeDNA(info.DNA)
-	info.Protein = translate(info.DNA)
-	if len(info.DNA) > 50000 {
-		return info, fmt.Errorf("insert length %d bp exceeds 50kb limit", len(info.DNA))
+	protein, err := translateWithTable(info.DNA, info.TranslTable)
+	if err != nil {
+		return info, err
+	}
+	info.Protein = protein
+	if info.DNA.Len() > 50000 {
+		return info, fmt.Errorf("insert length %d bp exceeds 50kb limit", info.DNA.Len())
 	}
-	if len(info.DNA) > 10000 {
-		fmt.Fprintf(os.Stderr, "warning: insert length %d bp exceeds 10kb\n", len(info.DNA))
+	if info.DNA.Len() > 10000 {
+		fmt.Fprintf(os.Stderr, "warning: insert length %d bp exceeds 10kb\n", info.DNA.Len())
 	}
 	return info, nil
 }
@@ -746,19 +970,21 @@ func fetchInsertFromNCBI(accession, residueRange string) (seqInfo, error) {
 }
 
 func fetchNucleotideCDS(accession, residueRange string) (seqInfo, error) {
-	content, err := fetchGenBank("nuccore", accession)
+	raw, err := fetchGenBankCached("nuccore", accession)
 	if err != nil {
 		return seqInfo{}, err
 	}
-	name, seq, cdsInfo, err := parseGenBankCDS(content)
+	content := string(raw)
+	name, seq, cdsResult, err := parseGenBankCDS(accession, content)
 	if err != nil {
 		return seqInfo{}, err
 	}
 	info := seqInfo{
-		Name:    name,
-		DNA:     seq,
-		Protein: cdsInfo.Protein,
-		Source:  accession,
+		Name:        name,
+		DNA:         seq,
+		Protein:     cdsResult.Protein,
+		Source:      accession,
+		TranslTable: cdsResult.TranslTable,
 	}
 	if info.Name == "" {
 		info.Name = accession
@@ -776,30 +1002,44 @@ func fetchNucleotideCDS(accession, residueRange string) (seqInfo, error) {
 }
 
 func fetchProteinCDS(accession, residueRange string) (seqInfo, error) {
-	content, err := fetchGenBank("protein", accession)
+	raw, err := fetchGenBankCached("protein", accession)
 	if err != nil {
 		return seqInfo{}, err
 	}
-	proteinSeq, codedBy, err := parseProteinCodedBy(content)
+	content := string(raw)
+	rawProteinSeq, codedBy, err := parseProteinCodedBy(accession, content)
 	if err != nil {
 		return seqInfo{}, err
 	}
 	if codedBy == "" {
 		return seqInfo{}, fmt.Errorf("no coded_by field for protein %s", accession)
 	}
-	codedAcc, region, complement, err := parseCodedBy(codedBy)
+	proteinSeq, err := bioseq.New(accession, rawProteinSeq, bioseq.Protein)
 	if err != nil {
 		return seqInfo{}, err
 	}
-	nuccore, err := fetchGenBank("nuccore", codedAcc)
+	remoteFetch := func(acc string) (string, error) {
+		nuccoreRaw, err := fetchGenBankCached("nuccore", acc)
+		if err != nil {
+			return "", err
+		}
+		_, fullSeq := parseGenBankSequence(string(nuccoreRaw))
+		if fullSeq == "" {
+			return "", errors.New("no nucleotide sequence found")
+		}
+		return fullSeq, nil
+	}
+	loc, err := parseLocationTree(accession, 0, codedBy, codedBy)
 	if err != nil {
-		return seqInfo{}, err
+		loc, err = locateCodedByViaAlignment(codedBy, rawProteinSeq, remoteFetch)
+		if err != nil {
+			return seqInfo{}, err
+		}
 	}
-	_, fullSeq := parseGenBankSequence(nuccore)
-	if fullSeq == "" {
-		return seqInfo{}, errors.New("no nucleotide sequence found")
+	dna, err := extractSequenceRegion(bioseq.Sequence{}, loc, remoteFetch)
+	if err != nil {
+		return seqInfo{}, err
 	}
-	dna := extractSequenceRegion(fullSeq, region, complement)
 	info := seqInfo{
 		Name:    accession,
 		DNA:     dna,
@@ -818,45 +1058,292 @@ func fetchProteinCDS(accession, residueRange string) (seqInfo, error) {
 	return finalizeInsert(info)
 }
 
-func fetchGenBank(db, accession string) (string, error) {
-	url := fmt.Sprintf("https://eutils.ncbi.nlm.nih.gov/entrez/eutils/efetch.fcgi?db=%s&id=%s&rettype=gb&retmode=text", db, accession)
-	resp, err := http.Get(url) // #nosec G107 -- NCBI is required by spec
+// ncbiCacheConfig holds the on-disk cache settings derived from CLI flags in
+// main. fetchGenBankCached is called from deep within the insert-loading
+// call chain (fetchNucleotideCDS/fetchProteinCDS), so rather than threading
+// cache options through every intermediate signature we set this once at
+// startup, the same way the rate limiter below is a package-level singleton.
+var ncbiCacheConfig = struct {
+	dir     string
+	ttl     time.Duration
+	disable bool
+	refresh bool
+}{
+	ttl: 30 * 24 * time.Hour,
+}
+
+func defaultCacheDir() string {
+	home, err := os.UserHomeDir()
 	if err != nil {
-		return "", err
+		return filepath.Join(".benchaid", "cache")
+	}
+	return filepath.Join(home, ".benchaid", "cache")
+}
+
+func cachePath(dir, db, accession string) string {
+	return filepath.Join(dir, "ncbi", db, accession+".gb.gz")
+}
+
+// fetchGenBankCached serves db/accession from the on-disk gzip cache when its
+// mtime is within ncbiCacheConfig.ttl, otherwise issues a conditional
+// If-Modified-Since request and refreshes the cache entry on 200 (or just
+// its mtime on 304).
+func fetchGenBankCached(db, accession string) ([]byte, error) {
+	if ncbiCacheConfig.disable {
+		body, _, err := fetchGenBankHTTP(db, accession, "")
+		return body, err
+	}
+	dir := ncbiCacheConfig.dir
+	if dir == "" {
+		dir = defaultCacheDir()
+	}
+	path := cachePath(dir, db, accession)
+	cached, mtime, readErr := readCacheEntry(path)
+	haveCached := readErr == nil
+
+	if haveCached && !ncbiCacheConfig.refresh && time.Since(mtime) < ncbiCacheConfig.ttl {
+		return cached, nil
+	}
+
+	var ifModifiedSince string
+	if haveCached {
+		ifModifiedSince = mtime.UTC().Format(http.TimeFormat)
+	}
+	body, notModified, err := fetchGenBankHTTP(db, accession, ifModifiedSince)
+	if err != nil {
+		if haveCached {
+			fmt.Fprintf(os.Stderr, "warning: NCBI refresh failed, serving stale cache for %s/%s: %v\n", db, accession, err)
+			return cached, nil
+		}
+		return nil, err
+	}
+	if notModified {
+		now := time.Now()
+		_ = os.Chtimes(path, now, now)
+		return cached, nil
+	}
+	if writeErr := writeCacheEntry(path, body); writeErr != nil {
+		fmt.Fprintf(os.Stderr, "warning: failed to cache %s/%s: %v\n", db, accession, writeErr)
+	}
+	return body, nil
+}
+
+func readCacheEntry(path string) ([]byte, time.Time, error) {
+	info, err := os.Stat(path)
+	if err != nil {
+		return nil, time.Time{}, err
+	}
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, time.Time{}, err
+	}
+	defer f.Close()
+	gz, err := gzip.NewReader(f)
+	if err != nil {
+		return nil, time.Time{}, err
+	}
+	defer gz.Close()
+	data, err := io.ReadAll(gz)
+	if err != nil {
+		return nil, time.Time{}, err
+	}
+	return data, info.ModTime(), nil
+}
+
+func writeCacheEntry(path string, data []byte) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return err
+	}
+	f, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	gz := gzip.NewWriter(f)
+	if _, err := gz.Write(data); err != nil {
+		gz.Close()
+		f.Close()
+		return err
+	}
+	if err := gz.Close(); err != nil {
+		f.Close()
+		return err
+	}
+	return f.Close()
+}
+
+// tokenBucket is a minimal token-bucket rate limiter: Wait blocks until a
+// token is available, refilling at rate tokens/sec up to capacity.
+type tokenBucket struct {
+	mu       sync.Mutex
+	tokens   float64
+	capacity float64
+	rate     float64
+	last     time.Time
+}
+
+func newTokenBucket(rate float64) *tokenBucket {
+	return &tokenBucket{tokens: rate, capacity: rate, rate: rate, last: time.Now()}
+}
+
+func (b *tokenBucket) Wait() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	for {
+		now := time.Now()
+		b.tokens += now.Sub(b.last).Seconds() * b.rate
+		if b.tokens > b.capacity {
+			b.tokens = b.capacity
+		}
+		b.last = now
+		if b.tokens >= 1 {
+			b.tokens--
+			return
+		}
+		wait := time.Duration((1 - b.tokens) / b.rate * float64(time.Second))
+		b.mu.Unlock()
+		time.Sleep(wait)
+		b.mu.Lock()
+	}
+}
+
+var (
+	ncbiLimiter     *tokenBucket
+	ncbiLimiterOnce sync.Once
+)
+
+// ncbiRateLimiter enforces NCBI's e-utils guidance: 3 req/sec without an
+// API key, 10 req/sec with NCBI_API_KEY set.
+func ncbiRateLimiter() *tokenBucket {
+	ncbiLimiterOnce.Do(func() {
+		rate := 3.0
+		if os.Getenv("NCBI_API_KEY") != "" {
+			rate = 10.0
+		}
+		ncbiLimiter = newTokenBucket(rate)
+	})
+	return ncbiLimiter
+}
+
+// fetchGenBankHTTP issues the actual e-utils request, rate-limited and with
+// an optional If-Modified-Since header. The bool result reports a 304.
+func fetchGenBankHTTP(db, accession, ifModifiedSince string) ([]byte, bool, error) {
+	ncbiRateLimiter().Wait()
+	reqURL := fmt.Sprintf("https://eutils.ncbi.nlm.nih.gov/entrez/eutils/efetch.fcgi?db=%s&id=%s&rettype=gb&retmode=text", db, accession)
+	if key := os.Getenv("NCBI_API_KEY"); key != "" {
+		reqURL += "&api_key=" + url.QueryEscape(key)
+	}
+	req, err := http.NewRequest(http.MethodGet, reqURL, nil) // #nosec G107 -- NCBI is required by spec
+	if err != nil {
+		return nil, false, err
+	}
+	if ifModifiedSince != "" {
+		req.Header.Set("If-Modified-Since", ifModifiedSince)
+	}
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return nil, false, err
 	}
 	defer resp.Body.Close()
+	if resp.StatusCode == http.StatusNotModified {
+		_, _ = io.Copy(io.Discard, resp.Body)
+		return nil, true, nil
+	}
 	if resp.StatusCode != http.StatusOK {
 		body, _ := io.ReadAll(resp.Body)
-		return "", fmt.Errorf("ncbi fetch failed: %s: %s", resp.Status, strings.TrimSpace(string(body)))
+		return nil, false, fmt.Errorf("ncbi fetch failed: %s: %s", resp.Status, strings.TrimSpace(string(body)))
 	}
 	body, err := io.ReadAll(resp.Body)
 	if err != nil {
-		return "", err
+		return nil, false, err
 	}
-	return string(body), nil
+	return body, false, nil
+}
+
+func pruneCache(dir string, ttl time.Duration) (int, error) {
+	ncbiDir := filepath.Join(dir, "ncbi")
+	removed := 0
+	err := filepath.Walk(ncbiDir, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			if os.IsNotExist(err) {
+				return nil
+			}
+			return err
+		}
+		if info.IsDir() {
+			return nil
+		}
+		if time.Since(info.ModTime()) > ttl {
+			if rmErr := os.Remove(path); rmErr != nil {
+				return rmErr
+			}
+			removed++
+		}
+		return nil
+	})
+	if err != nil && os.IsNotExist(err) {
+		return removed, nil
+	}
+	return removed, err
+}
+
+func runCachePrune(args []string) {
+	fs := flag.NewFlagSet("cache prune", flag.ExitOnError)
+	cacheDir := fs.String("cache-dir", "", "Cache directory (default ~/.benchaid/cache)")
+	cacheTTL := fs.Duration("cache-ttl", 30*24*time.Hour, "Remove cached entries older than this")
+	fs.Parse(args)
+
+	dir := *cacheDir
+	if dir == "" {
+		dir = defaultCacheDir()
+	}
+	removed, err := pruneCache(dir, *cacheTTL)
+	if err != nil {
+		fatalf("cache prune failed: %v", err)
+	}
+	fmt.Fprintf(os.Stderr, "Pruned %d cached entries from %s\n", removed, dir)
 }
 
 type cdsInfo struct {
-	DNA     string
-	Protein string
+	DNA         bioseq.Sequence
+	Protein     bioseq.Sequence
+	TranslTable int
 }
 
-func parseGenBankCDS(content string) (string, string, cdsInfo, error) {
-	name, sequence := parseGenBankSequence(content)
-	if sequence == "" {
-		return "", "", cdsInfo{}, errors.New("no sequence found in GenBank")
+func parseGenBankCDS(file, content string) (string, bioseq.Sequence, cdsInfo, error) {
+	name, rawSequence := parseGenBankSequence(content)
+	if rawSequence == "" {
+		return "", bioseq.Sequence{}, cdsInfo{}, newSyntaxError(file, totalLines(content), lastNonEmptyLine(content), "no sequence found in GenBank", nil)
+	}
+	sequence, err := normalizeDNA(rawSequence)
+	if err != nil {
+		return "", bioseq.Sequence{}, cdsInfo{}, newSyntaxError(file, totalLines(content), lastNonEmptyLine(content), "invalid sequence in GenBank ORIGIN", err)
+	}
+	cdsFeatures, err := parseCDSFeatures(file, content)
+	if err != nil {
+		return "", bioseq.Sequence{}, cdsInfo{}, err
 	}
-	cdsFeatures := parseCDSFeatures(content)
 	if len(cdsFeatures) == 0 {
-		return name, sequence, cdsInfo{DNA: sequence, Protein: translate(sequence)}, nil
+		protein, err := translate(sequence)
+		if err != nil {
+			return "", bioseq.Sequence{}, cdsInfo{}, err
+		}
+		return name, sequence, cdsInfo{DNA: sequence, Protein: protein}, nil
 	}
 	cds := cdsFeatures[0]
-	dna := extractSequenceRegion(sequence, cds.Segments, cds.Complement)
-	protein := cds.Translation
-	if protein == "" {
-		protein = translate(dna)
+	dna, err := extractSequenceRegion(sequence, cds.Location, nil)
+	if err != nil {
+		return "", bioseq.Sequence{}, cdsInfo{}, err
 	}
-	return name, dna, cdsInfo{DNA: dna, Protein: protein}, nil
+	var protein bioseq.Sequence
+	if cds.Translation != "" {
+		protein, err = bioseq.New(name, cds.Translation, bioseq.Protein)
+	} else {
+		protein, err = translateWithTable(dna, cds.TranslTable)
+	}
+	if err != nil {
+		return "", bioseq.Sequence{}, cdsInfo{}, err
+	}
+	return name, dna, cdsInfo{DNA: dna, Protein: protein, TranslTable: cds.TranslTable}, nil
 }
 
 func parseGenBankSequence(content string) (string, string) {
@@ -893,22 +1380,19 @@ func parseGenBankSequence(content string) (string, string) {
 }
 
 type cdsFeature struct {
-	Segments    []segment
-	Complement  bool
+	Location    Location
 	Translation string
+	TranslTable int
 }
 
-type segment struct {
-	Start int
-	End   int
-}
-
-func parseCDSFeatures(content string) []cdsFeature {
+func parseCDSFeatures(file, content string) ([]cdsFeature, error) {
 	var features []cdsFeature
 	scanner := bufio.NewScanner(strings.NewReader(content))
 	var current *cdsFeature
 	var inFeatures bool
+	lineNum := 0
 	for scanner.Scan() {
+		lineNum++
 		line := scanner.Text()
 		if strings.HasPrefix(line, "FEATURES") {
 			inFeatures = true
@@ -922,8 +1406,11 @@ func parseCDSFeatures(content string) []cdsFeature {
 		}
 		if strings.HasPrefix(line, "     CDS") {
 			loc := strings.TrimSpace(line[8:])
-			segments, complement := parseLocation(loc)
-			feature := cdsFeature{Segments: segments, Complement: complement}
+			tree, err := parseLocationTree(file, lineNum, line, loc)
+			if err != nil {
+				return nil, err
+			}
+			feature := cdsFeature{Location: tree}
 			features = append(features, feature)
 			current = &features[len(features)-1]
 			continue
@@ -934,18 +1421,30 @@ func parseCDSFeatures(content string) []cdsFeature {
 			current.Translation = translation
 		} else if current != nil && current.Translation != "" && strings.HasPrefix(strings.TrimSpace(line), "\"") {
 			current.Translation += strings.Trim(strings.TrimSpace(line), "\"")
+		} else if current != nil && strings.Contains(line, "/transl_table=") {
+			raw := strings.TrimSpace(strings.TrimPrefix(strings.TrimSpace(line), "/transl_table="))
+			if table, err := strconv.Atoi(raw); err == nil {
+				current.TranslTable = table
+			}
 		}
 	}
-	return features
+	if err := scanner.Err(); err != nil {
+		return nil, newSyntaxError(file, lineNum, "", "failed to read GenBank features", err)
+	}
+	return features, nil
 }
 
-func parseProteinCodedBy(content string) (string, string, error) {
+func parseProteinCodedBy(file, content string) (string, string, error) {
 	var proteinSeq string
 	var codedBy string
 	scanner := bufio.NewScanner(strings.NewReader(content))
 	var inOrigin bool
+	lineNum := 0
+	var lastLine string
 	for scanner.Scan() {
+		lineNum++
 		line := scanner.Text()
+		lastLine = line
 		if strings.HasPrefix(line, "ORIGIN") {
 			inOrigin = true
 			continue
@@ -969,117 +1468,385 @@ func parseProteinCodedBy(content string) (string, string, error) {
 			codedBy += strings.Trim(strings.TrimSpace(line), "\"")
 		}
 	}
+	if err := scanner.Err(); err != nil {
+		return "", "", newSyntaxError(file, lineNum, lastLine, "failed to read protein record", err)
+	}
 	if proteinSeq == "" {
-		return "", "", errors.New("no protein sequence found")
+		return "", "", newSyntaxError(file, lineNum, lastLine, "no protein sequence found", nil)
 	}
 	return proteinSeq, codedBy, nil
 }
 
-func parseCodedBy(value string) (string, []segment, bool, error) {
-	clean := strings.TrimSpace(value)
-	accession := ""
-	complement := false
-	for strings.HasPrefix(clean, "complement(") && strings.HasSuffix(clean, ")") {
-		complement = true
-		clean = strings.TrimSuffix(strings.TrimPrefix(clean, "complement("), ")")
+// locateCodedByViaAlignment is the fallback fetchProteinCDS reaches for when
+// a /coded_by qualifier doesn't parse as a location tree (e.g. a malformed
+// complement(join(...)) spanning mRNA boundaries): it pulls out the
+// accession codedBy references, fetches that record's full sequence, and
+// uses Smith-Waterman across all three forward reading frames to find where
+// the protein actually lines up, rather than giving up entirely.
+func locateCodedByViaAlignment(codedBy, protein string, remoteFetch func(string) (string, error)) (Location, error) {
+	accession := codedByAccession(codedBy)
+	if accession == "" {
+		return nil, fmt.Errorf("could not find an accession in coded_by %q", codedBy)
+	}
+	nucleotide, err := remoteFetch(accession)
+	if err != nil {
+		return nil, err
 	}
-	if strings.HasPrefix(clean, "join(") && strings.HasSuffix(clean, ")") {
-		clean = strings.TrimSuffix(strings.TrimPrefix(clean, "join("), ")")
+	table := bioseq.StandardTable()
+	var best align.Alignment
+	bestFrame := -1
+	for frame := 0; frame < 3; frame++ {
+		translated := translateFrameLoose(nucleotide, frame, table)
+		aln := align.SmithWaterman(protein, translated, align.BLOSUM62, align.GapPenalty{Open: 10, Extend: 1})
+		if bestFrame == -1 || aln.Score > best.Score {
+			best, bestFrame = aln, frame
+		}
 	}
-	parts := strings.Split(clean, ",")
-	var segments []segment
-	for _, part := range parts {
-		part = strings.TrimSpace(part)
-		if part == "" {
+	if bestFrame == -1 || best.Identity < 50 {
+		return nil, fmt.Errorf("could not locate CDS for coded_by %q by alignment", codedBy)
+	}
+	return Remote{
+		Accession: accession,
+		Inner: Range{
+			Start: bestFrame + best.BStart*3 + 1,
+			End:   bestFrame + best.BEnd*3,
+		},
+	}, nil
+}
+
+// codedByAccession pulls the first NCBI-style accession (letters, optional
+// underscore, digits, optional version) out of a coded_by qualifier.
+func codedByAccession(codedBy string) string {
+	var b strings.Builder
+	inToken := false
+	for i := 0; i < len(codedBy); i++ {
+		ch := codedBy[i]
+		isAccessionChar := (ch >= 'A' && ch <= 'Z') || (ch >= '0' && ch <= '9') || ch == '_' || ch == '.'
+		if isAccessionChar {
+			b.WriteByte(ch)
+			inToken = true
 			continue
 		}
-		loc := part
-		if idx := strings.Index(part, ":"); idx != -1 {
-			if accession == "" {
-				accession = strings.TrimSpace(part[:idx])
-			}
-			loc = part[idx+1:]
+		if inToken {
+			break
 		}
-		segParts, segComplement := parseLocation(loc)
-		if segComplement {
-			complement = true
+	}
+	token := strings.Trim(b.String(), ".")
+	if token == "" || !strings.ContainsAny(token, "0123456789") {
+		return ""
+	}
+	return token
+}
+
+// translateFrameLoose translates nuc starting at frame, mapping any codon
+// the table doesn't recognize to 'X' instead of erroring — used only to
+// locate a CDS by alignment, where a best-effort translation across a long
+// mRNA is more useful than a hard failure on the first ambiguous base.
+func translateFrameLoose(nuc string, frame int, table bioseq.CodonTable) string {
+	var b strings.Builder
+	for i := frame; i+3 <= len(nuc); i += 3 {
+		aa, ok := table.Codons[nuc[i:i+3]]
+		if !ok {
+			aa = 'X'
 		}
-		segments = append(segments, segParts...)
+		b.WriteByte(aa)
 	}
-	if accession == "" {
-		return "", nil, false, errors.New("coded_by missing accession")
+	return b.String()
+}
+
+// Location is a parsed GenBank feature location: a bare/fuzzy range, a
+// join/order of sub-locations, a complement, a remote (cross-record)
+// reference, or an assembly gap. Evaluate walks the tree and resolves it
+// against a sequence, fetching remote records through remoteFetch as needed.
+type Location interface {
+	Evaluate(seq string, remoteFetch func(accession string) (string, error)) (string, error)
+}
+
+// Range is a simple (possibly fuzzy-ended) span, 1-based inclusive. A
+// single-base site such as "123" is represented as Start == End.
+type Range struct {
+	Start, End            int
+	FuzzyLeft, FuzzyRight bool
+}
+
+func (r Range) Evaluate(seq string, _ func(string) (string, error)) (string, error) {
+	start := r.Start - 1
+	end := r.End
+	if start < 0 || end > len(seq) || start >= end {
+		return "", fmt.Errorf("location range %d..%d out of bounds for sequence length %d", r.Start, r.End, len(seq))
 	}
-	if len(segments) == 0 {
-		return "", nil, false, errors.New("coded_by has no segments")
+	return seq[start:end], nil
+}
+
+// Join concatenates its parts in order, e.g. a spliced CDS's exons.
+type Join struct {
+	Parts []Location
+}
+
+func (j Join) Evaluate(seq string, remoteFetch func(string) (string, error)) (string, error) {
+	var b strings.Builder
+	for _, part := range j.Parts {
+		s, err := part.Evaluate(seq, remoteFetch)
+		if err != nil {
+			return "", err
+		}
+		b.WriteString(s)
 	}
-	return accession, segments, complement, nil
+	return b.String(), nil
 }
 
-func parseLocation(loc string) ([]segment, bool) {
-	clean := strings.TrimSpace(loc)
-	complement := false
-	if strings.HasPrefix(clean, "complement(") && strings.HasSuffix(clean, ")") {
-		complement = true
-		clean = strings.TrimPrefix(clean, "complement(")
-		clean = strings.TrimSuffix(clean, ")")
+// Order is like Join but makes no claim the parts are biologically
+// contiguous; for sequence extraction it concatenates the same way.
+type Order struct {
+	Parts []Location
+}
+
+func (o Order) Evaluate(seq string, remoteFetch func(string) (string, error)) (string, error) {
+	return Join(o).Evaluate(seq, remoteFetch)
+}
+
+// Complement reverse-complements its inner location's resolved sequence.
+type Complement struct {
+	Inner Location
+}
+
+func (c Complement) Evaluate(seq string, remoteFetch func(string) (string, error)) (string, error) {
+	inner, err := c.Inner.Evaluate(seq, remoteFetch)
+	if err != nil {
+		return "", err
+	}
+	return reverseComplement(inner), nil
+}
+
+// Remote is a cross-record reference, e.g. NC_000001.11:1..100. Evaluate
+// ignores the seq it's given and instead fetches Accession via remoteFetch.
+type Remote struct {
+	Accession string
+	Inner     Location
+}
+
+func (r Remote) Evaluate(_ string, remoteFetch func(string) (string, error)) (string, error) {
+	if remoteFetch == nil {
+		return "", fmt.Errorf("remote reference %s:... requires a remote fetcher", r.Accession)
 	}
-	if strings.HasPrefix(clean, "join(") && strings.HasSuffix(clean, ")") {
-		clean = strings.TrimPrefix(clean, "join(")
-		clean = strings.TrimSuffix(clean, ")")
+	remoteSeq, err := remoteFetch(r.Accession)
+	if err != nil {
+		return "", fmt.Errorf("fetch remote %s: %w", r.Accession, err)
 	}
-	parts := strings.Split(clean, ",")
-	var segments []segment
-	for _, part := range parts {
-		part = strings.TrimSpace(part)
-		if part == "" {
-			continue
+	return r.Inner.Evaluate(remoteSeq, remoteFetch)
+}
+
+// Gap represents an assembly gap of known length, e.g. gap(20).
+type Gap struct {
+	Length int
+}
+
+func (g Gap) Evaluate(string, func(string) (string, error)) (string, error) {
+	return strings.Repeat("N", g.Length), nil
+}
+
+// parseLocationTree parses a GenBank feature/coded_by location string with a
+// small recursive-descent parser covering ranges, fuzzy endpoints, single-base
+// sites, join(...)/order(...), complement(...), gap(n), and remote references
+// (accession:location). file/line/context identify where loc was read from so
+// a malformed segment is reported precisely instead of silently dropped.
+func parseLocationTree(file string, line int, context, loc string) (Location, error) {
+	p := &locationParser{file: file, line: line, context: context, s: strings.TrimSpace(loc)}
+	result, err := p.parseLocation()
+	if err != nil {
+		return nil, err
+	}
+	if p.pos != len(p.s) {
+		return nil, p.errf("unexpected trailing input: %q", p.s[p.pos:])
+	}
+	return result, nil
+}
+
+type locationParser struct {
+	file, context string
+	line          int
+	s             string
+	pos           int
+}
+
+func (p *locationParser) errf(format string, args ...interface{}) error {
+	return newSyntaxError(p.file, p.line, p.context, fmt.Sprintf(format, args...), nil)
+}
+
+func (p *locationParser) peek() byte {
+	if p.pos >= len(p.s) {
+		return 0
+	}
+	return p.s[p.pos]
+}
+
+func (p *locationParser) consumePrefix(prefix string) bool {
+	if strings.HasPrefix(p.s[p.pos:], prefix) {
+		p.pos += len(prefix)
+		return true
+	}
+	return false
+}
+
+func (p *locationParser) parseLocation() (Location, error) {
+	switch {
+	case p.consumePrefix("complement("):
+		inner, err := p.parseLocation()
+		if err != nil {
+			return nil, err
 		}
-		if strings.Contains(part, ":") {
-			part = strings.SplitN(part, ":", 2)[1]
+		if !p.consumePrefix(")") {
+			return nil, p.errf("expected ) to close complement(...)")
 		}
-		if strings.Contains(part, "..") {
-			rangeParts := strings.SplitN(part, "..", 2)
-			start := parseInt(strings.TrimLeft(rangeParts[0], "<>"))
-			end := parseInt(strings.TrimLeft(rangeParts[1], "<>"))
-			if start > 0 && end > 0 {
-				segments = append(segments, segment{Start: start, End: end})
-			}
+		return Complement{Inner: inner}, nil
+	case p.consumePrefix("join("):
+		parts, err := p.parseLocationList()
+		if err != nil {
+			return nil, err
+		}
+		if !p.consumePrefix(")") {
+			return nil, p.errf("expected ) to close join(...)")
+		}
+		return Join{Parts: parts}, nil
+	case p.consumePrefix("order("):
+		parts, err := p.parseLocationList()
+		if err != nil {
+			return nil, err
 		}
+		if !p.consumePrefix(")") {
+			return nil, p.errf("expected ) to close order(...)")
+		}
+		return Order{Parts: parts}, nil
+	case p.consumePrefix("gap("):
+		n, err := p.parseNumber()
+		if err != nil {
+			return nil, err
+		}
+		if !p.consumePrefix(")") {
+			return nil, p.errf("expected ) to close gap(...)")
+		}
+		return Gap{Length: n}, nil
 	}
-	return segments, complement
+	return p.parseRemoteOrRange()
 }
 
-func extractSequenceRegion(sequence string, segments []segment, complement bool) string {
-	var builder strings.Builder
-	for _, seg := range segments {
-		start := seg.Start - 1
-		end := seg.End
-		if start < 0 || end > len(sequence) || start >= end {
+func (p *locationParser) parseLocationList() ([]Location, error) {
+	var locs []Location
+	for {
+		loc, err := p.parseLocation()
+		if err != nil {
+			return nil, err
+		}
+		locs = append(locs, loc)
+		if p.consumePrefix(",") {
 			continue
 		}
-		builder.WriteString(sequence[start:end])
+		break
 	}
-	result := builder.String()
-	if complement {
-		result = reverseComplement(result)
+	return locs, nil
+}
+
+// parseRemoteOrRange detects an "accession:location" remote reference by
+// scanning ahead for a top-level ':' before any of ",()" — accessions never
+// contain those characters, so this disambiguates remote refs from ranges.
+func (p *locationParser) parseRemoteOrRange() (Location, error) {
+	rest := p.s[p.pos:]
+	idx := strings.IndexAny(rest, ":,()")
+	if idx >= 0 && rest[idx] == ':' {
+		accession := rest[:idx]
+		p.pos += idx + 1
+		inner, err := p.parseLocation()
+		if err != nil {
+			return nil, err
+		}
+		return Remote{Accession: accession, Inner: inner}, nil
 	}
-	return result
+	return p.parseRange()
+}
+
+func (p *locationParser) parseRange() (Location, error) {
+	startFuzzy, start, err := p.parsePoint()
+	if err != nil {
+		return nil, err
+	}
+	if p.consumePrefix("..") {
+		endFuzzy, end, err := p.parsePoint()
+		if err != nil {
+			return nil, err
+		}
+		return Range{Start: start, End: end, FuzzyLeft: startFuzzy, FuzzyRight: endFuzzy}, nil
+	}
+	return Range{Start: start, End: start}, nil
+}
+
+func (p *locationParser) parsePoint() (bool, int, error) {
+	fuzzy := false
+	if p.peek() == '<' || p.peek() == '>' {
+		fuzzy = true
+		p.pos++
+	}
+	n, err := p.parseNumber()
+	if err != nil {
+		return false, 0, err
+	}
+	return fuzzy, n, nil
+}
+
+func (p *locationParser) parseNumber() (int, error) {
+	start := p.pos
+	for p.pos < len(p.s) && p.s[p.pos] >= '0' && p.s[p.pos] <= '9' {
+		p.pos++
+	}
+	if p.pos == start {
+		return 0, p.errf("expected a number at %q", p.s[p.pos:])
+	}
+	n, err := strconv.Atoi(p.s[start:p.pos])
+	if err != nil {
+		return 0, p.errf("invalid number: %v", err)
+	}
+	return n, nil
 }
 
-func applyResidueRange(cds, protein, rangeStr string) (string, string, error) {
-	if protein == "" {
-		protein = translate(cds)
+// extractSequenceRegion resolves loc against seq, fetching any remote
+// references through remoteFetch (nil if the location is known to be local),
+// and wraps the result back into a bioseq.Sequence.
+func extractSequenceRegion(seq bioseq.Sequence, loc Location, remoteFetch func(accession string) (string, error)) (bioseq.Sequence, error) {
+	if loc == nil {
+		return bioseq.Sequence{}, nil
 	}
-	start, end, err := parseResidueRange(rangeStr, len(protein))
+	resolved, err := loc.Evaluate(seq.String(), remoteFetch)
 	if err != nil {
-		return "", "", err
+		return bioseq.Sequence{}, err
+	}
+	return normalizeDNA(resolved)
+}
+
+func applyResidueRange(cds, protein bioseq.Sequence, rangeStr string) (bioseq.Sequence, bioseq.Sequence, error) {
+	if protein.Len() == 0 {
+		p, err := translate(cds)
+		if err != nil {
+			return bioseq.Sequence{}, bioseq.Sequence{}, err
+		}
+		protein = p
+	}
+	start, end, err := parseResidueRange(rangeStr, protein.Len())
+	if err != nil {
+		return bioseq.Sequence{}, bioseq.Sequence{}, err
 	}
 	dnaStart := (start - 1) * 3
 	dnaEnd := end * 3
-	if dnaStart < 0 || dnaEnd > len(cds) {
-		return "", "", fmt.Errorf("residue range %s out of bounds for CDS length", rangeStr)
+	if dnaStart < 0 || dnaEnd > cds.Len() {
+		return bioseq.Sequence{}, bioseq.Sequence{}, fmt.Errorf("residue range %s out of bounds for CDS length", rangeStr)
+	}
+	dnaRange, err := cds.Subseq(dnaStart, dnaEnd)
+	if err != nil {
+		return bioseq.Sequence{}, bioseq.Sequence{}, err
 	}
-	return cds[dnaStart:dnaEnd], protein[start-1 : end], nil
+	proteinRange, err := protein.Subseq(start-1, end)
+	if err != nil {
+		return bioseq.Sequence{}, bioseq.Sequence{}, err
+	}
+	return dnaRange, proteinRange, nil
 }
 
 func parseResidueRange(rangeStr string, max int) (int, int, error) {
@@ -1107,11 +1874,26 @@ func buildConstruct(vector Vector, insert seqInfo, method string) (Construct, Va
 	if vector.InsertionSite <= 0 {
 		return Construct{}, ValidationResult{}, errors.New("vector insertion_site is missing")
 	}
-	constructSeq, err := assembleConstruct(vector, insert.DNA, method)
+	constructSeqRaw, err := assembleConstruct(vector, insert.DNA.String(), method)
+	if err != nil {
+		return Construct{}, ValidationResult{}, err
+	}
+	constructSeq, err := normalizeDNA(constructSeqRaw)
 	if err != nil {
 		return Construct{}, ValidationResult{}, err
 	}
-	features := buildFeatures(vector, insert, len(insert.DNA))
+	features := buildFeatures(vector, insert, insert.DNA.Len())
+
+	var primerPairs []primers.PrimerPair
+	if isOverlapMethod(method) {
+		pair, perr := designInsertPrimers(vector, insert)
+		if perr != nil {
+			return Construct{}, ValidationResult{}, perr
+		}
+		primerPairs = []primers.PrimerPair{pair}
+		features = append(features, buildPrimerFeatures(pair, vector.InsertionSite, insert.DNA.Len())...)
+	}
+
 	name := sanitizeName(fmt.Sprintf("%s_%s", vector.Name, insert.Name))
 	desc := fmt.Sprintf("%s in %s vector", insert.Name, vector.Name)
 	if insert.Range != "" {
@@ -1121,20 +1903,23 @@ func buildConstruct(vector Vector, insert seqInfo, method string) (Construct, Va
 		desc = fmt.Sprintf("%s-%s", vector.NTerminalTag, desc)
 	}
 	construct := Construct{
-		Name:            name,
-		Description:     desc,
-		Sequence:        constructSeq,
-		Length:          len(constructSeq),
-		Vector:          vector.Name,
-		InsertName:      insert.Name,
-		InsertSource:    insert.Source,
-		InsertRange:     insert.Range,
-		CloningMethod:   method,
-		Features:        features,
-		ExpectedProtein: "",
-		InsertProtein:   insert.Protein,
-		InsertDNA:       insert.DNA,
-		CreatedDate:     time.Now().Format("02-Jan-2006"),
+		Name:                name,
+		Description:         desc,
+		Sequence:            constructSeq,
+		Length:              constructSeq.Len(),
+		Vector:              vector.Name,
+		InsertName:          insert.Name,
+		InsertSource:        insert.Source,
+		InsertRange:         insert.Range,
+		CloningMethod:       method,
+		Features:            features,
+		ExpectedProtein:     "",
+		InsertProtein:       insert.Protein.String(),
+		InsertDNA:           insert.DNA.String(),
+		CreatedDate:         time.Now().Format("02-Jan-2006"),
+		Primers:             primerPairs,
+		NTerminalTag:        vector.NTerminalTag,
+		VectorInsertionSite: vector.InsertionSite,
 	}
 
 	validation := validateConstruct(vector, construct, insert)
@@ -1143,13 +1928,14 @@ func buildConstruct(vector Vector, insert seqInfo, method string) (Construct, Va
 }
 
 func assembleConstruct(vector Vector, insert string, method string) (string, error) {
+	vectorSeq := vector.Sequence.String()
 	cutIndex := vector.InsertionSite - 1
-	if cutIndex < 0 || cutIndex > len(vector.Sequence) {
-		return "", fmt.Errorf("insertion_site %d outside vector length %d", vector.InsertionSite, len(vector.Sequence))
+	if cutIndex < 0 || cutIndex > len(vectorSeq) {
+		return "", fmt.Errorf("insertion_site %d outside vector length %d", vector.InsertionSite, len(vectorSeq))
 	}
 	switch strings.ToLower(method) {
 	case "lic", "gibson", "slic":
-		return vector.Sequence[:cutIndex] + insert + vector.Sequence[cutIndex:], nil
+		return vectorSeq[:cutIndex] + insert + vectorSeq[cutIndex:], nil
 	case "restriction":
 		if len(vector.RestrictionSites) < 2 {
 			return "", errors.New("restriction cloning requires two restriction sites in vector definition")
@@ -1160,7 +1946,91 @@ func assembleConstruct(vector Vector, insert string, method string) (string, err
 	}
 }
 
+// isOverlapMethod reports whether method is one of the overlap-dependent
+// cloning methods assembleConstruct splices in silico (lic/gibson/slic),
+// as opposed to restriction cloning, which has no PCR step to design
+// primers for.
+func isOverlapMethod(method string) bool {
+	switch strings.ToLower(method) {
+	case "lic", "gibson", "slic":
+		return true
+	default:
+		return false
+	}
+}
+
+// designInsertPrimers designs the amplification primer pair for splicing
+// insert into vector at vector.InsertionSite, using the vector sequence on
+// either side of the cut as the homology-overhang source.
+func designInsertPrimers(vector Vector, insert seqInfo) (primers.PrimerPair, error) {
+	opts := primers.DefaultOptions()
+	fiveFlank, threeFlank := vectorFlanks(vector, opts.OverhangMaxLen)
+	return primers.Design(fiveFlank, insert.DNA.String(), threeFlank, opts)
+}
+
+// vectorFlanks returns up to maxLen bases of vector sequence immediately
+// before and after the insertion site, for use as homology-overhang
+// source material.
+func vectorFlanks(vector Vector, maxLen int) (fiveFlank, threeFlank string) {
+	vectorSeq := vector.Sequence.String()
+	cutIndex := vector.InsertionSite - 1
+	start := cutIndex - maxLen
+	if start < 0 {
+		start = 0
+	}
+	if cutIndex > len(vectorSeq) {
+		cutIndex = len(vectorSeq)
+	}
+	fiveFlank = vectorSeq[start:cutIndex]
+	end := cutIndex + maxLen
+	if end > len(vectorSeq) {
+		end = len(vectorSeq)
+	}
+	threeFlank = vectorSeq[cutIndex:end]
+	return fiveFlank, threeFlank
+}
+
+// buildPrimerFeatures turns a designed PrimerPair into primer_bind
+// features located against the assembled construct, so the primers show
+// up alongside the insert/tag features in the GenBank output.
+func buildPrimerFeatures(pair primers.PrimerPair, insertionSite, insertLength int) []Feature {
+	fwdOverhangLen := len(pair.Forward.Sequence) - len(pair.Forward.AnnealingSequence)
+	fwdStart := insertionSite - fwdOverhangLen
+	fwdEnd := insertionSite + len(pair.Forward.AnnealingSequence) - 1
+
+	insertEnd := insertionSite + insertLength - 1
+	revOverhangLen := len(pair.Reverse.Sequence) - len(pair.Reverse.AnnealingSequence)
+	revStart := insertEnd - len(pair.Reverse.AnnealingSequence) + 1
+	revEnd := insertEnd + revOverhangLen
+
+	return []Feature{
+		{
+			Name:   "Forward primer",
+			Type:   "primer_bind",
+			Start:  fwdStart,
+			End:    fwdEnd,
+			Strand: "+",
+			Qualifiers: map[string]string{
+				"label": "Forward primer",
+				"note":  fmt.Sprintf("%s; Tm %.1fC anneal / %.1fC overhang", pair.Forward.Sequence, pair.Forward.Tm, pair.Forward.OverhangTm),
+			},
+		},
+		{
+			Name:   "Reverse primer",
+			Type:   "primer_bind",
+			Start:  revStart,
+			End:    revEnd,
+			Strand: "-",
+			Qualifiers: map[string]string{
+				"label": "Reverse primer",
+				"note":  fmt.Sprintf("%s; Tm %.1fC anneal / %.1fC overhang", pair.Reverse.Sequence, pair.Reverse.Tm, pair.Reverse.OverhangTm),
+			},
+		},
+	}
+}
+
 func assembleRestriction(vector Vector, insert string) (string, error) {
+	vectorSeq := vector.Sequence.String()
 	site5, ok := restrictionSites[vector.RestrictionSites[0]]
 	if !ok {
 		return "", fmt.Errorf("unknown restriction site: %s", vector.RestrictionSites[0])
@@ -1169,16 +2039,16 @@ func assembleRestriction(vector Vector, insert string) (string, error) {
 	if !ok {
 		return "", fmt.Errorf("unknown restriction site: %s", vector.RestrictionSites[1])
 	}
-	pos5 := strings.Index(vector.Sequence, site5.Sequence)
-	pos3 := strings.Index(vector.Sequence, site3.Sequence)
+	pos5 := strings.Index(vectorSeq, site5.Sequence)
+	pos3 := strings.Index(vectorSeq, site3.Sequence)
 	if pos5 == -1 || pos3 == -1 {
 		return "", errors.New("restriction sites not found in vector sequence")
 	}
 	if pos5 >= pos3 {
 		return "", errors.New("restriction site order is invalid")
 	}
-	vector5 := vector.Sequence[:pos5+site5.CutIndex]
-	vector3 := vector.Sequence[pos3+site3.CutIndex:]
+	vector5 := vectorSeq[:pos5+site5.CutIndex]
+	vector3 := vectorSeq[pos3+site3.CutIndex:]
 	return vector5 + insert + vector3, nil
 }
 
@@ -1216,8 +2086,11 @@ func buildFeatures(vector Vector, insert seqInfo, insertLength int) []Feature {
 		}
 		insertFeature.Qualifiers["note"] = note
 	}
-	if insert.Protein != "" {
-		insertFeature.Qualifiers["translation"] = insert.Protein
+	if insert.Protein.Len() != 0 {
+		insertFeature.Qualifiers["translation"] = insert.Protein.String()
+	}
+	if insert.TranslTable != 0 && insert.TranslTable != 1 {
+		insertFeature.Qualifiers["transl_table"] = strconv.Itoa(insert.TranslTable)
 	}
 	features = append(features, insertFeature)
 
@@ -1233,8 +2106,11 @@ func buildFeatures(vector Vector, insert seqInfo, insertLength int) []Feature {
 				"label": fmt.Sprintf("%s-%s", tagFeature.Name, insert.Name),
 			},
 		}
-		if insert.Protein != "" && vector.NTerminalTag != "" {
-			fusion.Qualifiers["translation"] = vector.NTerminalTag + insert.Protein
+		if insert.Protein.Len() != 0 && vector.NTerminalTag != "" {
+			fusion.Qualifiers["translation"] = vector.NTerminalTag + insert.Protein.String()
+		}
+		if insert.TranslTable != 0 && insert.TranslTable != 1 {
+			fusion.Qualifiers["transl_table"] = strconv.Itoa(insert.TranslTable)
 		}
 		features = append(features, fusion)
 	}
@@ -1256,43 +2132,352 @@ func validateConstruct(vector Vector, construct Construct, insert seqInfo) Valid
 	var warnings []string
 	var errorsList []string
 
-	if insert.DNA == "" {
+	if insert.DNA.Len() == 0 {
 		errorsList = append(errorsList, "insert sequence is empty")
 	}
 	if vector.ReadingFrame >= 0 {
 		if (vector.InsertionSite-1)%3 != vector.ReadingFrame {
 			warnings = append(warnings, fmt.Sprintf("insertion site frame mismatch (expected frame %d)", vector.ReadingFrame))
 		}
-		if len(insert.DNA)%3 != 0 {
+		if insert.DNA.Len()%3 != 0 {
 			warnings = append(warnings, "insert length is not a multiple of 3")
 		}
 	}
-	if vector.NTerminalTag == "" && !strings.HasPrefix(insert.DNA, "ATG") {
-		warnings = append(warnings, "insert does not start with ATG and no N-terminal tag provided")
+	if vector.NTerminalTag == "" && !hasValidStartCodon(insert.DNA.String(), insert.TranslTable) {
+		warnings = append(warnings, "insert does not start with a valid start codon for its genetic code and no N-terminal tag provided")
 	}
 
-	if hasInternalStop(insert.Protein) {
+	insertProtein := insert.Protein.String()
+	if hasInternalStop(insertProtein) {
 		warnings = append(warnings, "insert protein contains internal stop codon")
 	}
-	fusionProtein := insert.Protein
+	fusionProtein := insertProtein
 	if isAminoAcidSequence(vector.NTerminalTag) {
-		fusionProtein = vector.NTerminalTag + insert.Protein
+		fusionProtein = vector.NTerminalTag + insertProtein
 	}
 	if hasInternalStop(fusionProtein) {
 		warnings = append(warnings, "fusion protein contains internal stop codon")
 	}
 
+	if insert.Protein.Len() != 0 && insert.DNA.Len() != 0 {
+		if recomputed, err := translateWithTable(insert.DNA, insert.TranslTable); err == nil {
+			aln := align.NeedlemanWunsch(insertProtein, recomputed.String(), align.BLOSUM62, align.GapPenalty{Open: 10, Extend: 1})
+			if aln.Identity < 100 {
+				warnings = append(warnings, fmt.Sprintf(
+					"insert protein does not match a direct translation of the insert DNA (identity %.1f%%, first mismatch at residue %d)",
+					aln.Identity, aln.MismatchPosition()+1))
+			}
+		}
+	}
+
 	return ValidationResult{
 		Valid:             len(errorsList) == 0,
 		Warnings:          warnings,
 		Errors:            errorsList,
 		FusionProtein:     fusionProtein,
 		FusionLengthAA:    len(fusionProtein),
-		ConstructLengthBP: len(construct.Sequence),
+		ConstructLengthBP: construct.Sequence.Len(),
+	}
+}
+
+// OutputFormat is a pluggable construct output format, registered under a
+// short name in outputFormats so a single invocation can emit one file per
+// requested format (or stream one to stdout via --stdout).
+type OutputFormat interface {
+	Name() string
+	Extension() string
+	Write(w io.Writer, c Construct, v ValidationResult) error
+}
+
+var outputFormats = map[string]func() OutputFormat{}
+
+func init() {
+	outputFormats["gb"] = func() OutputFormat { return genbankFormat{} }
+	outputFormats["gbk"] = func() OutputFormat { return genbankFormat{} }
+	outputFormats["fasta"] = func() OutputFormat { return fastaFormat{} }
+	outputFormats["csv"] = func() OutputFormat { return csvFormat{} }
+	outputFormats["sbol"] = func() OutputFormat { return sbolFormat{} }
+	outputFormats["json"] = func() OutputFormat { return jsonFormat{} }
+	outputFormats["jsonl"] = func() OutputFormat { return jsonlFormat{} }
+	outputFormats["gff3"] = func() OutputFormat { return gff3Format{} }
+	outputFormats["vcf"] = func() OutputFormat { return vcfFormat{} }
+}
+
+func formatByName(name string) (OutputFormat, error) {
+	ctor, ok := outputFormats[strings.ToLower(strings.TrimSpace(name))]
+	if !ok {
+		return nil, fmt.Errorf("unknown output format: %s", name)
+	}
+	return ctor(), nil
+}
+
+type genbankFormat struct{}
+
+func (genbankFormat) Name() string      { return "gb" }
+func (genbankFormat) Extension() string { return ".gb" }
+
+func (genbankFormat) Write(w io.Writer, c Construct, _ ValidationResult) error {
+	_, err := w.Write(buildGenBank(c))
+	return err
+}
+
+type fastaFormat struct{}
+
+func (fastaFormat) Name() string      { return "fasta" }
+func (fastaFormat) Extension() string { return ".fasta" }
+
+func (fastaFormat) Write(w io.Writer, c Construct, _ ValidationResult) error {
+	var buf bytes.Buffer
+	fmt.Fprintf(&buf, ">%s %s\n", c.Name, c.Description)
+	writeFastaBody(&buf, c.Sequence.String())
+	for _, feat := range c.Features {
+		seq := featureSequence(c.Sequence.String(), feat)
+		if seq == "" {
+			continue
+		}
+		label := feat.Name
+		if label == "" {
+			label = feat.Type
+		}
+		fmt.Fprintf(&buf, ">%s_%s\n", c.Name, sanitizeName(label))
+		writeFastaBody(&buf, seq)
+	}
+	_, err := w.Write(buf.Bytes())
+	return err
+}
+
+func writeFastaBody(buf *bytes.Buffer, seq string) {
+	for i := 0; i < len(seq); i += 70 {
+		end := i + 70
+		if end > len(seq) {
+			end = len(seq)
+		}
+		fmt.Fprintln(buf, seq[i:end])
+	}
+}
+
+// featureSequence extracts the sequence region for a feature carrying
+// 1-based Start/End coordinates; fusion features built only from a
+// join(...) Location string (no Start/End) are skipped.
+func featureSequence(sequence string, feat Feature) string {
+	if feat.Start <= 0 || feat.End <= 0 || feat.End > len(sequence) || feat.Start > feat.End {
+		return ""
+	}
+	seq := sequence[feat.Start-1 : feat.End]
+	if feat.Strand == "-" {
+		seq = reverseComplement(seq)
+	}
+	return seq
+}
+
+type csvFormat struct{}
+
+func (csvFormat) Name() string      { return "csv" }
+func (csvFormat) Extension() string { return ".csv" }
+
+func (csvFormat) Write(w io.Writer, c Construct, v ValidationResult) error {
+	var buf bytes.Buffer
+	cw := csv.NewWriter(&buf)
+	if err := cw.Write([]string{"name", "length", "vector", "insert", "cloning_method", "tag", "expected_protein", "warnings"}); err != nil {
+		return err
+	}
+	row := []string{
+		c.Name,
+		strconv.Itoa(c.Length),
+		c.Vector,
+		c.InsertName,
+		c.CloningMethod,
+		c.NTerminalTag,
+		c.ExpectedProtein,
+		strings.Join(v.Warnings, "; "),
+	}
+	if err := cw.Write(row); err != nil {
+		return err
+	}
+	cw.Flush()
+	if err := cw.Error(); err != nil {
+		return err
+	}
+	_, err := w.Write(buf.Bytes())
+	return err
+}
+
+type sbolFormat struct{}
+
+func (sbolFormat) Name() string      { return "sbol" }
+func (sbolFormat) Extension() string { return ".xml" }
+
+// Write emits a minimal SBOL2/RDF-XML ComponentDefinition for the construct,
+// with sub-components for the vector backbone, the N-terminal tag (if any),
+// and the insert. It's not a full SBOL toolchain round-trip, just enough
+// structure for downstream SBOL-aware tools to pick up the pieces.
+func (sbolFormat) Write(w io.Writer, c Construct, _ ValidationResult) error {
+	var buf bytes.Buffer
+	id := sanitizeName(c.Name)
+	fmt.Fprintf(&buf, "<?xml version=\"1.0\" encoding=\"UTF-8\"?>\n")
+	fmt.Fprintf(&buf, "<rdf:RDF xmlns:rdf=\"http://www.w3.org/1999/02/22-rdf-syntax-ns#\" xmlns:sbol=\"http://sbols.org/v2#\">\n")
+	fmt.Fprintf(&buf, "  <sbol:ComponentDefinition rdf:about=\"http://benchaid.local/%s\">\n", id)
+	fmt.Fprintf(&buf, "    <sbol:displayId>%s</sbol:displayId>\n", escapeGenBank(id))
+	fmt.Fprintf(&buf, "    <sbol:description>%s</sbol:description>\n", escapeGenBank(c.Description))
+	writeSBOLComponent(&buf, id, "backbone", c.Vector)
+	if c.NTerminalTag != "" {
+		writeSBOLComponent(&buf, id, "tag", c.NTerminalTag)
+	}
+	writeSBOLComponent(&buf, id, "insert", c.InsertName)
+	fmt.Fprintf(&buf, "  </sbol:ComponentDefinition>\n")
+	fmt.Fprintf(&buf, "</rdf:RDF>\n")
+	_, err := w.Write(buf.Bytes())
+	return err
+}
+
+func writeSBOLComponent(buf *bytes.Buffer, parentID, role, name string) {
+	compID := sanitizeName(name)
+	fmt.Fprintf(buf, "    <sbol:component>\n")
+	fmt.Fprintf(buf, "      <sbol:Component rdf:about=\"http://benchaid.local/%s/%s\">\n", parentID, role)
+	fmt.Fprintf(buf, "        <sbol:displayId>%s</sbol:displayId>\n", escapeGenBank(compID))
+	fmt.Fprintf(buf, "        <sbol:role>%s</sbol:role>\n", role)
+	fmt.Fprintf(buf, "      </sbol:Component>\n")
+	fmt.Fprintf(buf, "    </sbol:component>\n")
+}
+
+type jsonFormat struct{}
+
+func (jsonFormat) Name() string      { return "json" }
+func (jsonFormat) Extension() string { return ".json" }
+
+func (jsonFormat) Write(w io.Writer, c Construct, _ ValidationResult) error {
+	data, err := json.MarshalIndent(c, "", "  ")
+	if err != nil {
+		return err
+	}
+	data = append(data, '\n')
+	_, err = w.Write(data)
+	return err
+}
+
+type jsonlFormat struct{}
+
+func (jsonlFormat) Name() string      { return "jsonl" }
+func (jsonlFormat) Extension() string { return ".jsonl" }
+
+// Write emits one JSON object per construct feature (features-only mode),
+// for LIMS/analysis tooling that only cares about annotation coordinates.
+func (jsonlFormat) Write(w io.Writer, c Construct, _ ValidationResult) error {
+	enc := json.NewEncoder(w)
+	for _, feat := range c.Features {
+		if err := enc.Encode(feat); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+type gff3Format struct{}
+
+func (gff3Format) Name() string      { return "gff3" }
+func (gff3Format) Extension() string { return ".gff3" }
+
+// Write emits GFF3 (https://github.com/The-Sequence-Ontology/Specifications/blob/master/gff3.md)
+// with construct.Features as rows plus a trailing ##FASTA section carrying
+// the assembled sequence, so tools like IGV can load annotations and
+// sequence from a single file.
+func (gff3Format) Write(w io.Writer, c Construct, _ ValidationResult) error {
+	var buf bytes.Buffer
+	fmt.Fprintln(&buf, "##gff-version 3")
+	fmt.Fprintf(&buf, "##sequence-region %s 1 %d\n", gffSeqID(c.Name), c.Length)
+	for _, feat := range c.Features {
+		start, end := feat.Start, feat.End
+		if start <= 0 || end <= 0 {
+			continue
+		}
+		strand := "+"
+		if feat.Strand == "-" {
+			strand = "-"
+		}
+		featType := strings.ToLower(feat.Type)
+		if featType == "" {
+			featType = "region"
+		}
+		attrs := []string{fmt.Sprintf("ID=%s", gffEscape(sanitizeName(feat.Name)))}
+		if feat.Name != "" {
+			attrs = append(attrs, fmt.Sprintf("Name=%s", gffEscape(feat.Name)))
+		}
+		keys := make([]string, 0, len(feat.Qualifiers))
+		for key := range feat.Qualifiers {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+		for _, key := range keys {
+			attrs = append(attrs, fmt.Sprintf("%s=%s", key, gffEscape(feat.Qualifiers[key])))
+		}
+		fmt.Fprintf(&buf, "%s\t%s\t%s\t%d\t%d\t.\t%s\t.\t%s\n",
+			gffSeqID(c.Name), "benchaid", featType, start, end, strand, strings.Join(attrs, ";"))
+	}
+	fmt.Fprintln(&buf, "##FASTA")
+	fmt.Fprintf(&buf, ">%s\n", gffSeqID(c.Name))
+	writeFastaBody(&buf, c.Sequence.String())
+	_, err := w.Write(buf.Bytes())
+	return err
+}
+
+func gffSeqID(name string) string {
+	id := sanitizeName(name)
+	if id == "" {
+		return "construct"
+	}
+	return id
+}
+
+func gffEscape(value string) string {
+	replacer := strings.NewReplacer(";", "%3B", "=", "%3D", "&", "%26", ",", "%2C", "\t", "%09")
+	return replacer.Replace(value)
+}
+
+type vcfFormat struct{}
+
+func (vcfFormat) Name() string      { return "vcf" }
+func (vcfFormat) Extension() string { return ".vcf" }
+
+// Write expresses the construct as a VCF 4.2 record of the insert edit
+// against the parent vector. Only the insert's own splice point is known
+// post-assembly (not the vector's original flanking bases), so every
+// cloning method is recorded as a pure insertion at VectorInsertionSite:
+// REF is "." and ALT is the insert DNA, which is exact for lic/gibson/slic
+// and an approximation for restriction cloning (where a vector fragment
+// between the two cut sites is also excised).
+func (vcfFormat) Write(w io.Writer, c Construct, _ ValidationResult) error {
+	var buf bytes.Buffer
+	fmt.Fprintln(&buf, "##fileformat=VCFv4.2")
+	fmt.Fprintf(&buf, "##contig=<ID=%s,length=%d>\n", vcfChrom(c.Vector), c.Length)
+	fmt.Fprintln(&buf, `##INFO=<ID=SVTYPE,Number=1,Type=String,Description="Type of structural variant">`)
+	fmt.Fprintln(&buf, `##INFO=<ID=SVLEN,Number=1,Type=Integer,Description="Length of the insertion">`)
+	fmt.Fprintln(&buf, `##INFO=<ID=CLONING_METHOD,Number=1,Type=String,Description="Cloning method used to build the construct">`)
+	fmt.Fprintln(&buf, `##INFO=<ID=VECTOR,Number=1,Type=String,Description="Parent vector name">`)
+	fmt.Fprintln(&buf, "#CHROM\tPOS\tID\tREF\tALT\tQUAL\tFILTER\tINFO")
+	pos := c.VectorInsertionSite - 1
+	if pos < 1 {
+		pos = 1
+	}
+	id := c.InsertName
+	if id == "" {
+		id = "insert"
 	}
+	info := fmt.Sprintf("SVTYPE=INS;SVLEN=%d;CLONING_METHOD=%s;VECTOR=%s",
+		len(c.InsertDNA), c.CloningMethod, vcfChrom(c.Vector))
+	fmt.Fprintf(&buf, "%s\t%d\t%s\t.\t%s\t.\tPASS\t%s\n", vcfChrom(c.Vector), pos, sanitizeName(id), c.InsertDNA, info)
+	_, err := w.Write(buf.Bytes())
+	return err
 }
 
-func writeGenBank(construct Construct, filepath string) error {
+func vcfChrom(name string) string {
+	chrom := sanitizeName(name)
+	if chrom == "" {
+		return "vector"
+	}
+	return chrom
+}
+
+func buildGenBank(construct Construct) []byte {
 	var buf bytes.Buffer
 	date := time.Now().Format("02-Jan-2006")
 	name := construct.Name
@@ -1341,10 +2526,10 @@ func writeGenBank(construct Construct, filepath string) error {
 	}
 
 	fmt.Fprintf(&buf, "ORIGIN\n")
-	writeOrigin(&buf, construct.Sequence)
+	writeOrigin(&buf, construct.Sequence.String())
 	fmt.Fprintf(&buf, "//\n")
 
-	return os.WriteFile(filepath, buf.Bytes(), 0644)
+	return buf.Bytes()
 }
 
 func writeOrigin(buf *bytes.Buffer, sequence string) {
@@ -1384,35 +2569,34 @@ func escapeGenBank(value string) string {
 	return strings.ReplaceAll(value, "\"", "'")
 }
 
-func writeJSON(construct Construct, path string) error {
-	data, err := json.MarshalIndent(construct, "", "  ")
-	if err != nil {
-		return err
-	}
-	data = append(data, '\n')
-	return os.WriteFile(path, data, 0644)
-}
-
-func readSequenceFile(path string) (string, string, error) {
+func readSequenceFile(path string) (string, bioseq.Sequence, error) {
 	content, err := os.ReadFile(path)
 	if err != nil {
-		return "", "", err
+		return "", bioseq.Sequence{}, err
 	}
 	text := strings.TrimSpace(string(content))
 	if strings.HasPrefix(text, ">") {
-		return parseFasta(text)
+		return parseFasta(path, text)
 	}
 	if strings.Contains(text, "LOCUS") && strings.Contains(text, "ORIGIN") {
 		name, seq := parseGenBankSequence(text)
-		return name, seq, nil
+		dna, err := normalizeDNA(seq)
+		if err != nil {
+			return "", bioseq.Sequence{}, newSyntaxError(path, totalLines(text), lastNonEmptyLine(text), "invalid sequence in GenBank record", err)
+		}
+		return name, dna, nil
+	}
+	dna, err := normalizeDNA(text)
+	if err != nil {
+		return "", bioseq.Sequence{}, newSyntaxError(path, totalLines(text), lastNonEmptyLine(text), "invalid raw sequence", err)
 	}
-	return "", normalizeDNA(text), nil
+	return "", dna, nil
 }
 
-func parseFasta(content string) (string, string, error) {
+func parseFasta(file, content string) (string, bioseq.Sequence, error) {
 	lines := strings.Split(content, "\n")
-	if len(lines) == 0 {
-		return "", "", errors.New("empty FASTA")
+	if len(lines) == 0 || strings.TrimSpace(lines[0]) == "" {
+		return "", bioseq.Sequence{}, newSyntaxError(file, 1, content, "empty FASTA", nil)
 	}
 	name := strings.TrimSpace(strings.TrimPrefix(lines[0], ">"))
 	var builder strings.Builder
@@ -1423,10 +2607,31 @@ func parseFasta(content string) (string, string, error) {
 		}
 		builder.WriteString(line)
 	}
-	return name, normalizeDNA(builder.String()), nil
+	dna, err := normalizeDNA(builder.String())
+	if err != nil {
+		return "", bioseq.Sequence{}, newSyntaxError(file, len(lines), lines[len(lines)-1], "invalid FASTA sequence", err)
+	}
+	return name, dna, nil
 }
 
-func normalizeDNA(seq string) string {
+// normalizeDNA strips whitespace/numbering from a raw DNA string and wraps
+// the cleaned letters in a bioseq.Sequence, catching stray non-ACGTN
+// characters (e.g. amino acids) at the point a sequence enters the program.
+func normalizeDNA(seq string) (bioseq.Sequence, error) {
+	var builder strings.Builder
+	for _, ch := range seq {
+		if ch == ' ' || ch == '\t' || ch == '\r' || ch == '\n' || (ch >= '0' && ch <= '9') {
+			continue
+		}
+		builder.WriteRune(ch)
+	}
+	return bioseq.New("", builder.String(), bioseq.DNA)
+}
+
+// cleanDNALetters keeps only A/C/G/T/N (either case, upper-cased) and
+// silently drops every other character, returning a plain string, for
+// fields (junctions) that aren't migrated to bioseq.Sequence.
+func cleanDNALetters(seq string) string {
 	var builder strings.Builder
 	for _, ch := range seq {
 		switch ch {
@@ -1458,18 +2663,34 @@ func reverseComplement(seq string) string {
 	return builder.String()
 }
 
-func translate(dna string) string {
-	dna = normalizeDNA(dna)
-	var builder strings.Builder
-	for i := 0; i+2 < len(dna); i += 3 {
-		codon := dna[i : i+3]
-		aa, ok := codonTable[codon]
-		if !ok {
-			aa = "X"
-		}
-		builder.WriteString(aa)
+// translationTable is the genetic code translate() uses, selected via
+// --codon-table. It's a package-level singleton for the same reason
+// ncbiCacheConfig is: translate is called from deep within the insert-loading
+// call chain, and threading a table value through every intermediate
+// signature would be more disruptive than the pragmatic shared default.
+var translationTable = bioseq.StandardTable()
+
+// translate conceptually translates a multiple-of-3 DNA sequence in frame 0
+// against translationTable. An incomplete trailing codon or an unrecognized
+// codon is a hard error, unlike the old ad hoc version which silently
+// emitted 'X' for both.
+func translate(dna bioseq.Sequence) (bioseq.Sequence, error) {
+	return dna.Translate(0, translationTable)
+}
+
+// translateWithTable is translate's counterpart for sequences whose genetic
+// code is known up front, such as a GenBank CDS feature's /transl_table=
+// qualifier. tableID 0 (no qualifier present) falls back to translationTable,
+// the same default translate() uses.
+func translateWithTable(dna bioseq.Sequence, tableID int) (bioseq.Sequence, error) {
+	if tableID == 0 {
+		return translate(dna)
 	}
-	return builder.String()
+	table, ok := bioseq.Table(tableID)
+	if !ok {
+		return bioseq.Sequence{}, fmt.Errorf("unknown genetic code table %d", tableID)
+	}
+	return dna.Translate(0, table)
 }
 
 func sanitizeName(name string) string {
@@ -1481,6 +2702,22 @@ func sanitizeName(name string) string {
 	return name
 }
 
+// hasValidStartCodon reports whether dna's first codon can initiate
+// translation under tableID's genetic code (tableID 0 falls back to
+// translationTable, the same default translate()/translateWithTable use).
+func hasValidStartCodon(dna string, tableID int) bool {
+	if len(dna) < 3 {
+		return false
+	}
+	table := translationTable
+	if tableID != 0 {
+		if t, ok := bioseq.Table(tableID); ok {
+			table = t
+		}
+	}
+	return table.Starts[dna[:3]]
+}
+
 func hasInternalStop(protein string) bool {
 	if len(protein) <= 1 {
 		return false
@@ -1503,21 +2740,3 @@ func isAminoAcidSequence(seq string) bool {
 	return true
 }
 
-var codonTable = map[string]string{
-	"TTT": "F", "TTC": "F", "TTA": "L", "TTG": "L",
-	"TCT": "S", "TCC": "S", "TCA": "S", "TCG": "S",
-	"TAT": "Y", "TAC": "Y", "TAA": "*", "TAG": "*",
-	"TGT": "C", "TGC": "C", "TGA": "*", "TGG": "W",
-	"CTT": "L", "CTC": "L", "CTA": "L", "CTG": "L",
-	"CCT": "P", "CCC": "P", "CCA": "P", "CCG": "P",
-	"CAT": "H", "CAC": "H", "CAA": "Q", "CAG": "Q",
-	"CGT": "R", "CGC": "R", "CGA": "R", "CGG": "R",
-	"ATT": "I", "ATC": "I", "ATA": "I", "ATG": "M",
-	"ACT": "T", "ACC": "T", "ACA": "T", "ACG": "T",
-	"AAT": "N", "AAC": "N", "AAA": "K", "AAG": "K",
-	"AGT": "S", "AGC": "S", "AGA": "R", "AGG": "R",
-	"GTT": "V", "GTC": "V", "GTA": "V", "GTG": "V",
-	"GCT": "A", "GCC": "A", "GCA": "A", "GCG": "A",
-	"GAT": "D", "GAC": "D", "GAA": "E", "GAG": "E",
-	"GGT": "G", "GGC": "G", "GGA": "G", "GGG": "G",
-}
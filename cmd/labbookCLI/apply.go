@@ -0,0 +1,420 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// applyDocument is one Kubernetes-style document in a `registry apply -f`
+// manifest: `apiVersion: benchaid/v1`, `kind: Plasmid|ProteinPreparation|
+// Expression|Primers|CryoEMGrid`, a metadata block naming/identifying the
+// item, and a spec whose keys are the same flag names `registry create`
+// takes (without the leading `--`), e.g. `primer-sequence`, `grid-id`.
+type applyDocument struct {
+	APIVersion string                 `yaml:"apiVersion"`
+	Kind       string                 `yaml:"kind"`
+	Metadata   applyDocumentMetadata  `yaml:"metadata"`
+	Spec       map[string]interface{} `yaml:"spec"`
+}
+
+type applyDocumentMetadata struct {
+	Name      string `yaml:"name"`
+	ID        int    `yaml:"id"`
+	Workspace string `yaml:"workspace"`
+}
+
+// applyKindFlags maps a manifest `kind` onto the `--kind` value
+// parseRegistryPayloadWithID expects, since manifests use PascalCase
+// (matching the Kubernetes convention this format borrows) while the CLI's
+// own --kind flag accepts the registry's own kind strings.
+var applyKindFlags = map[string]string{
+	"Plasmid":            "Plasmid",
+	"ProteinPreparation": "Protein preparation",
+	"Expression":         "Expression",
+	"Primers":            "Primers",
+	"CryoEMGrid":         "Cryo-EM Grid",
+}
+
+// lastAppliedMetaKey stores the JSON-encoded metadata this CLI last wrote
+// to an item via `registry apply`, mirroring kubectl's
+// kubectl.kubernetes.io/last-applied-configuration annotation. It is the
+// "base" of the three-way merge (current server state, last-applied,
+// desired) that makes repeated applies idempotent: a field removed from
+// the manifest is deleted only if nothing else changed it since the last
+// apply, and a field never mentioned in the manifest but set out-of-band
+// (e.g. by `registry update-protein`) survives.
+const lastAppliedMetaKey = "_benchaidLastApplied"
+
+// applySummary is the machine-readable tally `registry apply` prints at
+// the end, so a CI pipeline seeding a dev server can check it instead of
+// scraping per-item text output.
+type applySummary struct {
+	Created   int      `json:"created"`
+	Updated   int      `json:"updated"`
+	Unchanged int      `json:"unchanged"`
+	Failed    int      `json:"failed"`
+	Pruned    int      `json:"pruned,omitempty"`
+	Errors    []string `json:"errors,omitempty"`
+}
+
+func registryApplyCmd(c client, args []string) {
+	fs := flag.NewFlagSet("registry apply", flag.ExitOnError)
+	file := fs.String("f", "", "Path to a YAML or JSON manifest (one or more --- separated documents)")
+	diff := fs.Bool("diff", false, "Print the JSON patch that would be sent instead of applying it")
+	prune := fs.Bool("prune", false, "Delete items in the selected workspace/kinds that aren't present in the manifest")
+	workspace := fs.String("workspace", c.workspace, "Workspace new items default into and --prune scopes its deletions to")
+	_ = fs.Parse(args)
+
+	if strings.TrimSpace(*file) == "" {
+		exitOnError(errors.New("-f is required"))
+	}
+	docs, err := parseApplyManifest(*file)
+	exitOnError(err)
+	if len(docs) == 0 {
+		exitOnError(errors.New("manifest contains no documents"))
+	}
+
+	summary := applySummary{}
+	appliedKinds := map[string]bool{}
+	appliedNames := map[string]bool{}
+
+	for _, doc := range docs {
+		if doc.APIVersion != "" && doc.APIVersion != "benchaid/v1" {
+			summary.Failed++
+			summary.Errors = append(summary.Errors, fmt.Sprintf("%s: unsupported apiVersion %q", doc.Metadata.Name, doc.APIVersion))
+			continue
+		}
+		kindFlag, ok := applyKindFlags[doc.Kind]
+		if !ok {
+			summary.Failed++
+			summary.Errors = append(summary.Errors, fmt.Sprintf("%s: unknown kind %q", doc.Metadata.Name, doc.Kind))
+			continue
+		}
+		if strings.TrimSpace(doc.Metadata.Workspace) == "" {
+			doc.Metadata.Workspace = *workspace
+		}
+		appliedKinds[kindFlag] = true
+
+		existing, err := resolveApplyTarget(c, doc, kindFlag)
+		if err != nil {
+			summary.Failed++
+			summary.Errors = append(summary.Errors, fmt.Sprintf("%s: %v", doc.Metadata.Name, err))
+			continue
+		}
+		appliedNames[doc.Metadata.Name] = true
+
+		desiredArgs := buildRegistryApplyArgs(doc, kindFlag)
+		_, desired := parseRegistryPayloadWithID(c, "registry apply", desiredArgs)
+
+		if existing == nil {
+			if *diff {
+				printApplyDiff(doc.Metadata.Name, nil, desired.Metadata)
+				continue
+			}
+			desired.Metadata = withLastApplied(desired.Metadata)
+			body, err := c.request("POST", "/api/registry", desired)
+			if err != nil {
+				summary.Failed++
+				summary.Errors = append(summary.Errors, fmt.Sprintf("%s: %v", doc.Metadata.Name, err))
+				continue
+			}
+			fmt.Println(string(body))
+			summary.Created++
+			continue
+		}
+
+		lastApplied := decodeLastApplied(existing.Metadata)
+		currentMeta := withoutLastApplied(existing.Metadata)
+		merged := threeWayMergeMetadata(currentMeta, lastApplied, desired.Metadata)
+
+		patch := registryPatchPayload{}
+		if existing.Name != desired.Name {
+			patch.Name = &desired.Name
+		}
+		if desired.Description != nil && (existing.Description == nil || *existing.Description != *desired.Description) {
+			patch.Description = desired.Description
+		}
+		if strings.TrimSpace(doc.Metadata.Workspace) != "" && existing.Workspace != doc.Metadata.Workspace {
+			value := doc.Metadata.Workspace
+			patch.Workspace = &value
+		}
+		if !metadataEqual(currentMeta, merged) {
+			patch.Metadata = withLastApplied(merged)
+		}
+
+		if patch.Name == nil && patch.Description == nil && patch.Workspace == nil && patch.Metadata == nil {
+			summary.Unchanged++
+			continue
+		}
+		if *diff {
+			printApplyDiff(doc.Metadata.Name, currentMeta, merged)
+			continue
+		}
+		body, err := c.request("PATCH", fmt.Sprintf("/api/registry/%d", existing.ID), patch)
+		if err != nil {
+			summary.Failed++
+			summary.Errors = append(summary.Errors, fmt.Sprintf("%s: %v", doc.Metadata.Name, err))
+			continue
+		}
+		fmt.Println(string(body))
+		summary.Updated++
+	}
+
+	if *prune && !*diff {
+		summary.Pruned = pruneRegistryWorkspace(c, *workspace, appliedKinds, appliedNames)
+	}
+
+	out, err := json.Marshal(summary)
+	exitOnError(err)
+	fmt.Println(string(out))
+}
+
+// parseApplyManifest reads one file containing one or more YAML (or plain
+// JSON, which is valid YAML) documents separated by a `---` line, the same
+// multi-document convention kubectl apply -f uses.
+func parseApplyManifest(path string) ([]applyDocument, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	dec := yaml.NewDecoder(strings.NewReader(string(data)))
+	var docs []applyDocument
+	for {
+		var doc applyDocument
+		if err := dec.Decode(&doc); err != nil {
+			if err == io.EOF {
+				break
+			}
+			return nil, fmt.Errorf("parsing manifest: %w", err)
+		}
+		if strings.TrimSpace(doc.Kind) == "" {
+			continue
+		}
+		docs = append(docs, doc)
+	}
+	return docs, nil
+}
+
+// buildRegistryApplyArgs turns one manifest document's spec into the same
+// --flag value pairs a user would type at the command line, so it runs
+// through parseRegistryPayloadWithID exactly like `registry create` does:
+// the same parseBool coercion, splitTags tag splitting, normalizeKind
+// normalization, and fillPrimerThermodynamics fill-in.
+func buildRegistryApplyArgs(doc applyDocument, kindFlag string) []string {
+	args := []string{"--kind", kindFlag}
+	if strings.TrimSpace(doc.Metadata.Name) != "" {
+		args = append(args, "--name", doc.Metadata.Name)
+	}
+	if strings.TrimSpace(doc.Metadata.Workspace) != "" {
+		args = append(args, "--workspace", doc.Metadata.Workspace)
+	}
+	keys := make([]string, 0, len(doc.Spec))
+	for k := range doc.Spec {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	for _, k := range keys {
+		args = append(args, "--"+k, specValueString(doc.Spec[k]))
+	}
+	return args
+}
+
+// specValueString renders a manifest spec value the way a user would type it
+// on the command line. yaml.v3 decodes a YAML list (e.g. "tags: [cryo, em]")
+// as []interface{}, which fmt.Sprint would render as the bracketed,
+// space-separated "[cryo em]" instead of the comma-separated string
+// splitTags (main.go) expects from a --tags/--resistance flag value, so
+// list-valued fields are joined with "," here to match.
+func specValueString(v interface{}) string {
+	switch vv := v.(type) {
+	case []interface{}:
+		parts := make([]string, len(vv))
+		for i, item := range vv {
+			parts[i] = fmt.Sprint(item)
+		}
+		return strings.Join(parts, ",")
+	case []string:
+		return strings.Join(vv, ",")
+	default:
+		return fmt.Sprint(v)
+	}
+}
+
+// resolveApplyTarget figures out which existing registry item (if any) a
+// manifest document refers to: metadata.id is authoritative when set,
+// otherwise it's looked up by exact name+kind within the target workspace
+// so re-applying the same manifest updates rather than duplicates.
+func resolveApplyTarget(c client, doc applyDocument, kindFlag string) (*registryItem, error) {
+	if doc.Metadata.ID > 0 {
+		item, err := fetchRegistryByID(c, doc.Metadata.ID)
+		if err != nil {
+			return nil, err
+		}
+		return &item, nil
+	}
+	if strings.TrimSpace(doc.Metadata.Name) == "" {
+		return nil, errors.New("metadata.name or metadata.id is required")
+	}
+	items, err := listRegistryItems(c, doc.Metadata.Workspace, kindFlag)
+	if err != nil {
+		return nil, err
+	}
+	for i := range items {
+		if items[i].Name == doc.Metadata.Name {
+			return &items[i], nil
+		}
+	}
+	return nil, nil
+}
+
+func listRegistryItems(c client, workspace, kind string) ([]registryItem, error) {
+	path := "/api/registry?kind=" + strings.ReplaceAll(kind, " ", "%20")
+	if strings.TrimSpace(workspace) != "" {
+		path += "&workspace=" + workspace
+	}
+	body, err := c.request("GET", path, nil)
+	if err != nil {
+		return nil, err
+	}
+	var payload struct {
+		Items []registryItem `json:"items"`
+	}
+	if err := json.Unmarshal(body, &payload); err != nil {
+		return nil, err
+	}
+	return payload.Items, nil
+}
+
+// threeWayMergeMetadata reproduces kubectl's strategic merge: fields set in
+// desired win outright; fields present in lastApplied but dropped from
+// desired are deleted, unless something other than this CLI changed them
+// since (current no longer matches lastApplied), in which case the
+// out-of-band value is kept; anything else in current that apply has never
+// touched passes through untouched.
+func threeWayMergeMetadata(current, lastApplied, desired map[string]interface{}) map[string]interface{} {
+	merged := map[string]interface{}{}
+	for k, v := range current {
+		merged[k] = v
+	}
+	for k, v := range desired {
+		merged[k] = v
+	}
+	for k, lastVal := range lastApplied {
+		if _, stillDesired := desired[k]; stillDesired {
+			continue
+		}
+		if curVal, inCurrent := current[k]; inCurrent && fmt.Sprint(curVal) == fmt.Sprint(lastVal) {
+			delete(merged, k)
+		}
+	}
+	return merged
+}
+
+func decodeLastApplied(meta map[string]interface{}) map[string]interface{} {
+	raw, ok := meta[lastAppliedMetaKey].(string)
+	if !ok || raw == "" {
+		return map[string]interface{}{}
+	}
+	var decoded map[string]interface{}
+	if err := json.Unmarshal([]byte(raw), &decoded); err != nil {
+		return map[string]interface{}{}
+	}
+	return decoded
+}
+
+func withoutLastApplied(meta map[string]interface{}) map[string]interface{} {
+	out := map[string]interface{}{}
+	for k, v := range meta {
+		if k == lastAppliedMetaKey {
+			continue
+		}
+		out[k] = v
+	}
+	return out
+}
+
+func withLastApplied(meta map[string]interface{}) map[string]interface{} {
+	stripped := withoutLastApplied(meta)
+	encoded, err := json.Marshal(stripped)
+	if err != nil {
+		return meta
+	}
+	out := map[string]interface{}{}
+	for k, v := range stripped {
+		out[k] = v
+	}
+	out[lastAppliedMetaKey] = string(encoded)
+	return out
+}
+
+func metadataEqual(a, b map[string]interface{}) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for k, v := range a {
+		other, ok := b[k]
+		if !ok || fmt.Sprint(v) != fmt.Sprint(other) {
+			return false
+		}
+	}
+	return true
+}
+
+// printApplyDiff prints the field-level additions/changes/removals that
+// applying `next` over `current` would make, in lieu of --diff actually
+// sending the request.
+func printApplyDiff(name string, current, next map[string]interface{}) {
+	type change struct {
+		Field string      `json:"field"`
+		From  interface{} `json:"from,omitempty"`
+		To    interface{} `json:"to,omitempty"`
+	}
+	var changes []change
+	seen := map[string]bool{}
+	for k, v := range next {
+		seen[k] = true
+		if old, ok := current[k]; !ok || fmt.Sprint(old) != fmt.Sprint(v) {
+			changes = append(changes, change{Field: k, From: current[k], To: v})
+		}
+	}
+	for k, v := range current {
+		if !seen[k] {
+			changes = append(changes, change{Field: k, From: v, To: nil})
+		}
+	}
+	sort.Slice(changes, func(i, j int) bool { return changes[i].Field < changes[j].Field })
+	out, err := json.Marshal(map[string]interface{}{"name": name, "changes": changes})
+	exitOnError(err)
+	fmt.Println(string(out))
+}
+
+// pruneRegistryWorkspace deletes every item in workspace whose kind was
+// touched by this apply run but whose name wasn't declared in the
+// manifest, so a manifest is the authoritative set of items for the kinds
+// it covers.
+func pruneRegistryWorkspace(c client, workspace string, kinds map[string]bool, keepNames map[string]bool) int {
+	pruned := 0
+	for kind := range kinds {
+		items, err := listRegistryItems(c, workspace, kind)
+		if err != nil {
+			continue
+		}
+		for _, item := range items {
+			if keepNames[item.Name] {
+				continue
+			}
+			if _, err := c.request("DELETE", fmt.Sprintf("/api/registry/%d", item.ID), nil); err == nil {
+				pruned++
+			}
+		}
+	}
+	return pruned
+}
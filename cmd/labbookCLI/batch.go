@@ -0,0 +1,190 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"os"
+	"os/signal"
+	"strings"
+	"sync"
+	"syscall"
+	"time"
+)
+
+// batchJob is one entry of a --file jobs.json array: the labbookCLI
+// subcommand and argument list to re-dispatch in-process, e.g.
+// {"cmd":"entries","args":["append","--id","42","--append-file","x.html"]}.
+type batchJob struct {
+	Cmd  string   `json:"cmd"`
+	Args []string `json:"args"`
+}
+
+// batchResult is the NDJSON shape batchCmd writes to stdout, one line per
+// job, in the order jobs finish rather than job-file order (workers take
+// jobs concurrently, though captureDispatchMu lets only one of them
+// dispatch at a time) — JobIndex ties a line back to its place in the file.
+type batchResult struct {
+	JobIndex   int    `json:"jobIndex"`
+	Status     string `json:"status"` // "ok", "error", or "aborted"
+	DurationMS int64  `json:"durationMs"`
+	Response   string `json:"response,omitempty"`
+	Error      string `json:"error,omitempty"`
+}
+
+// batchSummary is the final NDJSON line, distinguished from a batchResult
+// by having no "jobIndex".
+type batchSummary struct {
+	Summary   bool `json:"summary"`
+	Total     int  `json:"total"`
+	Completed int  `json:"completed"`
+	Failed    int  `json:"failed"`
+	Aborted   int  `json:"aborted"`
+}
+
+// batchDispatchableCommands are the top-level commands batchCmd will
+// re-enter dispatch for. Keeping an explicit allow-list here (rather than
+// trying every cmd against dispatch and catching the "unknown command"
+// case) means a typo'd job.Cmd comes back as an ordinary {"status":"error"}
+// NDJSON line instead of hitting dispatch's default case, which calls
+// usage() and os.Exit(1) same as any other unknown command would.
+var batchDispatchableCommands = map[string]bool{
+	"health": true, "auth": true, "registry": true, "entries": true,
+	"templates": true, "uploads": true, "audit": true, "api-keys": true,
+	"widgets": true, "watch": true, "attachments": true,
+}
+
+// batchCmd implements `labbookCLI batch --file jobs.json [--parallel N]
+// [--continue-on-error]`: an in-process worker pool that re-enters
+// dispatch for each job via captureDispatch (see run.go), the same way
+// `run` re-enters dispatch for scheduled jobs, so a batch shares this
+// process's single authenticated session instead of spawning one
+// subprocess per job. captureDispatch holds captureDispatchMu for a job's
+// whole dispatch, so the workers' dispatch calls run one at a time;
+// --parallel bounds how many jobs are taken up at once, not how many
+// dispatch together.
+//
+// That in-process reuse carries the same limitation runCmd documents:
+// dispatch's own commands call exitOnError (os.Exit) on a hard failure
+// rather than returning an error, and no goroutine can recover another
+// goroutine's os.Exit. --continue-on-error therefore governs what this
+// command can actually control — an unknown job.Cmd, caught against
+// batchDispatchableCommands before dispatch ever runs — rather than a
+// promise that a job's own API/validation error leaves the rest of the
+// batch running; a job that hits exitOnError still takes the whole batch
+// down, exactly as it would under `run`.
+func batchCmd(c client, args []string) {
+	fs := flag.NewFlagSet("batch", flag.ExitOnError)
+	filePath := fs.String("file", "", "Path to a JSON array of {cmd, args} jobs")
+	parallel := fs.Int("parallel", 4, "Maximum number of jobs taken up at once (their dispatch calls still run one at a time)")
+	continueOnError := fs.Bool("continue-on-error", false, "Keep scheduling remaining jobs after one reports an error")
+	_ = fs.Parse(args)
+
+	if strings.TrimSpace(*filePath) == "" {
+		exitOnError(fmt.Errorf("file is required"))
+	}
+	data, err := os.ReadFile(*filePath)
+	exitOnError(err)
+	var jobs []batchJob
+	exitOnError(json.Unmarshal(data, &jobs))
+	if len(jobs) == 0 {
+		exitOnError(fmt.Errorf("%s defines no jobs", *filePath))
+	}
+
+	workers := *parallel
+	if workers <= 0 {
+		workers = 1
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	enc := json.NewEncoder(os.Stdout)
+	var outMu sync.Mutex
+	emit := func(r batchResult) {
+		outMu.Lock()
+		defer outMu.Unlock()
+		_ = enc.Encode(r)
+	}
+
+	var stopScheduling bool
+	var stopMu sync.Mutex
+	shouldStop := func() bool {
+		stopMu.Lock()
+		defer stopMu.Unlock()
+		return stopScheduling
+	}
+	markFailure := func() {
+		if !*continueOnError {
+			stopMu.Lock()
+			stopScheduling = true
+			stopMu.Unlock()
+		}
+	}
+
+	var completed, failed, aborted int
+	var countMu sync.Mutex
+
+	sem := make(chan struct{}, workers)
+	var wg sync.WaitGroup
+	for i, job := range jobs {
+		if ctx.Err() != nil || shouldStop() {
+			emit(batchResult{JobIndex: i, Status: "aborted", Error: "batch stopped before this job started"})
+			countMu.Lock()
+			aborted++
+			countMu.Unlock()
+			continue
+		}
+		if !batchDispatchableCommands[job.Cmd] {
+			emit(batchResult{JobIndex: i, Status: "error", Error: fmt.Sprintf("unknown command %q", job.Cmd)})
+			countMu.Lock()
+			failed++
+			countMu.Unlock()
+			markFailure()
+			continue
+		}
+
+		sem <- struct{}{}
+		wg.Add(1)
+		go func(i int, job batchJob) {
+			defer wg.Done()
+			defer func() { <-sem }()
+
+			started := time.Now()
+			stdout, exitCode := captureDispatch(c, job.Cmd, job.Args)
+			result := batchResult{
+				JobIndex:   i,
+				DurationMS: time.Since(started).Milliseconds(),
+				Response:   strings.TrimSpace(stdout),
+			}
+			if exitCode == 0 {
+				result.Status = "ok"
+				countMu.Lock()
+				completed++
+				countMu.Unlock()
+			} else {
+				result.Status = "error"
+				result.Error = fmt.Sprintf("job exited with status %d", exitCode)
+				countMu.Lock()
+				failed++
+				countMu.Unlock()
+				markFailure()
+			}
+			emit(result)
+		}(i, job)
+	}
+	wg.Wait()
+
+	_ = enc.Encode(batchSummary{
+		Summary:   true,
+		Total:     len(jobs),
+		Completed: completed,
+		Failed:    failed,
+		Aborted:   aborted,
+	})
+
+	if failed > 0 {
+		os.Exit(1)
+	}
+}
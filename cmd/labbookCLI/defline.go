@@ -0,0 +1,85 @@
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"strings"
+	"text/template"
+
+	"benchaid/internal/defline"
+)
+
+// deflineTemplateFuncs are the small set of sprig-style string helpers a
+// --defline-template is allowed to call, since this repo doesn't depend on
+// sprig itself.
+var deflineTemplateFuncs = template.FuncMap{
+	"upper": strings.ToUpper,
+	"lower": strings.ToLower,
+	"trim":  strings.TrimSpace,
+	"default": func(def, value string) string {
+		if strings.TrimSpace(value) == "" {
+			return def
+		}
+		return value
+	},
+	"join": func(sep string, values ...string) string {
+		var nonEmpty []string
+		for _, v := range values {
+			if strings.TrimSpace(v) != "" {
+				nonEmpty = append(nonEmpty, v)
+			}
+		}
+		return strings.Join(nonEmpty, sep)
+	},
+}
+
+// computeDefline returns the definition line for a registry item of kind
+// with the given metadata: tmplText rendered as a Go template against meta
+// when set, otherwise the kind's registered internal/defline.Formatter.
+func computeDefline(kind string, meta map[string]interface{}, tmplText string) (string, error) {
+	if strings.TrimSpace(tmplText) == "" {
+		return defline.Generate(normalizeKind(kind), meta), nil
+	}
+	tmpl, err := template.New("defline-template").Funcs(deflineTemplateFuncs).Parse(tmplText)
+	if err != nil {
+		return "", fmt.Errorf("--defline-template: %w", err)
+	}
+	var b strings.Builder
+	if err := tmpl.Execute(&b, meta); err != nil {
+		return "", fmt.Errorf("--defline-template: %w", err)
+	}
+	return b.String(), nil
+}
+
+// registryDeflineCmd implements `labbookCLI registry defline --id N
+// [--defline-template] [--set]`: it recomputes the item's definition line
+// from its current metadata and prints it, or PATCHes it onto the item's
+// name when --set is given, the same recomputation `registry create`/
+// `registry update` run automatically when --name is left empty.
+func registryDeflineCmd(c client, args []string) {
+	fs := flag.NewFlagSet("registry defline", flag.ExitOnError)
+	id := fs.Int("id", 0, "Registry ID")
+	tmplText := fs.String("defline-template", "", "Go template overriding the kind's built-in defline format")
+	set := fs.Bool("set", false, "Patch the computed defline onto the item's name")
+	_ = fs.Parse(args)
+	if *id == 0 {
+		exitOnError(errors.New("id is required"))
+	}
+
+	item, err := fetchRegistryByID(c, *id)
+	exitOnError(err)
+	computed, err := computeDefline(item.Kind, item.Metadata, *tmplText)
+	exitOnError(err)
+	if strings.TrimSpace(computed) == "" {
+		exitOnError(fmt.Errorf("could not derive a defline for kind %q from its current metadata", item.Kind))
+	}
+
+	if !*set {
+		fmt.Println(computed)
+		return
+	}
+	body, err := c.request("PATCH", fmt.Sprintf("/api/registry/%d", *id), registryPatchPayload{Name: &computed})
+	exitOnError(err)
+	fmt.Println(string(body))
+}
@@ -0,0 +1,260 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"strings"
+
+	"benchaid/internal/rsql"
+)
+
+// filterFieldMapper translates a single top-level RSQL comparison into an
+// existing query parameter (name, value), or reports ok=false when cmp
+// should instead be evaluated client-side.
+type filterFieldMapper func(cmp *rsql.CmpNode) (name, value string, ok bool)
+
+// registryFilterMapper maps the subset of --filter expressions that
+// "registry list" can already satisfy via its existing --kind parameter.
+func registryFilterMapper(cmp *rsql.CmpNode) (string, string, bool) {
+	if cmp.Field == "kind" && cmp.Op == rsql.OpEQ && len(cmp.Values) == 1 {
+		return "kind", cmp.Values[0], true
+	}
+	return "", "", false
+}
+
+// entriesFilterMapper maps the subset of --filter expressions that "entries
+// list" can already satisfy via its existing --project, --tag, --from, and
+// --to parameters.
+func entriesFilterMapper(cmp *rsql.CmpNode) (string, string, bool) {
+	switch {
+	case cmp.Field == "project" && cmp.Op == rsql.OpEQ && len(cmp.Values) == 1:
+		return "project", cmp.Values[0], true
+	case cmp.Field == "tag" && (cmp.Op == rsql.OpEQ || cmp.Op == rsql.OpIN) && len(cmp.Values) > 0:
+		return "tag", strings.Join(cmp.Values, ","), true
+	case cmp.Field == "createdAt" && (cmp.Op == rsql.OpGT || cmp.Op == rsql.OpGE) && len(cmp.Values) == 1:
+		return "from", cmp.Values[0], true
+	case cmp.Field == "createdAt" && (cmp.Op == rsql.OpLT || cmp.Op == rsql.OpLE) && len(cmp.Values) == 1:
+		return "to", cmp.Values[0], true
+	}
+	return "", "", false
+}
+
+// entriesFilterableFields and entriesSortableFields are the --filter/--sort
+// selectors "entries list" accepts beyond the open-ended "metadata.*"
+// namespace, which validateFilterFields and parseSortParam always allow.
+// "tag" is filterable (entriesFilterMapper above sends it as the server's
+// tag parameter; client-side it matches against the entry's tags array)
+// but not meaningfully orderable, so it's left out of the sortable set.
+var entriesFilterableFields = map[string]bool{
+	"id": true, "project": true, "tag": true, "createdAt": true, "title": true,
+}
+
+var entriesSortableFields = map[string]bool{
+	"id": true, "project": true, "createdAt": true, "title": true,
+}
+
+// auditFilterableFields and auditSortableFields are the --filter/--sort
+// selectors "audit list" accepts. The audit log has no typed response
+// struct in this CLI (see genericMapResolver), so this list is the only
+// place its field names are pinned down.
+var auditFilterableFields = map[string]bool{
+	"id": true, "actorEmail": true, "action": true, "resourceType": true, "resourceId": true, "createdAt": true,
+}
+
+var auditSortableFields = map[string]bool{
+	"id": true, "actorEmail": true, "action": true, "resourceType": true, "createdAt": true,
+}
+
+// validateFilterFields walks node and returns an error naming the first
+// selector that isn't in allowed, so a typo'd or unsupported field path
+// fails fast with a helpful message instead of silently never matching.
+// Anything under "metadata." is always allowed, since user metadata keys
+// can't be enumerated up front.
+func validateFilterFields(node rsql.Node, allowed map[string]bool) error {
+	switch n := node.(type) {
+	case *rsql.AndNode:
+		if err := validateFilterFields(n.Left, allowed); err != nil {
+			return err
+		}
+		return validateFilterFields(n.Right, allowed)
+	case *rsql.OrNode:
+		if err := validateFilterFields(n.Left, allowed); err != nil {
+			return err
+		}
+		return validateFilterFields(n.Right, allowed)
+	case *rsql.CmpNode:
+		if strings.HasPrefix(n.Field, "metadata.") || allowed[n.Field] {
+			return nil
+		}
+		return fmt.Errorf("--filter: unknown field %q", n.Field)
+	}
+	return nil
+}
+
+// parseSortParam validates a comma-separated --sort expression (each field
+// optionally prefixed with '-' for descending, e.g. "createdAt,-title")
+// against allowed and, if every field checks out, returns it unchanged for
+// use as the server's "sort=" query parameter.
+func parseSortParam(sortExpr string, allowed map[string]bool) (string, error) {
+	for _, field := range strings.Split(sortExpr, ",") {
+		name := strings.TrimPrefix(strings.TrimSpace(field), "-")
+		if name == "" {
+			return "", fmt.Errorf("--sort: empty field in %q", sortExpr)
+		}
+		if !strings.HasPrefix(name, "metadata.") && !allowed[name] {
+			return "", fmt.Errorf("--sort: unknown field %q", name)
+		}
+	}
+	return sortExpr, nil
+}
+
+// extractServerParams walks the top-level AND chain of node, peeling off
+// every comparison mapper understands into a query parameter and leaving
+// the rest (including anything under an OR, which can't be split this way)
+// as the remainder to evaluate client-side. It returns a nil remainder when
+// mapper accounted for the whole expression.
+func extractServerParams(node rsql.Node, mapper filterFieldMapper) (url.Values, rsql.Node) {
+	params := url.Values{}
+	remainder := extractServerParamsNode(node, params, mapper)
+	return params, remainder
+}
+
+func extractServerParamsNode(node rsql.Node, params url.Values, mapper filterFieldMapper) rsql.Node {
+	and, ok := node.(*rsql.AndNode)
+	if !ok {
+		if cmp, ok := node.(*rsql.CmpNode); ok {
+			if name, value, ok := mapper(cmp); ok {
+				params.Add(name, value)
+				return nil
+			}
+		}
+		return node
+	}
+	left := extractServerParamsNode(and.Left, params, mapper)
+	right := extractServerParamsNode(and.Right, params, mapper)
+	switch {
+	case left == nil && right == nil:
+		return nil
+	case left == nil:
+		return right
+	case right == nil:
+		return left
+	default:
+		return &rsql.AndNode{Left: left, Right: right}
+	}
+}
+
+// registryItemResolver resolves --filter field paths against a decoded
+// registryItem: "id", "name", "kind", "description", and "createdAt" as
+// scalars, plus "metadata.*" dotted into its Metadata map.
+func registryItemResolver(item registryItem) rsql.Resolver {
+	return func(field string) (interface{}, bool) {
+		switch {
+		case field == "id":
+			return item.ID, true
+		case field == "name":
+			return item.Name, true
+		case field == "kind":
+			return item.Kind, true
+		case field == "description":
+			if item.Description == nil {
+				return nil, false
+			}
+			return *item.Description, true
+		case field == "createdAt":
+			return item.CreatedAt, true
+		case strings.HasPrefix(field, "metadata."):
+			return resolveDottedPath(item.Metadata, strings.TrimPrefix(field, "metadata."))
+		}
+		return nil, false
+	}
+}
+
+// filterRegistryItemsByRSQL keeps only the items matching node, evaluated
+// via registryItemResolver.
+func filterRegistryItemsByRSQL(items []registryItem, node rsql.Node) []registryItem {
+	filtered := make([]registryItem, 0, len(items))
+	for _, item := range items {
+		if rsql.Eval(node, registryItemResolver(item)) {
+			filtered = append(filtered, item)
+		}
+	}
+	return filtered
+}
+
+// genericMapResolver resolves --filter field paths against a decoded JSON
+// object of unknown shape (used for "entries list", which has no typed
+// response struct), dotting through nested objects. "tag" additionally
+// aliases to a top-level "tags" array, matching the --tag flag's naming.
+func genericMapResolver(item map[string]interface{}) rsql.Resolver {
+	return func(field string) (interface{}, bool) {
+		if field == "tag" {
+			if v, ok := item["tags"]; ok {
+				return v, true
+			}
+		}
+		return resolveDottedPath(item, field)
+	}
+}
+
+func resolveDottedPath(root map[string]interface{}, path string) (interface{}, bool) {
+	var cur interface{} = root
+	for _, segment := range strings.Split(path, ".") {
+		m, ok := cur.(map[string]interface{})
+		if !ok {
+			return nil, false
+		}
+		v, ok := m[segment]
+		if !ok {
+			return nil, false
+		}
+		cur = v
+	}
+	return cur, true
+}
+
+// filterJSONItems decodes a list response body (either a bare JSON array or
+// an {"items": [...], ...} wrapper, the shape "registry list" uses), drops
+// every element node doesn't match, and re-encodes the result. A wrapper's
+// "total" field, if present, is updated to the filtered count.
+func filterJSONItems(body []byte, node rsql.Node) ([]byte, error) {
+	items, wrapper, wrapped, err := decodeJSONItems(body)
+	if err != nil {
+		return nil, err
+	}
+	filtered := make([]interface{}, 0, len(items))
+	for _, raw := range items {
+		m, ok := raw.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		if rsql.Eval(node, genericMapResolver(m)) {
+			filtered = append(filtered, raw)
+		}
+	}
+	if wrapped {
+		wrapper["items"] = filtered
+		if _, ok := wrapper["total"]; ok {
+			wrapper["total"] = len(filtered)
+		}
+		return json.Marshal(wrapper)
+	}
+	return json.Marshal(filtered)
+}
+
+func decodeJSONItems(body []byte) ([]interface{}, map[string]interface{}, bool, error) {
+	var wrapper map[string]interface{}
+	if err := json.Unmarshal(body, &wrapper); err == nil {
+		if raw, ok := wrapper["items"]; ok {
+			if items, ok := raw.([]interface{}); ok {
+				return items, wrapper, true, nil
+			}
+		}
+	}
+	var items []interface{}
+	if err := json.Unmarshal(body, &items); err != nil {
+		return nil, nil, false, fmt.Errorf("--filter: unrecognized response shape: %w", err)
+	}
+	return items, nil, false, nil
+}
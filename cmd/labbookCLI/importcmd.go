@@ -0,0 +1,183 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"regexp"
+	"strings"
+
+	"benchaid/internal/seqio"
+)
+
+// defaultResistancePattern matches the antibiotic-resistance marker names
+// GenBank /note qualifiers commonly carry (e.g. a CDS noted "AmpR" or
+// "KanR selectable marker"); --resistance-pattern overrides it for labs
+// using other marker naming conventions.
+var defaultResistancePattern = regexp.MustCompile(`(?i)\b(AmpR|KanR|CmR|CamR|TetR|HygR|ZeoR|SpecR|GentR|NeoR|PuroR|BleoR)\b`)
+
+// registryImportCmd implements `labbookCLI registry import --kind plasmid
+// --format genbank|fasta --file ...` and `--kind primers --format fasta
+// --file ...`: it parses the file with internal/seqio and feeds each
+// record through the same registryCreatePayload shape registry create
+// builds, POSTing one per record (or, with --dry-run, printing the
+// payload instead) rather than requiring a flag-by-flag create call per
+// sequence already sitting in a file a lab maintains.
+func registryImportCmd(c client, args []string) {
+	fs := flag.NewFlagSet("registry import", flag.ExitOnError)
+	kind := fs.String("kind", "", "plasmid or primers")
+	format := fs.String("format", "", "genbank or fasta")
+	filePath := fs.String("file", "", "Path to the FASTA/GenBank file to import")
+	resistancePattern := fs.String("resistance-pattern", "", "Regexp matched against feature /note qualifiers to detect resistance markers (default catches AmpR, KanR, CmR, ...)")
+	allowAmbig := fs.Bool("allow-ambig", false, "Tolerate ambiguous bases in imported primer sequences instead of failing the computed-field fill-in")
+	dryRun := fs.Bool("dry-run", false, "Print the resulting registry payloads instead of posting them")
+	_ = fs.Parse(args)
+
+	normalizedKind := normalizeKind(*kind)
+	if normalizedKind != "plasmid" && normalizedKind != "primers" {
+		exitOnError(errors.New("--kind must be plasmid or primers"))
+	}
+	if strings.TrimSpace(*filePath) == "" {
+		exitOnError(errors.New("file is required"))
+	}
+	data, err := os.ReadFile(*filePath)
+	exitOnError(err)
+
+	resistanceRe := defaultResistancePattern
+	if strings.TrimSpace(*resistancePattern) != "" {
+		resistanceRe, err = regexp.Compile(*resistancePattern)
+		exitOnError(err)
+	}
+
+	var payloads []registryCreatePayload
+	switch {
+	case normalizedKind == "plasmid" && *format == "genbank":
+		records, err := seqio.ParseGenBank(string(data))
+		exitOnError(err)
+		for _, rec := range records {
+			payloads = append(payloads, genBankPlasmidPayload(rec, resistanceRe))
+		}
+	case normalizedKind == "plasmid" && *format == "fasta":
+		records, err := seqio.ParseFasta(string(data))
+		exitOnError(err)
+		for _, rec := range records {
+			payloads = append(payloads, fastaPlasmidPayload(rec))
+		}
+	case normalizedKind == "primers" && *format == "fasta":
+		records, err := seqio.ParseFasta(string(data))
+		exitOnError(err)
+		for _, rec := range records {
+			payloads = append(payloads, fastaPrimerPayload(rec, *allowAmbig))
+		}
+	default:
+		exitOnError(fmt.Errorf("unsupported --kind/--format combination: %s/%s", *kind, *format))
+	}
+
+	for i := range payloads {
+		if payloads[i].Workspace == "" {
+			payloads[i].Workspace = c.workspace
+		}
+	}
+
+	for _, payload := range payloads {
+		if *dryRun {
+			out, err := json.Marshal(payload)
+			exitOnError(err)
+			fmt.Println(string(out))
+			continue
+		}
+		body, err := c.request("POST", "/api/registry", payload)
+		exitOnError(err)
+		fmt.Println(string(body))
+	}
+}
+
+// genBankPlasmidPayload maps one parsed GenBank record onto the plasmid
+// metadata shape `registry create --kind plasmid` builds: insert from the
+// first CDS's /product (falling back to /gene), backbone from a
+// /note="backbone:..." qualifier on any feature (falling back to the
+// LOCUS name), resistance from any feature whose /note matches
+// resistanceRe, and sequenceAA translated from the first CDS's own
+// sequence region, honoring its /transl_table=.
+func genBankPlasmidPayload(rec seqio.GenBankRecord, resistanceRe *regexp.Regexp) registryCreatePayload {
+	meta := map[string]interface{}{}
+
+	var cds *seqio.Feature
+	var resistances []string
+	for i, f := range rec.Features {
+		if f.Type == "CDS" && cds == nil {
+			cds = &rec.Features[i]
+		}
+		if note := f.Qualifier("note"); note != "" {
+			if _, backbone, ok := strings.Cut(note, "backbone:"); ok {
+				meta["backbone"] = strings.TrimSpace(backbone)
+			}
+			if m := resistanceRe.FindString(note); m != "" {
+				resistances = append(resistances, m)
+			}
+		}
+	}
+
+	if cds != nil {
+		if product := cds.Qualifier("product"); product != "" {
+			meta["insert"] = product
+		} else if gene := cds.Qualifier("gene"); gene != "" {
+			meta["insert"] = gene
+		}
+		if nt, err := seqio.ExtractRegion(rec.Sequence, cds.Location); err == nil {
+			if aa, err := seqio.Translate(nt, cds.Qualifier("transl_table")); err == nil {
+				meta["sequenceAA"] = aa
+			}
+		}
+	}
+	if _, ok := meta["backbone"]; !ok && rec.Locus != "" {
+		meta["backbone"] = rec.Locus
+	}
+	if len(resistances) > 0 {
+		meta["resistance"] = strings.Join(resistances, ", ")
+	}
+	if strings.TrimSpace(rec.Accession) != "" && rec.Accession != "." {
+		meta["plasmidId"] = rec.Accession
+	}
+	if strings.TrimSpace(rec.Definition) != "" {
+		meta["comments"] = rec.Definition
+	}
+
+	name := rec.Locus
+	if name == "" {
+		name = rec.Accession
+	}
+	if name == "" {
+		exitOnError(errors.New("genbank record has neither a LOCUS name nor an ACCESSION to use as the registry item name"))
+	}
+	return registryCreatePayload{Name: name, Kind: "Plasmid", Metadata: meta}
+}
+
+// fastaPlasmidPayload handles the minimal `--kind plasmid --format fasta`
+// case: without a FEATURES table there's no insert/backbone/resistance to
+// extract, so only the record ID and description carry over.
+func fastaPlasmidPayload(rec seqio.FastaRecord) registryCreatePayload {
+	meta := map[string]interface{}{"plasmidId": rec.ID}
+	if rec.Description != "" {
+		meta["comments"] = rec.Description
+	}
+	return registryCreatePayload{Name: rec.ID, Kind: "Plasmid", Metadata: meta}
+}
+
+// fastaPrimerPayload maps one parsed FASTA record onto the primers
+// metadata shape, reusing fillPrimerThermodynamics so an imported primer
+// gets the same computed length/GC/MW/Tm fields `registry create --kind
+// primers --primer-sequence` does.
+func fastaPrimerPayload(rec seqio.FastaRecord, allowAmbig bool) registryCreatePayload {
+	meta := map[string]interface{}{
+		"primerId":       rec.ID,
+		"primerSequence": rec.Sequence,
+	}
+	if rec.Description != "" {
+		meta["primerComment"] = rec.Description
+	}
+	fillPrimerThermodynamics(meta, allowAmbig)
+	return registryCreatePayload{Name: rec.ID, Kind: "Primers", Metadata: meta}
+}
@@ -2,24 +2,45 @@ package main
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
 	"errors"
 	"flag"
 	"fmt"
 	"io"
+	"math"
+	"math/rand"
 	"mime/multipart"
 	"net/http"
 	"net/url"
 	"os"
+	"os/signal"
 	"path/filepath"
 	"strconv"
 	"strings"
+	"sync"
+	"syscall"
+	"time"
+
+	"benchaid/internal/html2text"
+	"benchaid/internal/primercalc"
+	"benchaid/internal/rsql"
+	"benchaid/internal/seqvalidate"
 )
 
 type client struct {
-	baseURL string
-	apiKey  string
-	token   string
+	baseURL   string
+	apiKey    string
+	token     string
+	workspace string
+	// ctx is the root context request/requestNoAuth issue their HTTP call
+	// under, canceled on SIGINT/SIGTERM by main so a hung request doesn't
+	// wedge a scripted CI invocation. nil is treated as context.Background().
+	ctx context.Context
+	// timeout bounds each request/requestNoAuth call (not requestUpload,
+	// whose callers manage their own transfer-length-appropriate
+	// cancellation); see --timeout. 0 disables the deadline.
+	timeout time.Duration
 }
 
 type registryCreatePayload struct {
@@ -27,6 +48,7 @@ type registryCreatePayload struct {
 	Kind        string                 `json:"kind"`
 	Description *string                `json:"description,omitempty"`
 	Metadata    map[string]interface{} `json:"metadata,omitempty"`
+	Workspace   string                 `json:"workspace,omitempty"`
 }
 
 type entryPayload struct {
@@ -73,6 +95,7 @@ type registryItem struct {
 	Description *string                `json:"description,omitempty"`
 	Metadata    map[string]interface{} `json:"metadata,omitempty"`
 	CreatedAt   string                 `json:"createdAt"`
+	Workspace   string                 `json:"workspace,omitempty"`
 }
 
 type registryPatchPayload struct {
@@ -80,6 +103,7 @@ type registryPatchPayload struct {
 	Kind        *string                `json:"kind,omitempty"`
 	Description *string                `json:"description,omitempty"`
 	Metadata    map[string]interface{} `json:"metadata,omitempty"`
+	Workspace   *string                `json:"workspace,omitempty"`
 }
 
 type attachment struct {
@@ -102,30 +126,60 @@ func main() {
 	rootBaseURL := rootFlags.String("base-url", baseURL, "API base URL")
 	rootAPIKey := rootFlags.String("api-key", apiKey, "API key (Bearer)")
 	rootToken := rootFlags.String("token", token, "Bearer token (JWT)")
+	rootWorkspace := rootFlags.String("workspace", resolveDefaultWorkspace(), "Active workspace/lab-group; comma-separated or \"*\" for all visible on list/search commands. Defaults from $LABBOOK_WORKSPACE or ~/.labbook/config.json")
+	rootTimeout := rootFlags.Duration("timeout", 0, "Per-request timeout (e.g. 30s, 2m); 0 disables (default). Doesn't bound attachment transfers, which cancel on SIGINT/SIGTERM instead")
 	_ = rootFlags.Parse(os.Args[1:2])
 
+	// ctx is canceled on SIGINT/SIGTERM so a hung request.request/
+	// requestNoAuth call aborts instead of wedging a scripted CI
+	// invocation; stop restores the default signal behavior once main
+	// returns so a second Ctrl-C always kills the process immediately.
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
 	cmd := os.Args[1]
-	c := client{baseURL: normalizeBaseURL(*rootBaseURL), apiKey: *rootAPIKey, token: *rootToken}
+	c := client{
+		baseURL:   normalizeBaseURL(*rootBaseURL),
+		apiKey:    *rootAPIKey,
+		token:     *rootToken,
+		workspace: strings.TrimSpace(*rootWorkspace),
+		ctx:       ctx,
+		timeout:   *rootTimeout,
+	}
+	dispatch(c, cmd, os.Args[2:])
+}
 
+// dispatch routes a top-level command to its handler. It is split out of
+// main so that `run` can re-enter the CLI's own command dispatch in-process
+// for scheduled jobs, without exec'ing a subprocess.
+func dispatch(c client, cmd string, args []string) {
 	switch cmd {
 	case "health":
 		healthCmd(c)
 	case "auth":
-		authCmd(c, os.Args[2:])
+		authCmd(c, args)
 	case "registry":
-		registryCmd(c, os.Args[2:])
+		registryCmd(c, args)
 	case "entries":
-		entriesCmd(c, os.Args[2:])
+		entriesCmd(c, args)
 	case "templates":
-		templatesCmd(c, os.Args[2:])
+		templatesCmd(c, args)
 	case "uploads":
-		uploadsCmd(c, os.Args[2:])
+		uploadsCmd(c, args)
 	case "audit":
-		auditCmd(c, os.Args[2:])
+		auditCmd(c, args)
 	case "api-keys":
-		apiKeysCmd(c, os.Args[2:])
+		apiKeysCmd(c, args)
 	case "widgets":
-		widgetsCmd(c, os.Args[2:])
+		widgetsCmd(c, args)
+	case "watch":
+		watchTopLevelCmd(c, args)
+	case "run":
+		runCmd(c, args)
+	case "batch":
+		batchCmd(c, args)
+	case "attachments":
+		attachmentsCmd(c, args)
 	default:
 		usage()
 		os.Exit(1)
@@ -135,36 +189,53 @@ func main() {
 func usage() {
 	fmt.Println("labbookCLI <command> [args]")
 	fmt.Println("Global flags:")
-	fmt.Println("  --base-url --api-key --token")
+	fmt.Println("  --base-url --api-key --token --workspace --timeout")
+	fmt.Println("    (--timeout bounds each request/requestNoAuth call and retries transient failures with backoff; Ctrl-C/SIGTERM cancels in-flight requests and transfers)")
 	fmt.Println("Commands:")
 	fmt.Println("  health")
 	fmt.Println("  auth login --email --password")
 	fmt.Println("  auth me")
 	fmt.Println("  auth register --email --name --initials --password")
 	fmt.Println("  registry list [--q] [--kind] [--limit] [--offset] [--show-total]")
-	fmt.Println("  registry list [--used-in-entry] [--produced-by-entry] [--include-entry-links]")
+	fmt.Println("  registry list [--used-in-entry] [--produced-by-entry] [--include-entry-links] [--filter]")
+	fmt.Println("  registry list [--output text|json|jsonl|yaml|table] [--columns] [--output-filter]")
 	fmt.Println("  registry get --id [--show-entries]")
-	fmt.Println("  registry create --name --kind [--description] [--plasmid-id] [--insert] [--backbone] [--resistance] [--status] [--location] [--primers] [--concentration] [--sequenced] [--sequence-aa] [--comments]")
-	fmt.Println("  registry update --id [--name] [--kind] [--description] [--plasmid-id] [--insert] [--backbone] [--resistance] [--status] [--location] [--primers] [--concentration] [--sequenced] [--sequence-aa] [--comments] [--merge]")
+	fmt.Println("  registry create --kind [--name] [--description] [--plasmid-id] [--insert] [--backbone] [--resistance] [--status] [--location] [--primers] [--concentration] [--sequenced] [--sequence-aa] [--sequence-nt] [--comments] [--defline-template] [--strict]")
+	fmt.Println("    (--name is derived from metadata via internal/defline when left empty; see registry defline)")
+	fmt.Println("    (--sequence-aa/--sequence-nt/--primer-sequence are checked by internal/seqvalidate; findings print as JSON-lines on stderr, --strict refuses to build the payload on any error)")
+	fmt.Println("  registry update --id [--name] [--kind] [--description] [--plasmid-id] [--insert] [--backbone] [--resistance] [--status] [--location] [--primers] [--concentration] [--sequenced] [--sequence-aa] [--sequence-nt] [--comments] [--merge] [--defline-template]")
 	fmt.Println("  registry create --name --kind \"Protein preparation\" [--aliquot-label] [--concentration-mg-ml] [--concentration-um] [--a260-a280] [--molecular-weight-da] [--molar-extinction-coeff] [--storage-buffer] [--aliquot-size-ul] [--plasmid-ref-id] [--expression-system] [--species] [--available] [--prepped-by] [--prepped-on] [--aliquot-count] [--location]")
 	fmt.Println("  registry create --name --kind \"Expression\" [--expression-plasmid-id] [--expression-strain] [--virus-id] [--virus-volume] [--start-date] [--harvest-date] [--volume-per-flask] [--media] [--total-volume] [--iptg-mm] [--od-induction] [--temperature] [--induction-time] [--aliquot] [--yfp-harvest] [--purified] [--comment] [--location]")
-	fmt.Println("  registry create --name --kind \"Primers\" [--primer-id] [--primer-type] [--primer-sequence] [--primer-length] [--primer-mw] [--primer-gc] [--primer-tm] [--primer-company] [--primer-purification] [--primer-scale] [--primer-yield-ug] [--primer-yield-nmol] [--primer-conc-um] [--primer-conc-ngul] [--primer-comment]")
+	fmt.Println("  registry create --name --kind \"Primers\" [--primer-id] [--primer-type] [--primer-sequence] [--primer-length] [--primer-mw] [--primer-gc] [--primer-tm] [--primer-company] [--primer-purification] [--primer-scale] [--primer-yield-ug] [--primer-yield-nmol] [--primer-conc-um] [--primer-conc-ngul] [--primer-comment] [--allow-ambig]")
+	fmt.Println("    (--primer-length/--primer-gc/--primer-mw/--primer-tm/--primer-yield-ug are computed from --primer-sequence when left at 0)")
 	fmt.Println("  registry create --name --kind \"Cryo-EM Grid\" [--grid-id] [--grid-project] [--grid-type] [--grid-material] [--grid-mesh] [--grid-hole] [--grid-thickness] [--grid-lot] [--grid-storage] [--grid-status] [--sample-ref-id] [--sample-concentration] [--sample-buffer] [--sample-additives] [--applied-volume-ul] [--blot-time-s] [--blot-force] [--humidity] [--temperature-c] [--plunge-medium] [--glow-discharge] [--ice-quality] [--microscope] [--session-id] [--magnification] [--pixel-size-a] [--defocus-range] [--dose] [--movies-collected] [--screening-notes] [--best-areas] [--issues] [--linked-datasets] [--linked-reports]")
 	fmt.Println("  registry update --id ... (same flags as create)")
 	fmt.Println("  registry compute-proteins --id [--attachment-id] [--overwrite] [--include-backbone]")
 	fmt.Println("  registry update-protein --id --index [--is-target] [--name] [--tag] [--tag-location] [--cleavage-site] [--uniprot-id]")
 	fmt.Println("  registry export [--out]")
+	fmt.Println("  registry watch [--format text|json|ndjson|yaml|table] [--filter] [--project] [--kind] [--tag] [--id] [--since] [--transport sse|mqtt] [--mqtt-broker] [--mqtt-topic-prefix] [--mqtt-username] [--mqtt-password]")
+	fmt.Println("    (--transport sse tries text/event-stream first and falls back to a hand-rolled WebSocket upgrade on the same path if the server doesn't offer SSE; --since replays missed events from /api/audit before switching to the live stream)")
 	fmt.Println("  registry attachments list --id")
-	fmt.Println("  registry attachments upload --id --file")
+	fmt.Println("  registry attachments upload --id --file [--storage-backend] [--chunked] [--resume] [--chunk-size]")
+	fmt.Println("    (--chunked splits the file into --chunk-size parts (default 8MiB) each sent with its own SHA-256 and committed once all land; --resume picks up a prior --chunked upload of the same file from ~/.labbook/uploads, skipping parts the server already acked)")
 	fmt.Println("  registry attachments delete --id --attachment-id")
-	fmt.Println("  registry attachments download --id --attachment-id [--out]")
+	fmt.Println("  registry attachments download --id --attachment-id [--out] [--storage-backend]")
+	fmt.Println("  registry import --kind plasmid --format genbank|fasta --file plasmids.gb [--resistance-pattern] [--dry-run]")
+	fmt.Println("  registry import --kind primers --format fasta --file primers.fasta [--allow-ambig] [--dry-run]")
+	fmt.Println("  registry apply -f manifest.yaml [--diff] [--prune] [--workspace]")
+	fmt.Println("  registry defline --id [--defline-template] [--set]")
+	fmt.Println("  registry validate --id [--allow-ambig] [--strict]")
 	fmt.Println("  entries list [--q] [--project] [--tag] [--from] [--to]")
-	fmt.Println("  entries list [--uses-registry] [--produces-registry]")
-	fmt.Println("  entries get --id")
+	fmt.Println("  entries list [--uses-registry] [--produces-registry] [--filter] [--sort]")
+	fmt.Println("  entries list [--output text|json|jsonl|yaml|table] [--columns] [--output-filter]")
+	fmt.Println("    (--output/--columns/--output-filter are handled by internal/render; --output-filter is a small JSONPath subset, independent of the RSQL --filter above — other list commands still print raw JSON)")
+	fmt.Println("  entries get --id [--strip-html] [--format] [--width]")
 	fmt.Println("  entries create --title --content-html [--project] [--tags] [--content-file] [--widgets] [--widgets-file] [--metadata] [--metadata-file] [--uses] [--produces] [--registry-links] [--registry-links-file] [--agent-id]")
 	fmt.Println("  entries update --id --title --content-html [--project] [--tags] [--content-file] [--widgets] [--widgets-file] [--metadata] [--metadata-file] [--merge-metadata] [--uses] [--produces] [--registry-links] [--registry-links-file] [--agent-id]")
 	fmt.Println("  entries append --id --append-html [--append-file] [--agent-id]")
 	fmt.Println("  entries delete --id")
+	fmt.Println("  entries watch [--format text|json|ndjson|yaml|table] [--filter] [--project] [--kind] [--tag] [--id] [--since] [--transport sse|mqtt] [--mqtt-broker] [--mqtt-topic-prefix] [--mqtt-username] [--mqtt-password]")
+	fmt.Println("    (e.g. 'entries watch --id 42' for a single entry's changes, suitable as an inotifywait-style notification source around a lab notebook)")
 	fmt.Println("  entries links --id")
 	fmt.Println("  entries link --entry-id --registry-id --type [--details]")
 	fmt.Println("  entries unlink --entry-id --link-id")
@@ -172,27 +243,35 @@ func usage() {
 	fmt.Println("  entries unlock --id")
 	fmt.Println("  entries versions --id")
 	fmt.Println("  entries restore --id --version-id")
-	fmt.Println("  entries export-html --id [--out]")
+	fmt.Println("  entries export-html --id [--format html|text|markdown] [--width] [--out]")
+	fmt.Println("  entries export-text --id [--format text|markdown] [--width] [--out]")
 	fmt.Println("  entries shares list --id")
 	fmt.Println("  entries shares add --id --user-email --permission")
 	fmt.Println("  entries shares delete --id --share-id")
 	fmt.Println("  entries attachments list --id")
-	fmt.Println("  entries attachments upload --id --file")
+	fmt.Println("  entries attachments upload --id --file [--storage-backend] [--no-progress] [--silent] [--chunked] [--resume] [--chunk-size]")
 	fmt.Println("  entries attachments delete --id --attachment-id")
-	fmt.Println("  entries attachments download --id --attachment-id [--out]")
+	fmt.Println("  entries attachments download --id --attachment-id [--out] [--storage-backend] [--no-progress] [--silent]")
 	fmt.Println("  templates list")
 	fmt.Println("  templates create --name --content-html [--content-file]")
 	fmt.Println("  templates shares list --id")
 	fmt.Println("  templates shares add --id --user-email --permission")
 	fmt.Println("  templates shares delete --id --share-id")
-	fmt.Println("  templates render --id [--vars] [--vars-file] [--out] [--out-widgets]")
+	fmt.Println("  templates render --id [--vars] [--vars-file] [--format html|text|markdown] [--width] [--out] [--out-widgets]")
+	fmt.Println("  templates render-text --id [--vars] [--vars-file] [--format text|markdown] [--width] [--out]")
 	fmt.Println("  uploads upload --file")
-	fmt.Println("  audit list [--limit]")
+	fmt.Println("  audit list [--limit] [--filter] [--sort]")
 	fmt.Println("  api-keys create --name --user-email --scopes")
+	fmt.Println("  watch [--format] [--filter] [--since] [--transport] [--mqtt-broker] [--mqtt-topic-prefix] [--mqtt-username] [--mqtt-password] (both entries and registry topics)")
+	fmt.Println("  run --jobs jobs.toml [--once] [--dry-run] [--state-dir] [--metrics-addr]")
+	fmt.Println("  batch --file jobs.json [--parallel] [--continue-on-error]")
+	fmt.Println("  attachments verify [--backend]")
+	fmt.Println("  attachments sync --entry-id|--registry-id --dir [--include] [--exclude] [--prune] [--dry-run]")
+	fmt.Println("    (diffs --dir against the .labbook-sync manifest from the last sync, uploading new/changed files and, with --prune, deleting attachments for files removed locally)")
 	fmt.Println("  widgets types")
 	fmt.Println("  widgets compute --widgets [--widgets-file]")
 	fmt.Println("Environment:")
-	fmt.Println("  LABBOOK_BASE_URL, LABBOOK_API_KEY, LABBOOK_TOKEN")
+	fmt.Println("  LABBOOK_BASE_URL, LABBOOK_API_KEY, LABBOOK_TOKEN, LABBOOK_WORKSPACE")
 }
 
 func healthCmd(c client) {
@@ -264,7 +343,7 @@ func uploadsCmd(c client, args []string) {
 		if strings.TrimSpace(*filePath) == "" {
 			exitOnError(errors.New("file is required"))
 		}
-		body, err := c.requestUpload("/api/uploads", *filePath)
+		body, err := c.requestUpload(c.rootContext(), "/api/uploads", *filePath, false)
 		exitOnError(err)
 		fmt.Println(string(body))
 	default:
@@ -289,8 +368,24 @@ func registryCmd(c client, args []string) {
 		usedInEntry := fs.Int("used-in-entry", 0, "Entry ID (uses)")
 		producedByEntry := fs.Int("produced-by-entry", 0, "Entry ID (produces)")
 		includeEntryLinks := fs.Bool("include-entry-links", false, "Include entry links")
+		filterExpr := fs.String("filter", "", "RSQL filter expression, e.g. 'kind==Plasmid;metadata.resistance=in=(kan,amp)' (--show-total reports the pre-filter total)")
+		workspace := fs.String("workspace", c.workspace, "Workspace(s) to search (comma-separated, \"*\" for all visible); defaults to the active --workspace")
+		output := fs.String("output", "text", "Output format: text, json, jsonl, yaml, or table")
+		columns := fs.String("columns", "", "Comma-separated column list for --output table")
+		outputFilter := fs.String("output-filter", "", "Post-filter the result with a small JSONPath subset, e.g. '$[?(@.kind==\"Plasmid\")].name' (independent of the RSQL --filter above)")
 		_ = fs.Parse(args[1:])
 
+		var filterRemainder rsql.Node
+		if strings.TrimSpace(*filterExpr) != "" {
+			node, err := rsql.Parse(*filterExpr)
+			exitOnError(err)
+			serverParams, remainder := extractServerParams(node, registryFilterMapper)
+			filterRemainder = remainder
+			if v := serverParams.Get("kind"); v != "" {
+				*kind = v
+			}
+		}
+
 		params := url.Values{}
 		if strings.TrimSpace(*query) != "" {
 			params.Set("q", *query)
@@ -313,6 +408,12 @@ func registryCmd(c client, args []string) {
 		if *includeEntryLinks {
 			params.Set("includeEntryLinks", "1")
 		}
+		if strings.TrimSpace(*workspace) != "" {
+			params.Set("workspace", *workspace)
+		}
+		if filterRemainder != nil {
+			params.Set("filter", filterRemainder.String())
+		}
 		path := "/api/registry"
 		if encoded := params.Encode(); encoded != "" {
 			path += "?" + encoded
@@ -328,26 +429,35 @@ func registryCmd(c client, args []string) {
 			Total int            `json:"total"`
 		}
 		exitOnError(json.Unmarshal(body, &payload))
-		data, err := json.Marshal(payload.Items)
-		exitOnError(err)
-		fmt.Println(string(data))
+		if filterRemainder != nil {
+			payload.Items = filterRegistryItemsByRSQL(payload.Items, filterRemainder)
+		}
+		exitOnError(renderValue(*output, *outputFilter, parseColumns(*columns), payload.Items))
 	case "get":
 		fs := flag.NewFlagSet("registry get", flag.ExitOnError)
 		id := fs.Int("id", 0, "Registry ID")
 		showEntries := fs.Bool("show-entries", false, "Include entry links")
+		workspace := fs.String("workspace", c.workspace, "Workspace(s) to search (comma-separated, \"*\" for all visible); defaults to the active --workspace")
 		_ = fs.Parse(args[1:])
 		if *id == 0 {
 			exitOnError(errors.New("id is required"))
 		}
-		path := fmt.Sprintf("/api/registry/%d", *id)
+		params := url.Values{}
 		if *showEntries {
-			path += "?includeEntryLinks=1"
+			params.Set("includeEntryLinks", "1")
+		}
+		if strings.TrimSpace(*workspace) != "" {
+			params.Set("workspace", *workspace)
+		}
+		path := fmt.Sprintf("/api/registry/%d", *id)
+		if encoded := params.Encode(); encoded != "" {
+			path += "?" + encoded
 		}
 		body, err := c.request("GET", path, nil)
 		exitOnError(err)
 		fmt.Println(string(body))
 	case "create":
-		payload := parseRegistryPayload("registry create", args[1:])
+		payload := parseRegistryPayload(c, "registry create", args[1:])
 		body, err := c.request("POST", "/api/registry", payload)
 		exitOnError(err)
 		fmt.Println(string(body))
@@ -425,6 +535,16 @@ func registryCmd(c client, args []string) {
 		body, err := c.request("GET", "/api/registry/export/csv", nil)
 		exitOnError(err)
 		exitOnError(writeOutput(*out, body))
+	case "watch":
+		watchCmd(c, []string{"registry"}, args[1:])
+	case "import":
+		registryImportCmd(c, args[1:])
+	case "apply":
+		registryApplyCmd(c, args[1:])
+	case "defline":
+		registryDeflineCmd(c, args[1:])
+	case "validate":
+		registryValidateCmd(c, args[1:])
 	case "attachments":
 		if len(args) < 2 {
 			usage()
@@ -445,13 +565,33 @@ func registryCmd(c client, args []string) {
 			fs := flag.NewFlagSet("registry attachments upload", flag.ExitOnError)
 			id := fs.Int("id", 0, "Registry ID")
 			filePath := fs.String("file", "", "Path to file")
+			storageBackend := fs.String("storage-backend", "", "Mirror the upload to this object storage URL (s3://, azblob://, gs://, file://); defaults to LABBOOK_STORAGE_URL")
+			chunked := fs.Bool("chunked", false, "Upload in fixed-size parts instead of a single request; required for --resume")
+			resume := fs.Bool("resume", false, "Resume a prior --chunked upload of this file, skipping parts the server already acked")
+			chunkSize := fs.Int64("chunk-size", defaultChunkSize, "Part size in bytes for --chunked/--resume uploads")
 			_ = fs.Parse(args[2:])
 			if *id == 0 || strings.TrimSpace(*filePath) == "" {
 				exitOnError(errors.New("id and file are required"))
 			}
-			body, err := c.requestUpload(fmt.Sprintf("/api/registry/%d/attachments", *id), *filePath)
+			targetPath := fmt.Sprintf("/api/registry/%d/attachments", *id)
+			var body []byte
+			var err error
+			if *chunked || *resume {
+				u := NewUploader(c)
+				u.ChunkSize = *chunkSize
+				u.Resume = *resume
+				body, err = u.Upload(c.rootContext(), targetPath, *filePath)
+			} else {
+				body, err = c.requestUpload(c.rootContext(), targetPath, *filePath, false)
+			}
 			exitOnError(err)
 			fmt.Println(string(body))
+			if backendURL := storageBackendURL(*storageBackend); backendURL != "" {
+				attachmentID := parseUploadedAttachmentID(body)
+				var wg sync.WaitGroup
+				mirrorUploadAttachmentAsync(&wg, backendURL, attachmentID, *filePath)
+				wg.Wait()
+			}
 		case "delete":
 			fs := flag.NewFlagSet("registry attachments delete", flag.ExitOnError)
 			id := fs.Int("id", 0, "Registry ID")
@@ -468,16 +608,14 @@ func registryCmd(c client, args []string) {
 			id := fs.Int("id", 0, "Registry ID")
 			attachmentID := fs.Int("attachment-id", 0, "Attachment ID")
 			out := fs.String("out", "", "Write output to file")
+			storageBackend := fs.String("storage-backend", "", "Fall back to this object storage URL if the server is unreachable; defaults to LABBOOK_STORAGE_URL")
 			_ = fs.Parse(args[2:])
 			if *id == 0 || *attachmentID == 0 {
 				exitOnError(errors.New("id and attachment-id are required"))
 			}
-			fileURL, fileName := fetchAttachmentURL(c, fmt.Sprintf("/api/registry/%d/attachments", *id), *attachmentID)
-			downloadPath := *out
-			if strings.TrimSpace(downloadPath) == "" {
-				downloadPath = fileName
-			}
-			exitOnError(downloadFile(c.baseURL, fileURL, downloadPath))
+			listPath := fmt.Sprintf("/api/registry/%d/attachments", *id)
+			backendURL := storageBackendURL(*storageBackend)
+			exitOnError(downloadAttachmentOrMirror(c.rootContext(), c, listPath, *attachmentID, backendURL, *out, false))
 		default:
 			usage()
 			os.Exit(1)
@@ -503,10 +641,10 @@ func parseRegistryUpdateArgs(c client, args []string) (int, bool, interface{}) {
 		existing = &item
 	}
 	if merge {
-		_, patch := parseRegistryPatchPayloadWithID("registry update", stripMergeFlag(args), existing)
+		_, patch := parseRegistryPatchPayloadWithID(c, "registry update", stripMergeFlag(args), existing)
 		return id, true, patch
 	}
-	_, full := parseRegistryPayloadWithID("registry update", stripMergeFlag(args))
+	_, full := parseRegistryPayloadWithID(c, "registry update", stripMergeFlag(args))
 	return id, false, full
 }
 
@@ -571,8 +709,34 @@ func entriesCmd(c client, args []string) {
 		to := fs.String("to", "", "To date (YYYY-MM-DD)")
 		usesRegistry := fs.Int("uses-registry", 0, "Registry ID (uses)")
 		producesRegistry := fs.Int("produces-registry", 0, "Registry ID (produces)")
+		filterExpr := fs.String("filter", "", "RSQL filter expression, e.g. 'project==\"Cloning\";tags=in=(cryo,em)'")
+		sortExpr := fs.String("sort", "", "Comma-separated sort fields, '-' prefix for descending, e.g. 'createdAt,-title'")
+		output := fs.String("output", "text", "Output format: text, json, jsonl, yaml, or table")
+		columns := fs.String("columns", "", "Comma-separated column list for --output table")
+		outputFilter := fs.String("output-filter", "", "Post-filter the result with a small JSONPath subset, e.g. '$[?(@.project==\"Cloning\")].title' (independent of the RSQL --filter above)")
 		_ = fs.Parse(args[1:])
 
+		var filterRemainder rsql.Node
+		if strings.TrimSpace(*filterExpr) != "" {
+			node, err := rsql.Parse(*filterExpr)
+			exitOnError(err)
+			exitOnError(validateFilterFields(node, entriesFilterableFields))
+			serverParams, remainder := extractServerParams(node, entriesFilterMapper)
+			filterRemainder = remainder
+			if v := serverParams.Get("project"); v != "" && strings.TrimSpace(*project) == "" {
+				*project = v
+			}
+			if v := serverParams.Get("tag"); v != "" && strings.TrimSpace(*tag) == "" {
+				*tag = v
+			}
+			if v := serverParams.Get("from"); v != "" && strings.TrimSpace(*from) == "" {
+				*from = v
+			}
+			if v := serverParams.Get("to"); v != "" && strings.TrimSpace(*to) == "" {
+				*to = v
+			}
+		}
+
 		queryParams := make([]string, 0)
 		if strings.TrimSpace(*query) != "" {
 			queryParams = append(queryParams, "q="+url.QueryEscape(*query))
@@ -595,22 +759,52 @@ func entriesCmd(c client, args []string) {
 		if *producesRegistry > 0 {
 			queryParams = append(queryParams, fmt.Sprintf("producesRegistry=%d", *producesRegistry))
 		}
+		if filterRemainder != nil {
+			queryParams = append(queryParams, "filter="+url.QueryEscape(filterRemainder.String()))
+		}
+		if strings.TrimSpace(*sortExpr) != "" {
+			sort, err := parseSortParam(*sortExpr, entriesSortableFields)
+			exitOnError(err)
+			queryParams = append(queryParams, "sort="+url.QueryEscape(sort))
+		}
 		path := "/api/entries"
 		if len(queryParams) > 0 {
 			path += "?" + strings.Join(queryParams, "&")
 		}
 		body, err := c.request("GET", path, nil)
 		exitOnError(err)
-		fmt.Println(string(body))
+		if filterRemainder != nil {
+			filtered, err := filterJSONItems(body, filterRemainder)
+			exitOnError(err)
+			body = filtered
+		}
+		if strings.TrimSpace(*outputFilter) == "" && (strings.TrimSpace(*output) == "" || strings.EqualFold(*output, "text")) {
+			fmt.Println(string(body))
+			return
+		}
+		var items interface{}
+		exitOnError(json.Unmarshal(body, &items))
+		exitOnError(renderValue(*output, *outputFilter, parseColumns(*columns), items))
 	case "get":
 		fs := flag.NewFlagSet("entries get", flag.ExitOnError)
 		id := fs.Int("id", 0, "Entry ID")
+		stripHTML := fs.Bool("strip-html", false, "Render contentHtml as readable plain text instead of raw JSON")
+		format := fs.String("format", "text", "text|markdown (with --strip-html)")
+		width := fs.Int("width", html2text.DefaultWidth, "Word-wrap width, 0 = no wrap (with --strip-html)")
 		_ = fs.Parse(args[1:])
 		if *id == 0 {
 			exitOnError(errors.New("id is required"))
 		}
 		body, err := c.request("GET", fmt.Sprintf("/api/entries/%d", *id), nil)
 		exitOnError(err)
+		if *stripHTML {
+			var payload entryPayload
+			exitOnError(json.Unmarshal(body, &payload))
+			text, err := html2text.Convert(payload.ContentHTML, html2textOptions(*format, *width, payload.Widgets))
+			exitOnError(err)
+			fmt.Print(text)
+			return
+		}
 		fmt.Println(string(body))
 	case "create":
 		payload, _ := parseEntryPayload("entries create", args[1:])
@@ -712,6 +906,8 @@ func entriesCmd(c client, args []string) {
 		body, err := c.request("DELETE", fmt.Sprintf("/api/entries/%d", *id), nil)
 		exitOnError(err)
 		fmt.Println(string(body))
+	case "watch":
+		watchCmd(c, []string{"entries"}, args[1:])
 	case "versions":
 		fs := flag.NewFlagSet("entries versions", flag.ExitOnError)
 		id := fs.Int("id", 0, "Entry ID")
@@ -736,14 +932,46 @@ func entriesCmd(c client, args []string) {
 	case "export-html":
 		fs := flag.NewFlagSet("entries export-html", flag.ExitOnError)
 		id := fs.Int("id", 0, "Entry ID")
+		format := fs.String("format", "html", "html|text|markdown")
+		width := fs.Int("width", html2text.DefaultWidth, "Word-wrap width, 0 = no wrap (with --format text/markdown)")
+		out := fs.String("out", "", "Write output to file")
+		_ = fs.Parse(args[1:])
+		if *id == 0 {
+			exitOnError(errors.New("id is required"))
+		}
+		switch *format {
+		case "html", "":
+			body, err := c.request("GET", fmt.Sprintf("/api/entries/%d/export/html", *id), nil)
+			exitOnError(err)
+			exitOnError(writeOutput(*out, body))
+		case "text", "markdown":
+			entry, err := c.request("GET", fmt.Sprintf("/api/entries/%d", *id), nil)
+			exitOnError(err)
+			var payload entryPayload
+			exitOnError(json.Unmarshal(entry, &payload))
+			text, err := html2text.Convert(payload.ContentHTML, html2textOptions(*format, *width, payload.Widgets))
+			exitOnError(err)
+			exitOnError(writeOutput(*out, []byte(text)))
+		default:
+			exitOnError(fmt.Errorf("unknown --format %q (want html, text, or markdown)", *format))
+		}
+	case "export-text":
+		fs := flag.NewFlagSet("entries export-text", flag.ExitOnError)
+		id := fs.Int("id", 0, "Entry ID")
+		format := fs.String("format", "text", "text|markdown")
+		width := fs.Int("width", html2text.DefaultWidth, "Word-wrap width (0 = no wrap)")
 		out := fs.String("out", "", "Write output to file")
 		_ = fs.Parse(args[1:])
 		if *id == 0 {
 			exitOnError(errors.New("id is required"))
 		}
-		body, err := c.request("GET", fmt.Sprintf("/api/entries/%d/export/html", *id), nil)
+		entry, err := c.request("GET", fmt.Sprintf("/api/entries/%d", *id), nil)
 		exitOnError(err)
-		exitOnError(writeOutput(*out, body))
+		var payload entryPayload
+		exitOnError(json.Unmarshal(entry, &payload))
+		text, err := html2text.Convert(payload.ContentHTML, html2textOptions(*format, *width, payload.Widgets))
+		exitOnError(err)
+		exitOnError(writeOutput(*out, []byte(text)))
 	case "shares":
 		if len(args) < 2 {
 			usage()
@@ -808,13 +1036,38 @@ func entriesCmd(c client, args []string) {
 			fs := flag.NewFlagSet("entries attachments upload", flag.ExitOnError)
 			id := fs.Int("id", 0, "Entry ID")
 			filePath := fs.String("file", "", "Path to file")
+			storageBackend := fs.String("storage-backend", "", "Mirror the upload to this object storage URL (s3://, azblob://, gs://, file://); defaults to LABBOOK_STORAGE_URL")
+			noProgress := fs.Bool("no-progress", false, "Don't render a transfer progress bar to stderr")
+			silent := fs.Bool("silent", false, "Suppress the transfer progress bar and other non-essential stderr output")
+			chunked := fs.Bool("chunked", false, "Upload in fixed-size parts instead of a single request; required for --resume")
+			resume := fs.Bool("resume", false, "Resume a prior --chunked upload of this file, skipping parts the server already acked")
+			chunkSize := fs.Int64("chunk-size", defaultChunkSize, "Part size in bytes for --chunked/--resume uploads")
 			_ = fs.Parse(args[2:])
 			if *id == 0 || strings.TrimSpace(*filePath) == "" {
 				exitOnError(errors.New("id and file are required"))
 			}
-			body, err := c.requestUpload(fmt.Sprintf("/api/entries/%d/attachments", *id), *filePath)
+			ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+			defer stop()
+			targetPath := fmt.Sprintf("/api/entries/%d/attachments", *id)
+			var body []byte
+			var err error
+			if *chunked || *resume {
+				u := NewUploader(c)
+				u.ChunkSize = *chunkSize
+				u.Resume = *resume
+				u.Progress = !*noProgress && !*silent
+				body, err = u.Upload(ctx, targetPath, *filePath)
+			} else {
+				body, err = c.requestUpload(ctx, targetPath, *filePath, !*noProgress && !*silent)
+			}
 			exitOnError(err)
 			fmt.Println(string(body))
+			if backendURL := storageBackendURL(*storageBackend); backendURL != "" {
+				attachmentID := parseUploadedAttachmentID(body)
+				var wg sync.WaitGroup
+				mirrorUploadAttachmentAsync(&wg, backendURL, attachmentID, *filePath)
+				wg.Wait()
+			}
 		case "delete":
 			fs := flag.NewFlagSet("entries attachments delete", flag.ExitOnError)
 			id := fs.Int("id", 0, "Entry ID")
@@ -831,16 +1084,18 @@ func entriesCmd(c client, args []string) {
 			id := fs.Int("id", 0, "Entry ID")
 			attachmentID := fs.Int("attachment-id", 0, "Attachment ID")
 			out := fs.String("out", "", "Write output to file")
+			storageBackend := fs.String("storage-backend", "", "Fall back to this object storage URL if the server is unreachable; defaults to LABBOOK_STORAGE_URL")
+			noProgress := fs.Bool("no-progress", false, "Don't render a transfer progress bar to stderr")
+			silent := fs.Bool("silent", false, "Suppress the transfer progress bar and other non-essential stderr output")
 			_ = fs.Parse(args[2:])
 			if *id == 0 || *attachmentID == 0 {
 				exitOnError(errors.New("id and attachment-id are required"))
 			}
-			fileURL, fileName := fetchAttachmentURL(c, fmt.Sprintf("/api/entries/%d/attachments", *id), *attachmentID)
-			downloadPath := *out
-			if strings.TrimSpace(downloadPath) == "" {
-				downloadPath = fileName
-			}
-			exitOnError(downloadFile(c.baseURL, fileURL, downloadPath))
+			ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+			defer stop()
+			listPath := fmt.Sprintf("/api/entries/%d/attachments", *id)
+			backendURL := storageBackendURL(*storageBackend)
+			exitOnError(downloadAttachmentOrMirror(ctx, c, listPath, *attachmentID, backendURL, *out, !*noProgress && !*silent))
 		default:
 			usage()
 			os.Exit(1)
@@ -871,7 +1126,9 @@ func templatesCmd(c client, args []string) {
 		id := fs.Int("id", 0, "Template ID")
 		vars := fs.String("vars", "", "Variables JSON")
 		varsFile := fs.String("vars-file", "", "Variables JSON file")
-		out := fs.String("out", "", "Write rendered HTML output to file")
+		format := fs.String("format", "html", "html|text|markdown")
+		width := fs.Int("width", html2text.DefaultWidth, "Word-wrap width, 0 = no wrap (with --format text/markdown)")
+		out := fs.String("out", "", "Write rendered output to file")
 		outWidgets := fs.String("out-widgets", "", "Write widgets JSON array to file")
 		_ = fs.Parse(args[1:])
 		if *id == 0 {
@@ -882,8 +1139,8 @@ func templatesCmd(c client, args []string) {
 		body, err := c.request("POST", fmt.Sprintf("/api/templates/%d/render", *id), payload)
 		exitOnError(err)
 
-		// Backward compatible behavior: if no output flags are provided, print raw JSON response.
-		if strings.TrimSpace(*out) == "" && strings.TrimSpace(*outWidgets) == "" {
+		// Backward compatible behavior: html format with no output flags prints the raw JSON response.
+		if *format == "html" && strings.TrimSpace(*out) == "" && strings.TrimSpace(*outWidgets) == "" {
 			exitOnError(writeOutput("", body))
 			break
 		}
@@ -895,11 +1152,22 @@ func templatesCmd(c client, args []string) {
 		var resp templateRenderResponse
 		exitOnError(json.Unmarshal(body, &resp))
 
-		// If --out is provided, write HTML there; otherwise print HTML to stdout.
-		if strings.TrimSpace(*out) != "" {
-			exitOnError(writeOutput(*out, []byte(resp.RenderedHTML)))
-		} else {
-			exitOnError(writeOutput("", []byte(resp.RenderedHTML)))
+		switch *format {
+		case "html":
+			// If --out is provided, write HTML there; otherwise print HTML to stdout.
+			if strings.TrimSpace(*out) != "" {
+				exitOnError(writeOutput(*out, []byte(resp.RenderedHTML)))
+			} else {
+				exitOnError(writeOutput("", []byte(resp.RenderedHTML)))
+			}
+		case "text", "markdown":
+			var widgets interface{}
+			_ = json.Unmarshal(resp.Widgets, &widgets)
+			text, err := html2text.Convert(resp.RenderedHTML, html2textOptions(*format, *width, widgets))
+			exitOnError(err)
+			exitOnError(writeOutput(*out, []byte(text)))
+		default:
+			exitOnError(fmt.Errorf("unknown --format %q (want html, text, or markdown)", *format))
 		}
 
 		// If --out-widgets is provided, write the widgets array to that file.
@@ -914,6 +1182,32 @@ func templatesCmd(c client, args []string) {
 			}
 			exitOnError(writeOutput(*outWidgets, widgetsBytes))
 		}
+	case "render-text":
+		fs := flag.NewFlagSet("templates render-text", flag.ExitOnError)
+		id := fs.Int("id", 0, "Template ID")
+		vars := fs.String("vars", "", "Variables JSON")
+		varsFile := fs.String("vars-file", "", "Variables JSON file")
+		format := fs.String("format", "text", "text|markdown")
+		width := fs.Int("width", html2text.DefaultWidth, "Word-wrap width (0 = no wrap)")
+		out := fs.String("out", "", "Write output to file")
+		_ = fs.Parse(args[1:])
+		if *id == 0 {
+			exitOnError(errors.New("id is required"))
+		}
+		varMap := parseVars(*vars, *varsFile)
+		payload := map[string]interface{}{"variables": varMap}
+		body, err := c.request("POST", fmt.Sprintf("/api/templates/%d/render", *id), payload)
+		exitOnError(err)
+
+		type templateRenderResponse struct {
+			RenderedHTML string      `json:"renderedHtml"`
+			Widgets      interface{} `json:"widgets"`
+		}
+		var resp templateRenderResponse
+		exitOnError(json.Unmarshal(body, &resp))
+		text, err := html2text.Convert(resp.RenderedHTML, html2textOptions(*format, *width, resp.Widgets))
+		exitOnError(err)
+		exitOnError(writeOutput(*out, []byte(text)))
 	case "shares":
 		if len(args) < 2 {
 			usage()
@@ -968,10 +1262,32 @@ func auditCmd(c client, args []string) {
 	exitOnError(ensureToken(c))
 	fs := flag.NewFlagSet("audit list", flag.ExitOnError)
 	limit := fs.Int("limit", 50, "Number of entries")
+	filterExpr := fs.String("filter", "", "RSQL filter expression, e.g. 'action==update;resourceType==entry'")
+	sortExpr := fs.String("sort", "", "Comma-separated sort fields, '-' prefix for descending, e.g. '-createdAt'")
 	_ = fs.Parse(args)
+
+	var node rsql.Node
+	if strings.TrimSpace(*filterExpr) != "" {
+		var err error
+		node, err = rsql.Parse(*filterExpr)
+		exitOnError(err)
+		exitOnError(validateFilterFields(node, auditFilterableFields))
+	}
+
 	path := fmt.Sprintf("/api/audit?limit=%d", *limit)
+	if strings.TrimSpace(*sortExpr) != "" {
+		sort, err := parseSortParam(*sortExpr, auditSortableFields)
+		exitOnError(err)
+		path += "&sort=" + url.QueryEscape(sort)
+	}
 	body, err := c.request("GET", path, nil)
 	exitOnError(err)
+	if node != nil {
+		filtered, err := filterJSONItems(body, node)
+		exitOnError(err)
+		fmt.Println(string(filtered))
+		return
+	}
 	fmt.Println(string(body))
 }
 
@@ -1149,17 +1465,18 @@ func parseEntryPayloadWithID(name string, args []string) (int, entryPayload, boo
 	return *id, payload, metadataProvided, *mergeMetadata
 }
 
-func parseRegistryPayload(name string, args []string) registryCreatePayload {
-	_, payload := parseRegistryPayloadWithID(name, args)
+func parseRegistryPayload(c client, name string, args []string) registryCreatePayload {
+	_, payload := parseRegistryPayloadWithID(c, name, args)
 	return payload
 }
 
-func parseRegistryPayloadWithID(name string, args []string) (int, registryCreatePayload) {
+func parseRegistryPayloadWithID(c client, name string, args []string) (int, registryCreatePayload) {
 	fs := flag.NewFlagSet(name, flag.ExitOnError)
 	id := fs.Int("id", 0, "Registry ID")
 	nameValue := fs.String("name", "", "Name")
 	kind := fs.String("kind", "", "Kind")
 	description := fs.String("description", "", "Description")
+	workspace := fs.String("workspace", "", "Workspace/lab-group this item belongs to (defaults to the active --workspace)")
 	plasmidID := fs.String("plasmid-id", "", "Plasmid ID")
 	insert := fs.String("insert", "", "Insert")
 	backbone := fs.String("backbone", "", "Backbone")
@@ -1170,6 +1487,7 @@ func parseRegistryPayloadWithID(name string, args []string) (int, registryCreate
 	concentration := fs.Float64("concentration", 0, "Concentration (ng/uL)")
 	sequenced := fs.String("sequenced", "", "Sequenced? (yes/no)")
 	sequenceAA := fs.String("sequence-aa", "", "Sequence of insert/ORF (AA)")
+	sequenceNT := fs.String("sequence-nt", "", "Nucleotide sequence of insert/ORF")
 	comments := fs.String("comments", "", "Comments")
 	expressionPlasmidID := fs.Int("expression-plasmid-id", 0, "Plasmid registry ID")
 	expressionStrain := fs.String("expression-strain", "", "Expression strain")
@@ -1252,10 +1570,13 @@ func parseRegistryPayloadWithID(name string, args []string) (int, registryCreate
 	preppedBy := fs.String("prepped-by", "", "Prepped by")
 	preppedOn := fs.String("prepped-on", "", "Prepped on (YYYY-MM-DD)")
 	aliquotCount := fs.Int("aliquot-count", 0, "# Aliquots")
+	allowAmbig := fs.Bool("allow-ambig", false, "Tolerate ambiguous bases in --primer-sequence instead of failing the computed-field fill-in")
+	deflineTemplate := fs.String("defline-template", "", "Go template overriding the kind's built-in defline format when --name is left empty")
+	strict := fs.Bool("strict", false, "Refuse to build the payload if sequence validation (see registry validate) finds any errors")
 	_ = fs.Parse(args)
 
-	if strings.TrimSpace(*nameValue) == "" || strings.TrimSpace(*kind) == "" {
-		exitOnError(errors.New("name and kind are required"))
+	if strings.TrimSpace(*kind) == "" {
+		exitOnError(errors.New("kind is required"))
 	}
 
 	normalizedKind := normalizeKind(*kind)
@@ -1281,6 +1602,10 @@ func parseRegistryPayloadWithID(name string, args []string) (int, registryCreate
 	if strings.TrimSpace(*description) != "" {
 		payload.Description = description
 	}
+	payload.Workspace = strings.TrimSpace(*workspace)
+	if payload.Workspace == "" {
+		payload.Workspace = c.workspace
+	}
 
 	if normalizedKind == "plasmid" {
 		meta := map[string]interface{}{}
@@ -1316,6 +1641,12 @@ func parseRegistryPayloadWithID(name string, args []string) (int, registryCreate
 		if strings.TrimSpace(*sequenceAA) != "" {
 			meta["sequenceAA"] = *sequenceAA
 		}
+		if strings.TrimSpace(*sequenceNT) != "" {
+			meta["sequenceNT"] = *sequenceNT
+		}
+		if *molecularWeightDa > 0 {
+			meta["molecularWeightDa"] = *molecularWeightDa
+		}
 		if strings.TrimSpace(*comments) != "" {
 			meta["comments"] = *comments
 		}
@@ -1352,6 +1683,7 @@ func parseRegistryPayloadWithID(name string, args []string) (int, registryCreate
 		}
 		if *plasmidRefID > 0 {
 			meta["plasmidRefId"] = *plasmidRefID
+			annotateCrossWorkspaceRef(c, meta, *plasmidRefID, payload.Workspace, "plasmidRefWorkspace")
 		}
 		if strings.TrimSpace(*expressionSystem) != "" {
 			meta["expressionSystem"] = *expressionSystem
@@ -1385,12 +1717,14 @@ func parseRegistryPayloadWithID(name string, args []string) (int, registryCreate
 		meta := map[string]interface{}{}
 		if *expressionPlasmidID > 0 {
 			meta["expressionPlasmidRefId"] = *expressionPlasmidID
+			annotateCrossWorkspaceRef(c, meta, *expressionPlasmidID, payload.Workspace, "expressionPlasmidRefWorkspace")
 		}
 		if strings.TrimSpace(*expressionStrain) != "" {
 			meta["expressionStrain"] = *expressionStrain
 		}
 		if *virusID > 0 {
 			meta["virusRefId"] = *virusID
+			annotateCrossWorkspaceRef(c, meta, *virusID, payload.Workspace, "virusRefWorkspace")
 		}
 		if *virusVolume > 0 {
 			meta["virusVolume"] = *virusVolume
@@ -1491,6 +1825,7 @@ func parseRegistryPayloadWithID(name string, args []string) (int, registryCreate
 		if strings.TrimSpace(*primerComment) != "" {
 			meta["primerComment"] = *primerComment
 		}
+		fillPrimerThermodynamics(meta, *allowAmbig)
 		if len(meta) > 0 {
 			payload.Metadata = meta
 		}
@@ -1530,6 +1865,7 @@ func parseRegistryPayloadWithID(name string, args []string) (int, registryCreate
 		}
 		if *sampleRefID > 0 {
 			meta["sampleRefId"] = *sampleRefID
+			annotateCrossWorkspaceRef(c, meta, *sampleRefID, payload.Workspace, "sampleRefWorkspace")
 		}
 		if strings.TrimSpace(*sampleConcentration) != "" {
 			meta["sampleConcentration"] = *sampleConcentration
@@ -1605,15 +1941,27 @@ func parseRegistryPayloadWithID(name string, args []string) (int, registryCreate
 		}
 	}
 
+	if strings.TrimSpace(payload.Name) == "" {
+		computed, err := computeDefline(payload.Kind, payload.Metadata, *deflineTemplate)
+		exitOnError(err)
+		if strings.TrimSpace(computed) == "" {
+			exitOnError(errors.New("name is required (or enough metadata to derive one automatically; see registry defline)"))
+		}
+		payload.Name = computed
+	}
+
+	reportSequenceFindings(seqvalidate.ValidateMetadata(payload.Metadata, seqvalidateOptions(*allowAmbig)), *strict)
+
 	return *id, payload
 }
 
-func parseRegistryPatchPayloadWithID(name string, args []string, existing *registryItem) (int, registryPatchPayload) {
+func parseRegistryPatchPayloadWithID(c client, name string, args []string, existing *registryItem) (int, registryPatchPayload) {
 	fs := flag.NewFlagSet(name, flag.ExitOnError)
 	id := fs.Int("id", 0, "Registry ID")
 	nameValue := fs.String("name", "", "Name")
 	kind := fs.String("kind", "", "Kind")
 	description := fs.String("description", "", "Description")
+	workspace := fs.String("workspace", "", "Move this item to a different workspace/lab-group")
 	plasmidID := fs.String("plasmid-id", "", "Plasmid ID")
 	insert := fs.String("insert", "", "Insert")
 	backbone := fs.String("backbone", "", "Backbone")
@@ -1624,6 +1972,7 @@ func parseRegistryPatchPayloadWithID(name string, args []string, existing *regis
 	concentration := fs.Float64("concentration", 0, "Concentration (ng/uL)")
 	sequenced := fs.String("sequenced", "", "Sequenced? (yes/no)")
 	sequenceAA := fs.String("sequence-aa", "", "Sequence of insert/ORF (AA)")
+	sequenceNT := fs.String("sequence-nt", "", "Nucleotide sequence of insert/ORF")
 	comments := fs.String("comments", "", "Comments")
 	expressionPlasmidID := fs.Int("expression-plasmid-id", 0, "Plasmid registry ID")
 	expressionStrain := fs.String("expression-strain", "", "Expression strain")
@@ -1706,6 +2055,8 @@ func parseRegistryPatchPayloadWithID(name string, args []string, existing *regis
 	preppedBy := fs.String("prepped-by", "", "Prepped by")
 	preppedOn := fs.String("prepped-on", "", "Prepped on (YYYY-MM-DD)")
 	aliquotCount := fs.Int("aliquot-count", 0, "# Aliquots")
+	allowAmbig := fs.Bool("allow-ambig", false, "Tolerate ambiguous bases in --primer-sequence instead of failing the computed-field fill-in")
+	deflineTemplate := fs.String("defline-template", "", "Go template overriding the kind's built-in defline format when --name is left empty")
 	_ = fs.Parse(args)
 
 	if *id == 0 {
@@ -1742,6 +2093,18 @@ func parseRegistryPatchPayloadWithID(name string, args []string, existing *regis
 	if strings.TrimSpace(*description) != "" {
 		payload.Description = description
 	}
+	// workspaceValue only decides which workspace a cross-workspace ref (see
+	// annotateCrossWorkspaceRef) is compared against; unlike create, a patch
+	// without --workspace leaves the item's existing workspace untouched.
+	workspaceValue := strings.TrimSpace(*workspace)
+	if workspaceValue != "" {
+		value := workspaceValue
+		payload.Workspace = &value
+	} else if existing != nil {
+		workspaceValue = existing.Workspace
+	} else {
+		workspaceValue = c.workspace
+	}
 
 	if normalizedKind == "plasmid" {
 		meta := map[string]interface{}{}
@@ -1777,6 +2140,12 @@ func parseRegistryPatchPayloadWithID(name string, args []string, existing *regis
 		if strings.TrimSpace(*sequenceAA) != "" {
 			meta["sequenceAA"] = *sequenceAA
 		}
+		if strings.TrimSpace(*sequenceNT) != "" {
+			meta["sequenceNT"] = *sequenceNT
+		}
+		if *molecularWeightDa > 0 {
+			meta["molecularWeightDa"] = *molecularWeightDa
+		}
 		if strings.TrimSpace(*comments) != "" {
 			meta["comments"] = *comments
 		}
@@ -1813,6 +2182,7 @@ func parseRegistryPatchPayloadWithID(name string, args []string, existing *regis
 		}
 		if *plasmidRefID > 0 {
 			meta["plasmidRefId"] = *plasmidRefID
+			annotateCrossWorkspaceRef(c, meta, *plasmidRefID, workspaceValue, "plasmidRefWorkspace")
 		}
 		if strings.TrimSpace(*expressionSystem) != "" {
 			meta["expressionSystem"] = *expressionSystem
@@ -1846,12 +2216,14 @@ func parseRegistryPatchPayloadWithID(name string, args []string, existing *regis
 		meta := map[string]interface{}{}
 		if *expressionPlasmidID > 0 {
 			meta["expressionPlasmidRefId"] = *expressionPlasmidID
+			annotateCrossWorkspaceRef(c, meta, *expressionPlasmidID, workspaceValue, "expressionPlasmidRefWorkspace")
 		}
 		if strings.TrimSpace(*expressionStrain) != "" {
 			meta["expressionStrain"] = *expressionStrain
 		}
 		if *virusID > 0 {
 			meta["virusRefId"] = *virusID
+			annotateCrossWorkspaceRef(c, meta, *virusID, workspaceValue, "virusRefWorkspace")
 		}
 		if *virusVolume > 0 {
 			meta["virusVolume"] = *virusVolume
@@ -1952,6 +2324,7 @@ func parseRegistryPatchPayloadWithID(name string, args []string, existing *regis
 		if strings.TrimSpace(*primerComment) != "" {
 			meta["primerComment"] = *primerComment
 		}
+		fillPrimerThermodynamics(meta, *allowAmbig)
 		if len(meta) > 0 {
 			payload.Metadata = meta
 		}
@@ -1991,6 +2364,7 @@ func parseRegistryPatchPayloadWithID(name string, args []string, existing *regis
 		}
 		if *sampleRefID > 0 {
 			meta["sampleRefId"] = *sampleRefID
+			annotateCrossWorkspaceRef(c, meta, *sampleRefID, workspaceValue, "sampleRefWorkspace")
 		}
 		if strings.TrimSpace(*sampleConcentration) != "" {
 			meta["sampleConcentration"] = *sampleConcentration
@@ -2066,6 +2440,22 @@ func parseRegistryPatchPayloadWithID(name string, args []string, existing *regis
 		}
 	}
 
+	var baseMeta map[string]interface{}
+	if existing != nil {
+		baseMeta = existing.Metadata
+	}
+	if strings.TrimSpace(*nameValue) == "" && payload.Metadata != nil {
+		merged := mergeMetadata(baseMeta, payload.Metadata)
+		computed, err := computeDefline(kindValue, merged, *deflineTemplate)
+		exitOnError(err)
+		if strings.TrimSpace(computed) != "" {
+			payload.Name = &computed
+		}
+	}
+	if payload.Metadata != nil {
+		reportSequenceFindings(seqvalidate.ValidateMetadata(mergeMetadata(baseMeta, payload.Metadata), seqvalidateOptions(*allowAmbig)), false)
+	}
+
 	return *id, payload
 }
 
@@ -2084,6 +2474,97 @@ func splitTags(value string) []string {
 	return tags
 }
 
+// defaultPrimerSaltMonovalentM and defaultPrimerSaltDivalentM are the
+// buffer conditions fillPrimerThermodynamics assumes when computing Tm:
+// 50 mM monovalent cation and no divalent cation, the same default most
+// primer-design tools (e.g. IDT's OligoAnalyzer) use absent a user-supplied
+// buffer composition. This CLI has no flags for salt conditions, so they
+// aren't configurable — if that's ever needed, thread them through
+// alongside --primer-conc-um rather than hardcoding here.
+const (
+	defaultPrimerSaltMonovalentM = 0.05
+	defaultPrimerSaltDivalentM   = 0.0
+	// defaultPrimerConcUM is used for the Tm calculation when the user
+	// didn't set --primer-conc-um, matching OligoAnalyzer's default.
+	defaultPrimerConcUM = 0.25
+)
+
+// fillPrimerThermodynamics computes any of primerLength/primerGcContent/
+// primerMw/primerTm that meta's primerSequence implies but the user left
+// unset, and derives primerYieldUg from primerYieldNmol once MW is known.
+// It's a no-op if primerSequence is empty or every derivable field is
+// already set. A sequence primercalc.Compute rejects (ambiguous bases, or
+// too short to form a single NN step) is reported with exitOnError unless
+// allowAmbig is set, in which case the fields are simply left as the user
+// supplied them.
+func fillPrimerThermodynamics(meta map[string]interface{}, allowAmbig bool) {
+	seq, _ := meta["primerSequence"].(string)
+	if strings.TrimSpace(seq) == "" {
+		return
+	}
+	needsLength := meta["primerLength"] == nil
+	needsGC := meta["primerGcContent"] == nil
+	needsMW := meta["primerMw"] == nil
+	needsTm := meta["primerTm"] == nil
+	if !needsLength && !needsGC && !needsMW && !needsTm {
+		return
+	}
+
+	concUM := defaultPrimerConcUM
+	if v, ok := meta["primerConcentrationUm"].(float64); ok && v > 0 {
+		concUM = v
+	}
+	length, gc, mw, tm, err := primercalc.Compute(seq, defaultPrimerSaltMonovalentM, defaultPrimerSaltDivalentM, concUM)
+	if err != nil {
+		if allowAmbig {
+			return
+		}
+		exitOnError(fmt.Errorf("--primer-sequence: %w", err))
+	}
+	if needsLength {
+		meta["primerLength"] = float64(length)
+	}
+	if needsGC {
+		meta["primerGcContent"] = gc
+	}
+	if needsMW {
+		meta["primerMw"] = mw
+	}
+	if needsTm {
+		meta["primerTm"] = tm
+	}
+	if meta["primerYieldUg"] == nil {
+		if nmol, ok := meta["primerYieldNmol"].(float64); ok && nmol > 0 {
+			meta["primerYieldUg"] = math.Round(nmol*mw/1000*100) / 100
+		}
+	}
+}
+
+// seqvalidateOptions builds the seqvalidate.Options every sequence check
+// in this file runs with. allowAmbig mirrors --allow-ambig's existing
+// meaning for fillPrimerThermodynamics: tolerate IUPAC ambiguity codes in
+// nucleotide sequences instead of flagging them.
+func seqvalidateOptions(allowAmbig bool) seqvalidate.Options {
+	opts := seqvalidate.DefaultOptions()
+	opts.AllowAmbiguousNT = allowAmbig
+	return opts
+}
+
+// reportSequenceFindings prints each finding as a JSON-lines stream to
+// stderr (so it doesn't interleave with a command's JSON payload on
+// stdout) and, when strict is set, refuses to continue if any finding is
+// an error — the --strict behavior registry create wires up.
+func reportSequenceFindings(findings []seqvalidate.Finding, strict bool) {
+	for _, f := range findings {
+		data, err := json.Marshal(f)
+		exitOnError(err)
+		fmt.Fprintln(os.Stderr, string(data))
+	}
+	if strict && seqvalidate.HasErrors(findings) {
+		exitOnError(fmt.Errorf("--strict: sequence validation found errors, refusing to submit"))
+	}
+}
+
 func filterRegistryItems(items []registryItem, query, kind string) []registryItem {
 	query = strings.ToLower(strings.TrimSpace(query))
 	kind = strings.ToLower(strings.TrimSpace(kind))
@@ -2121,92 +2602,248 @@ func parseBool(value string) (bool, error) {
 	}
 }
 
+// requestRetries is how many additional attempts an idempotent request
+// gets past the first, once a transient network or 5xx/429 error is hit.
+const requestRetries = 3
+
+// requestRetryBaseDelay and requestRetryMaxDelay bound the exponential
+// backoff requestCtx waits between retries, before jitter.
+const (
+	requestRetryBaseDelay = 250 * time.Millisecond
+	requestRetryMaxDelay  = 5 * time.Second
+)
+
+// request issues an authenticated JSON request under c's root context
+// (see client.ctx) bounded by c.timeout, retrying transient failures — see
+// requestCtx.
 func (c client) request(method, path string, payload interface{}) ([]byte, error) {
-	var body io.Reader
+	ctx, cancel := c.requestContext()
+	defer cancel()
+	return c.requestCtx(ctx, method, path, payload, true)
+}
+
+// requestNoAuth is request without the Authorization header, for
+// endpoints (login, register) a caller hits before it has a token.
+func (c client) requestNoAuth(method, path string, payload interface{}) ([]byte, error) {
+	ctx, cancel := c.requestContext()
+	defer cancel()
+	return c.requestCtx(ctx, method, path, payload, false)
+}
+
+// rootContext returns c.ctx, or context.Background() if it's unset (e.g.
+// a client built outside main, such as in a test). Unlike
+// requestContext, it carries no --timeout deadline — callers streaming an
+// attachment want to cancel on SIGINT, not on a fixed per-request clock.
+func (c client) rootContext() context.Context {
+	if c.ctx != nil {
+		return c.ctx
+	}
+	return context.Background()
+}
+
+// requestContext derives the context request/requestNoAuth issue their
+// HTTP call under: c.ctx (canceled on SIGINT/SIGTERM, see main) bounded by
+// c.timeout when it's set. Callers must invoke the returned cancel once
+// the request completes.
+func (c client) requestContext() (context.Context, context.CancelFunc) {
+	base := c.ctx
+	if base == nil {
+		base = context.Background()
+	}
+	if c.timeout <= 0 {
+		return base, func() {}
+	}
+	return context.WithTimeout(base, c.timeout)
+}
+
+// requestCtx is the shared implementation behind request/requestNoAuth:
+// it JSON-encodes payload (if any), attaches the Authorization header
+// when auth is set, and issues the request, retrying up to
+// requestRetries times — with exponential backoff and jitter, honoring a
+// 429/503's Retry-After — when the verb is idempotent (GET/PUT/DELETE/
+// HEAD) and the failure looks transient (a network error, or a 429/5xx
+// response). ctx governs the whole attempt sequence, so its deadline or a
+// SIGINT cancellation (see main) aborts immediately instead of waiting
+// out the rest of the retries.
+func (c client) requestCtx(ctx context.Context, method, path string, payload interface{}, auth bool) ([]byte, error) {
+	var bodyBytes []byte
 	if payload != nil {
 		data, err := json.Marshal(payload)
 		if err != nil {
 			return nil, err
 		}
-		body = bytes.NewReader(data)
+		bodyBytes = data
 	}
-	req, err := http.NewRequest(method, c.baseURL+path, body)
-	if err != nil {
-		return nil, err
+
+	var lastErr error
+	for attempt := 0; attempt <= requestRetries; attempt++ {
+		var body io.Reader
+		if bodyBytes != nil {
+			body = bytes.NewReader(bodyBytes)
+		}
+		req, err := http.NewRequestWithContext(ctx, method, c.baseURL+path, body)
+		if err != nil {
+			return nil, err
+		}
+		if auth {
+			if authHeader := c.authHeader(); authHeader != "" {
+				req.Header.Set("Authorization", "Bearer "+authHeader)
+			}
+		}
+		if payload != nil {
+			req.Header.Set("Content-Type", "application/json")
+		}
+
+		res, err := http.DefaultClient.Do(req)
+		if err != nil {
+			if ctx.Err() != nil {
+				return nil, ctx.Err()
+			}
+			if !isIdempotentMethod(method) || attempt == requestRetries {
+				return nil, err
+			}
+			lastErr = err
+			if !waitForRetry(ctx, requestBackoffDelay(attempt, 0)) {
+				return nil, ctx.Err()
+			}
+			continue
+		}
+
+		data, err := io.ReadAll(res.Body)
+		res.Body.Close()
+		if err != nil {
+			return nil, err
+		}
+		if res.StatusCode >= 300 {
+			lastErr = fmt.Errorf("request failed: %s", strings.TrimSpace(string(data)))
+			if isIdempotentMethod(method) && isRetryableStatus(res.StatusCode) && attempt < requestRetries {
+				if !waitForRetry(ctx, requestBackoffDelay(attempt, retryAfterDelay(res.Header))) {
+					return nil, ctx.Err()
+				}
+				continue
+			}
+			return nil, lastErr
+		}
+		return data, nil
 	}
-	if authHeader := c.authHeader(); authHeader != "" {
-		req.Header.Set("Authorization", "Bearer "+authHeader)
+	return nil, lastErr
+}
+
+// isIdempotentMethod reports whether method may be safely retried without
+// risking a duplicate side effect.
+func isIdempotentMethod(method string) bool {
+	switch strings.ToUpper(method) {
+	case http.MethodGet, http.MethodPut, http.MethodDelete, http.MethodHead:
+		return true
+	default:
+		return false
 	}
-	if payload != nil {
-		req.Header.Set("Content-Type", "application/json")
+}
+
+// isRetryableStatus reports whether status looks like a transient
+// server-side condition (rate limiting or an overloaded/unavailable
+// upstream) rather than a request the client should stop resending.
+func isRetryableStatus(status int) bool {
+	switch status {
+	case http.StatusTooManyRequests, http.StatusBadGateway, http.StatusServiceUnavailable, http.StatusGatewayTimeout:
+		return true
+	default:
+		return status >= 500
 	}
-	res, err := http.DefaultClient.Do(req)
-	if err != nil {
-		return nil, err
+}
+
+// retryAfterDelay parses a 429/503 response's Retry-After header (either
+// a delay in seconds or an HTTP-date) into a wait duration, returning 0 if
+// the header is absent, unparseable, or already in the past.
+func retryAfterDelay(header http.Header) time.Duration {
+	value := strings.TrimSpace(header.Get("Retry-After"))
+	if value == "" {
+		return 0
 	}
-	defer res.Body.Close()
-	data, err := io.ReadAll(res.Body)
-	if err != nil {
-		return nil, err
+	if seconds, err := strconv.Atoi(value); err == nil {
+		return time.Duration(seconds) * time.Second
 	}
-	if res.StatusCode >= 300 {
-		return nil, fmt.Errorf("request failed: %s", strings.TrimSpace(string(data)))
+	if when, err := http.ParseTime(value); err == nil {
+		if delay := time.Until(when); delay > 0 {
+			return delay
+		}
 	}
-	return data, nil
+	return 0
 }
 
-func (c client) requestNoAuth(method, path string, payload interface{}) ([]byte, error) {
-	var body io.Reader
-	if payload != nil {
-		data, err := json.Marshal(payload)
-		if err != nil {
-			return nil, err
-		}
-		body = bytes.NewReader(data)
-	}
-	req, err := http.NewRequest(method, c.baseURL+path, body)
-	if err != nil {
-		return nil, err
+// requestBackoffDelay computes the exponential-backoff-with-jitter wait
+// before retry attempt (0-indexed), floored at minDelay (e.g. a server's
+// Retry-After) and capped at requestRetryMaxDelay.
+func requestBackoffDelay(attempt int, minDelay time.Duration) time.Duration {
+	delay := requestRetryBaseDelay * time.Duration(1<<uint(attempt))
+	if delay > requestRetryMaxDelay {
+		delay = requestRetryMaxDelay
 	}
-	if payload != nil {
-		req.Header.Set("Content-Type", "application/json")
+	jittered := time.Duration(float64(delay) * (0.5 + rand.Float64()*0.5))
+	if jittered < minDelay {
+		return minDelay
 	}
-	res, err := http.DefaultClient.Do(req)
-	if err != nil {
-		return nil, err
-	}
-	defer res.Body.Close()
-	data, err := io.ReadAll(res.Body)
-	if err != nil {
-		return nil, err
+	return jittered
+}
+
+// waitForRetry sleeps for delay, returning false without waiting the full
+// duration if ctx is canceled or expires first.
+func waitForRetry(ctx context.Context, delay time.Duration) bool {
+	if delay <= 0 {
+		return ctx.Err() == nil
 	}
-	if res.StatusCode >= 300 {
-		return nil, fmt.Errorf("request failed: %s", strings.TrimSpace(string(data)))
+	timer := time.NewTimer(delay)
+	defer timer.Stop()
+	select {
+	case <-timer.C:
+		return true
+	case <-ctx.Done():
+		return false
 	}
-	return data, nil
 }
 
-func (c client) requestUpload(path, filePath string) ([]byte, error) {
+// requestUpload streams filePath to path as a multipart/form-data POST.
+// The file is read through an io.Pipe by a background goroutine writing
+// into a multipart.Writer, rather than buffered whole into memory, so a
+// multi-GB attachment doesn't need its own copy held in RAM. When
+// progressEnabled is set, a progress bar tracking bytes/rate/ETA is drawn
+// to stderr (newProgressWriter itself decides whether stderr is actually
+// a TTY worth drawing to). If ctx is canceled mid-transfer the pipe write
+// fails, the request aborts, and the bar reports "Aborted." instead of
+// silently dying.
+func (c client) requestUpload(ctx context.Context, path, filePath string, progressEnabled bool) ([]byte, error) {
 	file, err := os.Open(filePath)
 	if err != nil {
 		return nil, err
 	}
 	defer file.Close()
-
-	var body bytes.Buffer
-	writer := multipart.NewWriter(&body)
-	part, err := writer.CreateFormFile("file", filepath.Base(filePath))
+	info, err := file.Stat()
 	if err != nil {
 		return nil, err
 	}
-	if _, err := io.Copy(part, file); err != nil {
-		return nil, err
-	}
-	if err := writer.Close(); err != nil {
-		return nil, err
-	}
 
-	req, err := http.NewRequest("POST", c.baseURL+path, &body)
+	progress := newProgressWriter(os.Stderr, "upload "+filepath.Base(filePath), info.Size(), progressEnabled)
+
+	pr, pw := io.Pipe()
+	writer := multipart.NewWriter(pw)
+	go func() {
+		part, err := writer.CreateFormFile("file", filepath.Base(filePath))
+		if err != nil {
+			pw.CloseWithError(err)
+			return
+		}
+		var reader io.Reader = file
+		if progress != nil {
+			reader = io.TeeReader(reader, progress)
+		}
+		if _, err := io.Copy(part, reader); err != nil {
+			pw.CloseWithError(err)
+			return
+		}
+		pw.CloseWithError(writer.Close())
+	}()
+
+	req, err := http.NewRequestWithContext(ctx, "POST", c.baseURL+path, pr)
 	if err != nil {
 		return nil, err
 	}
@@ -2216,6 +2853,10 @@ func (c client) requestUpload(path, filePath string) ([]byte, error) {
 	req.Header.Set("Content-Type", writer.FormDataContentType())
 	res, err := http.DefaultClient.Do(req)
 	if err != nil {
+		if ctx.Err() != nil {
+			progress.Abort()
+			return nil, errors.New("Aborted.")
+		}
 		return nil, err
 	}
 	defer res.Body.Close()
@@ -2226,6 +2867,7 @@ func (c client) requestUpload(path, filePath string) ([]byte, error) {
 	if res.StatusCode >= 300 {
 		return nil, fmt.Errorf("request failed: %s", strings.TrimSpace(string(data)))
 	}
+	progress.Finish()
 	return data, nil
 }
 
@@ -2237,6 +2879,32 @@ func getEnvOrDefault(key, fallback string) string {
 	return value
 }
 
+// resolveDefaultWorkspace picks what --workspace falls back to when it
+// isn't passed explicitly: $LABBOOK_WORKSPACE first, then a "workspace" key
+// in ~/.labbook/config.json (for a lab machine that's always pointed at one
+// group), else "" (the server decides, typically "all workspaces the token
+// can see").
+func resolveDefaultWorkspace() string {
+	if v := strings.TrimSpace(os.Getenv("LABBOOK_WORKSPACE")); v != "" {
+		return v
+	}
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return ""
+	}
+	data, err := os.ReadFile(filepath.Join(home, ".labbook", "config.json"))
+	if err != nil {
+		return ""
+	}
+	var cfg struct {
+		Workspace string `json:"workspace"`
+	}
+	if json.Unmarshal(data, &cfg) != nil {
+		return ""
+	}
+	return strings.TrimSpace(cfg.Workspace)
+}
+
 func normalizeBaseURL(value string) string {
 	trimmed := strings.TrimSpace(value)
 	return strings.TrimRight(trimmed, "/")
@@ -2312,6 +2980,16 @@ func metadataMatches(metadata map[string]interface{}, query string) bool {
 	return false
 }
 
+// html2textOptions builds html2text.Options from the --format/--width flags
+// shared by export-text, render-text, and get --strip-html.
+func html2textOptions(format string, width int, widgets interface{}) html2text.Options {
+	f := html2text.FormatText
+	if strings.EqualFold(format, "markdown") {
+		f = html2text.FormatMarkdown
+	}
+	return html2text.Options{Format: f, Width: width, Widgets: widgets}
+}
+
 func writeOutput(path string, data []byte) error {
 	if strings.TrimSpace(path) == "" {
 		fmt.Println(string(data))
@@ -2508,6 +3186,27 @@ func fetchRegistryByID(c client, id int) (registryItem, error) {
 	return item, nil
 }
 
+// annotateCrossWorkspaceRef resolves a cross-workspace registry reference
+// (e.g. a Cryo-EM grid's sampleRefId pointing at a protein prep owned by a
+// sister lab) and, when it lives in a different workspace than the item
+// being written, records that source workspace under metaKey so readers
+// don't need a second lookup to know the reference crosses a lab boundary.
+// It's a best-effort annotation: a zero refID or a lookup failure (bad ID,
+// no access) is silently skipped, since the server validates the reference
+// itself when the payload is posted.
+func annotateCrossWorkspaceRef(c client, meta map[string]interface{}, refID int, workspace, metaKey string) {
+	if refID <= 0 {
+		return
+	}
+	ref, err := fetchRegistryByID(c, refID)
+	if err != nil {
+		return
+	}
+	if ref.Workspace != "" && ref.Workspace != workspace {
+		meta[metaKey] = ref.Workspace
+	}
+}
+
 func fetchAttachmentURL(c client, path string, attachmentID int) (string, string) {
 	body, err := c.request("GET", path, nil)
 	exitOnError(err)
@@ -2522,7 +3221,13 @@ func fetchAttachmentURL(c client, path string, attachmentID int) (string, string
 	return "", ""
 }
 
-func downloadFile(baseURL, fileURL, outPath string) error {
+// downloadFile streams fileURL to outPath via an intermediate outPath+".part"
+// file, renamed into place only once the transfer completes cleanly. A
+// progress bar (bytes/rate/ETA) is drawn to stderr when progressEnabled is
+// set. If ctx is canceled mid-transfer (SIGINT/SIGTERM) or the copy
+// otherwise fails, the partial ".part" file is removed rather than left
+// behind as a file that looks complete but isn't.
+func downloadFile(ctx context.Context, baseURL, fileURL, outPath string, progressEnabled bool) error {
 	if strings.TrimSpace(fileURL) == "" {
 		return errors.New("file URL is empty")
 	}
@@ -2530,17 +3235,49 @@ func downloadFile(baseURL, fileURL, outPath string) error {
 	if strings.HasPrefix(fileURL, "/") {
 		target = strings.TrimRight(baseURL, "/") + fileURL
 	}
-	res, err := http.Get(target)
+	req, err := http.NewRequestWithContext(ctx, "GET", target, nil)
 	if err != nil {
 		return err
 	}
+	res, err := http.DefaultClient.Do(req)
+	if err != nil {
+		if ctx.Err() != nil {
+			return errors.New("Aborted.")
+		}
+		return err
+	}
 	defer res.Body.Close()
 	if res.StatusCode >= 300 {
 		return fmt.Errorf("download failed: %s", res.Status)
 	}
-	data, err := io.ReadAll(res.Body)
+
+	progress := newProgressWriter(os.Stderr, "download "+filepath.Base(outPath), res.ContentLength, progressEnabled)
+
+	partPath := outPath + ".part"
+	out, err := os.Create(partPath)
 	if err != nil {
 		return err
 	}
-	return os.WriteFile(outPath, data, 0644)
+	var reader io.Reader = res.Body
+	if progress != nil {
+		reader = io.TeeReader(reader, progress)
+	}
+	_, copyErr := io.Copy(out, reader)
+	closeErr := out.Close()
+	if copyErr != nil || closeErr != nil {
+		os.Remove(partPath)
+		progress.Abort()
+		if ctx.Err() != nil {
+			return errors.New("Aborted.")
+		}
+		if copyErr != nil {
+			return copyErr
+		}
+		return closeErr
+	}
+	if err := os.Rename(partPath, outPath); err != nil {
+		return err
+	}
+	progress.Finish()
+	return nil
 }
@@ -0,0 +1,58 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+
+	"benchaid/internal/render"
+)
+
+// renderValue prints value — already a typed slice/struct a list command
+// decoded from the server's response, such as []registryItem — in the
+// --output format the command was invoked with, applying an
+// --output-filter JSONPath expression (see internal/render) first when
+// one is set. json/jsonl/yaml/table all go through render.Render; text
+// (the default) stays a plain json.Marshal, so a command that leaves
+// --output/--output-filter unset prints exactly what it always did
+// before those flags existed.
+func renderValue(output, outputFilter string, columns []string, value interface{}) error {
+	if strings.TrimSpace(outputFilter) != "" {
+		filtered, err := render.FilterPath(value, outputFilter)
+		if err != nil {
+			return err
+		}
+		value = filtered
+	}
+	format, err := render.ParseFormat(output)
+	if err != nil {
+		return err
+	}
+	if format == render.Text {
+		data, err := json.Marshal(value)
+		if err != nil {
+			return err
+		}
+		fmt.Println(string(data))
+		return nil
+	}
+	return render.Render(os.Stdout, value, format, columns)
+}
+
+// parseColumns splits --columns' comma-separated value into a column
+// list, dropping empty entries; an empty value yields nil (render.Render
+// then infers columns from the first row).
+func parseColumns(value string) []string {
+	value = strings.TrimSpace(value)
+	if value == "" {
+		return nil
+	}
+	var cols []string
+	for _, p := range strings.Split(value, ",") {
+		if p = strings.TrimSpace(p); p != "" {
+			cols = append(cols, p)
+		}
+	}
+	return cols
+}
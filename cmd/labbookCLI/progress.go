@@ -0,0 +1,134 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"strings"
+	"time"
+)
+
+// progressWriter renders a bytes-transferred/rate/ETA bar to an underlying
+// io.Writer (stderr in practice) as data flows through Write, throttled so
+// a fast local transfer doesn't flood the terminal with redraws.
+type progressWriter struct {
+	out       *os.File
+	label     string
+	total     int64
+	done      int64
+	start     time.Time
+	lastDraw  time.Time
+	lastWidth int
+}
+
+const progressRedrawInterval = 150 * time.Millisecond
+
+// newProgressWriter returns a progressWriter, or nil if progress output is
+// disabled (either explicitly or because out isn't a terminal) — callers
+// treat a nil *progressWriter as "no progress reporting" throughout.
+func newProgressWriter(out *os.File, label string, total int64, enabled bool) *progressWriter {
+	if !enabled || !isTerminal(out) {
+		return nil
+	}
+	return &progressWriter{out: out, label: label, total: total, start: time.Now()}
+}
+
+func isTerminal(f *os.File) bool {
+	info, err := f.Stat()
+	if err != nil {
+		return false
+	}
+	return (info.Mode() & os.ModeCharDevice) != 0
+}
+
+// Write lets progressWriter be used as the sink of an io.TeeReader around
+// whatever is actually being read, so it sees every byte without taking
+// over the copy loop itself.
+func (p *progressWriter) Write(b []byte) (int, error) {
+	if p == nil {
+		return len(b), nil
+	}
+	p.done += int64(len(b))
+	now := time.Now()
+	if now.Sub(p.lastDraw) >= progressRedrawInterval {
+		p.draw(now)
+		p.lastDraw = now
+	}
+	return len(b), nil
+}
+
+func (p *progressWriter) draw(now time.Time) {
+	elapsed := now.Sub(p.start).Seconds()
+	rate := float64(0)
+	if elapsed > 0 {
+		rate = float64(p.done) / elapsed
+	}
+
+	var pct string
+	var eta string
+	if p.total > 0 {
+		pct = fmt.Sprintf(" %5.1f%%", float64(p.done)/float64(p.total)*100)
+		if rate > 0 {
+			remaining := float64(p.total-p.done) / rate
+			eta = fmt.Sprintf(" ETA %s", formatDuration(time.Duration(remaining*float64(time.Second))))
+		}
+	}
+
+	line := fmt.Sprintf("\r%s %s/%s%s %s/s%s", p.label, formatBytes(p.done), formatBytes(p.total), pct, formatBytes(int64(rate)), eta)
+	pad := p.lastWidth - len(line)
+	p.lastWidth = len(line)
+	if pad > 0 {
+		line += strings.Repeat(" ", pad)
+	}
+	fmt.Fprint(p.out, line)
+}
+
+// Finish draws a final 100%-complete line (when the total was known) and
+// moves to a fresh line so subsequent output doesn't overwrite the bar.
+func (p *progressWriter) Finish() {
+	if p == nil {
+		return
+	}
+	p.draw(time.Now())
+	fmt.Fprintln(p.out)
+}
+
+// Abort leaves the bar in place but reports that the transfer was
+// canceled, so the user doesn't read the last percentage as "it finished".
+func (p *progressWriter) Abort() {
+	if p == nil {
+		return
+	}
+	fmt.Fprintln(p.out, "\nAborted.")
+}
+
+func formatBytes(n int64) string {
+	const unit = 1024
+	if n < unit {
+		return fmt.Sprintf("%dB", n)
+	}
+	div, exp := int64(unit), 0
+	for v := n / unit; v >= unit; v /= unit {
+		div *= unit
+		exp++
+	}
+	return fmt.Sprintf("%.1f%ciB", float64(n)/float64(div), "KMGTPE"[exp])
+}
+
+func formatDuration(d time.Duration) string {
+	if d < 0 {
+		d = 0
+	}
+	d = d.Round(time.Second)
+	h := d / time.Hour
+	d -= h * time.Hour
+	m := d / time.Minute
+	d -= m * time.Minute
+	s := d / time.Second
+	if h > 0 {
+		return fmt.Sprintf("%dh%02dm%02ds", h, m, s)
+	}
+	if m > 0 {
+		return fmt.Sprintf("%dm%02ds", m, s)
+	}
+	return fmt.Sprintf("%ds", s)
+}
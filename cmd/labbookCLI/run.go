@@ -0,0 +1,359 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"text/template"
+	"time"
+
+	"github.com/BurntSushi/toml"
+
+	"benchaid/internal/cron"
+)
+
+// jobsFile is the top-level shape of a --jobs TOML file: a flat list of
+// [[job]] entries, matching the cron-driven job model in mercury/cron.
+type jobsFile struct {
+	Job []jobSpec `toml:"job"`
+}
+
+// jobSpec is one [[job]] entry: a name, a cron schedule (5-field or an
+// @hourly/@daily/@weekly/@every shortcut), and the labbookCLI command + args
+// to re-dispatch in-process when it fires. Command/Args may reference Go
+// templates (see jobTemplateData).
+type jobSpec struct {
+	Name     string   `toml:"name"`
+	Schedule string   `toml:"schedule"`
+	Command  string   `toml:"command"`
+	Args     []string `toml:"args"`
+}
+
+// jobTemplateData is exposed to Command/Args template expansion.
+type jobTemplateData struct {
+	Date string // today, YYYY-MM-DD
+	Now  string // RFC3339 timestamp of this run
+	Jobs map[string]map[string]interface{}
+}
+
+// jobRunLog is one line of the rolling JSON log written under --state-dir.
+type jobRunLog struct {
+	Job        string    `json:"job"`
+	StartedAt  time.Time `json:"startedAt"`
+	DurationMS int64     `json:"durationMs"`
+	ExitCode   int       `json:"exitCode"`
+	Stdout     string    `json:"stdout"`
+	Stderr     string    `json:"stderr,omitempty"`
+}
+
+// runCmd implements `labbookCLI run --jobs jobs.toml [--once] [--dry-run]
+// [--state-dir] [--metrics-addr]`: a scheduler that re-enters the CLI's own
+// command dispatch in-process for each due job (no exec, so auth/env is
+// shared), following the cron-driven job model in the mercury/cron package.
+//
+// Jobs run one at a time as they come due: dispatch's commands print to
+// os.Stdout and call exitOnError (which calls os.Exit) on failure, so
+// runCmd captures stdout by swapping it out for a pipe around each
+// dispatch call, and cannot recover from a job's exitOnError the way a
+// subprocess-per-job design could — a job hitting a hard API error takes
+// the scheduler down with it. That mirrors the rest of the CLI's
+// fail-fast error handling rather than adding a parallel error-propagation
+// path just for `run`.
+func runCmd(c client, args []string) {
+	fs := flag.NewFlagSet("run", flag.ExitOnError)
+	jobsPath := fs.String("jobs", "", "Path to a TOML jobs file")
+	once := fs.Bool("once", false, "Run all currently due jobs once and exit")
+	dryRun := fs.Bool("dry-run", false, "Print the resolved schedule and templated commands without running")
+	stateDir := fs.String("state-dir", getEnvOrDefault("LABBOOK_RUN_STATE_DIR", ".labbook-run"), "Directory for the rolling JSON run log")
+	metricsAddr := fs.String("metrics-addr", "", "Address to serve Prometheus-style /metrics on, e.g. :9110")
+	_ = fs.Parse(args)
+
+	if strings.TrimSpace(*jobsPath) == "" {
+		exitOnError(fmt.Errorf("jobs is required"))
+	}
+	data, err := os.ReadFile(*jobsPath)
+	exitOnError(err)
+	var file jobsFile
+	exitOnError(toml.Unmarshal(data, &file))
+	if len(file.Job) == 0 {
+		exitOnError(fmt.Errorf("%s defines no [[job]] entries", *jobsPath))
+	}
+
+	schedules := make(map[string]cron.Schedule, len(file.Job))
+	for _, job := range file.Job {
+		s, err := cron.Parse(job.Schedule)
+		if err != nil {
+			exitOnError(fmt.Errorf("job %q: %w", job.Name, err))
+		}
+		schedules[job.Name] = s
+	}
+
+	m := newRunMetrics()
+	if strings.TrimSpace(*metricsAddr) != "" {
+		go serveMetrics(*metricsAddr, m)
+	}
+
+	results := map[string]map[string]interface{}{}
+	if *dryRun {
+		now := time.Now()
+		for _, job := range file.Job {
+			next := schedules[job.Name].Next(now.Add(-time.Minute))
+			command, renderedArgs, err := renderJob(job, now, results)
+			exitOnError(err)
+			fmt.Printf("%s  next=%s  %s %s\n", job.Name, next.Format(time.RFC3339), command, strings.Join(renderedArgs, " "))
+		}
+		return
+	}
+
+	exitOnError(os.MkdirAll(*stateDir, 0755))
+	logPath := filepath.Join(*stateDir, "jobs.log")
+
+	if *once {
+		now := time.Now()
+		for _, job := range file.Job {
+			next := schedules[job.Name].Next(now.Add(-time.Minute))
+			if next.After(now) {
+				continue
+			}
+			result := runJob(c, job, now, results, logPath, m)
+			results[job.Name] = result
+		}
+		return
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+
+	next := make(map[string]time.Time, len(file.Job))
+	now := time.Now()
+	for _, job := range file.Job {
+		next[job.Name] = schedules[job.Name].Next(now)
+	}
+
+	timer := time.NewTimer(time.Until(earliest(next)))
+	defer timer.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case fired := <-timer.C:
+			for _, job := range file.Job {
+				if !next[job.Name].After(fired) {
+					result := runJob(c, job, fired, results, logPath, m)
+					results[job.Name] = result
+					next[job.Name] = schedules[job.Name].Next(fired)
+				}
+			}
+			timer.Reset(time.Until(earliest(next)))
+		}
+	}
+}
+
+func earliest(next map[string]time.Time) time.Time {
+	var min time.Time
+	for _, t := range next {
+		if min.IsZero() || t.Before(min) {
+			min = t
+		}
+	}
+	return min
+}
+
+// renderJob expands job.Command/job.Args as Go templates against
+// jobTemplateData, with an `env` function for environment variable lookup.
+func renderJob(job jobSpec, now time.Time, prior map[string]map[string]interface{}) (string, []string, error) {
+	data := jobTemplateData{
+		Date: now.Format("2006-01-02"),
+		Now:  now.Format(time.RFC3339),
+		Jobs: prior,
+	}
+	funcs := template.FuncMap{"env": os.Getenv}
+
+	render := func(name, text string) (string, error) {
+		tmpl, err := template.New(name).Funcs(funcs).Parse(text)
+		if err != nil {
+			return "", err
+		}
+		var b strings.Builder
+		if err := tmpl.Execute(&b, data); err != nil {
+			return "", err
+		}
+		return b.String(), nil
+	}
+
+	command, err := render(job.Name+"/command", job.Command)
+	if err != nil {
+		return "", nil, fmt.Errorf("job %q command template: %w", job.Name, err)
+	}
+	renderedArgs := make([]string, len(job.Args))
+	for i, a := range job.Args {
+		rendered, err := render(fmt.Sprintf("%s/args[%d]", job.Name, i), a)
+		if err != nil {
+			return "", nil, fmt.Errorf("job %q args template: %w", job.Name, err)
+		}
+		renderedArgs[i] = rendered
+	}
+	return command, renderedArgs, nil
+}
+
+// runJob renders and re-dispatches a single job in-process, capturing its
+// stdout, appending a jobRunLog line to logPath, and recording Prometheus
+// counters/duration on m.
+func runJob(c client, job jobSpec, now time.Time, prior map[string]map[string]interface{}, logPath string, m *runMetrics) map[string]interface{} {
+	command, renderedArgs, err := renderJob(job, now, prior)
+	exitOnError(err)
+
+	started := time.Now()
+	stdout, exitCode := captureDispatch(c, command, renderedArgs)
+	duration := time.Since(started)
+
+	m.record(job.Name, exitCode == 0, duration)
+
+	entry := jobRunLog{
+		Job:        job.Name,
+		StartedAt:  started,
+		DurationMS: duration.Milliseconds(),
+		ExitCode:   exitCode,
+		Stdout:     stdout,
+	}
+	appendRunLog(logPath, entry)
+
+	return map[string]interface{}{
+		"stdout":   stdout,
+		"exitCode": exitCode,
+	}
+}
+
+// captureDispatchMu serializes the os.Stdout swap below: os.Stdout is a
+// single package-level variable, so two goroutines capturing concurrently
+// (batchCmd runs one captureDispatch per worker) would race on it and could
+// leak one job's output into another's buffer, or straight to the real
+// terminal. runCmd's sequential caller never contended on this, but
+// batchCmd's worker pool does.
+var captureDispatchMu sync.Mutex
+
+// captureDispatch runs dispatch(c, command, args) with os.Stdout
+// temporarily redirected to a pipe, and returns what it printed. It reports
+// exitCode 1 only when command is empty or the stdout pipe can't be
+// created, and 0 whenever dispatch returns: dispatch's own commands call
+// exitOnError (os.Exit) rather than returning an error, so a nonzero exit
+// from a command never reaches here to be reported — see runCmd's doc
+// comment.
+func captureDispatch(c client, command string, args []string) (stdout string, exitCode int) {
+	fields := strings.Fields(command)
+	if len(fields) == 0 {
+		return "", 1
+	}
+
+	captureDispatchMu.Lock()
+	defer captureDispatchMu.Unlock()
+
+	r, w, err := os.Pipe()
+	if err != nil {
+		return "", 1
+	}
+	realStdout := os.Stdout
+	os.Stdout = w
+
+	done := make(chan string, 1)
+	go func() {
+		var buf bytes.Buffer
+		_, _ = io.Copy(&buf, r)
+		done <- buf.String()
+	}()
+
+	dispatch(c, fields[0], append(fields[1:], args...))
+
+	_ = w.Close()
+	os.Stdout = realStdout
+	return <-done, 0
+}
+
+func appendRunLog(path string, entry jobRunLog) {
+	data, err := json.Marshal(entry)
+	if err != nil {
+		return
+	}
+	f, err := os.OpenFile(path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644)
+	if err != nil {
+		return
+	}
+	defer f.Close()
+	_, _ = f.Write(append(data, '\n'))
+}
+
+// runMetrics holds the Prometheus-style counters/duration samples exposed
+// on --metrics-addr: total runs and failures per job, and each job's run
+// durations, which /metrics renders as a summary's _sum and _count.
+type runMetrics struct {
+	mu        sync.Mutex
+	runs      map[string]int
+	failures  map[string]int
+	durations map[string][]time.Duration
+}
+
+func newRunMetrics() *runMetrics {
+	return &runMetrics{
+		runs:      map[string]int{},
+		failures:  map[string]int{},
+		durations: map[string][]time.Duration{},
+	}
+}
+
+func (m *runMetrics) record(job string, ok bool, d time.Duration) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.runs[job]++
+	if !ok {
+		m.failures[job]++
+	}
+	m.durations[job] = append(m.durations[job], d)
+}
+
+// serveMetrics serves a Prometheus text-exposition /metrics endpoint until
+// the process exits.
+func serveMetrics(addr string, m *runMetrics) {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/metrics", func(w http.ResponseWriter, r *http.Request) {
+		m.mu.Lock()
+		defer m.mu.Unlock()
+
+		jobs := make([]string, 0, len(m.runs))
+		for job := range m.runs {
+			jobs = append(jobs, job)
+		}
+		sort.Strings(jobs)
+
+		fmt.Fprintln(w, "# HELP labbookcli_run_total Total number of job runs.")
+		fmt.Fprintln(w, "# TYPE labbookcli_run_total counter")
+		for _, job := range jobs {
+			fmt.Fprintf(w, "labbookcli_run_total{job=%q} %d\n", job, m.runs[job])
+		}
+		fmt.Fprintln(w, "# HELP labbookcli_run_failures_total Total number of failed job runs.")
+		fmt.Fprintln(w, "# TYPE labbookcli_run_failures_total counter")
+		for _, job := range jobs {
+			fmt.Fprintf(w, "labbookcli_run_failures_total{job=%q} %d\n", job, m.failures[job])
+		}
+		fmt.Fprintln(w, "# HELP labbookcli_run_duration_seconds Job run duration.")
+		fmt.Fprintln(w, "# TYPE labbookcli_run_duration_seconds summary")
+		for _, job := range jobs {
+			var sum time.Duration
+			for _, d := range m.durations[job] {
+				sum += d
+			}
+			fmt.Fprintf(w, "labbookcli_run_duration_seconds_sum{job=%q} %f\n", job, sum.Seconds())
+			fmt.Fprintf(w, "labbookcli_run_duration_seconds_count{job=%q} %d\n", job, len(m.durations[job]))
+		}
+	})
+	_ = http.ListenAndServe(addr, mux)
+}
@@ -0,0 +1,224 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"benchaid/storage"
+)
+
+// mirrorStateDir holds the sidecar JSON files recorded by mirror-mode
+// uploads (see storage.Sidecar), keyed by attachment ID.
+const mirrorStateDir = ".labbook-mirror"
+
+// storageBackendURL resolves the --storage-backend flag, falling back to
+// LABBOOK_STORAGE_URL, the same precedence every other LABBOOK_* setting
+// uses in this CLI.
+func storageBackendURL(flagVal string) string {
+	if strings.TrimSpace(flagVal) != "" {
+		return flagVal
+	}
+	return getEnvOrDefault("LABBOOK_STORAGE_URL", "")
+}
+
+// attachmentKeyPrefix is the object-storage key prefix every attachment's
+// blob is stored under, shared by mirror upload and fallback download so
+// the latter can find the former without the server.
+func attachmentKeyPrefix(attachmentID int) string {
+	return fmt.Sprintf("attachments/%d", attachmentID)
+}
+
+// parseUploadedAttachmentID pulls the new attachment's ID out of the JSON
+// body requestUpload returned, so the mirror copy can be keyed the same
+// way the server's own attachment record is.
+func parseUploadedAttachmentID(body []byte) int {
+	var created attachment
+	if err := json.Unmarshal(body, &created); err != nil {
+		return 0
+	}
+	return created.ID
+}
+
+// mirrorUploadAttachment copies filePath to backendURL under
+// attachments/<id>/<basename> and records a storage.Sidecar, after a
+// successful server-side upload has already returned. It blocks the
+// caller until the copy finishes — a single-shot CLI process has no
+// daemon to hand a truly detached background copy off to, so "async"
+// here means "the user's upload already succeeded before this runs",
+// not "this outlives the process."
+func mirrorUploadAttachment(backendURL string, attachmentID int, filePath string) error {
+	blob, err := storage.Open(backendURL)
+	if err != nil {
+		return fmt.Errorf("mirror: %w", err)
+	}
+	file, err := os.Open(filePath)
+	if err != nil {
+		return fmt.Errorf("mirror: %w", err)
+	}
+	defer file.Close()
+
+	key := fmt.Sprintf("%s/%s", attachmentKeyPrefix(attachmentID), filepath.Base(filePath))
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Minute)
+	defer cancel()
+	sidecar, err := storage.MirrorUpload(ctx, blob, key, attachmentID, file, nil)
+	if err != nil {
+		return fmt.Errorf("mirror: %w", err)
+	}
+	return storage.WriteSidecar(mirrorStateDir, sidecar)
+}
+
+// mirrorUploadAttachmentAsync fires mirrorUploadAttachment on wg and
+// returns immediately; call wg.Wait() before the process exits so the
+// copy isn't silently dropped (see mirrorUploadAttachment's doc comment
+// on what "async" means for a one-shot CLI).
+func mirrorUploadAttachmentAsync(wg *sync.WaitGroup, backendURL string, attachmentID int, filePath string) {
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		if err := mirrorUploadAttachment(backendURL, attachmentID, filePath); err != nil {
+			fmt.Fprintln(os.Stderr, "mirror upload:", err)
+		}
+	}()
+}
+
+// downloadAttachmentOrMirror tries the server-side download first (the
+// existing fetchAttachmentURL + downloadFile path) and, only if that
+// fails and a storage backend is configured, falls back to the mirrored
+// copy under attachments/<id>/ in backendURL. ctx governs only the
+// server-side attempt (so a SIGINT/SIGTERM during that leg cleans up its
+// partial ".part" file) — the mirror fallback gets its own fixed timeout
+// below, matching how mirrorUploadAttachment treats "async" elsewhere in
+// this file.
+func downloadAttachmentOrMirror(ctx context.Context, c client, listPath string, attachmentID int, backendURL, outPath string, progressEnabled bool) error {
+	fileURL, fileName := tryFetchAttachmentURL(c, listPath, attachmentID)
+	if fileURL != "" {
+		downloadPath := outPath
+		if strings.TrimSpace(downloadPath) == "" {
+			downloadPath = fileName
+		}
+		if err := downloadFile(ctx, c.baseURL, fileURL, downloadPath, progressEnabled); err == nil {
+			return nil
+		}
+	}
+	if strings.TrimSpace(backendURL) == "" {
+		return fmt.Errorf("server download failed and no --storage-backend/LABBOOK_STORAGE_URL is configured for a mirror fallback")
+	}
+
+	blob, err := storage.Open(backendURL)
+	if err != nil {
+		return err
+	}
+	mirrorCtx, cancel := context.WithTimeout(context.Background(), 5*time.Minute)
+	defer cancel()
+	keys, err := blob.List(mirrorCtx, attachmentKeyPrefix(attachmentID)+"/")
+	if err != nil || len(keys) == 0 {
+		return fmt.Errorf("no mirrored copy found for attachment %d", attachmentID)
+	}
+	r, _, err := blob.Get(mirrorCtx, keys[0])
+	if err != nil {
+		return err
+	}
+	defer r.Close()
+
+	downloadPath := outPath
+	if strings.TrimSpace(downloadPath) == "" {
+		downloadPath = filepath.Base(keys[0])
+	}
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return err
+	}
+	fmt.Fprintln(os.Stderr, "server unreachable; restored", downloadPath, "from mirror")
+	return os.WriteFile(downloadPath, data, 0644)
+}
+
+// tryFetchAttachmentURL is fetchAttachmentURL without the exitOnError
+// calls, so downloadAttachmentOrMirror can fall back to the mirror
+// instead of killing the process when the server is unreachable.
+func tryFetchAttachmentURL(c client, path string, attachmentID int) (string, string) {
+	body, err := c.request("GET", path, nil)
+	if err != nil {
+		return "", ""
+	}
+	var attachments []attachment
+	if err := json.Unmarshal(body, &attachments); err != nil {
+		return "", ""
+	}
+	for _, file := range attachments {
+		if file.ID == attachmentID {
+			return file.FileUrl, file.FileName
+		}
+	}
+	return "", ""
+}
+
+// attachmentsCmd implements the top-level `labbookCLI attachments verify`
+// command: walk every recorded mirror sidecar, recompute its hash against
+// the configured backend, and report drift. `attachments sync` is handed
+// to attachmentsSyncCmd.
+func attachmentsCmd(c client, args []string) {
+	if len(args) < 1 {
+		usage()
+		os.Exit(1)
+	}
+	switch args[0] {
+	case "verify":
+		fs := flag.NewFlagSet("attachments verify", flag.ExitOnError)
+		backend := fs.String("backend", "", "Object storage backend URL (defaults to LABBOOK_STORAGE_URL)")
+		_ = fs.Parse(args[1:])
+
+		backendURL := storageBackendURL(*backend)
+		if backendURL == "" {
+			exitOnError(errors.New("backend is required (or set LABBOOK_STORAGE_URL)"))
+		}
+		blob, err := storage.Open(backendURL)
+		exitOnError(err)
+		sidecars, err := storage.ListSidecars(mirrorStateDir)
+		exitOnError(err)
+		if len(sidecars) == 0 {
+			fmt.Println("no mirrored attachments recorded under " + mirrorStateDir)
+			return
+		}
+
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Minute)
+		defer cancel()
+		drift := 0
+		for _, s := range sidecars {
+			keys, err := blob.List(ctx, attachmentKeyPrefix(s.AttachmentID)+"/")
+			if err != nil || len(keys) == 0 {
+				fmt.Printf("attachment %d: MISSING from backend (%v)\n", s.AttachmentID, err)
+				drift++
+				continue
+			}
+			matches, got, err := storage.VerifyObject(ctx, blob, keys[0], s.SHA256)
+			if err != nil {
+				fmt.Printf("attachment %d: error reading %s: %v\n", s.AttachmentID, keys[0], err)
+				drift++
+				continue
+			}
+			if !matches {
+				fmt.Printf("attachment %d: DRIFT — recorded %s, backend has %s\n", s.AttachmentID, s.SHA256, got)
+				drift++
+				continue
+			}
+			fmt.Printf("attachment %d: OK\n", s.AttachmentID)
+		}
+		if drift > 0 {
+			os.Exit(1)
+		}
+	case "sync":
+		attachmentsSyncCmd(c, args[1:])
+	default:
+		usage()
+		os.Exit(1)
+	}
+}
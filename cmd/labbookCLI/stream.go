@@ -0,0 +1,378 @@
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"crypto/rand"
+	"crypto/sha1"
+	"crypto/tls"
+	"encoding/base64"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"benchaid/events"
+)
+
+// stream opens one attempt at a long-lived event connection to path,
+// preferring a standard text/event-stream (streamSSE) and falling back
+// to a WebSocket upgrade (streamWebSocket) when the server's response to
+// the SSE attempt doesn't look like an event stream. Each event decoded
+// off the wire is handed to handler as it arrives. stream returns once
+// handler returns a non-nil error, ctx is canceled, or the connection
+// ends; reconnecting across attempts is the caller's job — see
+// sseSource, which wraps stream in a backoff loop for watchCmd.
+func (c client) stream(ctx context.Context, path, lastEventID string, handler func(events.Event) error) error {
+	switchToWS, err := c.streamSSE(ctx, path, lastEventID, handler)
+	if !switchToWS {
+		return err
+	}
+	return c.streamWebSocket(ctx, path, handler)
+}
+
+// streamSSE is the text/event-stream half of stream: GET path with
+// Accept: text/event-stream and Last-Event-ID: lastEventID (when set),
+// decoding "event:"/"data:"/"id:" frames incrementally and calling
+// handler for each. If the server's response doesn't look like an event
+// stream at all — a 404, 400, 426 or 501 status, or a 2xx whose
+// Content-Type isn't text/event-stream — it reports switchToWS=true without attempting to
+// parse the body, so stream can retry the same path over WebSocket
+// instead of treating it as a (retryable) SSE failure.
+func (c client) streamSSE(ctx context.Context, path, lastEventID string, handler func(events.Event) error) (switchToWS bool, err error) {
+	req, err := http.NewRequestWithContext(ctx, "GET", c.baseURL+path, nil)
+	if err != nil {
+		return false, err
+	}
+	req.Header.Set("Accept", "text/event-stream")
+	if authHeader := c.authHeader(); authHeader != "" {
+		req.Header.Set("Authorization", "Bearer "+authHeader)
+	}
+	if lastEventID != "" {
+		req.Header.Set("Last-Event-ID", lastEventID)
+	}
+	res, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return false, err
+	}
+	defer res.Body.Close()
+	// A 2xx response whose Content-Type isn't text/event-stream, or one of
+	// the statuses a server conventionally uses to say "this endpoint
+	// doesn't speak SSE, try something else" (404 no such transport, 400
+	// bad request for an SSE GET, 426 upgrade required, 501 not
+	// implemented) means try WebSocket instead. Any other error status
+	// (401/403/500/503, ...) would fail a WebSocket handshake against the
+	// same endpoint too, so it's reported as-is rather than doubling up
+	// the failed connection attempt.
+	switch {
+	case res.StatusCode == http.StatusNotFound, res.StatusCode == http.StatusBadRequest,
+		res.StatusCode == http.StatusUpgradeRequired, res.StatusCode == http.StatusNotImplemented:
+		return true, nil
+	case res.StatusCode >= 300:
+		return false, fmt.Errorf("GET %s: unexpected status %d", path, res.StatusCode)
+	case !strings.Contains(res.Header.Get("Content-Type"), "text/event-stream"):
+		return true, nil
+	}
+
+	var eventName string
+	var dataLines []string
+	var id string
+	flush := func() error {
+		if len(dataLines) == 0 {
+			return nil
+		}
+		ev, err := parseSSEEvent(eventName, dataLines, id)
+		dataLines = nil
+		eventName = ""
+		if err != nil {
+			return err
+		}
+		return handler(ev)
+	}
+
+	scanner := bufio.NewScanner(res.Body)
+	scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		line := scanner.Text()
+		switch {
+		case line == "":
+			if err := flush(); err != nil {
+				return false, err
+			}
+		case strings.HasPrefix(line, "event:"):
+			eventName = strings.TrimSpace(strings.TrimPrefix(line, "event:"))
+		case strings.HasPrefix(line, "data:"):
+			dataLines = append(dataLines, strings.TrimPrefix(strings.TrimPrefix(line, "data:"), " "))
+		case strings.HasPrefix(line, "id:"):
+			id = strings.TrimSpace(strings.TrimPrefix(line, "id:"))
+		case strings.HasPrefix(line, ":"):
+			// comment/keep-alive line, ignored
+		}
+	}
+	if err := flush(); err != nil {
+		return false, err
+	}
+	return false, scanner.Err()
+}
+
+const wsGUID = "258EAFA5-E914-47DA-95CA-C5AB0DC85B11"
+
+const (
+	wsOpText  = 0x1
+	wsOpClose = 0x8
+	wsOpPing  = 0x9
+	wsOpPong  = 0xA
+)
+
+// streamWebSocket opens a WebSocket connection to path (resolved against
+// c.baseURL) and decodes each text frame as the same wireEvent JSON shape
+// SSE's "data:" field carries, handing the result to handler. It
+// implements just enough of RFC 6455 to receive a server's event frames
+// and answer its pings: this tree has no module manifest to vendor a
+// WebSocket library into (the same constraint mqttSource documents for
+// MQTT), so unlike SSE — which rides net/http — this is hand-rolled over
+// a raw net.Conn.
+func (c client) streamWebSocket(ctx context.Context, path string, handler func(events.Event) error) error {
+	conn, r, err := dialWebSocket(ctx, c.baseURL, path, c.authHeader())
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+
+	done := make(chan struct{})
+	defer close(done)
+	go func() {
+		select {
+		case <-ctx.Done():
+			conn.Close()
+		case <-done:
+		}
+	}()
+
+	for {
+		opcode, payload, err := readWSFrame(r)
+		if err != nil {
+			if ctx.Err() != nil {
+				return ctx.Err()
+			}
+			return err
+		}
+		switch opcode {
+		case wsOpText:
+			ev, err := parseSSEEvent("", []string{string(payload)}, "")
+			if err != nil {
+				return err
+			}
+			if err := handler(ev); err != nil {
+				return err
+			}
+		case wsOpPing:
+			if err := writeWSFrame(conn, wsOpPong, payload); err != nil {
+				return err
+			}
+		case wsOpClose:
+			return io.EOF
+		}
+	}
+}
+
+// dialWebSocket performs the RFC 6455 opening handshake against
+// baseURL+path over a raw TCP (or TLS, for https) connection and returns
+// the connection along with a *bufio.Reader already positioned right
+// after the response headers, so no frame bytes the server sent ahead of
+// our next read are lost.
+func dialWebSocket(ctx context.Context, baseURL, path, authHeader string) (net.Conn, *bufio.Reader, error) {
+	u, err := url.Parse(baseURL + path)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var d net.Dialer
+	var conn net.Conn
+	switch u.Scheme {
+	case "https":
+		conn, err = tlsDial(ctx, &d, hostWithPort(u, "443"))
+	case "http":
+		conn, err = d.DialContext(ctx, "tcp", hostWithPort(u, "80"))
+	default:
+		return nil, nil, fmt.Errorf("unsupported scheme %q for websocket", u.Scheme)
+	}
+	if err != nil {
+		return nil, nil, err
+	}
+
+	keyBytes := make([]byte, 16)
+	if _, err := rand.Read(keyBytes); err != nil {
+		conn.Close()
+		return nil, nil, err
+	}
+	key := base64.StdEncoding.EncodeToString(keyBytes)
+
+	requestPath := u.Path
+	if u.RawQuery != "" {
+		requestPath += "?" + u.RawQuery
+	}
+	var req bytes.Buffer
+	fmt.Fprintf(&req, "GET %s HTTP/1.1\r\n", requestPath)
+	fmt.Fprintf(&req, "Host: %s\r\n", u.Host)
+	req.WriteString("Upgrade: websocket\r\n")
+	req.WriteString("Connection: Upgrade\r\n")
+	fmt.Fprintf(&req, "Sec-WebSocket-Key: %s\r\n", key)
+	req.WriteString("Sec-WebSocket-Version: 13\r\n")
+	if authHeader != "" {
+		fmt.Fprintf(&req, "Authorization: Bearer %s\r\n", authHeader)
+	}
+	req.WriteString("\r\n")
+	if _, err := conn.Write(req.Bytes()); err != nil {
+		conn.Close()
+		return nil, nil, err
+	}
+
+	r := bufio.NewReader(conn)
+	statusLine, err := r.ReadString('\n')
+	if err != nil {
+		conn.Close()
+		return nil, nil, err
+	}
+	if !strings.Contains(statusLine, "101") {
+		conn.Close()
+		return nil, nil, fmt.Errorf("websocket handshake failed: %s", strings.TrimSpace(statusLine))
+	}
+	var accept string
+	for {
+		line, err := r.ReadString('\n')
+		if err != nil {
+			conn.Close()
+			return nil, nil, err
+		}
+		line = strings.TrimRight(line, "\r\n")
+		if line == "" {
+			break
+		}
+		if name, value, ok := strings.Cut(line, ":"); ok && strings.EqualFold(strings.TrimSpace(name), "Sec-WebSocket-Accept") {
+			accept = strings.TrimSpace(value)
+		}
+	}
+	sum := sha1.Sum([]byte(key + wsGUID))
+	if want := base64.StdEncoding.EncodeToString(sum[:]); accept != want {
+		conn.Close()
+		return nil, nil, errors.New("websocket handshake: Sec-WebSocket-Accept mismatch")
+	}
+	return conn, r, nil
+}
+
+func tlsDial(ctx context.Context, d *net.Dialer, addr string) (net.Conn, error) {
+	tlsDialer := tls.Dialer{NetDialer: d}
+	return tlsDialer.DialContext(ctx, "tcp", addr)
+}
+
+// hostWithPort returns u's host and port (u.Port() if set, defaultPort
+// otherwise) joined for net.Dial, via u.Hostname()/net.JoinHostPort so a
+// bracketed IPv6 literal (e.g. "[::1]") is re-bracketed correctly rather
+// than double-bracketed or mistaken for already having a port because of
+// the colons inside it.
+func hostWithPort(u *url.URL, defaultPort string) string {
+	port := u.Port()
+	if port == "" {
+		port = defaultPort
+	}
+	return net.JoinHostPort(u.Hostname(), port)
+}
+
+// maxWSFrameSize bounds a single incoming WebSocket frame: events here
+// are one small JSON object each, so there's no legitimate reason for a
+// server to send more than this in one frame. Without a cap, a malicious
+// or buggy server could claim a multi-gigabyte length and make
+// readWSFrame try to allocate it.
+const maxWSFrameSize = 16 * 1024 * 1024
+
+// readWSFrame reads one RFC 6455 frame from r. Continuation frames
+// (fragmented messages) aren't expected from an events endpoint whose
+// messages are each a single small JSON object, so a non-final frame is
+// treated as an error rather than silently truncated.
+func readWSFrame(r *bufio.Reader) (opcode byte, payload []byte, err error) {
+	var header [2]byte
+	if _, err := io.ReadFull(r, header[:]); err != nil {
+		return 0, nil, err
+	}
+	fin := header[0]&0x80 != 0
+	opcode = header[0] & 0x0F
+	masked := header[1]&0x80 != 0
+	length := int64(header[1] & 0x7F)
+	switch length {
+	case 126:
+		var ext [2]byte
+		if _, err := io.ReadFull(r, ext[:]); err != nil {
+			return 0, nil, err
+		}
+		length = int64(binary.BigEndian.Uint16(ext[:]))
+	case 127:
+		var ext [8]byte
+		if _, err := io.ReadFull(r, ext[:]); err != nil {
+			return 0, nil, err
+		}
+		length = int64(binary.BigEndian.Uint64(ext[:]))
+	}
+	if length > maxWSFrameSize {
+		return 0, nil, fmt.Errorf("websocket: frame of %d bytes exceeds the %d byte limit", length, maxWSFrameSize)
+	}
+	var maskKey [4]byte
+	if masked {
+		if _, err := io.ReadFull(r, maskKey[:]); err != nil {
+			return 0, nil, err
+		}
+	}
+	data := make([]byte, length)
+	if _, err := io.ReadFull(r, data); err != nil {
+		return 0, nil, err
+	}
+	if masked {
+		for i := range data {
+			data[i] ^= maskKey[i%4]
+		}
+	}
+	if !fin {
+		return 0, nil, errors.New("websocket: fragmented frames are not supported")
+	}
+	return opcode, data, nil
+}
+
+// writeWSFrame writes one RFC 6455 frame to w. Per spec, every
+// client-to-server frame must be masked with a random key.
+func writeWSFrame(w io.Writer, opcode byte, payload []byte) error {
+	header := []byte{0x80 | opcode}
+	length := len(payload)
+	switch {
+	case length <= 125:
+		header = append(header, 0x80|byte(length))
+	case length <= 0xFFFF:
+		var ext [2]byte
+		binary.BigEndian.PutUint16(ext[:], uint16(length))
+		header = append(header, 0x80|126)
+		header = append(header, ext[:]...)
+	default:
+		var ext [8]byte
+		binary.BigEndian.PutUint64(ext[:], uint64(length))
+		header = append(header, 0x80|127)
+		header = append(header, ext[:]...)
+	}
+	var maskKey [4]byte
+	if _, err := rand.Read(maskKey[:]); err != nil {
+		return err
+	}
+	header = append(header, maskKey[:]...)
+	masked := make([]byte, length)
+	for i, b := range payload {
+		masked[i] = b ^ maskKey[i%4]
+	}
+	if _, err := w.Write(header); err != nil {
+		return err
+	}
+	_, err := w.Write(masked)
+	return err
+}
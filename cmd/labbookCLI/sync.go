@@ -0,0 +1,263 @@
+package main
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// syncManifestDir holds the per-(target, directory) sync manifests
+// recorded by attachments sync, alongside the mirrorStateDir sidecars
+// this CLI already keeps in the working directory.
+const syncManifestDir = ".labbook-sync"
+
+// syncManifestEntry is what attachments sync remembers about one synced
+// local file, so a repeat run can tell an unchanged file from one that
+// needs re-uploading without re-listing or re-hashing anything remote.
+type syncManifestEntry struct {
+	Hash         string `json:"hash"`
+	Size         int64  `json:"size"`
+	AttachmentID int    `json:"attachmentId"`
+}
+
+// syncManifest is the on-disk record of one `attachments sync` target:
+// every relative path under dir this CLI has uploaded, mapped to its
+// content hash and the attachment ID the server assigned it.
+type syncManifest struct {
+	TargetPath string                       `json:"targetPath"`
+	Dir        string                       `json:"dir"`
+	Entries    map[string]syncManifestEntry `json:"entries"`
+}
+
+func syncManifestKey(targetPath, dir string) string {
+	sum := sha256.Sum256([]byte(targetPath + "|" + dir))
+	return hex.EncodeToString(sum[:])[:16]
+}
+
+func syncManifestPath(targetPath, dir string) string {
+	return filepath.Join(syncManifestDir, syncManifestKey(targetPath, dir)+".json")
+}
+
+func loadSyncManifest(targetPath, dir string) *syncManifest {
+	empty := &syncManifest{TargetPath: targetPath, Dir: dir, Entries: map[string]syncManifestEntry{}}
+	data, err := os.ReadFile(syncManifestPath(targetPath, dir))
+	if err != nil {
+		return empty
+	}
+	var m syncManifest
+	if json.Unmarshal(data, &m) != nil || m.Entries == nil {
+		return empty
+	}
+	return &m
+}
+
+func (m *syncManifest) save() error {
+	if err := os.MkdirAll(syncManifestDir, 0o755); err != nil {
+		return err
+	}
+	data, err := json.MarshalIndent(m, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(syncManifestPath(m.TargetPath, m.Dir), data, 0o644)
+}
+
+// hashFile returns the lowercase hex SHA-256 of path's contents and its
+// size, read in one pass.
+func hashFile(path string) (hash string, size int64, err error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return "", 0, err
+	}
+	defer f.Close()
+	h := sha256.New()
+	n, err := io.Copy(h, f)
+	if err != nil {
+		return "", 0, err
+	}
+	return hex.EncodeToString(h.Sum(nil)), n, nil
+}
+
+// matchesSyncFilter reports whether relPath (slash-separated, relative
+// to the sync root) should be synced given optional include/exclude glob
+// patterns. Patterns are matched via filepath.Match against both the
+// full relative path and its base name, so "*.tif" reaches files in any
+// subdirectory without needing "**" (filepath.Match doesn't support it).
+// An empty include matches everything; a matching exclude always wins.
+func matchesSyncFilter(relPath, include, exclude string) (bool, error) {
+	slashPath := filepath.ToSlash(relPath)
+	if include != "" {
+		ok, err := globMatches(include, slashPath)
+		if err != nil {
+			return false, err
+		}
+		if !ok {
+			return false, nil
+		}
+	}
+	if exclude != "" {
+		ok, err := globMatches(exclude, slashPath)
+		if err != nil {
+			return false, err
+		}
+		if ok {
+			return false, nil
+		}
+	}
+	return true, nil
+}
+
+func globMatches(pattern, slashPath string) (bool, error) {
+	if ok, err := filepath.Match(pattern, slashPath); err != nil {
+		return false, err
+	} else if ok {
+		return true, nil
+	}
+	return filepath.Match(pattern, filepath.Base(slashPath))
+}
+
+// syncAttachments walks dir, uploading every new or changed file as an
+// attachment to targetPath (the same resource path requestUpload's
+// single-shot POST would hit, e.g. /api/entries/7/attachments or
+// /api/registry/42/attachments) and, with prune, deleting attachments a
+// prior sync created for files no longer present locally. A
+// per-(targetPath, dir) manifest under .labbook-sync tracks relative
+// path -> content hash -> attachment ID, the same way mirror uploads
+// track attachment ID -> backend hash under .labbook-mirror, so repeat
+// runs diff against what this CLI already knows instead of re-listing
+// and re-hashing every remote attachment from scratch. dryRun prints
+// what would happen without uploading, deleting, or touching the
+// manifest.
+func syncAttachments(c client, targetPath, dir, include, exclude string, prune, dryRun bool) error {
+	info, err := os.Stat(dir)
+	if err != nil {
+		return err
+	}
+	if !info.IsDir() {
+		return fmt.Errorf("%s is not a directory", dir)
+	}
+
+	manifest := loadSyncManifest(targetPath, dir)
+
+	var relPaths []string
+	err = filepath.WalkDir(dir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if d.IsDir() {
+			return nil
+		}
+		rel, err := filepath.Rel(dir, path)
+		if err != nil {
+			return err
+		}
+		relPaths = append(relPaths, rel)
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+	sort.Strings(relPaths)
+
+	seen := map[string]bool{}
+	for _, rel := range relPaths {
+		seen[rel] = true
+		ok, err := matchesSyncFilter(rel, include, exclude)
+		if err != nil {
+			return err
+		}
+		if !ok {
+			continue
+		}
+
+		full := filepath.Join(dir, rel)
+		hash, size, err := hashFile(full)
+		if err != nil {
+			return err
+		}
+		existing, tracked := manifest.Entries[rel]
+		if tracked && existing.Hash == hash {
+			continue
+		}
+
+		verb := "upload"
+		if tracked {
+			verb = "update"
+		}
+		if dryRun {
+			fmt.Printf("would %s %s (%d bytes)\n", verb, rel, size)
+			continue
+		}
+		body, err := c.requestUpload(c.rootContext(), targetPath, full, false)
+		if err != nil {
+			return fmt.Errorf("%s %s: %w", verb, rel, err)
+		}
+		attachmentID := parseUploadedAttachmentID(body)
+		manifest.Entries[rel] = syncManifestEntry{Hash: hash, Size: size, AttachmentID: attachmentID}
+		fmt.Printf("%sed %s as attachment %d\n", verb, rel, attachmentID)
+	}
+
+	if prune {
+		for rel, entry := range manifest.Entries {
+			if seen[rel] {
+				continue
+			}
+			if dryRun {
+				fmt.Printf("would delete %s (attachment %d)\n", rel, entry.AttachmentID)
+				continue
+			}
+			if _, err := c.request("DELETE", fmt.Sprintf("%s/%d", targetPath, entry.AttachmentID), nil); err != nil {
+				return fmt.Errorf("delete attachment %d for %s: %w", entry.AttachmentID, rel, err)
+			}
+			fmt.Printf("deleted %s (attachment %d)\n", rel, entry.AttachmentID)
+			delete(manifest.Entries, rel)
+		}
+	}
+
+	if dryRun {
+		return nil
+	}
+	return manifest.save()
+}
+
+// attachmentsSyncCmd implements `labbookCLI attachments sync --entry-id|
+// --registry-id --dir <localDir> [--include] [--exclude] [--prune]
+// [--dry-run]`: recursively upload a local directory's files as
+// attachments on one entry or registry item, keeping the two in sync on
+// repeat runs.
+func attachmentsSyncCmd(c client, args []string) {
+	fs := flag.NewFlagSet("attachments sync", flag.ExitOnError)
+	entryID := fs.Int("entry-id", 0, "Entry ID to sync attachments onto")
+	registryID := fs.Int("registry-id", 0, "Registry ID to sync attachments onto")
+	dir := fs.String("dir", "", "Local directory to sync")
+	include := fs.String("include", "", "Only sync files whose path (relative to --dir) matches this glob")
+	exclude := fs.String("exclude", "", "Skip files whose path (relative to --dir) matches this glob")
+	prune := fs.Bool("prune", false, "Delete attachments this command previously created for files no longer present locally")
+	dryRun := fs.Bool("dry-run", false, "Print planned uploads/deletes without making any changes")
+	_ = fs.Parse(args)
+
+	if (*entryID == 0) == (*registryID == 0) {
+		exitOnError(errors.New("exactly one of --entry-id or --registry-id is required"))
+	}
+	if strings.TrimSpace(*dir) == "" {
+		exitOnError(errors.New("dir is required"))
+	}
+
+	var targetPath string
+	if *entryID != 0 {
+		targetPath = fmt.Sprintf("/api/entries/%d/attachments", *entryID)
+	} else {
+		targetPath = fmt.Sprintf("/api/registry/%d/attachments", *registryID)
+	}
+
+	exitOnError(syncAttachments(c, targetPath, *dir, *include, *exclude, *prune, *dryRun))
+}
@@ -0,0 +1,43 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+
+	"benchaid/internal/seqvalidate"
+)
+
+// registryValidateCmd implements `labbookCLI registry validate --id N
+// [--allow-ambig] [--strict]`: it re-runs the same sequence checks
+// parseRegistryPayloadWithID/parseRegistryPatchPayloadWithID apply on
+// create/update against an item already sitting in the registry, printing
+// each Finding as a JSON-lines stream on stdout so the command is usable
+// both for a human skimming results and for a CI job grepping them. With
+// --strict, a registry item carrying any error-severity finding exits
+// non-zero instead of just reporting it.
+func registryValidateCmd(c client, args []string) {
+	fs := flag.NewFlagSet("registry validate", flag.ExitOnError)
+	id := fs.Int("id", 0, "Registry ID")
+	allowAmbig := fs.Bool("allow-ambig", false, "Tolerate ambiguous bases in nucleotide sequences")
+	strict := fs.Bool("strict", false, "Exit non-zero if any finding is an error")
+	_ = fs.Parse(args)
+	if *id == 0 {
+		exitOnError(errors.New("id is required"))
+	}
+
+	item, err := fetchRegistryByID(c, *id)
+	exitOnError(err)
+
+	findings := seqvalidate.ValidateMetadata(item.Metadata, seqvalidateOptions(*allowAmbig))
+	for _, f := range findings {
+		data, err := json.Marshal(f)
+		exitOnError(err)
+		fmt.Println(string(data))
+	}
+	if *strict && seqvalidate.HasErrors(findings) {
+		os.Exit(1)
+	}
+}
@@ -0,0 +1,365 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"math/rand"
+	"net/url"
+	"os"
+	"os/signal"
+	"strings"
+	"time"
+
+	"benchaid/events"
+	"benchaid/internal/render"
+	"benchaid/internal/rsql"
+)
+
+// EventSource is a live feed of events.Event, decoupling watchCmd from the
+// specific transport (SSE today, MQTT once vendored — see mqttSource).
+// Both returned channels close together once the source gives up or ctx is
+// canceled; a source that hits a non-retryable error sends it on errc and
+// then closes both.
+type EventSource interface {
+	Events(ctx context.Context) (<-chan events.Event, <-chan error)
+}
+
+const (
+	sseInitialBackoff = 500 * time.Millisecond
+	sseMaxBackoff     = 30 * time.Second
+)
+
+// nextSSEBackoff computes a decorrelated-jitter reconnect delay, the same
+// shape as internal/vendor/retry.go's nextBackoff: a random value between
+// sseInitialBackoff and 3x the previous delay, capped at sseMaxBackoff.
+func nextSSEBackoff(prev time.Duration) time.Duration {
+	if prev <= 0 {
+		prev = sseInitialBackoff
+	}
+	ceiling := prev * 3
+	if ceiling > sseMaxBackoff {
+		ceiling = sseMaxBackoff
+	}
+	if ceiling <= sseInitialBackoff {
+		return sseInitialBackoff
+	}
+	return sseInitialBackoff + time.Duration(rand.Int63n(int64(ceiling-sseInitialBackoff)))
+}
+
+// sseSource streams events.Event from GET path (e.g.
+// "/api/events?topics=entries,registry"), auto-reconnecting with backoff and
+// resuming via the Last-Event-ID header after a drop. Each attempt is one
+// call to client.stream, which tries text/event-stream first and falls
+// back to a WebSocket upgrade for servers that only offer that.
+type sseSource struct {
+	client      client
+	path        string
+	lastEventID string
+}
+
+func (s *sseSource) Events(ctx context.Context) (<-chan events.Event, <-chan error) {
+	out := make(chan events.Event)
+	errc := make(chan error, 1)
+	go func() {
+		defer close(out)
+		defer close(errc)
+		backoff := time.Duration(0)
+		for ctx.Err() == nil {
+			err := s.streamOnce(ctx, out)
+			if ctx.Err() != nil {
+				return
+			}
+			if err != nil {
+				select {
+				case errc <- err:
+				default:
+				}
+			}
+			backoff = nextSSEBackoff(backoff)
+			select {
+			case <-time.After(backoff):
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+	return out, errc
+}
+
+func (s *sseSource) streamOnce(ctx context.Context, out chan<- events.Event) error {
+	return s.client.stream(ctx, s.path, s.lastEventID, func(ev events.Event) error {
+		if ev.ID != "" {
+			s.lastEventID = ev.ID
+		}
+		select {
+		case out <- ev:
+			return nil
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	})
+}
+
+// wireEvent is the JSON shape expected inside an SSE "data:" field: the
+// event envelope itself. The SSE "event:" field only fills in Type when the
+// envelope has none; when both are set the envelope's Type is kept and the
+// two are not compared.
+type wireEvent struct {
+	ID        string          `json:"id"`
+	Type      events.Type     `json:"type"`
+	Timestamp time.Time       `json:"timestamp"`
+	Data      json.RawMessage `json:"data"`
+}
+
+func parseSSEEvent(eventName string, dataLines []string, fallbackID string) (events.Event, error) {
+	var w wireEvent
+	if err := json.Unmarshal([]byte(strings.Join(dataLines, "\n")), &w); err != nil {
+		return events.Event{}, fmt.Errorf("parse SSE event data: %w", err)
+	}
+	if eventName != "" && w.Type == "" {
+		w.Type = events.Type(eventName)
+	}
+	if w.ID == "" {
+		w.ID = fallbackID
+	}
+	return events.Event{ID: w.ID, Type: w.Type, Timestamp: w.Timestamp, Data: w.Data}, nil
+}
+
+// mqttOptions bundles --mqtt-* flags and their LABBOOK_MQTT_* env fallbacks.
+type mqttOptions struct {
+	Broker      string
+	TopicPrefix string
+	Username    string
+	Password    string
+}
+
+// mqttSource is the MQTT transport named in the --transport flag's help
+// text. It is not implemented: subscribing to "labbook/entries/#" and
+// "labbook/registry/#" needs github.com/eclipse/paho.mqtt.golang, and this
+// tree has no go.mod to add it to (see the module's other source-only
+// packages). Events returns a descriptive error rather than silently
+// degrading to no events.
+type mqttSource struct {
+	opts mqttOptions
+}
+
+func (m *mqttSource) Events(ctx context.Context) (<-chan events.Event, <-chan error) {
+	out := make(chan events.Event)
+	errc := make(chan error, 1)
+	close(out)
+	errc <- fmt.Errorf("--transport=mqtt is not available in this build: it requires vendoring github.com/eclipse/paho.mqtt.golang, which this source tree has no module manifest for; use --transport=sse instead")
+	close(errc)
+	return out, errc
+}
+
+func watchTopLevelCmd(c client, args []string) {
+	watchCmd(c, []string{"entries", "registry"}, args)
+}
+
+// watchCmd implements "watch", "entries watch", and "registry watch": it
+// opens a live EventSource, optionally backfilling recent history from
+// /api/audit first, and prints one event per line until interrupted.
+func watchCmd(c client, defaultTopics []string, args []string) {
+	exitOnError(ensureToken(c))
+	fs := flag.NewFlagSet("watch", flag.ExitOnError)
+	format := fs.String("format", "ndjson", "Output format: json, ndjson, text, yaml, or table")
+	filterExpr := fs.String("filter", "", "RSQL filter expression over event data (see 'registry list --filter')")
+	project := fs.String("project", "", "Only entry events for this project (ignored if --filter is set)")
+	kind := fs.String("kind", "", "Only registry events of this kind (ignored if --filter is set)")
+	tag := fs.String("tag", "", "Only events tagged with this tag (ignored if --filter is set)")
+	id := fs.Int("id", 0, "Only events for this single entry/registry item ID, e.g. 'entries watch --id 42' (ignored if --filter is set; not meaningful on the combined top-level 'watch')")
+	since := fs.String("since", "", "Backfill from this audit entry ID or RFC3339 timestamp before streaming live")
+	transport := fs.String("transport", "sse", "Transport: sse or mqtt")
+	mqttBroker := fs.String("mqtt-broker", getEnvOrDefault("LABBOOK_MQTT_BROKER", ""), "MQTT broker URL (--transport=mqtt)")
+	mqttTopicPrefix := fs.String("mqtt-topic-prefix", getEnvOrDefault("LABBOOK_MQTT_TOPIC_PREFIX", "labbook/"), "MQTT topic prefix (--transport=mqtt)")
+	mqttUsername := fs.String("mqtt-username", getEnvOrDefault("LABBOOK_MQTT_USERNAME", ""), "MQTT username (--transport=mqtt)")
+	mqttPassword := fs.String("mqtt-password", getEnvOrDefault("LABBOOK_MQTT_PASSWORD", ""), "MQTT password (--transport=mqtt)")
+	_ = fs.Parse(args)
+
+	idField := "entryId"
+	if len(defaultTopics) == 1 && defaultTopics[0] == "registry" {
+		idField = "registryId"
+	}
+	node, err := watchFilterNode(*filterExpr, *project, *kind, *tag, idField, *id)
+	exitOnError(err)
+
+	if strings.TrimSpace(*since) != "" {
+		backfillAudit(c, *since, node, *format)
+	}
+
+	var source EventSource
+	switch *transport {
+	case "sse":
+		source = &sseSource{
+			client: c,
+			path:   "/api/events?topics=" + url.QueryEscape(strings.Join(defaultTopics, ",")),
+		}
+	case "mqtt":
+		source = &mqttSource{opts: mqttOptions{
+			Broker:      *mqttBroker,
+			TopicPrefix: *mqttTopicPrefix,
+			Username:    *mqttUsername,
+			Password:    *mqttPassword,
+		}}
+	default:
+		exitOnError(fmt.Errorf("unknown --transport %q (want sse or mqtt)", *transport))
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+
+	out, errc := source.Events(ctx)
+	for {
+		select {
+		case ev, ok := <-out:
+			if !ok {
+				out = nil
+				continue
+			}
+			if node != nil && !rsql.Eval(node, eventResolver(ev)) {
+				continue
+			}
+			printEvent(ev, *format)
+		case err, ok := <-errc:
+			if !ok {
+				errc = nil
+				continue
+			}
+			if err != nil {
+				fmt.Fprintln(os.Stderr, "watch:", err)
+			}
+		case <-ctx.Done():
+			return
+		}
+		if out == nil && errc == nil {
+			return
+		}
+	}
+}
+
+// watchFilterNode builds the rsql.Node watchCmd filters events against:
+// --filter verbatim if set, otherwise an AND of whichever of
+// --project/--kind/--tag/--id were given, or nil if none were. --id
+// compares against idField ("entryId" or "registryId", chosen by the
+// caller from which topic is being watched) via eventResolver's generic
+// fallback onto the event's Data payload.
+func watchFilterNode(filterExpr, project, kind, tag, idField string, id int) (rsql.Node, error) {
+	if strings.TrimSpace(filterExpr) != "" {
+		return rsql.Parse(filterExpr)
+	}
+	var node rsql.Node
+	and := func(field, value string) {
+		if strings.TrimSpace(value) == "" {
+			return
+		}
+		cmp := &rsql.CmpNode{Field: field, Op: rsql.OpEQ, Values: []string{value}}
+		if node == nil {
+			node = cmp
+			return
+		}
+		node = &rsql.AndNode{Left: node, Right: cmp}
+	}
+	and("project", project)
+	and("kind", kind)
+	and("tag", tag)
+	if id > 0 {
+		and(idField, fmt.Sprintf("%d", id))
+	}
+	return node, nil
+}
+
+// eventResolver resolves --filter field paths against a decoded event: "id",
+// "type", and "timestamp" from the envelope, plus whatever fields its typed
+// Data payload carries (e.g. "project", "kind", "tag" via a "tags" alias).
+func eventResolver(ev events.Event) rsql.Resolver {
+	var data map[string]interface{}
+	_ = json.Unmarshal(ev.Data, &data) // best-effort; unresolved fields just won't match
+
+	return func(field string) (interface{}, bool) {
+		switch field {
+		case "id":
+			return ev.ID, true
+		case "type":
+			return string(ev.Type), true
+		case "timestamp":
+			return ev.Timestamp.Format(time.RFC3339), true
+		}
+		if data == nil {
+			return nil, false
+		}
+		return genericMapResolver(data)(field)
+	}
+}
+
+// printEvent prints one event in --format. yaml/table are rendered via
+// internal/render (the same renderer "list" commands' --output uses) —
+// note that since watchCmd calls printEvent once per event rather than
+// on a batched slice, "table" prints its own header before every row
+// instead of once for the whole stream.
+func printEvent(ev events.Event, format string) {
+	switch format {
+	case "text":
+		fmt.Printf("%s\t%s\t%s\n", ev.Timestamp.Format(time.RFC3339), ev.Type, string(ev.Data))
+	case "json", "ndjson", "":
+		data, err := json.Marshal(ev)
+		if err != nil {
+			fmt.Fprintln(os.Stderr, "watch: failed to encode event:", err)
+			return
+		}
+		fmt.Println(string(data))
+	case "yaml", "table":
+		rf, err := render.ParseFormat(format)
+		exitOnError(err)
+		// ev.Data is json.RawMessage; encoding/json inlines it as nested
+		// JSON (see the "json"/"ndjson" case above), but yaml.Marshal has
+		// no such special case and would otherwise emit it as a raw byte
+		// list. Round-trip through JSON first so render.Render sees a
+		// plain map.
+		data, err := json.Marshal(ev)
+		exitOnError(err)
+		var generic interface{}
+		exitOnError(json.Unmarshal(data, &generic))
+		if err := render.Render(os.Stdout, generic, rf, nil); err != nil {
+			fmt.Fprintln(os.Stderr, "watch: failed to render event:", err)
+		}
+	default:
+		exitOnError(fmt.Errorf("unknown --format %q (want json, ndjson, text, yaml, or table)", format))
+	}
+}
+
+// backfillAudit fetches one page of /api/audit (limit=500) for history at
+// or after since (an audit entry ID or an RFC3339 timestamp), printing every
+// entry in it that matches node before watchCmd switches to the live
+// stream. Entries past that first page of 500 are not fetched.
+func backfillAudit(c client, since string, node rsql.Node, format string) {
+	path := "/api/audit?since=" + url.QueryEscape(since) + "&limit=500"
+	body, err := c.request("GET", path, nil)
+	exitOnError(err)
+
+	items, wrapper, _, err := decodeJSONItems(body)
+	if err != nil {
+		exitOnError(fmt.Errorf("--since backfill: %w", err))
+	}
+	_ = wrapper
+	for _, raw := range items {
+		m, ok := raw.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		if node != nil && !rsql.Eval(node, genericMapResolver(m)) {
+			continue
+		}
+		data, err := json.Marshal(m)
+		if err != nil {
+			continue
+		}
+		var ev events.Event
+		if err := json.Unmarshal(data, &ev); err == nil && ev.Type != "" {
+			printEvent(ev, format)
+			continue
+		}
+		fmt.Println(string(data))
+	}
+}
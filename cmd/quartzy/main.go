@@ -1,24 +1,49 @@
 package main
 
 import (
+	"bufio"
 	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"database/sql"
+	"encoding/csv"
+	"encoding/hex"
 	"encoding/json"
+	"errors"
 	"flag"
 	"fmt"
 	"io"
+	"log"
+	mathrand "math/rand"
 	"net/http"
 	"net/url"
 	"os"
+	"os/signal"
+	"sort"
+	"strconv"
 	"strings"
+	"sync"
 	"time"
+
+	_ "github.com/mattn/go-sqlite3"
 )
 
 const defaultBaseURL = "https://api.quartzy.com"
 
+const (
+	defaultRetryMax  = 5
+	defaultRetryBase = 500 * time.Millisecond
+	defaultRetryCap  = 30 * time.Second
+)
+
 type client struct {
-	baseURL string
-	token   string
-	http    *http.Client
+	baseURL   string
+	token     string
+	http      *http.Client
+	retryMax  int
+	retryBase time.Duration
 }
 
 func main() {
@@ -44,6 +69,12 @@ func main() {
 		runTypes(os.Args[2:])
 	case "webhooks":
 		runWebhooks(os.Args[2:])
+	case "sync":
+		runSync(os.Args[2:])
+	case "query":
+		runQuery(os.Args[2:])
+	case "diff":
+		runDiff(os.Args[2:])
 	default:
 		usage()
 		os.Exit(1)
@@ -53,6 +84,8 @@ func main() {
 func usage() {
 	fmt.Fprintf(os.Stderr, "Quartzy CLI\n\n")
 	fmt.Fprintf(os.Stderr, "Usage:\n")
+	fmt.Fprintf(os.Stderr, "  (every list subcommand also accepts --all, --max <N>, and --stream)\n")
+	fmt.Fprintf(os.Stderr, "  (every data subcommand also accepts --output json|jsonl|table|csv|tsv and --fields a,b,c.d)\n")
 	fmt.Fprintf(os.Stderr, "  quartzy health\n")
 	fmt.Fprintf(os.Stderr, "  quartzy user\n")
 	fmt.Fprintf(os.Stderr, "  quartzy labs list [--organization-id <UUID>] [--page <N>]\n")
@@ -60,17 +93,23 @@ func usage() {
 	fmt.Fprintf(os.Stderr, "  quartzy inventory list [--lab-id <UUID>] [--page <N>]\n")
 	fmt.Fprintf(os.Stderr, "  quartzy inventory get --id <UUID>\n")
 	fmt.Fprintf(os.Stderr, "  quartzy inventory update --id <UUID> --quantity <VALUE>\n")
+	fmt.Fprintf(os.Stderr, "  quartzy inventory bulk-update --file <stock.csv> [--concurrency <N>] [--dry-run] [--continue-on-error]\n")
 	fmt.Fprintf(os.Stderr, "  quartzy order-requests list [--lab-id <UUID>] [--page <N>]\n")
 	fmt.Fprintf(os.Stderr, "  quartzy order-requests list --created [--lab-id <UUID>] [--page <N>]\n")
 	fmt.Fprintf(os.Stderr, "  quartzy order-requests list --status <PENDING|CREATED|CANCELLED|APPROVED|ORDERED|BACKORDERED|RECEIVED>[,<STATUS>...] [--lab-id <UUID>] [--page <N>]\n")
 	fmt.Fprintf(os.Stderr, "  quartzy order-requests get --id <UUID>\n")
 	fmt.Fprintf(os.Stderr, "  quartzy order-requests create --lab-id <UUID> --type-id <UUID> --name <NAME> --vendor-name <NAME> --catalog-number <NUM> --price-amount <INT> --price-currency <CODE> --quantity <INT> [--vendor-product-id <UUID>] [--required-before <YYYY-MM-DD>] [--notes <TEXT>]\n")
 	fmt.Fprintf(os.Stderr, "  quartzy order-requests update --id <UUID> --status <CREATED|CANCELLED|APPROVED|ORDERED|BACKORDERED|RECEIVED>\n")
+	fmt.Fprintf(os.Stderr, "  quartzy order-requests bulk-create --file <orders.csv> [--concurrency <N>] [--dry-run] [--continue-on-error]\n")
 	fmt.Fprintf(os.Stderr, "  quartzy types list [--lab-id <UUID>] [--name <NAME>] [--page <N>]\n")
 	fmt.Fprintf(os.Stderr, "  quartzy webhooks list [--organization-id <UUID>] [--page <N>]\n")
 	fmt.Fprintf(os.Stderr, "  quartzy webhooks get --id <UUID>\n")
 	fmt.Fprintf(os.Stderr, "  quartzy webhooks create --url <URL> (--lab-id <UUID> | --organization-id <UUID>) [--name <NAME>] [--event-types <CSV>] [--is-enabled <true|false>] [--is-verified <true|false>] [--is-signed <true|false>]\n")
-	fmt.Fprintf(os.Stderr, "  quartzy webhooks update --id <UUID> --is-enabled <true|false>\n\n")
+	fmt.Fprintf(os.Stderr, "  quartzy webhooks update --id <UUID> --is-enabled <true|false>\n")
+	fmt.Fprintf(os.Stderr, "  quartzy webhooks serve --addr <HOST:PORT> --secret <HMAC_SECRET> [--event-types <CSV>] [--log <path>] [--forward <URL>] [--max-skew <DURATION>]\n")
+	fmt.Fprintf(os.Stderr, "  quartzy sync [--lab-id <UUID>] [--db <path>]\n")
+	fmt.Fprintf(os.Stderr, "  quartzy query [--db <path>] <SQL>\n")
+	fmt.Fprintf(os.Stderr, "  quartzy diff [--db <path>] --since <RFC3339>\n\n")
 	fmt.Fprintf(os.Stderr, "Env (from .env): QUARTZY_KEY, QUARTZY_BASE_URL, QUARTZY_LAB_ID, QUARTZY_ORGANIZATION_ID\n")
 }
 
@@ -79,8 +118,10 @@ func runHealth(args []string) {
 		usage()
 		os.Exit(1)
 	}
-	c := newClient("", false)
-	resp, err := c.doRequest("GET", "/healthz", nil, nil)
+	c := newClient("", false, defaultRetryMax, defaultRetryBase)
+	ctx, cancel := rootContext()
+	defer cancel()
+	resp, err := c.doRequest(ctx, "GET", "/healthz", nil, nil)
 	if err != nil {
 		fatalf("health failed: %v", err)
 	}
@@ -89,15 +130,17 @@ func runHealth(args []string) {
 
 func runUser(args []string) {
 	fs := flag.NewFlagSet("user", flag.ExitOnError)
-	token := baseFlags(fs)
+	token, output, fields, retryMax, retryBase := commonFlags(fs)
 	fs.Parse(args)
 
-	c := newClient(*token, true)
-	resp, err := c.doRequest("GET", "/user", nil, nil)
+	c := newClient(*token, true, *retryMax, *retryBase)
+	ctx, cancel := rootContext()
+	defer cancel()
+	resp, err := c.doRequest(ctx, "GET", "/user", nil, nil)
 	if err != nil {
 		fatalf("user request failed: %v", err)
 	}
-	printJSON(resp)
+	renderOutput(resp, *output, *fields)
 }
 
 func runLabs(args []string) {
@@ -120,7 +163,8 @@ func runLabsList(args []string) {
 	fs := flag.NewFlagSet("labs list", flag.ExitOnError)
 	orgID := fs.String("organization-id", "", "Organization ID")
 	page := fs.Int("page", 0, "Page number")
-	token := baseFlags(fs)
+	all, max, stream := pageWalkFlags(fs)
+	token, output, fields, retryMax, retryBase := commonFlags(fs)
 	fs.Parse(args)
 
 	query := url.Values{}
@@ -132,29 +176,37 @@ func runLabsList(args []string) {
 		query.Set("page", fmt.Sprintf("%d", *page))
 	}
 
-	c := newClient(*token, true)
-	resp, err := c.doRequest("GET", "/labs", query, nil)
+	c := newClient(*token, true, *retryMax, *retryBase)
+	ctx, cancel := rootContext()
+	defer cancel()
+	if *all || *stream {
+		runListAll(ctx, c, "/labs", query, *max, *stream, *output, *fields, "labs list")
+		return
+	}
+	resp, err := c.doRequest(ctx, "GET", "/labs", query, nil)
 	if err != nil {
 		fatalf("labs list failed: %v", err)
 	}
-	printJSON(resp)
+	renderOutput(resp, *output, *fields)
 }
 
 func runLabsGet(args []string) {
 	fs := flag.NewFlagSet("labs get", flag.ExitOnError)
 	id := fs.String("id", "", "Lab ID")
-	token := baseFlags(fs)
+	token, output, fields, retryMax, retryBase := commonFlags(fs)
 	fs.Parse(args)
 
 	if *id == "" {
 		fatalf("provide --id")
 	}
-	c := newClient(*token, true)
-	resp, err := c.doRequest("GET", "/labs/"+url.PathEscape(*id), nil, nil)
+	c := newClient(*token, true, *retryMax, *retryBase)
+	ctx, cancel := rootContext()
+	defer cancel()
+	resp, err := c.doRequest(ctx, "GET", "/labs/"+url.PathEscape(*id), nil, nil)
 	if err != nil {
 		fatalf("labs get failed: %v", err)
 	}
-	printJSON(resp)
+	renderOutput(resp, *output, *fields)
 }
 
 func runInventory(args []string) {
@@ -169,6 +221,8 @@ func runInventory(args []string) {
 		runInventoryGet(args[1:])
 	case "update":
 		runInventoryUpdate(args[1:])
+	case "bulk-update":
+		runInventoryBulkUpdate(args[1:])
 	default:
 		usage()
 		os.Exit(1)
@@ -179,7 +233,8 @@ func runInventoryList(args []string) {
 	fs := flag.NewFlagSet("inventory list", flag.ExitOnError)
 	labID := fs.String("lab-id", "", "Lab ID")
 	page := fs.Int("page", 0, "Page number")
-	token := baseFlags(fs)
+	all, max, stream := pageWalkFlags(fs)
+	token, output, fields, retryMax, retryBase := commonFlags(fs)
 	fs.Parse(args)
 
 	query := url.Values{}
@@ -191,36 +246,44 @@ func runInventoryList(args []string) {
 		query.Set("page", fmt.Sprintf("%d", *page))
 	}
 
-	c := newClient(*token, true)
-	resp, err := c.doRequest("GET", "/inventory-items", query, nil)
+	c := newClient(*token, true, *retryMax, *retryBase)
+	ctx, cancel := rootContext()
+	defer cancel()
+	if *all || *stream {
+		runListAll(ctx, c, "/inventory-items", query, *max, *stream, *output, *fields, "inventory list")
+		return
+	}
+	resp, err := c.doRequest(ctx, "GET", "/inventory-items", query, nil)
 	if err != nil {
 		fatalf("inventory list failed: %v", err)
 	}
-	printJSON(resp)
+	renderOutput(resp, *output, *fields)
 }
 
 func runInventoryGet(args []string) {
 	fs := flag.NewFlagSet("inventory get", flag.ExitOnError)
 	id := fs.String("id", "", "Inventory item ID")
-	token := baseFlags(fs)
+	token, output, fields, retryMax, retryBase := commonFlags(fs)
 	fs.Parse(args)
 
 	if *id == "" {
 		fatalf("provide --id")
 	}
-	c := newClient(*token, true)
-	resp, err := c.doRequest("GET", "/inventory-items/"+url.PathEscape(*id), nil, nil)
+	c := newClient(*token, true, *retryMax, *retryBase)
+	ctx, cancel := rootContext()
+	defer cancel()
+	resp, err := c.doRequest(ctx, "GET", "/inventory-items/"+url.PathEscape(*id), nil, nil)
 	if err != nil {
 		fatalf("inventory get failed: %v", err)
 	}
-	printJSON(resp)
+	renderOutput(resp, *output, *fields)
 }
 
 func runInventoryUpdate(args []string) {
 	fs := flag.NewFlagSet("inventory update", flag.ExitOnError)
 	id := fs.String("id", "", "Inventory item ID")
 	quantity := fs.String("quantity", "", "Quantity")
-	token := baseFlags(fs)
+	token, output, fields, retryMax, retryBase := commonFlags(fs)
 	fs.Parse(args)
 
 	if *id == "" || *quantity == "" {
@@ -230,12 +293,67 @@ func runInventoryUpdate(args []string) {
 	body := map[string]string{
 		"quantity": *quantity,
 	}
-	c := newClient(*token, true)
-	resp, err := c.doRequest("PUT", "/inventory-items/"+url.PathEscape(*id), nil, body)
+	c := newClient(*token, true, *retryMax, *retryBase)
+	ctx, cancel := rootContext()
+	defer cancel()
+	resp, err := c.doRequest(ctx, "PUT", "/inventory-items/"+url.PathEscape(*id), nil, body)
 	if err != nil {
 		fatalf("inventory update failed: %v", err)
 	}
-	printJSON(resp)
+	renderOutput(resp, *output, *fields)
+}
+
+func runInventoryBulkUpdate(args []string) {
+	fs := flag.NewFlagSet("inventory bulk-update", flag.ExitOnError)
+	file := fs.String("file", "", "CSV file of rows to update (header: id,quantity)")
+	concurrency := fs.Int("concurrency", 4, "Number of concurrent update requests")
+	dryRun := fs.Bool("dry-run", false, "Validate and show the JSON body that would be PUT, without calling the API")
+	continueOnError := fs.Bool("continue-on-error", false, "Keep processing remaining rows after a row fails")
+	resultFormat := fs.String("result-format", "csv", "Result format: csv or jsonl")
+	token, _, _, retryMax, retryBase := commonFlags(fs)
+	fs.Parse(args)
+
+	if *file == "" {
+		fatalf("provide --file")
+	}
+	rows, err := readCSVRows(*file)
+	if err != nil {
+		fatalf("read --file: %v", err)
+	}
+
+	ids := make([]string, len(rows))
+	bodies := make([]map[string]string, len(rows))
+	for i, row := range rows {
+		if row["id"] == "" || row["quantity"] == "" {
+			fatalf("row %d: missing id or quantity", i+1)
+		}
+		ids[i] = row["id"]
+		bodies[i] = map[string]string{"quantity": row["quantity"]}
+	}
+
+	results := make([]bulkResult, len(rows))
+	if *dryRun {
+		for i, body := range bodies {
+			data, _ := json.MarshalIndent(body, "", "  ")
+			results[i] = bulkResult{Row: i + 1, OK: true, ID: ids[i], Body: string(data)}
+		}
+		writeBulkResults(results, *resultFormat)
+		return
+	}
+
+	c := newClient(*token, true, *retryMax, *retryBase)
+	ctx, cancel := rootContext()
+	defer cancel()
+
+	runBulk(ctx, *concurrency, len(bodies), *continueOnError, func(ctx context.Context, i int) bulkResult {
+		_, err := c.doRequest(ctx, "PUT", "/inventory-items/"+url.PathEscape(ids[i]), nil, bodies[i])
+		if err != nil {
+			return bulkResult{Row: i + 1, ID: ids[i], OK: false, Error: err.Error()}
+		}
+		return bulkResult{Row: i + 1, ID: ids[i], OK: true}
+	}, results)
+
+	writeBulkResults(results, *resultFormat)
 }
 
 func runOrderRequests(args []string) {
@@ -252,6 +370,8 @@ func runOrderRequests(args []string) {
 		runOrderRequestsCreate(args[1:])
 	case "update":
 		runOrderRequestsUpdate(args[1:])
+	case "bulk-create":
+		runOrderRequestsBulkCreate(args[1:])
 	default:
 		usage()
 		os.Exit(1)
@@ -264,7 +384,8 @@ func runOrderRequestsList(args []string) {
 	page := fs.Int("page", 0, "Page number")
 	status := fs.String("status", "", "Filter by status (comma-separated)")
 	createdOnly := fs.Bool("created", false, "Only include pending status")
-	token := baseFlags(fs)
+	all, max, stream := pageWalkFlags(fs)
+	token, output, fields, retryMax, retryBase := commonFlags(fs)
 	fs.Parse(args)
 
 	query := url.Values{}
@@ -293,8 +414,14 @@ func runOrderRequestsList(args []string) {
 		query.Add("status[]", entry)
 	}
 
-	c := newClient(*token, true)
-	resp, err := c.doRequest("GET", "/order-requests", query, nil)
+	c := newClient(*token, true, *retryMax, *retryBase)
+	ctx, cancel := rootContext()
+	defer cancel()
+	if *all || *stream {
+		runListAll(ctx, c, "/order-requests", query, *max, *stream, *output, *fields, "order-requests list")
+		return
+	}
+	resp, err := c.doRequest(ctx, "GET", "/order-requests", query, nil)
 	if err != nil {
 		fatalf("order-requests list failed: %v", err)
 	}
@@ -303,27 +430,29 @@ func runOrderRequestsList(args []string) {
 		if err != nil {
 			fatalf("filter order-requests failed: %v", err)
 		}
-		printJSON(filtered)
+		renderOutput(filtered, *output, *fields)
 		return
 	}
-	printJSON(resp)
+	renderOutput(resp, *output, *fields)
 }
 
 func runOrderRequestsGet(args []string) {
 	fs := flag.NewFlagSet("order-requests get", flag.ExitOnError)
 	id := fs.String("id", "", "Order request ID")
-	token := baseFlags(fs)
+	token, output, fields, retryMax, retryBase := commonFlags(fs)
 	fs.Parse(args)
 
 	if *id == "" {
 		fatalf("provide --id")
 	}
-	c := newClient(*token, true)
-	resp, err := c.doRequest("GET", "/order-requests/"+url.PathEscape(*id), nil, nil)
+	c := newClient(*token, true, *retryMax, *retryBase)
+	ctx, cancel := rootContext()
+	defer cancel()
+	resp, err := c.doRequest(ctx, "GET", "/order-requests/"+url.PathEscape(*id), nil, nil)
 	if err != nil {
 		fatalf("order-requests get failed: %v", err)
 	}
-	printJSON(resp)
+	renderOutput(resp, *output, *fields)
 }
 
 func runOrderRequestsCreate(args []string) {
@@ -339,7 +468,7 @@ func runOrderRequestsCreate(args []string) {
 	vendorProductID := fs.String("vendor-product-id", "", "Vendor product ID")
 	requiredBefore := fs.String("required-before", "", "Required before (YYYY-MM-DD)")
 	notes := fs.String("notes", "", "Notes")
-	token := baseFlags(fs)
+	token, output, fields, retryMax, retryBase := commonFlags(fs)
 	fs.Parse(args)
 
 	labValue := envOrFlag(*labID, "QUARTZY_LAB_ID")
@@ -369,19 +498,128 @@ func runOrderRequestsCreate(args []string) {
 		body["notes"] = *notes
 	}
 
-	c := newClient(*token, true)
-	resp, err := c.doRequest("POST", "/order-requests", nil, body)
+	c := newClient(*token, true, *retryMax, *retryBase)
+	ctx, cancel := rootContext()
+	defer cancel()
+	resp, err := c.doRequest(ctx, "POST", "/order-requests", nil, body)
 	if err != nil {
 		fatalf("order-requests create failed: %v", err)
 	}
-	printJSON(resp)
+	renderOutput(resp, *output, *fields)
+}
+
+// bulkOrderRequestColumns lists the CSV header names accepted by
+// order-requests bulk-create, mapping one-to-one onto the flags accepted by
+// order-requests create.
+var bulkOrderRequestColumns = []string{
+	"lab-id", "type-id", "name", "vendor-name", "catalog-number",
+	"price-amount", "price-currency", "quantity", "vendor-product-id",
+	"required-before", "notes",
+}
+
+// buildOrderRequestBody validates a single bulk-create CSV row and turns it
+// into the same request body shape runOrderRequestsCreate sends.
+func buildOrderRequestBody(row map[string]string) (map[string]interface{}, error) {
+	labValue := envOrFlag(row["lab-id"], "QUARTZY_LAB_ID")
+	if labValue == "" || row["type-id"] == "" || row["name"] == "" || row["vendor-name"] == "" ||
+		row["catalog-number"] == "" || row["price-amount"] == "" || row["price-currency"] == "" {
+		return nil, fmt.Errorf("missing one of lab-id, type-id, name, vendor-name, catalog-number, price-amount, price-currency")
+	}
+	quantity, err := strconv.Atoi(row["quantity"])
+	if err != nil || quantity <= 0 {
+		return nil, fmt.Errorf("quantity must be a positive integer, got %q", row["quantity"])
+	}
+	if row["required-before"] != "" {
+		if _, err := time.Parse("2006-01-02", row["required-before"]); err != nil {
+			return nil, fmt.Errorf("required-before must be YYYY-MM-DD, got %q", row["required-before"])
+		}
+	}
+
+	body := map[string]interface{}{
+		"lab_id":         labValue,
+		"type_id":        row["type-id"],
+		"name":           row["name"],
+		"vendor_name":    row["vendor-name"],
+		"catalog_number": row["catalog-number"],
+		"price": map[string]string{
+			"amount":   row["price-amount"],
+			"currency": row["price-currency"],
+		},
+		"quantity": quantity,
+	}
+	if row["vendor-product-id"] != "" {
+		body["vendor_product_id"] = row["vendor-product-id"]
+	}
+	if row["required-before"] != "" {
+		body["required_before"] = row["required-before"]
+	}
+	if row["notes"] != "" {
+		body["notes"] = row["notes"]
+	}
+	return body, nil
+}
+
+func runOrderRequestsBulkCreate(args []string) {
+	fs := flag.NewFlagSet("order-requests bulk-create", flag.ExitOnError)
+	file := fs.String("file", "", "CSV file of rows to create (header: "+strings.Join(bulkOrderRequestColumns, ",")+")")
+	concurrency := fs.Int("concurrency", 4, "Number of concurrent create requests")
+	dryRun := fs.Bool("dry-run", false, "Validate and show the JSON body that would be POSTed, without calling the API")
+	continueOnError := fs.Bool("continue-on-error", false, "Keep processing remaining rows after a row fails")
+	resultFormat := fs.String("result-format", "csv", "Result format: csv or jsonl")
+	token, _, _, retryMax, retryBase := commonFlags(fs)
+	fs.Parse(args)
+
+	if *file == "" {
+		fatalf("provide --file")
+	}
+	rows, err := readCSVRows(*file)
+	if err != nil {
+		fatalf("read --file: %v", err)
+	}
+
+	bodies := make([]map[string]interface{}, len(rows))
+	for i, row := range rows {
+		body, err := buildOrderRequestBody(row)
+		if err != nil {
+			fatalf("row %d: %v", i+1, err)
+		}
+		bodies[i] = body
+	}
+
+	results := make([]bulkResult, len(rows))
+	if *dryRun {
+		for i, body := range bodies {
+			data, _ := json.MarshalIndent(body, "", "  ")
+			results[i] = bulkResult{Row: i + 1, OK: true, Body: string(data)}
+		}
+		writeBulkResults(results, *resultFormat)
+		return
+	}
+
+	c := newClient(*token, true, *retryMax, *retryBase)
+	ctx, cancel := rootContext()
+	defer cancel()
+
+	runBulk(ctx, *concurrency, len(bodies), *continueOnError, func(ctx context.Context, i int) bulkResult {
+		resp, err := c.doRequest(ctx, "POST", "/order-requests", nil, bodies[i])
+		if err != nil {
+			return bulkResult{Row: i + 1, OK: false, Error: err.Error()}
+		}
+		var created struct {
+			ID string `json:"id"`
+		}
+		json.Unmarshal(resp, &created)
+		return bulkResult{Row: i + 1, OK: true, ID: created.ID}
+	}, results)
+
+	writeBulkResults(results, *resultFormat)
 }
 
 func runOrderRequestsUpdate(args []string) {
 	fs := flag.NewFlagSet("order-requests update", flag.ExitOnError)
 	id := fs.String("id", "", "Order request ID")
 	status := fs.String("status", "", "Status")
-	token := baseFlags(fs)
+	token, output, fields, retryMax, retryBase := commonFlags(fs)
 	fs.Parse(args)
 
 	if *id == "" || *status == "" {
@@ -394,12 +632,14 @@ func runOrderRequestsUpdate(args []string) {
 	body := map[string]string{
 		"status": *status,
 	}
-	c := newClient(*token, true)
-	resp, err := c.doRequest("PUT", "/order-requests/"+url.PathEscape(*id), nil, body)
+	c := newClient(*token, true, *retryMax, *retryBase)
+	ctx, cancel := rootContext()
+	defer cancel()
+	resp, err := c.doRequest(ctx, "PUT", "/order-requests/"+url.PathEscape(*id), nil, body)
 	if err != nil {
 		fatalf("order-requests update failed: %v", err)
 	}
-	printJSON(resp)
+	renderOutput(resp, *output, *fields)
 }
 
 func runTypes(args []string) {
@@ -421,7 +661,8 @@ func runTypesList(args []string) {
 	labID := fs.String("lab-id", "", "Lab ID")
 	name := fs.String("name", "", "Type name")
 	page := fs.Int("page", 0, "Page number")
-	token := baseFlags(fs)
+	all, max, stream := pageWalkFlags(fs)
+	token, output, fields, retryMax, retryBase := commonFlags(fs)
 	fs.Parse(args)
 
 	query := url.Values{}
@@ -436,12 +677,18 @@ func runTypesList(args []string) {
 		query.Set("page", fmt.Sprintf("%d", *page))
 	}
 
-	c := newClient(*token, true)
-	resp, err := c.doRequest("GET", "/types", query, nil)
+	c := newClient(*token, true, *retryMax, *retryBase)
+	ctx, cancel := rootContext()
+	defer cancel()
+	if *all || *stream {
+		runListAll(ctx, c, "/types", query, *max, *stream, *output, *fields, "types list")
+		return
+	}
+	resp, err := c.doRequest(ctx, "GET", "/types", query, nil)
 	if err != nil {
 		fatalf("types list failed: %v", err)
 	}
-	printJSON(resp)
+	renderOutput(resp, *output, *fields)
 }
 
 func runWebhooks(args []string) {
@@ -458,6 +705,8 @@ func runWebhooks(args []string) {
 		runWebhooksCreate(args[1:])
 	case "update":
 		runWebhooksUpdate(args[1:])
+	case "serve":
+		runWebhooksServe(args[1:])
 	default:
 		usage()
 		os.Exit(1)
@@ -468,7 +717,8 @@ func runWebhooksList(args []string) {
 	fs := flag.NewFlagSet("webhooks list", flag.ExitOnError)
 	orgID := fs.String("organization-id", "", "Organization ID")
 	page := fs.Int("page", 0, "Page number")
-	token := baseFlags(fs)
+	all, max, stream := pageWalkFlags(fs)
+	token, output, fields, retryMax, retryBase := commonFlags(fs)
 	fs.Parse(args)
 
 	query := url.Values{}
@@ -480,29 +730,37 @@ func runWebhooksList(args []string) {
 		query.Set("page", fmt.Sprintf("%d", *page))
 	}
 
-	c := newClient(*token, true)
-	resp, err := c.doRequest("GET", "/webhooks", query, nil)
+	c := newClient(*token, true, *retryMax, *retryBase)
+	ctx, cancel := rootContext()
+	defer cancel()
+	if *all || *stream {
+		runListAll(ctx, c, "/webhooks", query, *max, *stream, *output, *fields, "webhooks list")
+		return
+	}
+	resp, err := c.doRequest(ctx, "GET", "/webhooks", query, nil)
 	if err != nil {
 		fatalf("webhooks list failed: %v", err)
 	}
-	printJSON(resp)
+	renderOutput(resp, *output, *fields)
 }
 
 func runWebhooksGet(args []string) {
 	fs := flag.NewFlagSet("webhooks get", flag.ExitOnError)
 	id := fs.String("id", "", "Webhook ID")
-	token := baseFlags(fs)
+	token, output, fields, retryMax, retryBase := commonFlags(fs)
 	fs.Parse(args)
 
 	if *id == "" {
 		fatalf("provide --id")
 	}
-	c := newClient(*token, true)
-	resp, err := c.doRequest("GET", "/webhooks/"+url.PathEscape(*id), nil, nil)
+	c := newClient(*token, true, *retryMax, *retryBase)
+	ctx, cancel := rootContext()
+	defer cancel()
+	resp, err := c.doRequest(ctx, "GET", "/webhooks/"+url.PathEscape(*id), nil, nil)
 	if err != nil {
 		fatalf("webhooks get failed: %v", err)
 	}
-	printJSON(resp)
+	renderOutput(resp, *output, *fields)
 }
 
 func runWebhooksCreate(args []string) {
@@ -515,7 +773,7 @@ func runWebhooksCreate(args []string) {
 	isEnabled := fs.String("is-enabled", "", "true|false")
 	isVerified := fs.String("is-verified", "", "true|false")
 	isSigned := fs.String("is-signed", "", "true|false")
-	token := baseFlags(fs)
+	token, output, fields, retryMax, retryBase := commonFlags(fs)
 	fs.Parse(args)
 
 	if *urlValue == "" {
@@ -561,19 +819,21 @@ func runWebhooksCreate(args []string) {
 		body["is_signed"] = val
 	}
 
-	c := newClient(*token, true)
-	resp, err := c.doRequest("POST", "/webhooks", nil, body)
+	c := newClient(*token, true, *retryMax, *retryBase)
+	ctx, cancel := rootContext()
+	defer cancel()
+	resp, err := c.doRequest(ctx, "POST", "/webhooks", nil, body)
 	if err != nil {
 		fatalf("webhooks create failed: %v", err)
 	}
-	printJSON(resp)
+	renderOutput(resp, *output, *fields)
 }
 
 func runWebhooksUpdate(args []string) {
 	fs := flag.NewFlagSet("webhooks update", flag.ExitOnError)
 	id := fs.String("id", "", "Webhook ID")
 	isEnabled := fs.String("is-enabled", "", "true|false")
-	token := baseFlags(fs)
+	token, output, fields, retryMax, retryBase := commonFlags(fs)
 	fs.Parse(args)
 
 	if *id == "" || *isEnabled == "" {
@@ -588,19 +848,269 @@ func runWebhooksUpdate(args []string) {
 	body := map[string]bool{
 		"is_enabled": val,
 	}
-	c := newClient(*token, true)
-	resp, err := c.doRequest("PUT", "/webhooks/"+url.PathEscape(*id), nil, body)
+	c := newClient(*token, true, *retryMax, *retryBase)
+	ctx, cancel := rootContext()
+	defer cancel()
+	resp, err := c.doRequest(ctx, "PUT", "/webhooks/"+url.PathEscape(*id), nil, body)
 	if err != nil {
 		fatalf("webhooks update failed: %v", err)
 	}
-	printJSON(resp)
+	renderOutput(resp, *output, *fields)
 }
 
-func baseFlags(fs *flag.FlagSet) *string {
-	return fs.String("token", "", "Access token (overrides QUARTZY_KEY)")
+// webhookVerifier checks Quartzy webhook deliveries against a shared HMAC-SHA256
+// secret, rejecting stale timestamps to guard against replay. It has no
+// dependency on the rest of this package, so it can be embedded directly in
+// other Go services that also need to receive signed Quartzy webhooks.
+type webhookVerifier struct {
+	secret []byte
+	skew   time.Duration
+}
+
+func newWebhookVerifier(secret string, skew time.Duration) *webhookVerifier {
+	if skew <= 0 {
+		skew = 5 * time.Minute
+	}
+	return &webhookVerifier{secret: []byte(secret), skew: skew}
 }
 
-func newClient(tokenOverride string, requireToken bool) *client {
+// Verify checks sigHeader (hex-encoded HMAC-SHA256 of "<timestamp>.<body>")
+// against the verifier's secret in constant time, and rejects the request if
+// tsHeader falls outside the configured skew window.
+func (v *webhookVerifier) Verify(body []byte, sigHeader, tsHeader string) error {
+	if sigHeader == "" {
+		return fmt.Errorf("missing signature header")
+	}
+	if tsHeader == "" {
+		return fmt.Errorf("missing timestamp header")
+	}
+	tsUnix, err := strconv.ParseInt(tsHeader, 10, 64)
+	if err != nil {
+		return fmt.Errorf("invalid timestamp header: %w", err)
+	}
+	ts := time.Unix(tsUnix, 0)
+	if age := time.Since(ts); age > v.skew || age < -v.skew {
+		return fmt.Errorf("timestamp outside allowed skew of %s", v.skew)
+	}
+
+	mac := hmac.New(sha256.New, v.secret)
+	mac.Write([]byte(tsHeader))
+	mac.Write([]byte("."))
+	mac.Write(body)
+	expected := mac.Sum(nil)
+
+	sig, err := hex.DecodeString(strings.TrimSpace(sigHeader))
+	if err != nil {
+		return fmt.Errorf("invalid signature encoding: %w", err)
+	}
+	if !hmac.Equal(sig, expected) {
+		return fmt.Errorf("signature mismatch")
+	}
+	return nil
+}
+
+func runWebhooksServe(args []string) {
+	fs := flag.NewFlagSet("webhooks serve", flag.ExitOnError)
+	addr := fs.String("addr", ":8080", "Address to listen on")
+	secret := fs.String("secret", "", "HMAC shared secret used to sign webhook deliveries")
+	eventTypes := fs.String("event-types", "", "Only accept these comma-separated event types (default: all)")
+	logPath := fs.String("log", "", "Append validated events as JSONL to this file (default: stdout)")
+	forward := fs.String("forward", "", "Re-POST validated event bodies to this URL")
+	maxSkew := fs.Duration("max-skew", 5*time.Minute, "Maximum allowed clock skew between the event timestamp and now")
+	fs.Parse(args)
+
+	secretValue := envOrFlag(*secret, "QUARTZY_WEBHOOK_SECRET")
+	if secretValue == "" {
+		fatalf("provide --secret or set QUARTZY_WEBHOOK_SECRET")
+	}
+
+	var allowedEvents map[string]struct{}
+	if *eventTypes != "" {
+		allowedEvents = make(map[string]struct{})
+		for _, ev := range splitCSV(*eventTypes) {
+			allowedEvents[ev] = struct{}{}
+		}
+	}
+
+	var out io.Writer = os.Stdout
+	if *logPath != "" {
+		f, err := os.OpenFile(*logPath, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0o644)
+		if err != nil {
+			fatalf("open --log: %v", err)
+		}
+		defer f.Close()
+		out = f
+	}
+	writer := bufio.NewWriter(out)
+	defer writer.Flush()
+	// net/http runs this handler once per request on its own goroutine, so
+	// writer's Write/WriteString/Flush sequence below needs to be
+	// serialized or concurrent deliveries can interleave mid-line.
+	var writeMu sync.Mutex
+
+	verifier := newWebhookVerifier(secretValue, *maxSkew)
+	httpClient := &http.Client{Timeout: 10 * time.Second}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/", func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost {
+			http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+			return
+		}
+		body, err := io.ReadAll(io.LimitReader(r.Body, 10<<20))
+		if err != nil {
+			http.Error(w, "failed to read body", http.StatusBadRequest)
+			return
+		}
+		if err := verifier.Verify(body, r.Header.Get("X-Quartzy-Signature"), r.Header.Get("X-Quartzy-Timestamp")); err != nil {
+			log.Printf("webhook rejected: %v", err)
+			http.Error(w, "invalid signature", http.StatusUnauthorized)
+			return
+		}
+
+		var event map[string]interface{}
+		if err := json.Unmarshal(body, &event); err != nil {
+			http.Error(w, "invalid JSON body", http.StatusBadRequest)
+			return
+		}
+		if allowedEvents != nil {
+			evType, _ := event["event_type"].(string)
+			if _, ok := allowedEvents[evType]; !ok {
+				w.WriteHeader(http.StatusOK)
+				return
+			}
+		}
+
+		writeMu.Lock()
+		writer.Write(body)
+		writer.WriteString("\n")
+		writer.Flush()
+		writeMu.Unlock()
+
+		if *forward != "" {
+			go forwardWebhook(httpClient, *forward, body)
+		}
+
+		w.WriteHeader(http.StatusOK)
+	})
+
+	log.Printf("quartzy webhooks serve listening on %s", *addr)
+	if err := http.ListenAndServe(*addr, mux); err != nil {
+		fatalf("webhooks serve failed: %v", err)
+	}
+}
+
+func forwardWebhook(httpClient *http.Client, forwardURL string, body []byte) {
+	resp, err := httpClient.Post(forwardURL, "application/json", bytes.NewReader(body))
+	if err != nil {
+		log.Printf("forward to %s failed: %v", forwardURL, err)
+		return
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		log.Printf("forward to %s returned status %d", forwardURL, resp.StatusCode)
+	}
+}
+
+// pageWalkFlags registers the --all/--max/--stream flags shared by every list
+// subcommand and returns pointers to their values.
+func pageWalkFlags(fs *flag.FlagSet) (*bool, *int, *bool) {
+	all := fs.Bool("all", false, "Walk every page until the API returns an empty page or --max is reached")
+	max := fs.Int("max", 0, "Stop after accumulating this many results with --all or --stream (0 = no cap)")
+	stream := fs.Bool("stream", false, "Emit one JSON object per line as each page arrives, instead of buffering the full result (implies --all)")
+	return all, max, stream
+}
+
+// listPage is one page of results from client.List, or the error that ended
+// the walk.
+type listPage struct {
+	Items []json.RawMessage
+	Err   error
+}
+
+// List walks path page by page starting at page 1, adding "page" to a copy of
+// baseQuery each time, until the API returns an empty page or max items have
+// been accumulated (max <= 0 means no cap). It streams pages back on the
+// returned channel so callers can process results incrementally instead of
+// buffering an unbounded response in memory.
+func (c *client) List(ctx context.Context, path string, baseQuery url.Values, max int) <-chan listPage {
+	ch := make(chan listPage)
+	go func() {
+		defer close(ch)
+		page := 1
+		total := 0
+		for {
+			query := cloneQuery(baseQuery)
+			query.Set("page", strconv.Itoa(page))
+
+			resp, err := c.doRequest(ctx, "GET", path, query, nil)
+			if err != nil {
+				ch <- listPage{Err: err}
+				return
+			}
+			var items []json.RawMessage
+			if err := json.Unmarshal(resp, &items); err != nil {
+				ch <- listPage{Err: fmt.Errorf("decode page %d: %w", page, err)}
+				return
+			}
+			if len(items) == 0 {
+				return
+			}
+			if max > 0 && total+len(items) > max {
+				items = items[:max-total]
+			}
+			ch <- listPage{Items: items}
+			total += len(items)
+			if max > 0 && total >= max {
+				return
+			}
+			page++
+		}
+	}()
+	return ch
+}
+
+func cloneQuery(query url.Values) url.Values {
+	out := url.Values{}
+	for k, v := range query {
+		out[k] = append([]string(nil), v...)
+	}
+	return out
+}
+
+// runListAll drives client.List for a list subcommand, either buffering every
+// page into a single JSON array (default) or emitting one JSON object per
+// line as each page arrives (--stream), to keep memory bounded for large
+// inventories.
+func runListAll(ctx context.Context, c *client, path string, baseQuery url.Values, max int, stream bool, output, fields, label string) {
+	ch := c.List(ctx, path, baseQuery, max)
+	if stream {
+		for page := range ch {
+			if page.Err != nil {
+				fatalf("%s failed: %v", label, page.Err)
+			}
+			for _, item := range page.Items {
+				fmt.Println(string(item))
+			}
+		}
+		return
+	}
+
+	var all []json.RawMessage
+	for page := range ch {
+		if page.Err != nil {
+			fatalf("%s failed: %v", label, page.Err)
+		}
+		all = append(all, page.Items...)
+	}
+	out, err := json.Marshal(all)
+	if err != nil {
+		fatalf("%s failed: %v", label, err)
+	}
+	renderOutput(out, output, fields)
+}
+
+func newClient(tokenOverride string, requireToken bool, retryMax int, retryBase time.Duration) *client {
 	token := tokenOverride
 	if token == "" {
 		token = os.Getenv("QUARTZY_KEY")
@@ -608,56 +1118,194 @@ func newClient(tokenOverride string, requireToken bool) *client {
 	if requireToken && token == "" {
 		fatalf("missing access token; set QUARTZY_KEY in .env or pass --token")
 	}
+	if retryMax <= 0 {
+		retryMax = defaultRetryMax
+	}
+	if retryBase <= 0 {
+		retryBase = defaultRetryBase
+	}
 	return &client{
 		baseURL: envOrDefault("QUARTZY_BASE_URL", defaultBaseURL),
 		token:   token,
 		http: &http.Client{
 			Timeout: 30 * time.Second,
 		},
+		retryMax:  retryMax,
+		retryBase: retryBase,
+	}
+}
+
+// isIdempotentMethod reports whether method may be retried without an
+// idempotency key, because repeating it has no additional side effect beyond
+// the first successful attempt.
+func isIdempotentMethod(method string) bool {
+	switch method {
+	case http.MethodGet, http.MethodPut, http.MethodDelete:
+		return true
+	default:
+		return false
 	}
 }
 
-func (c *client) doRequest(method, path string, query url.Values, body interface{}) ([]byte, error) {
+// doRequest issues method/path with automatic retries: network errors and
+// 429/502/503/504 responses are retried with full-jitter exponential backoff
+// (honoring a Retry-After header when present), up to c.retryMax attempts.
+// Non-idempotent verbs (currently just POST) get a stable Idempotency-Key
+// generated once and reused across every retry of the same logical call, so
+// the server can dedupe a request that succeeded but whose response was
+// lost. ctx cancellation (e.g. Ctrl-C) aborts the retry loop immediately.
+func (c *client) doRequest(ctx context.Context, method, path string, query url.Values, body interface{}) ([]byte, error) {
 	fullURL := strings.TrimRight(c.baseURL, "/") + path
 	if len(query) > 0 {
 		fullURL += "?" + query.Encode()
 	}
 
-	var payload io.Reader
+	var payload []byte
 	if body != nil {
 		data, err := json.Marshal(body)
 		if err != nil {
 			return nil, err
 		}
-		payload = bytes.NewReader(data)
+		payload = data
 	}
 
-	req, err := http.NewRequest(method, fullURL, payload)
-	if err != nil {
-		return nil, err
+	var idempotencyKey string
+	if !isIdempotentMethod(method) {
+		idempotencyKey = newIdempotencyKey()
 	}
-	req.Header.Set("Accept", "application/json")
-	if c.token != "" {
-		req.Header.Set("Access-Token", c.token)
+
+	var lastErr error
+	for attempt := 0; attempt < c.retryMax; attempt++ {
+		if attempt > 0 {
+			if err := sleepWithContext(ctx, retryDelay(attempt, c.retryBase, lastErr)); err != nil {
+				return nil, err
+			}
+		}
+
+		var reader io.Reader
+		if payload != nil {
+			reader = bytes.NewReader(payload)
+		}
+		req, err := http.NewRequestWithContext(ctx, method, fullURL, reader)
+		if err != nil {
+			return nil, err
+		}
+		req.Header.Set("Accept", "application/json")
+		if c.token != "" {
+			req.Header.Set("Access-Token", c.token)
+		}
+		if body != nil {
+			req.Header.Set("Content-Type", "application/json")
+		}
+		if idempotencyKey != "" {
+			req.Header.Set("Idempotency-Key", idempotencyKey)
+		}
+
+		resp, err := c.http.Do(req)
+		if err != nil {
+			if ctx.Err() != nil {
+				return nil, ctx.Err()
+			}
+			lastErr = err
+			continue
+		}
+
+		respBody, err := io.ReadAll(resp.Body)
+		resp.Body.Close()
+		if err != nil {
+			lastErr = err
+			continue
+		}
+
+		if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+			apiErr := fmt.Errorf("quartzy api error (%d): %s", resp.StatusCode, strings.TrimSpace(string(respBody)))
+			if isRetryableStatus(resp.StatusCode) && attempt < c.retryMax-1 {
+				lastErr = &retryableError{err: apiErr, retryAfter: parseRetryAfter(resp.Header.Get("Retry-After"))}
+				continue
+			}
+			return nil, apiErr
+		}
+		return respBody, nil
 	}
-	if body != nil {
-		req.Header.Set("Content-Type", "application/json")
+	return nil, fmt.Errorf("giving up after %d attempts: %w", c.retryMax, lastErr)
+}
+
+func isRetryableStatus(status int) bool {
+	switch status {
+	case http.StatusTooManyRequests, http.StatusBadGateway, http.StatusServiceUnavailable, http.StatusGatewayTimeout:
+		return true
+	default:
+		return false
 	}
+}
 
-	resp, err := c.http.Do(req)
-	if err != nil {
-		return nil, err
+// retryableError carries a server-provided Retry-After delay (zero if none
+// was sent) alongside the error that triggered the retry.
+type retryableError struct {
+	err        error
+	retryAfter time.Duration
+}
+
+func (e *retryableError) Error() string { return e.err.Error() }
+func (e *retryableError) Unwrap() error { return e.err }
+
+// retryDelay computes how long to wait before the given attempt (1-indexed
+// from the second attempt onward): full-jitter exponential backoff capped at
+// defaultRetryCap, unless lastErr carries an explicit Retry-After value, in
+// which case that takes priority.
+func retryDelay(attempt int, base time.Duration, lastErr error) time.Duration {
+	var retryable *retryableError
+	if errors.As(lastErr, &retryable) && retryable.retryAfter > 0 {
+		return retryable.retryAfter
 	}
-	defer resp.Body.Close()
+	backoff := base * time.Duration(1<<uint(attempt-1))
+	if backoff > defaultRetryCap {
+		backoff = defaultRetryCap
+	}
+	return time.Duration(mathrand.Int63n(int64(backoff) + 1))
+}
 
-	respBody, err := io.ReadAll(resp.Body)
-	if err != nil {
-		return nil, err
+// parseRetryAfter accepts either a Retry-After expressed as a number of
+// seconds or as an HTTP date, returning 0 if it can't be parsed.
+func parseRetryAfter(header string) time.Duration {
+	if header == "" {
+		return 0
 	}
-	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
-		return nil, fmt.Errorf("quartzy api error (%d): %s", resp.StatusCode, strings.TrimSpace(string(respBody)))
+	if secs, err := strconv.Atoi(header); err == nil {
+		return time.Duration(secs) * time.Second
+	}
+	if when, err := http.ParseTime(header); err == nil {
+		if d := time.Until(when); d > 0 {
+			return d
+		}
+	}
+	return 0
+}
+
+func sleepWithContext(ctx context.Context, d time.Duration) error {
+	if d <= 0 {
+		return nil
+	}
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
+
+// newIdempotencyKey generates a random UUID-like token used to dedupe
+// retries of the same logical POST on the server side.
+func newIdempotencyKey() string {
+	var b [16]byte
+	if _, err := rand.Read(b[:]); err != nil {
+		return fmt.Sprintf("fallback-%d", time.Now().UnixNano())
 	}
-	return respBody, nil
+	b[6] = (b[6] & 0x0f) | 0x40
+	b[8] = (b[8] & 0x3f) | 0x80
+	return fmt.Sprintf("%x-%x-%x-%x-%x", b[0:4], b[4:6], b[6:8], b[8:10], b[10:16])
 }
 
 func printJSON(data []byte) {
@@ -672,6 +1320,271 @@ func printJSON(data []byte) {
 	fmt.Println(string(data))
 }
 
+// commonFlags registers the flags shared by every data-returning subcommand:
+// the access token, the output-format and field-selection flags consumed by
+// renderOutput, and the retry-tuning flags consumed by newClient.
+func commonFlags(fs *flag.FlagSet) (*string, *string, *string, *int, *time.Duration) {
+	token := fs.String("token", "", "Access token (overrides QUARTZY_KEY)")
+	output := fs.String("output", "json", "Output format: json, jsonl, table, csv, tsv")
+	fields := fs.String("fields", "", "Comma-separated dot-notation fields to project before rendering (e.g. id,name,metadata.sku)")
+	retryMax := fs.Int("retry-max", defaultRetryMax, "Maximum request attempts on network errors and 429/502/503/504 responses")
+	retryBase := fs.Duration("retry-base", defaultRetryBase, "Base delay for exponential backoff between retries (doubles each attempt, capped at 30s)")
+	return token, output, fields, retryMax, retryBase
+}
+
+// rootContext returns a context that is cancelled when the process receives
+// SIGINT, so Ctrl-C aborts any in-flight request retries promptly instead of
+// leaving the CLI hung on a wedged connection.
+func rootContext() (context.Context, context.CancelFunc) {
+	return signal.NotifyContext(context.Background(), os.Interrupt)
+}
+
+// renderer renders a set of decoded JSON rows to w.
+type renderer interface {
+	Render(w io.Writer, rows []map[string]interface{}) error
+}
+
+func rendererFor(format string) (renderer, error) {
+	switch format {
+	case "", "json":
+		return jsonRenderer{}, nil
+	case "jsonl":
+		return jsonlRenderer{}, nil
+	case "table":
+		return tableRenderer{}, nil
+	case "csv":
+		return delimitedRenderer{delim: ','}, nil
+	case "tsv":
+		return delimitedRenderer{delim: '\t'}, nil
+	default:
+		return nil, fmt.Errorf("unknown --output %q (want json, jsonl, table, csv, or tsv)", format)
+	}
+}
+
+// renderOutput decodes a Quartzy API response (a single object or an array of
+// objects), optionally projects it down to --fields, and renders it with the
+// renderer selected by --output. It replaces the old printJSON sink at every
+// call site so plain `--output json` behaves exactly as before.
+func renderOutput(data []byte, output, fields string) {
+	if len(bytes.TrimSpace(data)) == 0 {
+		return
+	}
+	if output == "" || output == "json" {
+		if fields == "" {
+			printJSON(data)
+			return
+		}
+	}
+
+	rows, err := decodeRows(data)
+	if err != nil {
+		// Not a JSON object/array we can project or tabulate; fall back to
+		// the raw response rather than failing the command.
+		printJSON(data)
+		return
+	}
+	if fields != "" {
+		rows = projectFields(rows, splitCSV(fields))
+	}
+
+	rend, err := rendererFor(output)
+	if err != nil {
+		fatalf("%v", err)
+	}
+	if err := rend.Render(os.Stdout, rows); err != nil {
+		fatalf("render output: %v", err)
+	}
+}
+
+func decodeRows(data []byte) ([]map[string]interface{}, error) {
+	trimmed := bytes.TrimSpace(data)
+	if len(trimmed) == 0 {
+		return nil, nil
+	}
+	if trimmed[0] == '[' {
+		var rows []map[string]interface{}
+		if err := json.Unmarshal(trimmed, &rows); err != nil {
+			return nil, err
+		}
+		return rows, nil
+	}
+	var row map[string]interface{}
+	if err := json.Unmarshal(trimmed, &row); err != nil {
+		return nil, err
+	}
+	return []map[string]interface{}{row}, nil
+}
+
+// projectFields resolves each dot-notation path (with "[]" denoting "every
+// element of this array") against each row and returns a new set of rows
+// containing only those flattened fields, keyed by the original path.
+func projectFields(rows []map[string]interface{}, paths []string) []map[string]interface{} {
+	out := make([]map[string]interface{}, len(rows))
+	for i, row := range rows {
+		projected := make(map[string]interface{}, len(paths))
+		for _, path := range paths {
+			projected[path] = resolvePath(row, strings.Split(path, "."))
+		}
+		out[i] = projected
+	}
+	return out
+}
+
+// resolvePath walks a dotted field path through nested maps/slices. A
+// segment of "field[]" descends into every element of the array at "field"
+// and collects the remaining path from each, returning a slice.
+func resolvePath(value interface{}, segments []string) interface{} {
+	if len(segments) == 0 || value == nil {
+		return value
+	}
+	segment := segments[0]
+	rest := segments[1:]
+
+	arrayField := strings.HasSuffix(segment, "[]")
+	key := strings.TrimSuffix(segment, "[]")
+
+	m, ok := value.(map[string]interface{})
+	if !ok {
+		return nil
+	}
+	next, ok := m[key]
+	if !ok {
+		return nil
+	}
+	if !arrayField {
+		return resolvePath(next, rest)
+	}
+	items, ok := next.([]interface{})
+	if !ok {
+		return nil
+	}
+	out := make([]interface{}, len(items))
+	for i, item := range items {
+		out[i] = resolvePath(item, rest)
+	}
+	return out
+}
+
+func scalarString(v interface{}) string {
+	switch val := v.(type) {
+	case nil:
+		return ""
+	case string:
+		return val
+	case float64:
+		if val == float64(int64(val)) {
+			return strconv.FormatInt(int64(val), 10)
+		}
+		return strconv.FormatFloat(val, 'f', -1, 64)
+	default:
+		data, err := json.Marshal(val)
+		if err != nil {
+			return fmt.Sprintf("%v", val)
+		}
+		return string(data)
+	}
+}
+
+// rowColumns returns the union of every row's keys, sorted alphabetically,
+// so table/CSV output has a stable column set and order even when rows have
+// divergent shapes.
+func rowColumns(rows []map[string]interface{}) []string {
+	seen := map[string]struct{}{}
+	var cols []string
+	for _, row := range rows {
+		for k := range row {
+			if _, ok := seen[k]; !ok {
+				seen[k] = struct{}{}
+				cols = append(cols, k)
+			}
+		}
+	}
+	sort.Strings(cols)
+	return cols
+}
+
+type jsonRenderer struct{}
+
+func (jsonRenderer) Render(w io.Writer, rows []map[string]interface{}) error {
+	data, err := json.MarshalIndent(rows, "", "  ")
+	if err != nil {
+		return err
+	}
+	_, err = fmt.Fprintln(w, string(data))
+	return err
+}
+
+type jsonlRenderer struct{}
+
+func (jsonlRenderer) Render(w io.Writer, rows []map[string]interface{}) error {
+	enc := json.NewEncoder(w)
+	for _, row := range rows {
+		if err := enc.Encode(row); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+type tableRenderer struct{}
+
+func (tableRenderer) Render(w io.Writer, rows []map[string]interface{}) error {
+	cols := rowColumns(rows)
+	widths := make([]int, len(cols))
+	for i, col := range cols {
+		widths[i] = len(col)
+	}
+	cells := make([][]string, len(rows))
+	for i, row := range rows {
+		cells[i] = make([]string, len(cols))
+		for j, col := range cols {
+			s := scalarString(row[col])
+			cells[i][j] = s
+			if len(s) > widths[j] {
+				widths[j] = len(s)
+			}
+		}
+	}
+
+	writeRow := func(values []string) {
+		parts := make([]string, len(values))
+		for i, v := range values {
+			parts[i] = v + strings.Repeat(" ", widths[i]-len(v))
+		}
+		fmt.Fprintln(w, strings.Join(parts, "  "))
+	}
+	writeRow(cols)
+	for _, row := range cells {
+		writeRow(row)
+	}
+	return nil
+}
+
+// delimitedRenderer emits RFC 4180 CSV/TSV with a header row.
+type delimitedRenderer struct {
+	delim rune
+}
+
+func (r delimitedRenderer) Render(w io.Writer, rows []map[string]interface{}) error {
+	cols := rowColumns(rows)
+	cw := csv.NewWriter(w)
+	cw.Comma = r.delim
+	if err := cw.Write(cols); err != nil {
+		return err
+	}
+	for _, row := range rows {
+		record := make([]string, len(cols))
+		for i, col := range cols {
+			record[i] = scalarString(row[col])
+		}
+		if err := cw.Write(record); err != nil {
+			return err
+		}
+	}
+	cw.Flush()
+	return cw.Error()
+}
+
 func parseOptionalBool(val string) (bool, bool, error) {
 	val = strings.TrimSpace(strings.ToLower(val))
 	if val == "" {
@@ -700,6 +1613,427 @@ func splitCSV(val string) []string {
 	return out
 }
 
+// readCSVRows reads path as a CSV file whose header row names the fields
+// referenced by each subsequent record, returning one map per record keyed
+// by header name. Short rows leave trailing fields as the empty string.
+func readCSVRows(path string) ([]map[string]string, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	r := csv.NewReader(f)
+	r.TrimLeadingSpace = true
+	r.FieldsPerRecord = -1
+	records, err := r.ReadAll()
+	if err != nil {
+		return nil, err
+	}
+	if len(records) == 0 {
+		return nil, nil
+	}
+	header := records[0]
+	rows := make([]map[string]string, 0, len(records)-1)
+	for _, record := range records[1:] {
+		row := make(map[string]string, len(header))
+		for i, col := range header {
+			if i < len(record) {
+				row[col] = strings.TrimSpace(record[i])
+			}
+		}
+		rows = append(rows, row)
+	}
+	return rows, nil
+}
+
+// bulkResult is one line of output from a bulk-create/bulk-update run: which
+// input row it corresponds to, whether it succeeded, the resulting/target ID,
+// the error if it failed, and (in --dry-run mode) the JSON body that would
+// have been sent.
+type bulkResult struct {
+	Row   int    `json:"row"`
+	OK    bool   `json:"ok"`
+	ID    string `json:"id,omitempty"`
+	Error string `json:"error,omitempty"`
+	Body  string `json:"body,omitempty"`
+}
+
+// runBulk executes work(ctx, i) for i in [0, n) over a bounded worker pool of
+// size concurrency, storing each result at results[i]. If continueOnError is
+// false, the first failing row cancels ctx so the remaining in-flight and
+// not-yet-started rows stop promptly instead of continuing to hammer an API
+// that is already failing.
+func runBulk(ctx context.Context, concurrency, n int, continueOnError bool, work func(ctx context.Context, i int) bulkResult, results []bulkResult) {
+	if concurrency <= 0 {
+		concurrency = 1
+	}
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	sem := make(chan struct{}, concurrency)
+	var wg sync.WaitGroup
+	for i := 0; i < n; i++ {
+		i := i
+		if ctx.Err() != nil && !continueOnError {
+			results[i] = bulkResult{Row: i + 1, OK: false, Error: "skipped: aborted after an earlier failure"}
+			continue
+		}
+		sem <- struct{}{}
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			defer func() { <-sem }()
+			result := work(ctx, i)
+			results[i] = result
+			if !result.OK && !continueOnError {
+				cancel()
+			}
+		}()
+	}
+	wg.Wait()
+}
+
+// writeBulkResults writes one line per bulkResult to stdout, either as CSV
+// (row,ok,id,error,body) or as JSONL, so partial failures can be filtered and
+// re-run from the saved output.
+func writeBulkResults(results []bulkResult, format string) {
+	switch format {
+	case "", "csv":
+		w := csv.NewWriter(os.Stdout)
+		w.Write([]string{"row", "ok", "id", "error", "body"})
+		for _, r := range results {
+			w.Write([]string{strconv.Itoa(r.Row), strconv.FormatBool(r.OK), r.ID, r.Error, r.Body})
+		}
+		w.Flush()
+	case "jsonl":
+		enc := json.NewEncoder(os.Stdout)
+		for _, r := range results {
+			enc.Encode(r)
+		}
+	default:
+		fatalf("unknown --result-format %q (want csv or jsonl)", format)
+	}
+}
+
+// syncResources are the resources pulled into the local SQLite cache by
+// `quartzy sync`. Webhooks are deliberately left out: they're low-volume and
+// configuration-like rather than something worth querying offline.
+var syncResources = []struct {
+	table string
+	path  string
+}{
+	{"labs", "/labs"},
+	{"inventory_items", "/inventory-items"},
+	{"order_requests", "/order-requests"},
+	{"types", "/types"},
+}
+
+// syncSummary counts how a table changed during one sync pass.
+type syncSummary struct {
+	Created, Updated, Deleted, Unchanged int
+}
+
+func defaultDBPath() string {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return ".quartzy.db"
+	}
+	return home + "/.quartzy.db"
+}
+
+func openSyncDB(path string) (*sql.DB, error) {
+	db, err := sql.Open("sqlite3", path)
+	if err != nil {
+		return nil, err
+	}
+	for _, res := range syncResources {
+		schema := fmt.Sprintf(`CREATE TABLE IF NOT EXISTS %s (
+			id TEXT PRIMARY KEY,
+			raw_json TEXT NOT NULL,
+			updated_at TEXT,
+			content_hash TEXT NOT NULL,
+			fetched_at TEXT NOT NULL
+		)`, res.table)
+		if _, err := db.Exec(schema); err != nil {
+			db.Close()
+			return nil, fmt.Errorf("create table %s: %w", res.table, err)
+		}
+	}
+	return db, nil
+}
+
+func runSync(args []string) {
+	fs := flag.NewFlagSet("sync", flag.ExitOnError)
+	labID := fs.String("lab-id", "", "Lab ID")
+	dbPath := fs.String("db", "", "Path to local SQLite DB (default ~/.quartzy.db)")
+	token, _, _, retryMax, retryBase := commonFlags(fs)
+	fs.Parse(args)
+
+	path := *dbPath
+	if path == "" {
+		path = defaultDBPath()
+	}
+	db, err := openSyncDB(path)
+	if err != nil {
+		fatalf("open --db: %v", err)
+	}
+	defer db.Close()
+
+	query := url.Values{}
+	labValue := envOrFlag(*labID, "QUARTZY_LAB_ID")
+	if labValue != "" {
+		query.Set("lab_id", labValue)
+	}
+
+	c := newClient(*token, true, *retryMax, *retryBase)
+	ctx, cancel := rootContext()
+	defer cancel()
+
+	for _, res := range syncResources {
+		summary, err := syncOneResource(ctx, db, c, res.table, res.path, query)
+		if err != nil {
+			fatalf("sync %s: %v", res.table, err)
+		}
+		fmt.Printf("%s: created=%d updated=%d deleted=%d unchanged=%d\n",
+			res.table, summary.Created, summary.Updated, summary.Deleted, summary.Unchanged)
+	}
+}
+
+// syncOneResource walks every page of path via client.List, upserting each
+// item into table keyed by its "id" field and comparing a content hash
+// against what's already stored to classify it as created/updated/unchanged.
+// IDs present in the table but not seen in this pull are deleted, so every
+// run does a full paginated pull rather than filtering the request by
+// "updated_at" — a request-level incremental fetch would mean an unmodified
+// row never appears in this pass's "seen" set, and the deletion pass above
+// would drop it as if it had been removed from Quartzy. The "updated_at"
+// column is still stored from the API response when present, so downstream
+// consumers (see "quartzy diff --since") can query by recency even though
+// the pull itself isn't filtered by it.
+func syncOneResource(ctx context.Context, db *sql.DB, c *client, table, path string, query url.Values) (syncSummary, error) {
+	existing, err := loadExistingHashes(db, table)
+	if err != nil {
+		return syncSummary{}, err
+	}
+
+	var summary syncSummary
+	seen := make(map[string]struct{}, len(existing))
+	now := time.Now().UTC().Format(time.RFC3339)
+
+	for page := range c.List(ctx, path, query, 0) {
+		if page.Err != nil {
+			return summary, page.Err
+		}
+		for _, item := range page.Items {
+			var obj map[string]interface{}
+			if err := json.Unmarshal(item, &obj); err != nil {
+				return summary, fmt.Errorf("decode %s item: %w", table, err)
+			}
+			id := idString(obj["id"])
+			if id == "" {
+				continue
+			}
+			seen[id] = struct{}{}
+			hash := contentHash(item)
+			updatedAt, _ := obj["updated_at"].(string)
+
+			prevHash, existed := existing[id]
+			switch {
+			case !existed:
+				summary.Created++
+			case prevHash != hash:
+				summary.Updated++
+			default:
+				summary.Unchanged++
+			}
+
+			_, err := db.Exec(
+				fmt.Sprintf(`INSERT INTO %s (id, raw_json, updated_at, content_hash, fetched_at)
+					VALUES (?, ?, ?, ?, ?)
+					ON CONFLICT(id) DO UPDATE SET raw_json=excluded.raw_json, updated_at=excluded.updated_at, content_hash=excluded.content_hash, fetched_at=excluded.fetched_at`, table),
+				id, string(item), updatedAt, hash, now,
+			)
+			if err != nil {
+				return summary, fmt.Errorf("upsert %s row %s: %w", table, id, err)
+			}
+		}
+	}
+
+	for id := range existing {
+		if _, ok := seen[id]; ok {
+			continue
+		}
+		if _, err := db.Exec(fmt.Sprintf("DELETE FROM %s WHERE id = ?", table), id); err != nil {
+			return summary, fmt.Errorf("delete %s row %s: %w", table, id, err)
+		}
+		summary.Deleted++
+	}
+	return summary, nil
+}
+
+func loadExistingHashes(db *sql.DB, table string) (map[string]string, error) {
+	rows, err := db.Query(fmt.Sprintf("SELECT id, content_hash FROM %s", table))
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	out := map[string]string{}
+	for rows.Next() {
+		var id, hash string
+		if err := rows.Scan(&id, &hash); err != nil {
+			return nil, err
+		}
+		out[id] = hash
+	}
+	return out, rows.Err()
+}
+
+func idString(v interface{}) string {
+	switch val := v.(type) {
+	case string:
+		return val
+	case float64:
+		return strconv.FormatInt(int64(val), 10)
+	default:
+		return ""
+	}
+}
+
+func contentHash(data []byte) string {
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:])
+}
+
+func runQuery(args []string) {
+	fs := flag.NewFlagSet("query", flag.ExitOnError)
+	dbPath := fs.String("db", "", "Path to local SQLite DB (default ~/.quartzy.db)")
+	output := fs.String("output", "table", "Output format: json, jsonl, table, csv, tsv")
+	fs.Parse(args)
+
+	if fs.NArg() == 0 {
+		fatalf("provide a SQL query, e.g. quartzy query \"select * from inventory_items\"")
+	}
+	sqlText := strings.Join(fs.Args(), " ")
+
+	path := *dbPath
+	if path == "" {
+		path = defaultDBPath()
+	}
+	db, err := sql.Open("sqlite3", path)
+	if err != nil {
+		fatalf("open --db: %v", err)
+	}
+	defer db.Close()
+
+	rows, err := queryRows(db, sqlText)
+	if err != nil {
+		fatalf("query failed: %v", err)
+	}
+	rend, err := rendererFor(*output)
+	if err != nil {
+		fatalf("%v", err)
+	}
+	if err := rend.Render(os.Stdout, rows); err != nil {
+		fatalf("render output: %v", err)
+	}
+}
+
+// queryRows runs an arbitrary read query against the sync DB and decodes it
+// into the same []map[string]interface{} shape the JSON renderers expect.
+func queryRows(db *sql.DB, query string) ([]map[string]interface{}, error) {
+	rows, err := db.Query(query)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	cols, err := rows.Columns()
+	if err != nil {
+		return nil, err
+	}
+
+	var out []map[string]interface{}
+	for rows.Next() {
+		values := make([]interface{}, len(cols))
+		ptrs := make([]interface{}, len(cols))
+		for i := range values {
+			ptrs[i] = &values[i]
+		}
+		if err := rows.Scan(ptrs...); err != nil {
+			return nil, err
+		}
+		row := make(map[string]interface{}, len(cols))
+		for i, col := range cols {
+			if b, ok := values[i].([]byte); ok {
+				row[col] = string(b)
+			} else {
+				row[col] = values[i]
+			}
+		}
+		out = append(out, row)
+	}
+	return out, rows.Err()
+}
+
+func runDiff(args []string) {
+	fs := flag.NewFlagSet("diff", flag.ExitOnError)
+	dbPath := fs.String("db", "", "Path to local SQLite DB (default ~/.quartzy.db)")
+	since := fs.String("since", "", "Only show rows fetched or updated at or after this RFC3339 timestamp")
+	output := fs.String("output", "jsonl", "Output format: json, jsonl, table, csv, tsv")
+	fs.Parse(args)
+
+	if *since == "" {
+		fatalf("provide --since <RFC3339>")
+	}
+	if _, err := time.Parse(time.RFC3339, *since); err != nil {
+		fatalf("invalid --since: %v", err)
+	}
+
+	path := *dbPath
+	if path == "" {
+		path = defaultDBPath()
+	}
+	db, err := sql.Open("sqlite3", path)
+	if err != nil {
+		fatalf("open --db: %v", err)
+	}
+	defer db.Close()
+
+	var all []map[string]interface{}
+	for _, res := range syncResources {
+		rows, err := db.Query(fmt.Sprintf(
+			`SELECT id, raw_json, updated_at, fetched_at FROM %s WHERE fetched_at >= ? OR updated_at >= ?`, res.table),
+			*since, *since)
+		if err != nil {
+			fatalf("diff %s: %v", res.table, err)
+		}
+		for rows.Next() {
+			var id, rawJSON, updatedAt, fetchedAt sql.NullString
+			if err := rows.Scan(&id, &rawJSON, &updatedAt, &fetchedAt); err != nil {
+				rows.Close()
+				fatalf("diff %s: %v", res.table, err)
+			}
+			all = append(all, map[string]interface{}{
+				"resource":   res.table,
+				"id":         id.String,
+				"updated_at": updatedAt.String,
+				"fetched_at": fetchedAt.String,
+				"raw_json":   rawJSON.String,
+			})
+		}
+		rows.Close()
+	}
+
+	rend, err := rendererFor(*output)
+	if err != nil {
+		fatalf("%v", err)
+	}
+	if err := rend.Render(os.Stdout, all); err != nil {
+		fatalf("render output: %v", err)
+	}
+}
+
 func isValidOrderStatus(status string) bool {
 	switch status {
 	case "PENDING", "CREATED", "CANCELLED", "APPROVED", "ORDERED", "BACKORDERED", "RECEIVED":
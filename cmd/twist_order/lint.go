@@ -0,0 +1,325 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+)
+
+// lintEnzyme is one restriction enzyme's recognition sequence, checked on
+// both strands during --avoid site-avoidance scanning.
+type lintEnzyme struct {
+	Name        string
+	Recognition string
+}
+
+var lintEnzymes = map[string]lintEnzyme{
+	"BsaI":  {Name: "BsaI", Recognition: "GGTCTC"},
+	"BsmBI": {Name: "BsmBI", Recognition: "CGTCTC"},
+	"BbsI":  {Name: "BbsI", Recognition: "GAAGAC"},
+	"EcoRI": {Name: "EcoRI", Recognition: "GAATTC"},
+	"NotI":  {Name: "NotI", Recognition: "GCGGCCGC"},
+	"XhoI":  {Name: "XhoI", Recognition: "CTCGAG"},
+	"SapI":  {Name: "SapI", Recognition: "GCTCTTC"},
+}
+
+// lintIssue is one finding from lintSequence, 1-based like the positions
+// validateSequence already reports.
+type lintIssue struct {
+	Severity string `json:"severity"` // "error" blocks ordering, "warning" doesn't
+	Kind     string `json:"kind"`
+	Position int    `json:"position"`
+	Length   int    `json:"length,omitempty"`
+	Message  string `json:"message"`
+}
+
+// lintReport is the structured result of a lintSequence pass, written to
+// --lint-report when requested.
+type lintReport struct {
+	Length int         `json:"length"`
+	Passed bool        `json:"passed"`
+	Issues []lintIssue `json:"issues"`
+}
+
+// lintOptions configures lintSequence's thresholds.
+type lintOptions struct {
+	GCMin, GCMax     float64
+	GCWindow         int
+	MaxHomopolymerAT int
+	MaxHomopolymerGC int
+	RepeatLength     int
+	AvoidEnzymes     []lintEnzyme
+}
+
+func defaultLintOptions() lintOptions {
+	return lintOptions{
+		GCMin:            0.25,
+		GCMax:            0.65,
+		GCWindow:         50,
+		MaxHomopolymerAT: 10,
+		MaxHomopolymerGC: 6,
+		RepeatLength:     8,
+	}
+}
+
+// lintSequence is a pre-flight synthesizability check run before a
+// sequence is submitted to a vendor: GC content, homopolymers, repeats,
+// and restriction sites, the same categories Twist's own scoring flags.
+// Running it locally gives the same diagnostics instantly instead of
+// costing the ~10 minute scoring round trip on a sequence that was going
+// to be rejected anyway.
+func lintSequence(seq string, opts lintOptions) lintReport {
+	var issues []lintIssue
+
+	if gc := gcFraction(seq); gc < opts.GCMin || gc > opts.GCMax {
+		issues = append(issues, lintIssue{
+			Severity: "error",
+			Kind:     "gc-content",
+			Position: 1,
+			Length:   len(seq),
+			Message:  fmt.Sprintf("overall GC content %.1f%% is outside %.0f-%.0f%%", gc*100, opts.GCMin*100, opts.GCMax*100),
+		})
+	}
+	if opts.GCWindow > 0 && len(seq) >= opts.GCWindow {
+		for i := 0; i+opts.GCWindow <= len(seq); i++ {
+			gc := gcFraction(seq[i : i+opts.GCWindow])
+			if gc < opts.GCMin || gc > opts.GCMax {
+				issues = append(issues, lintIssue{
+					Severity: "error",
+					Kind:     "gc-window",
+					Position: i + 1,
+					Length:   opts.GCWindow,
+					Message:  fmt.Sprintf("%dnt window GC content %.1f%% is outside %.0f-%.0f%%", opts.GCWindow, gc*100, opts.GCMin*100, opts.GCMax*100),
+				})
+				// Skip past this window so a long GC-skewed stretch
+				// produces one issue per window length instead of one
+				// per base.
+				i += opts.GCWindow - 1
+			}
+		}
+	}
+
+	issues = append(issues, homopolymerIssues(seq, opts.MaxHomopolymerAT, opts.MaxHomopolymerGC)...)
+	issues = append(issues, repeatIssues(seq, opts.RepeatLength)...)
+	issues = append(issues, enzymeIssues(seq, opts.AvoidEnzymes)...)
+
+	passed := true
+	for _, iss := range issues {
+		if iss.Severity == "error" {
+			passed = false
+			break
+		}
+	}
+	return lintReport{Length: len(seq), Passed: passed, Issues: issues}
+}
+
+func gcFraction(seq string) float64 {
+	if len(seq) == 0 {
+		return 0
+	}
+	gc := 0
+	for i := 0; i < len(seq); i++ {
+		if seq[i] == 'G' || seq[i] == 'C' {
+			gc++
+		}
+	}
+	return float64(gc) / float64(len(seq))
+}
+
+// homopolymerIssues flags consecutive identical-base runs longer than
+// maxAT (for A/T) or maxGC (for G/C).
+func homopolymerIssues(seq string, maxAT, maxGC int) []lintIssue {
+	var issues []lintIssue
+	n := len(seq)
+	for i := 0; i < n; {
+		j := i + 1
+		for j < n && seq[j] == seq[i] {
+			j++
+		}
+		runLen := j - i
+		limit := maxGC
+		if seq[i] == 'A' || seq[i] == 'T' {
+			limit = maxAT
+		}
+		if limit > 0 && runLen > limit {
+			issues = append(issues, lintIssue{
+				Severity: "error",
+				Kind:     "homopolymer",
+				Position: i + 1,
+				Length:   runLen,
+				Message:  fmt.Sprintf("%d-base run of %q exceeds the %d-base limit", runLen, seq[i], limit),
+			})
+		}
+		i = j
+	}
+	return issues
+}
+
+// repeatIssues flags every recurrence of a repeatLen-mer, either as a
+// direct repeat (identical sequence reappearing, reported against its first
+// occurrence) or an inverted repeat (its reverse complement reappearing,
+// reported against its latest occurrence). Each overlapping repeatLen-mer
+// of a longer repeat is reported on its own; none is extended to the
+// longest repeat it belongs to.
+func repeatIssues(seq string, repeatLen int) []lintIssue {
+	if repeatLen <= 0 || len(seq) < repeatLen*2 {
+		return nil
+	}
+	var issues []lintIssue
+	direct := make(map[string]int, len(seq))
+	invertedSeen := make(map[string]int, len(seq))
+	for i := 0; i+repeatLen <= len(seq); i++ {
+		kmer := seq[i : i+repeatLen]
+		reportedThis := false
+		if first, ok := direct[kmer]; ok {
+			issues = append(issues, lintIssue{
+				Severity: "warning",
+				Kind:     "direct-repeat",
+				Position: first + 1,
+				Length:   repeatLen,
+				Message:  fmt.Sprintf("%dnt sequence repeats at position %d", repeatLen, i+1),
+			})
+			reportedThis = true
+		} else {
+			direct[kmer] = i
+		}
+		if first, ok := invertedSeen[lintReverseComplement(kmer)]; ok && !reportedThis {
+			issues = append(issues, lintIssue{
+				Severity: "warning",
+				Kind:     "inverted-repeat",
+				Position: first + 1,
+				Length:   repeatLen,
+				Message:  fmt.Sprintf("%dnt sequence has an inverted repeat (reverse complement) at position %d", repeatLen, i+1),
+			})
+		}
+		invertedSeen[kmer] = i
+	}
+	return issues
+}
+
+func lintReverseComplement(seq string) string {
+	complement := map[byte]byte{'A': 'T', 'T': 'A', 'C': 'G', 'G': 'C', 'N': 'N'}
+	out := make([]byte, len(seq))
+	for i := 0; i < len(seq); i++ {
+		c, ok := complement[seq[len(seq)-1-i]]
+		if !ok {
+			c = 'N'
+		}
+		out[i] = c
+	}
+	return string(out)
+}
+
+// enzymeIssues flags every occurrence, on either strand, of a
+// user-requested restriction enzyme's recognition sequence.
+func enzymeIssues(seq string, enzymes []lintEnzyme) []lintIssue {
+	var issues []lintIssue
+	for _, enz := range enzymes {
+		strands := []struct {
+			label string
+			site  string
+		}{
+			{"+", enz.Recognition},
+			{"-", lintReverseComplement(enz.Recognition)},
+		}
+		for _, strand := range strands {
+			start := 0
+			for {
+				idx := strings.Index(seq[start:], strand.site)
+				if idx < 0 {
+					break
+				}
+				pos := start + idx
+				issues = append(issues, lintIssue{
+					Severity: "error",
+					Kind:     "restriction-site",
+					Position: pos + 1,
+					Length:   len(strand.site),
+					Message:  fmt.Sprintf("%s site found on the %s strand", enz.Name, strand.label),
+				})
+				start = pos + 1
+			}
+		}
+	}
+	return issues
+}
+
+// resolveEnzymes looks up each comma-separated enzyme name in lintEnzymes.
+func resolveEnzymes(names string) ([]lintEnzyme, error) {
+	if names == "" {
+		return nil, nil
+	}
+	var enzymes []lintEnzyme
+	for _, name := range strings.Split(names, ",") {
+		name = strings.TrimSpace(name)
+		if name == "" {
+			continue
+		}
+		enz, ok := lintEnzymes[name]
+		if !ok {
+			return nil, fmt.Errorf("unknown enzyme %q for --avoid (known: BsaI, BsmBI, BbsI, EcoRI, NotI, XhoI, SapI)", name)
+		}
+		enzymes = append(enzymes, enz)
+	}
+	return enzymes, nil
+}
+
+func writeLintReport(path string, report lintReport) error {
+	data, err := json.MarshalIndent(report, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, data, 0o644)
+}
+
+func writeLintReportBatch(path string, reports map[string]lintReport) error {
+	data, err := json.MarshalIndent(reports, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, data, 0o644)
+}
+
+func printLintIssues(name string, report lintReport) {
+	if len(report.Issues) == 0 {
+		fmt.Fprintf(os.Stderr, "Lint %s: no issues found (%d bases)\n", name, report.Length)
+		return
+	}
+	for _, iss := range report.Issues {
+		fmt.Fprintf(os.Stderr, "Lint %s %s: [%s] position %d: %s\n", name, iss.Severity, iss.Kind, iss.Position, iss.Message)
+	}
+}
+
+// lintAndReport runs the synthesizability lint for name/seq using cfg's
+// flags, prints and optionally writes the report, and, unless --lint-only
+// is set, fatalfs if the sequence fails a blocking check. It returns true
+// if the caller should stop without proceeding to order, because
+// --lint-only was set; a failed check then does not stop the process.
+func lintAndReport(cfg *orderFlags, name, seq string) bool {
+	opts, err := cfg.lintOptions()
+	if err != nil {
+		fatalf("%v", err)
+	}
+	report := lintSequence(seq, opts)
+	printLintIssues(name, report)
+	if cfg.lintReportPath != "" {
+		if err := writeLintReport(cfg.lintReportPath, report); err != nil {
+			fatalf("failed to write lint report: %v", err)
+		}
+	}
+	if cfg.lintOnly {
+		return true
+	}
+	if !report.Passed {
+		fatalf("%s failed the synthesizability lint%s", name, lintReportSuffix(cfg.lintReportPath))
+	}
+	return false
+}
+
+func lintReportSuffix(path string) string {
+	if path == "" {
+		return ""
+	}
+	return fmt.Sprintf("; details written to %s", path)
+}
@@ -1,33 +1,34 @@
 package main
 
 import (
-	"bytes"
-	"crypto/rand"
+	"context"
+	"crypto/sha256"
 	"encoding/hex"
 	"encoding/json"
 	"errors"
 	"flag"
 	"fmt"
-	"io"
-	"net/http"
-	"net/url"
 	"os"
+	"os/signal"
 	"path/filepath"
+	"sort"
+	"strconv"
 	"strings"
+	"sync"
 	"time"
+
+	"benchaid/internal/vendor"
 )
 
+// requestTimeout bounds a single create-style vendor call (as opposed to
+// the long polls in WaitForScoring/WaitForQuote, which get their own
+// --scoring-timeout/--quote-timeout durations).
+const requestTimeout = 30 * time.Second
+
 const (
 	defaultBaseURL = "https://twist-api.twistdna.com"
 )
 
-type client struct {
-	baseURL string
-	email   string
-	token   string
-	http    *http.Client
-}
-
 func main() {
 	loadEnvFromFile(".env")
 
@@ -36,16 +37,23 @@ func main() {
 		os.Exit(1)
 	}
 
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+
 	cmd := os.Args[1]
 	switch cmd {
 	case "gene":
-		runGene(os.Args[2:])
+		runGene(ctx, os.Args[2:])
 	case "gene-block", "gene-blocks", "geneBlock":
-		runFragment(os.Args[2:])
+		runFragment(ctx, os.Args[2:])
 	case "fragment":
-		runFragment(os.Args[2:])
+		runFragment(ctx, os.Args[2:])
 	case "vectors":
-		runVectors(os.Args[2:])
+		runVectors(ctx, os.Args[2:])
+	case "batch":
+		runBatch(ctx, os.Args[2:])
+	case "journal":
+		runJournal(os.Args[2:])
 	default:
 		usage()
 		os.Exit(1)
@@ -53,16 +61,22 @@ func main() {
 }
 
 func usage() {
-	fmt.Fprintf(os.Stderr, "Twist DNA ordering CLI\n\n")
+	fmt.Fprintf(os.Stderr, "DNA synthesis ordering CLI (Twist, IDT)\n\n")
 	fmt.Fprintf(os.Stderr, "Usage:\n")
 	fmt.Fprintf(os.Stderr, "  twist_order gene --sequence <DNA> --name <NAME> --vector-id <ID> --insertion-point-id <ID> --recipient-address-id <ID> --first-name <FN> --last-name <LN> --phone <PHONE> [--payment-method-id <ID> | --no-po]\n")
 	fmt.Fprintf(os.Stderr, "  twist_order fragment --sequence <DNA> --name <NAME> --recipient-address-id <ID> --first-name <FN> --last-name <LN> --phone <PHONE> [--payment-method-id <ID> | --no-po]\n")
 	fmt.Fprintf(os.Stderr, "  twist_order gene-block --sequence <DNA> --name <NAME> --recipient-address-id <ID> --first-name <FN> --last-name <LN> --phone <PHONE> [--payment-method-id <ID> | --no-po]\n")
-	fmt.Fprintf(os.Stderr, "  twist_order vectors list\n\n")
-	fmt.Fprintf(os.Stderr, "Env (from .env): TWIST_API_TOKEN, TWIST_USER_EMAIL, TWIST_API_BASE_URL\n")
+	fmt.Fprintf(os.Stderr, "  twist_order vectors list\n")
+	fmt.Fprintf(os.Stderr, "  twist_order batch --sequence-file <FASTA or GenBank> --plate-size 96|384 --fill-method Vertical|Horizontal --name <NAME> --recipient-address-id <ID> --first-name <FN> --last-name <LN> --phone <PHONE> [--payment-method-id <ID> | --no-po]\n")
+	fmt.Fprintf(os.Stderr, "  twist_order journal ls|resume <key>\n\n")
+	fmt.Fprintf(os.Stderr, "gene, gene-block, fragment and batch accept --vendor twist|idt (default twist), and\n")
+	fmt.Fprintf(os.Stderr, "run a pre-flight synthesizability lint (GC content, homopolymers, repeats,\n")
+	fmt.Fprintf(os.Stderr, "--avoid <enzyme,...> restriction sites) before submitting; see --lint-report\n")
+	fmt.Fprintf(os.Stderr, "and --lint-only.\n")
+	fmt.Fprintf(os.Stderr, "Env (from .env): TWIST_API_TOKEN, TWIST_USER_EMAIL, TWIST_API_BASE_URL, IDT_API_TOKEN, IDT_API_BASE_URL\n")
 }
 
-func runGene(args []string) {
+func runGene(ctx context.Context, args []string) {
 	cfg := newOrderFlags("gene")
 	cfg.fs.Parse(args)
 
@@ -80,23 +94,35 @@ func runGene(args []string) {
 	if err != nil {
 		fatalf("failed to read sequence: %v", err)
 	}
+	if done := lintAndReport(cfg, cfg.name, seq); done {
+		return
+	}
 
-	c := cfg.client()
-	constructID, err := c.createConstruct(constructRequest{
-		Sequences:         []string{seq},
-		Name:              cfg.name,
-		Type:              "CLONED_GENE",
-		VectorMESUID:      cfg.vectorID,
-		InsertionPointMES: cfg.insertionPointID,
-		AdaptersOn:        nil,
-	})
+	j, err := loadJournal()
 	if err != nil {
-		fatalf("construct creation failed: %v", err)
+		fatalf("failed to load order journal: %v", err)
 	}
-	fmt.Fprintf(os.Stderr, "Construct created: %s\n", constructID)
+	ship := vendor.Shipment{
+		FirstName:          cfg.firstName,
+		LastName:           cfg.lastName,
+		Phone:              cfg.phone,
+		RecipientAddressID: cfg.recipientAddressID,
+	}
+	key := idempotencyKey(seq, ship, cfg.settingsFingerprint())
 
-	if err := c.waitForScoring(constructID, cfg.scoringWait, cfg.scoringInterval); err != nil {
-		fatalf("scoring failed: %v", err)
+	c, err := cfg.vendorClient()
+	if err != nil {
+		fatalf("%v", err)
+	}
+	constructID, err := ensureConstruct(ctx, j, key, c, vendor.ConstructSpec{
+		Sequence:         seq,
+		Name:             cfg.name,
+		Cloned:           true,
+		VectorID:         cfg.vectorID,
+		InsertionPointID: cfg.insertionPointID,
+	}, cfg.scoringWait, cfg.scoringInterval)
+	if err != nil {
+		fatalf("construct creation failed: %v", err)
 	}
 
 	orderSubProduct := "CLONAL_GENES_SHORT"
@@ -109,7 +135,7 @@ func runGene(args []string) {
 			"name":         "Delivery Format",
 			"product_code": cfg.deliveryFormat,
 			"configuration": map[string]string{
-				"fill_method": "Vertical",
+				"fill_method": cfg.fillMethod,
 			},
 		},
 	}
@@ -133,41 +159,39 @@ func runGene(args []string) {
 		})
 	}
 
-	quoteID, err := c.createQuote(quoteRequest{
-		ExternalID: cfg.externalID,
-		Project:    cfg.projectName,
-		Shipment: shipment{
-			FirstName:          cfg.firstName,
-			LastName:           cfg.lastName,
-			Phone:              cfg.phone,
-			RecipientAddressID: cfg.recipientAddressID,
-		},
-		ConstructID:     constructID,
+	quoteID, err := ensureQuote(ctx, j, key, c, vendor.OrderSpec{
+		ExternalID:      cfg.externalID,
+		Project:         cfg.projectName,
+		Shipment:        ship,
+		ConstructIDs:    []string{constructID},
 		OrderSubProduct: orderSubProduct,
 		OrderSettings:   orderSettings,
-	})
+		PaymentMethodID: cfg.paymentMethodID,
+		POReference:     cfg.poReference,
+		NoPO:            cfg.noPO,
+	}, cfg.quoteWait, cfg.quoteInterval)
 	if err != nil {
 		fatalf("quote creation failed: %v", err)
 	}
-	fmt.Fprintf(os.Stderr, "Quote created: %s\n", quoteID)
-
-	if err := c.waitForQuote(quoteID, cfg.quoteWait, cfg.quoteInterval); err != nil {
-		fatalf("quote failed: %v", err)
-	}
 
 	if cfg.quoteOnly {
 		fmt.Fprintf(os.Stderr, "Quote ready. Skipping order creation (--quote-only).\n")
 		return
 	}
 
-	orderID, err := c.createOrder(quoteID, cfg.paymentMethodID, cfg.poReference, cfg.noPO)
+	orderID, err := ensureOrder(ctx, j, key, c, quoteID, vendor.OrderSpec{
+		Shipment:        ship,
+		PaymentMethodID: cfg.paymentMethodID,
+		POReference:     cfg.poReference,
+		NoPO:            cfg.noPO,
+	})
 	if err != nil {
 		fatalf("order creation failed: %v", err)
 	}
 	fmt.Printf("Order created: %s\n", orderID)
 }
 
-func runFragment(args []string) {
+func runFragment(ctx context.Context, args []string) {
 	cfg := newOrderFlags("fragment")
 	cfg.fs.Parse(args)
 
@@ -182,23 +206,35 @@ func runFragment(args []string) {
 	if err != nil {
 		fatalf("failed to read sequence: %v", err)
 	}
+	if done := lintAndReport(cfg, cfg.name, seq); done {
+		return
+	}
+
+	j, err := loadJournal()
+	if err != nil {
+		fatalf("failed to load order journal: %v", err)
+	}
+	ship := vendor.Shipment{
+		FirstName:          cfg.firstName,
+		LastName:           cfg.lastName,
+		Phone:              cfg.phone,
+		RecipientAddressID: cfg.recipientAddressID,
+	}
+	key := idempotencyKey(seq, ship, cfg.settingsFingerprint())
 
 	adaptersOn := cfg.adaptersOn
-	c := cfg.client()
-	constructID, err := c.createConstruct(constructRequest{
-		Sequences:  []string{seq},
+	c, err := cfg.vendorClient()
+	if err != nil {
+		fatalf("%v", err)
+	}
+	constructID, err := ensureConstruct(ctx, j, key, c, vendor.ConstructSpec{
+		Sequence:   seq,
 		Name:       cfg.name,
-		Type:       "NON_CLONED_GENE",
 		AdaptersOn: &adaptersOn,
-	})
+	}, cfg.scoringWait, cfg.scoringInterval)
 	if err != nil {
 		fatalf("construct creation failed: %v", err)
 	}
-	fmt.Fprintf(os.Stderr, "Construct created: %s\n", constructID)
-
-	if err := c.waitForScoring(constructID, cfg.scoringWait, cfg.scoringInterval); err != nil {
-		fatalf("scoring failed: %v", err)
-	}
 
 	orderSubProduct := "NON_CLONAL_ADAPTERS_OFF"
 	if adaptersOn {
@@ -210,7 +246,7 @@ func runFragment(args []string) {
 			"name":         "Delivery Format",
 			"product_code": cfg.deliveryFormat,
 			"configuration": map[string]string{
-				"fill_method": "Vertical",
+				"fill_method": cfg.fillMethod,
 			},
 		},
 	}
@@ -232,26 +268,187 @@ func runFragment(args []string) {
 		})
 	}
 
-	quoteID, err := c.createQuote(quoteRequest{
-		ExternalID: cfg.externalID,
-		Project:    cfg.projectName,
-		Shipment: shipment{
-			FirstName:          cfg.firstName,
-			LastName:           cfg.lastName,
-			Phone:              cfg.phone,
-			RecipientAddressID: cfg.recipientAddressID,
-		},
-		ConstructID:     constructID,
+	quoteID, err := ensureQuote(ctx, j, key, c, vendor.OrderSpec{
+		ExternalID:      cfg.externalID,
+		Project:         cfg.projectName,
+		Shipment:        ship,
+		ConstructIDs:    []string{constructID},
 		OrderSubProduct: orderSubProduct,
 		OrderSettings:   orderSettings,
-	})
+		PaymentMethodID: cfg.paymentMethodID,
+		POReference:     cfg.poReference,
+		NoPO:            cfg.noPO,
+	}, cfg.quoteWait, cfg.quoteInterval)
 	if err != nil {
 		fatalf("quote creation failed: %v", err)
 	}
-	fmt.Fprintf(os.Stderr, "Quote created: %s\n", quoteID)
 
-	if err := c.waitForQuote(quoteID, cfg.quoteWait, cfg.quoteInterval); err != nil {
-		fatalf("quote failed: %v", err)
+	if cfg.quoteOnly {
+		fmt.Fprintf(os.Stderr, "Quote ready. Skipping order creation (--quote-only).\n")
+		return
+	}
+
+	orderID, err := ensureOrder(ctx, j, key, c, quoteID, vendor.OrderSpec{
+		Shipment:        ship,
+		PaymentMethodID: cfg.paymentMethodID,
+		POReference:     cfg.poReference,
+		NoPO:            cfg.noPO,
+	})
+	if err != nil {
+		fatalf("order creation failed: %v", err)
+	}
+	fmt.Printf("Order created: %s\n", orderID)
+}
+
+// runBatch consumes a multi-record FASTA or GenBank file, submits every
+// record as its own construct (scored in parallel), and quotes them all as
+// one multi-construct order laid out across a plate.
+func runBatch(ctx context.Context, args []string) {
+	cfg := newOrderFlags("batch")
+	cfg.fs.Parse(args)
+
+	if cfg.sequenceFile == "" {
+		fatalf("batch ordering requires --sequence-file (multi-record FASTA or GenBank)")
+	}
+	if err := cfg.validateShipment(); err != nil {
+		fatalf("%v", err)
+	}
+
+	data, err := os.ReadFile(filepath.Clean(cfg.sequenceFile))
+	if err != nil {
+		fatalf("failed to read %s: %v", cfg.sequenceFile, err)
+	}
+	records, err := parseRecords(string(data))
+	if err != nil {
+		fatalf("failed to parse records: %v", err)
+	}
+
+	layout, err := newPlateLayout(cfg.plateSize, cfg.fillMethod)
+	if err != nil {
+		fatalf("%v", err)
+	}
+	if len(records) > layout.capacity() {
+		fatalf("%d constructs exceed %d-well plate capacity", len(records), layout.capacity())
+	}
+
+	cleaned := make([]string, len(records))
+	lintOpts, err := cfg.lintOptions()
+	if err != nil {
+		fatalf("%v", err)
+	}
+	reports := make(map[string]lintReport, len(records))
+	anyFailed := false
+	for i, rec := range records {
+		clean := normalizeSequence(rec.Sequence)
+		if err := validateSequence(clean); err != nil {
+			fatalf("%s: %v", rec.Name, err)
+		}
+		cleaned[i] = clean
+		report := lintSequence(clean, lintOpts)
+		printLintIssues(rec.Name, report)
+		reports[rec.Name] = report
+		if !report.Passed {
+			anyFailed = true
+		}
+	}
+	if cfg.lintReportPath != "" {
+		if err := writeLintReportBatch(cfg.lintReportPath, reports); err != nil {
+			fatalf("failed to write lint report: %v", err)
+		}
+	}
+	if cfg.lintOnly {
+		return
+	}
+	if anyFailed {
+		fatalf("one or more sequences failed the synthesizability lint%s", lintReportSuffix(cfg.lintReportPath))
+	}
+
+	j, err := loadJournal()
+	if err != nil {
+		fatalf("failed to load order journal: %v", err)
+	}
+	ship := vendor.Shipment{
+		FirstName:          cfg.firstName,
+		LastName:           cfg.lastName,
+		Phone:              cfg.phone,
+		RecipientAddressID: cfg.recipientAddressID,
+	}
+	settings := cfg.settingsFingerprint()
+
+	c, err := cfg.vendorClient()
+	if err != nil {
+		fatalf("%v", err)
+	}
+	type constructResult struct {
+		id  string
+		err error
+	}
+	results := make([]constructResult, len(records))
+	var wg sync.WaitGroup
+	for i, rec := range records {
+		wg.Add(1)
+		go func(i int, rec sequenceRecord) {
+			defer wg.Done()
+			clean := cleaned[i]
+			adaptersOn := cfg.adaptersOn
+			key := idempotencyKey(clean, ship, settings+"|"+rec.Name)
+			id, err := ensureConstruct(ctx, j, key, c, vendor.ConstructSpec{
+				Sequence:   clean,
+				Name:       rec.Name,
+				AdaptersOn: &adaptersOn,
+			}, cfg.scoringWait, cfg.scoringInterval)
+			if err != nil {
+				results[i] = constructResult{err: fmt.Errorf("%s: %w", rec.Name, err)}
+				return
+			}
+			results[i] = constructResult{id: id}
+		}(i, rec)
+	}
+	wg.Wait()
+
+	constructIDs := make([]string, len(records))
+	for i, res := range results {
+		if res.err != nil {
+			fatalf("construct creation failed: %v", res.err)
+		}
+		fmt.Fprintf(os.Stderr, "Construct created: %s (%s, well %s)\n", res.id, records[i].Name, layout.well(i))
+		constructIDs[i] = res.id
+	}
+
+	orderSubProduct := "NON_CLONAL_ADAPTERS_OFF"
+	if cfg.adaptersOn {
+		orderSubProduct = "NON_CLONAL_ADAPTERS_ON"
+	}
+	orderSettings := []map[string]interface{}{
+		{
+			"name":         "Delivery Format",
+			"product_code": cfg.deliveryFormat,
+			"configuration": map[string]string{
+				"fill_method": cfg.fillMethod,
+			},
+		},
+	}
+	if cfg.bufferCode != "" {
+		orderSettings = append(orderSettings, map[string]interface{}{
+			"name":         "Buffer",
+			"product_code": cfg.bufferCode,
+		})
+	}
+
+	batchKey := idempotencyKey(strings.Join(constructIDs, ","), ship, settings)
+	quoteID, err := ensureQuote(ctx, j, batchKey, c, vendor.OrderSpec{
+		ExternalID:      cfg.externalID,
+		Project:         cfg.projectName,
+		Shipment:        ship,
+		ConstructIDs:    constructIDs,
+		OrderSubProduct: orderSubProduct,
+		OrderSettings:   orderSettings,
+		PaymentMethodID: cfg.paymentMethodID,
+		POReference:     cfg.poReference,
+		NoPO:            cfg.noPO,
+	}, cfg.quoteWait, cfg.quoteInterval)
+	if err != nil {
+		fatalf("quote creation failed: %v", err)
 	}
 
 	if cfg.quoteOnly {
@@ -259,14 +456,172 @@ func runFragment(args []string) {
 		return
 	}
 
-	orderID, err := c.createOrder(quoteID, cfg.paymentMethodID, cfg.poReference, cfg.noPO)
+	orderID, err := ensureOrder(ctx, j, batchKey, c, quoteID, vendor.OrderSpec{
+		Shipment:        ship,
+		PaymentMethodID: cfg.paymentMethodID,
+		POReference:     cfg.poReference,
+		NoPO:            cfg.noPO,
+	})
 	if err != nil {
 		fatalf("order creation failed: %v", err)
 	}
 	fmt.Printf("Order created: %s\n", orderID)
 }
 
-func runVectors(args []string) {
+// sequenceRecord is one named sequence parsed out of a multi-record input
+// file for batch ordering.
+type sequenceRecord struct {
+	Name     string
+	Sequence string
+}
+
+// parseRecords splits a multi-record FASTA or GenBank file into named
+// sequence records. A file starting with a GenBank LOCUS line is parsed as
+// one or more "//"-delimited GenBank records; anything else is parsed as
+// FASTA, where each "> header" line starts a new record.
+func parseRecords(data string) ([]sequenceRecord, error) {
+	if strings.HasPrefix(strings.TrimSpace(data), "LOCUS") {
+		return parseGenBankRecords(data)
+	}
+	return parseFASTARecords(data)
+}
+
+func parseFASTARecords(data string) ([]sequenceRecord, error) {
+	var records []sequenceRecord
+	var current *sequenceRecord
+	var seq strings.Builder
+	flush := func() {
+		if current != nil {
+			current.Sequence = seq.String()
+			records = append(records, *current)
+		}
+		seq.Reset()
+	}
+	for _, line := range strings.Split(data, "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" {
+			continue
+		}
+		if strings.HasPrefix(line, ">") {
+			flush()
+			name := strings.TrimSpace(strings.TrimPrefix(line, ">"))
+			if fields := strings.Fields(name); len(fields) > 0 {
+				name = fields[0]
+			}
+			current = &sequenceRecord{Name: name}
+			continue
+		}
+		if current == nil {
+			return nil, errors.New("sequence data before the first '>' header")
+		}
+		seq.WriteString(line)
+	}
+	flush()
+	if len(records) == 0 {
+		return nil, errors.New("no FASTA records found")
+	}
+	return records, nil
+}
+
+func parseGenBankRecords(data string) ([]sequenceRecord, error) {
+	var records []sequenceRecord
+	for _, block := range strings.Split(data, "//\n") {
+		block = strings.TrimSpace(block)
+		if block == "" {
+			continue
+		}
+		rec, err := parseGenBankBlock(block)
+		if err != nil {
+			return nil, err
+		}
+		records = append(records, rec)
+	}
+	if len(records) == 0 {
+		return nil, errors.New("no GenBank records found")
+	}
+	return records, nil
+}
+
+func parseGenBankBlock(block string) (sequenceRecord, error) {
+	lines := strings.Split(block, "\n")
+	if len(lines) == 0 || !strings.HasPrefix(lines[0], "LOCUS") {
+		return sequenceRecord{}, errors.New("genbank record missing LOCUS line")
+	}
+	fields := strings.Fields(lines[0])
+	if len(fields) < 2 {
+		return sequenceRecord{}, errors.New("genbank LOCUS line missing name")
+	}
+	name := fields[1]
+
+	inOrigin := false
+	var seq strings.Builder
+	for _, line := range lines[1:] {
+		trimmed := strings.TrimSpace(line)
+		if strings.HasPrefix(trimmed, "ORIGIN") {
+			inOrigin = true
+			continue
+		}
+		if !inOrigin {
+			continue
+		}
+		for _, field := range strings.Fields(trimmed) {
+			if _, err := strconv.Atoi(field); err == nil {
+				continue
+			}
+			seq.WriteString(field)
+		}
+	}
+	if seq.Len() == 0 {
+		return sequenceRecord{}, fmt.Errorf("genbank record %q has no ORIGIN sequence", name)
+	}
+	return sequenceRecord{Name: name, Sequence: seq.String()}, nil
+}
+
+// plateLayout maps a zero-based construct index to a well label for a
+// given plate size and fill order.
+type plateLayout struct {
+	fillMethod string
+	rows, cols int
+}
+
+func newPlateLayout(size int, fillMethod string) (plateLayout, error) {
+	var rows, cols int
+	switch size {
+	case 96:
+		rows, cols = 8, 12
+	case 384:
+		rows, cols = 16, 24
+	default:
+		return plateLayout{}, fmt.Errorf("unsupported plate size %d (must be 96 or 384)", size)
+	}
+	switch fillMethod {
+	case "Vertical", "Horizontal":
+	default:
+		return plateLayout{}, fmt.Errorf("unsupported fill method %q (must be Vertical or Horizontal)", fillMethod)
+	}
+	return plateLayout{fillMethod: fillMethod, rows: rows, cols: cols}, nil
+}
+
+func (p plateLayout) capacity() int {
+	return p.rows * p.cols
+}
+
+// well returns the well label (e.g. "A1") for the construct at the given
+// zero-based index, in fill-method order: Vertical fills down a column
+// before moving to the next one, Horizontal fills across a row first.
+func (p plateLayout) well(index int) string {
+	var row, col int
+	if p.fillMethod == "Horizontal" {
+		row = index / p.cols
+		col = index % p.cols
+	} else {
+		row = index % p.rows
+		col = index / p.rows
+	}
+	return fmt.Sprintf("%c%d", 'A'+row, col+1)
+}
+
+func runVectors(ctx context.Context, args []string) {
 	if len(args) < 1 {
 		fmt.Fprintf(os.Stderr, "Usage: twist_order vectors list\n")
 		os.Exit(1)
@@ -275,8 +630,11 @@ func runVectors(args []string) {
 	case "list":
 		cfg := newOrderFlags("vectors")
 		cfg.fs.Parse(args[1:])
-		c := cfg.client()
-		data, err := c.getJSON(fmt.Sprintf("/v1/users/%s/vectors/", pathEscape(cfg.email)))
+		if cfg.vendorName != "" && cfg.vendorName != "twist" {
+			fatalf("vectors has no equivalent for --vendor %s; it is Twist-specific", cfg.vendorName)
+		}
+		c := vendor.NewTwistClient(cfg.baseURL, cfg.email, cfg.token)
+		data, err := c.VectorsList(ctx)
 		if err != nil {
 			fatalf("vector list failed: %v", err)
 		}
@@ -303,10 +661,22 @@ type orderFlags struct {
 	bufferCode       string
 	normalization    float64
 	glycerolStock    bool
+	plateSize        int
+	fillMethod       string
+
+	avoid          string
+	lintReportPath string
+	lintOnly       bool
+	gcMin          float64
+	gcMax          float64
+	gcWindow       int
+	repeatLength   int
+
+	vendorName string
+	email      string
+	token      string
+	baseURL    string
 
-	email              string
-	token              string
-	baseURL            string
 	firstName          string
 	lastName           string
 	phone              string
@@ -340,10 +710,21 @@ func newOrderFlags(name string) *orderFlags {
 	fs.StringVar(&cfg.bufferCode, "buffer-code", "", "Optional buffer product code")
 	fs.Float64Var(&cfg.normalization, "normalization", 0, "Normalization value (0.5-2.0 for clonal genes)")
 	fs.BoolVar(&cfg.glycerolStock, "glycerol-stock", false, "Add glycerol stock for clonal genes")
-
-	fs.StringVar(&cfg.email, "email", os.Getenv("TWIST_USER_EMAIL"), "Twist user email (path param)")
-	fs.StringVar(&cfg.token, "token", os.Getenv("TWIST_API_TOKEN"), "Twist API token (X-End-User-Token)")
-	fs.StringVar(&cfg.baseURL, "base-url", envOrDefault("TWIST_API_BASE_URL", defaultBaseURL), "Twist API base URL")
+	fs.IntVar(&cfg.plateSize, "plate-size", 96, "Plate size for batch ordering (96 or 384)")
+	fs.StringVar(&cfg.fillMethod, "fill-method", "Vertical", "Plate fill method (Vertical or Horizontal)")
+
+	fs.StringVar(&cfg.avoid, "avoid", "", "Comma-separated restriction enzymes to flag if present (e.g. BsaI,BsmBI,EcoRI)")
+	fs.StringVar(&cfg.lintReportPath, "lint-report", "", "Write a structured JSON synthesizability lint report to this path")
+	fs.BoolVar(&cfg.lintOnly, "lint-only", false, "Only run the synthesizability lint, skip ordering")
+	fs.Float64Var(&cfg.gcMin, "gc-min", 0.25, "Minimum acceptable GC fraction, global and windowed")
+	fs.Float64Var(&cfg.gcMax, "gc-max", 0.65, "Maximum acceptable GC fraction, global and windowed")
+	fs.IntVar(&cfg.gcWindow, "gc-window", 50, "Sliding window size (nt) for windowed GC content checks")
+	fs.IntVar(&cfg.repeatLength, "repeat-length", 8, "Minimum repeat length (nt) flagged as a direct/inverted repeat")
+
+	fs.StringVar(&cfg.vendorName, "vendor", envOrDefault("TWIST_ORDER_VENDOR", "twist"), "Synthesis vendor to order from (twist or idt)")
+	fs.StringVar(&cfg.email, "email", os.Getenv("TWIST_USER_EMAIL"), "Twist user email (path param, twist only)")
+	fs.StringVar(&cfg.token, "token", "", "Vendor API token (defaults to TWIST_API_TOKEN or IDT_API_TOKEN depending on --vendor)")
+	fs.StringVar(&cfg.baseURL, "base-url", "", "Vendor API base URL (defaults to TWIST_API_BASE_URL or IDT_API_BASE_URL depending on --vendor)")
 
 	fs.StringVar(&cfg.firstName, "first-name", "", "Shipment first name")
 	fs.StringVar(&cfg.lastName, "last-name", "", "Shipment last name")
@@ -364,9 +745,12 @@ func newOrderFlags(name string) *orderFlags {
 }
 
 func (c *orderFlags) validateShipment() error {
-	if c.email == "" || c.token == "" {
+	if c.vendorName == "twist" && (c.email == "" || c.resolvedToken() == "") {
 		return errors.New("TWIST_USER_EMAIL and TWIST_API_TOKEN must be set (or pass --email/--token)")
 	}
+	if c.vendorName == "idt" && c.resolvedToken() == "" {
+		return errors.New("IDT_API_TOKEN must be set (or pass --token)")
+	}
 	if c.firstName == "" || c.lastName == "" || c.phone == "" || c.recipientAddressID == "" {
 		return errors.New("shipment fields required: --first-name, --last-name, --phone, --recipient-address-id")
 	}
@@ -376,267 +760,276 @@ func (c *orderFlags) validateShipment() error {
 	return nil
 }
 
-func (c *orderFlags) client() *client {
-	return &client{
-		baseURL: strings.TrimRight(c.baseURL, "/"),
-		email:   c.email,
-		token:   c.token,
-		http:    &http.Client{Timeout: 60 * time.Second},
+// resolvedToken returns the explicit --token flag if set, otherwise the
+// vendor-appropriate environment variable.
+func (c *orderFlags) resolvedToken() string {
+	if c.token != "" {
+		return c.token
 	}
+	if c.vendorName == "idt" {
+		return os.Getenv("IDT_API_TOKEN")
+	}
+	return os.Getenv("TWIST_API_TOKEN")
 }
 
-type constructRequest struct {
-	Sequences         []string `json:"sequences"`
-	Name              string   `json:"name"`
-	Type              string   `json:"type"`
-	VectorMESUID      string   `json:"vector_mes_uid,omitempty"`
-	InsertionPointMES string   `json:"insertion_point_mes_uid,omitempty"`
-	AdaptersOn        *bool    `json:"adapters_on,omitempty"`
-}
-
-type constructResponse struct {
-	ID string `json:"id"`
+// resolvedBaseURL returns the explicit --base-url flag if set, otherwise
+// the vendor-appropriate environment variable or built-in default.
+func (c *orderFlags) resolvedBaseURL() string {
+	if c.baseURL != "" {
+		return c.baseURL
+	}
+	if c.vendorName == "idt" {
+		return os.Getenv("IDT_API_BASE_URL")
+	}
+	return envOrDefault("TWIST_API_BASE_URL", defaultBaseURL)
 }
 
-func (c *client) createConstruct(req constructRequest) (string, error) {
-	payload, err := json.Marshal(req)
+// lintOptions builds the synthesizability lint thresholds for this run
+// from its GC/repeat flags and resolves --avoid into recognition sequences.
+func (c *orderFlags) lintOptions() (lintOptions, error) {
+	opts := defaultLintOptions()
+	opts.GCMin = c.gcMin
+	opts.GCMax = c.gcMax
+	opts.GCWindow = c.gcWindow
+	opts.RepeatLength = c.repeatLength
+	enzymes, err := resolveEnzymes(c.avoid)
 	if err != nil {
-		return "", err
-	}
-	path := fmt.Sprintf("/v1/users/%s/constructs/", pathEscape(c.email))
-	resp, err := c.request(http.MethodPost, path, payload)
-	if err != nil {
-		return "", err
+		return lintOptions{}, err
 	}
-	var out constructResponse
-	if err := json.Unmarshal(resp, &out); err != nil {
-		return "", err
-	}
-	if out.ID == "" {
-		return "", errors.New("missing construct id in response")
+	opts.AvoidEnzymes = enzymes
+	return opts, nil
+}
+
+// vendorClient builds the vendor.Client this order run should use, per
+// --vendor (or TWIST_ORDER_VENDOR).
+func (c *orderFlags) vendorClient() (vendor.Client, error) {
+	switch c.vendorName {
+	case "", "twist":
+		return vendor.NewTwistClient(c.resolvedBaseURL(), c.email, c.resolvedToken()), nil
+	case "idt":
+		return vendor.NewIDTClient(c.resolvedBaseURL(), c.resolvedToken()), nil
+	default:
+		return nil, fmt.Errorf("unknown --vendor %q (must be twist or idt)", c.vendorName)
 	}
-	return out.ID, nil
 }
 
-type constructStatus struct {
-	ID        string `json:"id"`
-	Scored    bool   `json:"scored"`
-	ScoreData struct {
-		Issues []map[string]interface{} `json:"issues"`
-	} `json:"score_data"`
+// journalEntry records the IDs issued for one idempotency key, so a
+// re-invocation with the same inputs can short-circuit rather than
+// re-submit (and re-charge) a construct/quote/order.
+type journalEntry struct {
+	Name        string    `json:"name,omitempty"`
+	ConstructID string    `json:"construct_id,omitempty"`
+	QuoteID     string    `json:"quote_id,omitempty"`
+	OrderID     string    `json:"order_id,omitempty"`
+	UpdatedAt   time.Time `json:"updated_at"`
 }
 
-func (c *client) waitForScoring(id string, timeout, interval time.Duration) error {
-	deadline := time.Now().Add(timeout)
-	for {
-		status, err := c.fetchConstructStatus(id)
-		if err != nil {
-			return err
-		}
-		if status.Scored {
-			if len(status.ScoreData.Issues) > 0 {
-				return fmt.Errorf("scoring issues returned: %v", status.ScoreData.Issues)
-			}
-			fmt.Fprintf(os.Stderr, "Scoring complete: %s\n", id)
-			return nil
-		}
-		if time.Now().After(deadline) {
-			return errors.New("scoring timeout exceeded")
-		}
-		time.Sleep(interval)
+// journal is the on-disk record of every idempotency key this tool has
+// seen, persisted to ~/.benchaid/twist-journal.json.
+type journal struct {
+	mu      sync.Mutex
+	path    string
+	entries map[string]journalEntry
+}
+
+func journalPath() (string, error) {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "", err
 	}
+	return filepath.Join(home, ".benchaid", "twist-journal.json"), nil
 }
 
-func (c *client) fetchConstructStatus(id string) (constructStatus, error) {
-	path := fmt.Sprintf("/v1/users/%s/constructs/describe/?id__in=%s&scored=true", pathEscape(c.email), queryEscape(id))
-	raw, err := c.getJSON(path)
+func loadJournal() (*journal, error) {
+	path, err := journalPath()
 	if err != nil {
-		return constructStatus{}, err
+		return nil, err
 	}
-	var items []constructStatus
-	if err := json.Unmarshal(raw, &items); err != nil {
-		return constructStatus{}, err
+	j := &journal{path: path, entries: map[string]journalEntry{}}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return j, nil
+		}
+		return nil, err
 	}
-	if len(items) == 0 {
-		return constructStatus{}, errors.New("no construct status returned")
+	if err := json.Unmarshal(data, &j.entries); err != nil {
+		return nil, fmt.Errorf("corrupt journal at %s: %w", path, err)
 	}
-	return items[0], nil
+	return j, nil
 }
 
-type shipment struct {
-	FirstName          string `json:"first_name"`
-	LastName           string `json:"last_name"`
-	Phone              string `json:"phone"`
-	RecipientAddressID string `json:"recipient_address_id"`
+func (j *journal) get(key string) (journalEntry, bool) {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	e, ok := j.entries[key]
+	return e, ok
 }
 
-type quoteRequest struct {
-	ExternalID      string                   `json:"external_id"`
-	Project         string                   `json:"ecommerce_project_name,omitempty"`
-	Shipment        shipment                 `json:"shipment"`
-	ConstructID     string                   `json:"-"`
-	OrderSubProduct string                   `json:"order_sub_product_type"`
-	OrderSettings   []map[string]interface{} `json:"order_settings,omitempty"`
+// update applies fn to the entry for key, stamps it, and persists the
+// whole journal back to disk. Safe for concurrent use (runBatch creates
+// constructs in parallel).
+func (j *journal) update(key string, fn func(*journalEntry)) error {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	e := j.entries[key]
+	fn(&e)
+	e.UpdatedAt = time.Now()
+	j.entries[key] = e
+	if err := os.MkdirAll(filepath.Dir(j.path), 0o755); err != nil {
+		return err
+	}
+	data, err := json.MarshalIndent(j.entries, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(j.path, data, 0o600)
 }
 
-func (c *client) createQuote(req quoteRequest) (string, error) {
-	if req.ExternalID == "" {
-		req.ExternalID = "twist-" + randomID(6)
-	}
-	payload := map[string]interface{}{
-		"external_id": req.ExternalID,
-		"shipment": map[string]string{
-			"first_name":           req.Shipment.FirstName,
-			"last_name":            req.Shipment.LastName,
-			"phone":                req.Shipment.Phone,
-			"recipient_address_id": req.Shipment.RecipientAddressID,
-		},
-		"containers": []map[string]interface{}{
-			{
-				"constructs": []map[string]interface{}{
-					{
-						"id":    req.ConstructID,
-						"index": 1,
-					},
-				},
-			},
-		},
-		"order_sub_product_type": req.OrderSubProduct,
-	}
-	if req.Project != "" {
-		payload["ecommerce_project_name"] = req.Project
-	}
-	if len(req.OrderSettings) > 0 {
-		payload["order_settings"] = req.OrderSettings
-	}
+// idempotencyKey derives a stable key for one order attempt from the
+// construct sequence, shipment, and order settings that would otherwise
+// be silently re-submitted on a retried invocation.
+func idempotencyKey(seq string, ship vendor.Shipment, settingsFingerprint string) string {
+	h := sha256.New()
+	h.Write([]byte(seq))
+	h.Write([]byte{0})
+	fmt.Fprintf(h, "%s|%s|%s|%s", ship.FirstName, ship.LastName, ship.Phone, ship.RecipientAddressID)
+	h.Write([]byte{0})
+	h.Write([]byte(settingsFingerprint))
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+// settingsFingerprint captures the order flags that change what gets
+// submitted, so two invocations with the same sequence but different
+// settings don't collide on the same idempotency key.
+func (cfg *orderFlags) settingsFingerprint() string {
+	return strings.Join([]string{
+		cfg.vendorName,
+		cfg.vectorID, cfg.insertionPointID,
+		strconv.FormatBool(cfg.adaptersOn),
+		cfg.dnaScale, cfg.deliveryFormat, cfg.bufferCode,
+		strconv.FormatFloat(cfg.normalization, 'f', -1, 64),
+		strconv.FormatBool(cfg.glycerolStock),
+		cfg.fillMethod,
+	}, "|")
+}
 
-	body, err := json.Marshal(payload)
+// ensureConstruct returns the journaled construct ID for key if one was
+// already submitted (skipping creation and re-scoring), or creates and
+// scores a new construct and records it.
+func ensureConstruct(ctx context.Context, j *journal, key string, c vendor.Client, spec vendor.ConstructSpec, scoringWait, scoringInterval time.Duration) (string, error) {
+	if e, ok := j.get(key); ok && e.ConstructID != "" {
+		fmt.Fprintf(os.Stderr, "Construct already submitted: %s (journal key %s)\n", e.ConstructID, key[:12])
+		return e.ConstructID, nil
+	}
+	createCtx, cancel := context.WithTimeout(ctx, requestTimeout)
+	id, err := c.CreateConstruct(createCtx, spec, key)
+	cancel()
 	if err != nil {
 		return "", err
 	}
-	path := fmt.Sprintf("/v1/users/%s/quotes/", pathEscape(c.email))
-	resp, err := c.request(http.MethodPost, path, body)
-	if err != nil {
-		return "", err
+	if err := j.update(key, func(e *journalEntry) {
+		e.Name = spec.Name
+		e.ConstructID = id
+	}); err != nil {
+		return "", fmt.Errorf("construct %s created but journal write failed: %w", id, err)
 	}
-	var out map[string]interface{}
-	if err := json.Unmarshal(resp, &out); err != nil {
+	fmt.Fprintf(os.Stderr, "Construct created: %s\n", id)
+	if err := c.WaitForScoring(ctx, id, scoringWait, scoringInterval); err != nil {
 		return "", err
 	}
-	id, _ := out["id"].(string)
-	if id == "" {
-		return "", errors.New("missing quote id in response")
-	}
 	return id, nil
 }
 
-func (c *client) waitForQuote(id string, timeout, interval time.Duration) error {
-	deadline := time.Now().Add(timeout)
-	for {
-		status, err := c.fetchQuoteStatus(id)
-		if err != nil {
-			return err
-		}
-		if status == "SUCCESS" {
-			fmt.Fprintf(os.Stderr, "Quote status: %s\n", status)
-			return nil
-		}
-		if status == "FAILED" {
-			return errors.New("quote failed")
-		}
-		if time.Now().After(deadline) {
-			return errors.New("quote timeout exceeded")
-		}
-		time.Sleep(interval)
+// ensureQuote mirrors ensureConstruct for the quote step.
+func ensureQuote(ctx context.Context, j *journal, key string, c vendor.Client, spec vendor.OrderSpec, quoteWait, quoteInterval time.Duration) (string, error) {
+	if e, ok := j.get(key); ok && e.QuoteID != "" {
+		fmt.Fprintf(os.Stderr, "Quote already created: %s (journal key %s)\n", e.QuoteID, key[:12])
+		return e.QuoteID, nil
 	}
-}
-
-func (c *client) fetchQuoteStatus(id string) (string, error) {
-	path := fmt.Sprintf("/v1/users/%s/quotes/%s/", pathEscape(c.email), pathEscape(id))
-	raw, err := c.getJSON(path)
+	createCtx, cancel := context.WithTimeout(ctx, requestTimeout)
+	id, err := c.CreateQuote(createCtx, spec, key)
+	cancel()
 	if err != nil {
 		return "", err
 	}
-	var out struct {
-		StatusInfo struct {
-			Status string `json:"status"`
-		} `json:"status_info"`
+	if err := j.update(key, func(e *journalEntry) { e.QuoteID = id }); err != nil {
+		return "", fmt.Errorf("quote %s created but journal write failed: %w", id, err)
 	}
-	if err := json.Unmarshal(raw, &out); err != nil {
+	fmt.Fprintf(os.Stderr, "Quote created: %s\n", id)
+	if err := c.WaitForQuote(ctx, id, quoteWait, quoteInterval); err != nil {
 		return "", err
 	}
-	if out.StatusInfo.Status == "" {
-		return "", errors.New("missing quote status")
-	}
-	return out.StatusInfo.Status, nil
+	return id, nil
 }
 
-func (c *client) createOrder(quoteID, paymentMethodID, poReference string, noPO bool) (string, error) {
-	payload := map[string]interface{}{
-		"quote_id": quoteID,
-	}
-	if noPO {
-		payload["payment_flow"] = "NO_PO"
-		payload["payment_method_id"] = nil
-	} else {
-		payload["payment_method_id"] = paymentMethodID
-		if poReference != "" {
-			payload["po_reference"] = poReference
-		}
-	}
-
-	body, err := json.Marshal(payload)
-	if err != nil {
-		return "", err
+// ensureOrder mirrors ensureConstruct for the order step.
+func ensureOrder(ctx context.Context, j *journal, key string, c vendor.Client, quoteID string, spec vendor.OrderSpec) (string, error) {
+	if e, ok := j.get(key); ok && e.OrderID != "" {
+		fmt.Fprintf(os.Stderr, "Order already created: %s (journal key %s)\n", e.OrderID, key[:12])
+		return e.OrderID, nil
 	}
-	path := fmt.Sprintf("/v1/users/%s/orders/", pathEscape(c.email))
-	resp, err := c.request(http.MethodPost, path, body)
+	createCtx, cancel := context.WithTimeout(ctx, requestTimeout)
+	id, err := c.CreateOrder(createCtx, quoteID, spec, key)
+	cancel()
 	if err != nil {
 		return "", err
 	}
-	var out map[string]interface{}
-	if err := json.Unmarshal(resp, &out); err != nil {
-		return "", err
-	}
-	id, _ := out["id"].(string)
-	if id == "" {
-		return "", errors.New("missing order id in response")
+	if err := j.update(key, func(e *journalEntry) { e.OrderID = id }); err != nil {
+		return "", fmt.Errorf("order %s created but journal write failed: %w", id, err)
 	}
 	return id, nil
 }
 
-func (c *client) getJSON(path string) ([]byte, error) {
-	return c.request(http.MethodGet, path, nil)
-}
-
-func (c *client) request(method, path string, body []byte) ([]byte, error) {
-	url := c.baseURL + path
-	var reader io.Reader
-	if body != nil {
-		reader = bytes.NewReader(body)
-	}
-	req, err := http.NewRequest(method, url, reader)
-	if err != nil {
-		return nil, err
-	}
-	req.Header.Set("X-End-User-Token", c.token)
-	req.Header.Set("Accept", "application/json")
-	if body != nil {
-		req.Header.Set("Content-Type", "application/json")
+// runJournal implements "twist_order journal ls|resume <key>" for
+// inspecting and picking back up interrupted runs.
+func runJournal(args []string) {
+	if len(args) < 1 {
+		fmt.Fprintf(os.Stderr, "Usage: twist_order journal ls|resume <key>\n")
+		os.Exit(1)
 	}
-	resp, err := c.http.Do(req)
+	j, err := loadJournal()
 	if err != nil {
-		return nil, err
+		fatalf("failed to load order journal: %v", err)
 	}
-	defer resp.Body.Close()
-	respBody, err := io.ReadAll(resp.Body)
-	if err != nil {
-		return nil, err
+	switch args[0] {
+	case "ls":
+		if len(j.entries) == 0 {
+			fmt.Println("(journal is empty)")
+			return
+		}
+		keys := make([]string, 0, len(j.entries))
+		for k := range j.entries {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+		for _, k := range keys {
+			e := j.entries[k]
+			fmt.Printf("%s  name=%-20s construct=%-14s quote=%-14s order=%-14s updated=%s\n",
+				k, e.Name, orDash(e.ConstructID), orDash(e.QuoteID), orDash(e.OrderID), e.UpdatedAt.Format(time.RFC3339))
+		}
+	case "resume":
+		if len(args) < 2 {
+			fatalf("usage: twist_order journal resume <key>")
+		}
+		e, ok := j.get(args[1])
+		if !ok {
+			fatalf("no journal entry for key %s", args[1])
+		}
+		fmt.Printf("name=%s construct=%s quote=%s order=%s updated=%s\n",
+			e.Name, orDash(e.ConstructID), orDash(e.QuoteID), orDash(e.OrderID), e.UpdatedAt.Format(time.RFC3339))
+		fmt.Fprintf(os.Stderr, "Re-run the original gene/fragment/batch command with identical inputs to resume: matching sequence/shipment/settings reuses these IDs automatically instead of re-submitting.\n")
+	default:
+		fmt.Fprintf(os.Stderr, "Usage: twist_order journal ls|resume <key>\n")
+		os.Exit(1)
 	}
-	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
-		return nil, fmt.Errorf("twist api error (%d): %s", resp.StatusCode, strings.TrimSpace(string(respBody)))
+}
+
+func orDash(s string) string {
+	if s == "" {
+		return "-"
 	}
-	return respBody, nil
+	return s
 }
 
 func loadEnvFromFile(path string) {
@@ -718,20 +1111,6 @@ func envOrDefault(key, def string) string {
 	return val
 }
 
-func randomID(bytesLen int) string {
-	buf := make([]byte, bytesLen)
-	_, _ = rand.Read(buf)
-	return hex.EncodeToString(buf)
-}
-
-func pathEscape(val string) string {
-	return url.PathEscape(val)
-}
-
-func queryEscape(val string) string {
-	return url.QueryEscape(val)
-}
-
 func fatalf(format string, args ...interface{}) {
 	fmt.Fprintf(os.Stderr, format+"\n", args...)
 	os.Exit(1)
@@ -0,0 +1,526 @@
+// Package codonopt recodes a protein into DNA favoring a target organism's
+// preferred synonymous codons, so an insert cloned into a heterologous
+// expression host doesn't stall translation on rare codons.
+package codonopt
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"math/rand"
+	"sort"
+	"strconv"
+	"strings"
+
+	"benchaid/bioseq"
+)
+
+// Table is a codon usage table: relative usage weight per codon, grouped
+// implicitly by the amino acid the standard genetic code assigns it.
+type Table struct {
+	Name  string
+	usage map[string]float64
+}
+
+// Usage returns codon's relative usage weight in the table (0 if absent).
+func (t Table) Usage(codon string) float64 {
+	return t.usage[codon]
+}
+
+// aaCodonGroups maps each amino acid (and stop, '*') to the codons that
+// encode it under the standard genetic code, derived once from
+// bioseq.StandardTable() so codonopt doesn't duplicate the codon table.
+var aaCodonGroups = buildAACodonGroups()
+
+func buildAACodonGroups() map[byte][]string {
+	groups := make(map[byte][]string)
+	for codon, aa := range bioseq.StandardTable().Codons {
+		groups[aa] = append(groups[aa], codon)
+	}
+	for aa := range groups {
+		sort.Strings(groups[aa])
+	}
+	return groups
+}
+
+func uniformTable(name string) Table {
+	usage := make(map[string]float64)
+	for _, codons := range aaCodonGroups {
+		for _, c := range codons {
+			usage[c] = 1.0
+		}
+	}
+	return Table{Name: name, usage: usage}
+}
+
+// biasedTable starts every codon at equal usage and boosts each amino acid's
+// preferred codon to dominance, the way real codon usage tables for
+// strongly biased genomes (E. coli, human) skew toward a handful of
+// synonymous codons per residue. These are representative presets, not a
+// transcription of a specific CUT download — call LoadKazusa with real
+// usage data for production-grade optimization.
+func biasedTable(name string, preferred map[byte]string, dominance float64) Table {
+	t := uniformTable(name)
+	for aa, codon := range preferred {
+		for _, c := range aaCodonGroups[aa] {
+			if c == codon {
+				t.usage[c] = dominance
+			}
+		}
+	}
+	return t
+}
+
+var builtinTables = map[string]Table{}
+
+func registerBuiltin(key string, t Table) {
+	builtinTables[key] = t
+}
+
+func init() {
+	registerBuiltin("ecoli", biasedTable("E. coli K12", map[byte]string{
+		'A': "GCG", 'R': "CGC", 'N': "AAC", 'D': "GAT", 'C': "TGC",
+		'Q': "CAG", 'E': "GAA", 'G': "GGC", 'H': "CAT", 'I': "ATC",
+		'L': "CTG", 'K': "AAA", 'M': "ATG", 'F': "TTT", 'P': "CCG",
+		'S': "AGC", 'T': "ACC", 'W': "TGG", 'Y': "TAT", 'V': "GTG",
+		'*': "TAA",
+	}, 8.0))
+	registerBuiltin("scerevisiae", biasedTable("S. cerevisiae", map[byte]string{
+		'A': "GCT", 'R': "AGA", 'N': "AAC", 'D': "GAT", 'C': "TGT",
+		'Q': "CAA", 'E': "GAA", 'G': "GGT", 'H': "CAT", 'I': "ATT",
+		'L': "TTG", 'K': "AAA", 'M': "ATG", 'F': "TTT", 'P': "CCA",
+		'S': "TCT", 'T': "ACT", 'W': "TGG", 'Y': "TAT", 'V': "GTT",
+		'*': "TAA",
+	}, 6.0))
+	registerBuiltin("ppastoris", biasedTable("P. pastoris", map[byte]string{
+		'A': "GCT", 'R': "AGA", 'N': "AAC", 'D': "GAT", 'C': "TGT",
+		'Q': "CAA", 'E': "GAA", 'G': "GGT", 'H': "CAC", 'I': "ATC",
+		'L': "TTG", 'K': "AAG", 'M': "ATG", 'F': "TTC", 'P': "CCA",
+		'S': "TCT", 'T': "ACC", 'W': "TGG", 'Y': "TAC", 'V': "GTT",
+		'*': "TAA",
+	}, 6.0))
+	registerBuiltin("hsapiens", biasedTable("H. sapiens", map[byte]string{
+		'A': "GCC", 'R': "AGG", 'N': "AAC", 'D': "GAC", 'C': "TGC",
+		'Q': "CAG", 'E': "GAG", 'G': "GGC", 'H': "CAC", 'I': "ATC",
+		'L': "CTG", 'K': "AAG", 'M': "ATG", 'F': "TTC", 'P': "CCC",
+		'S': "AGC", 'T': "ACC", 'W': "TGG", 'Y': "TAC", 'V': "GTG",
+		'*': "TGA",
+	}, 5.0))
+	registerBuiltin("sfrugiperda", biasedTable("S. frugiperda", map[byte]string{
+		'A': "GCC", 'R': "AGA", 'N': "AAC", 'D': "GAC", 'C': "TGC",
+		'Q': "CAA", 'E': "GAG", 'G': "GGC", 'H': "CAC", 'I': "ATC",
+		'L': "CTG", 'K': "AAG", 'M': "ATG", 'F': "TTC", 'P': "CCC",
+		'S': "AGC", 'T': "ACC", 'W': "TGG", 'Y': "TAC", 'V': "GTG",
+		'*': "TAA",
+	}, 5.0))
+	registerBuiltin("cho", biasedTable("CHO", map[byte]string{
+		'A': "GCC", 'R': "AGG", 'N': "AAC", 'D': "GAC", 'C': "TGC",
+		'Q': "CAG", 'E': "GAG", 'G': "GGC", 'H': "CAC", 'I': "ATC",
+		'L': "CTG", 'K': "AAG", 'M': "ATG", 'F': "TTC", 'P': "CCC",
+		'S': "AGC", 'T': "ACC", 'W': "TGG", 'Y': "TAC", 'V': "GTG",
+		'*': "TGA",
+	}, 5.0))
+}
+
+// Builtin looks up a preset table by organism key (case-insensitive, spaces
+// and dots stripped: "E. coli K12" and "ecoli" both resolve).
+func Builtin(organism string) (Table, bool) {
+	t, ok := builtinTables[normalizeOrganismKey(organism)]
+	return t, ok
+}
+
+func normalizeOrganismKey(organism string) string {
+	key := strings.ToLower(organism)
+	key = strings.NewReplacer(" ", "", ".", "", "_", "", "-", "").Replace(key)
+	switch key {
+	case "ecoli", "ecolik12", "escherichiacoli":
+		return "ecoli"
+	case "scerevisiae", "saccharomycescerevisiae", "yeast":
+		return "scerevisiae"
+	case "ppastoris", "pichiapastoris", "komagataellaphaffii":
+		return "ppastoris"
+	case "hsapiens", "homosapiens", "human":
+		return "hsapiens"
+	case "sfrugiperda", "spodopterafrugiperda", "sf9", "sf21":
+		return "sfrugiperda"
+	case "cho", "cricetulusgriseus", "chok1":
+		return "cho"
+	}
+	return key
+}
+
+// LoadKazusa parses a Kazusa Codon Usage Database-style table: whitespace
+// separated tokens where each codon (RNA or DNA alphabet) is optionally
+// followed by a single-letter amino acid code and then its usage frequency.
+// Parenthesized raw counts, if present, are ignored.
+func LoadKazusa(name, data string) (Table, error) {
+	usage := make(map[string]float64)
+	tokens := strings.Fields(data)
+	for i := 0; i < len(tokens); i++ {
+		codon := strings.ToUpper(strings.ReplaceAll(tokens[i], "U", "T"))
+		if !isCodon(codon) {
+			continue
+		}
+		j := i + 1
+		if j < len(tokens) && len(tokens[j]) == 1 && isAminoAcidLetter(tokens[j][0]) {
+			j++
+		}
+		if j >= len(tokens) {
+			continue
+		}
+		freq := strings.TrimLeft(tokens[j], "(")
+		if v, err := strconv.ParseFloat(freq, 64); err == nil {
+			usage[codon] = v
+		}
+	}
+	if len(usage) == 0 {
+		return Table{}, fmt.Errorf("codonopt: no codon usage values found in %q", name)
+	}
+	return Table{Name: name, usage: usage}, nil
+}
+
+func isCodon(s string) bool {
+	if len(s) != 3 {
+		return false
+	}
+	for i := 0; i < 3; i++ {
+		switch s[i] {
+		case 'A', 'C', 'G', 'T':
+		default:
+			return false
+		}
+	}
+	return true
+}
+
+func isAminoAcidLetter(b byte) bool {
+	return b >= 'A' && b <= 'Z'
+}
+
+// Options controls Optimize's codon selection strategy.
+type Options struct {
+	// Strategy is one of "most-frequent", "weighted-random", or
+	// "cai-targeted". Empty defaults to "most-frequent".
+	Strategy string
+	// Seed makes "weighted-random" reproducible; 0 uses an arbitrary but
+	// fixed default seed rather than a time-based one.
+	Seed int64
+	// TargetCAI is the Codon Adaptation Index "cai-targeted" aims for.
+	// Ignored by other strategies. Defaults to 0.8 if zero.
+	TargetCAI float64
+}
+
+// Optimize recodes protein (one letter per residue, '*' for a trailing stop
+// if present) into DNA using table's codon preferences under opts.Strategy.
+// The returned DNA always translates back to protein exactly; only codon
+// choice varies.
+func Optimize(protein string, table Table, opts Options) string {
+	switch opts.Strategy {
+	case "weighted-random":
+		return optimizeWeightedRandom(protein, table, opts)
+	case "cai-targeted":
+		return optimizeCAITargeted(protein, table, opts)
+	default:
+		return optimizeMostFrequent(protein, table)
+	}
+}
+
+func optimizeMostFrequent(protein string, table Table) string {
+	var b strings.Builder
+	for i := 0; i < len(protein); i++ {
+		b.WriteString(bestCodon(protein[i], table))
+	}
+	return b.String()
+}
+
+func bestCodon(aa byte, table Table) string {
+	codons := aaCodonGroups[aa]
+	if len(codons) == 0 {
+		return "NNN"
+	}
+	best := codons[0]
+	bestUsage := table.usage[best]
+	for _, c := range codons[1:] {
+		if table.usage[c] > bestUsage {
+			best, bestUsage = c, table.usage[c]
+		}
+	}
+	return best
+}
+
+func optimizeWeightedRandom(protein string, table Table, opts Options) string {
+	seed := opts.Seed
+	if seed == 0 {
+		seed = 1
+	}
+	rng := rand.New(rand.NewSource(seed))
+	var b strings.Builder
+	for i := 0; i < len(protein); i++ {
+		b.WriteString(sampleCodon(protein[i], table, rng))
+	}
+	return b.String()
+}
+
+func sampleCodon(aa byte, table Table, rng *rand.Rand) string {
+	codons := aaCodonGroups[aa]
+	if len(codons) == 0 {
+		return "NNN"
+	}
+	total := 0.0
+	for _, c := range codons {
+		total += table.usage[c]
+	}
+	if total <= 0 {
+		return codons[0]
+	}
+	target := rng.Float64() * total
+	cum := 0.0
+	for _, c := range codons {
+		cum += table.usage[c]
+		if target <= cum {
+			return c
+		}
+	}
+	return codons[len(codons)-1]
+}
+
+// relativeAdaptiveness computes w_i = f_i / max_f(aa) for every codon, the
+// per-codon term the Codon Adaptation Index (Sharp & Li 1987) is the
+// geometric mean of.
+func relativeAdaptiveness(table Table) map[string]float64 {
+	w := make(map[string]float64)
+	for _, codons := range aaCodonGroups {
+		maxF := 0.0
+		for _, c := range codons {
+			if table.usage[c] > maxF {
+				maxF = table.usage[c]
+			}
+		}
+		if maxF == 0 {
+			continue
+		}
+		for _, c := range codons {
+			w[c] = table.usage[c] / maxF
+		}
+	}
+	return w
+}
+
+// optimizeCAITargeted greedily picks codons so the running geometric-mean
+// CAI tracks opts.TargetCAI: once the sequence so far is already above
+// target, it spends some residues on lower-adaptiveness synonyms (still
+// picking the best among those below the running mean) to avoid drifting
+// further over; below target, it picks the highest-adaptiveness synonym.
+func optimizeCAITargeted(protein string, table Table, opts Options) string {
+	target := opts.TargetCAI
+	if target <= 0 {
+		target = 0.8
+	}
+	w := relativeAdaptiveness(table)
+	var b strings.Builder
+	logSum := 0.0
+	n := 0
+	for i := 0; i < len(protein); i++ {
+		aa := protein[i]
+		codons := aaCodonGroups[aa]
+		if len(codons) == 0 {
+			b.WriteString("NNN")
+			continue
+		}
+		runningCAI := 1.0
+		if n > 0 {
+			runningCAI = math.Exp(logSum / float64(n))
+		}
+		var chosen string
+		if runningCAI < target {
+			chosen = codons[0]
+			for _, c := range codons[1:] {
+				if w[c] > w[chosen] {
+					chosen = c
+				}
+			}
+		} else {
+			chosen = ""
+			for _, c := range codons {
+				if w[c] < runningCAI && (chosen == "" || w[c] > w[chosen]) {
+					chosen = c
+				}
+			}
+			if chosen == "" {
+				chosen = codons[0]
+				for _, c := range codons[1:] {
+					if w[c] < w[chosen] {
+						chosen = c
+					}
+				}
+			}
+		}
+		b.WriteString(chosen)
+		if wc := w[chosen]; wc > 0 {
+			logSum += math.Log(wc)
+			n++
+		}
+	}
+	return b.String()
+}
+
+// CAI computes the Sharp & Li (1987) Codon Adaptation Index of dna against
+// table: the geometric mean, over every codon, of that codon's relative
+// adaptiveness (its usage divided by the most-used synonym's usage for the
+// same amino acid). dna's length must be a multiple of 3.
+func CAI(dna string, table Table) (float64, error) {
+	if len(dna)%3 != 0 {
+		return 0, fmt.Errorf("codonopt: CAI: sequence length %d is not a multiple of 3", len(dna))
+	}
+	w := relativeAdaptiveness(table)
+	logSum := 0.0
+	n := 0
+	for i := 0; i+3 <= len(dna); i += 3 {
+		if wc, ok := w[dna[i:i+3]]; ok && wc > 0 {
+			logSum += math.Log(wc)
+			n++
+		}
+	}
+	if n == 0 {
+		return 0, errors.New("codonopt: CAI: no scorable codons")
+	}
+	return math.Exp(logSum / float64(n)), nil
+}
+
+// Constraints are post-processing rules Refine applies by recoding
+// individual codons to synonymous alternatives, never changing the encoded
+// protein.
+type Constraints struct {
+	// AvoidSites are DNA motifs (e.g. restriction recognition sequences)
+	// that must not appear in the output.
+	AvoidSites []string
+	// MaxHomopolymer caps consecutive identical-base runs (poly-A/T/etc.);
+	// 0 disables the check.
+	MaxHomopolymer int
+	// GCMin/GCMax bound the GC fraction (0-1) in a sliding window of
+	// GCWindow bases (default 50 if GCWindow is 0); both 0 disables the
+	// check.
+	GCMin, GCMax float64
+	GCWindow     int
+}
+
+// Refine scans dna (the output of Optimize, which encodes protein) codon by
+// codon and swaps in a synonymous alternative, highest-usage first, for any
+// codon whose removal clears a Constraints violation touching it. A codon
+// with no violation-clearing synonym is left as-is.
+func Refine(dna, protein string, table Table, c Constraints) string {
+	codons := []byte(dna)
+	window := c.GCWindow
+	if window <= 0 {
+		window = 50
+	}
+	for pos := 0; pos*3 < len(codons); pos++ {
+		start := pos * 3
+		if !violatesConstraints(string(codons), start, c, window) {
+			continue
+		}
+		aa := protein[pos]
+		alternatives := rankedSynonyms(aa, table)
+		for _, alt := range alternatives {
+			candidate := append(append([]byte(nil), codons[:start]...), alt...)
+			candidate = append(candidate, codons[start+3:]...)
+			if !violatesConstraints(string(candidate), start, c, window) {
+				codons = candidate
+				break
+			}
+		}
+	}
+	return string(codons)
+}
+
+func rankedSynonyms(aa byte, table Table) []string {
+	codons := append([]string(nil), aaCodonGroups[aa]...)
+	sort.Slice(codons, func(i, j int) bool {
+		return table.usage[codons[i]] > table.usage[codons[j]]
+	})
+	return codons
+}
+
+func violatesConstraints(dna string, pos int, c Constraints, window int) bool {
+	for _, site := range c.AvoidSites {
+		if site == "" {
+			continue
+		}
+		lo := pos - len(site) + 1
+		if lo < 0 {
+			lo = 0
+		}
+		hi := pos + 3
+		if hi > len(dna) {
+			hi = len(dna)
+		}
+		if strings.Contains(dna[lo:hi], strings.ToUpper(site)) {
+			return true
+		}
+	}
+	if c.MaxHomopolymer > 0 && hasHomopolymerRun(dna, pos, c.MaxHomopolymer) {
+		return true
+	}
+	if c.GCMin > 0 || c.GCMax > 0 {
+		if gcWindowOutOfRange(dna, pos, window, c.GCMin, c.GCMax) {
+			return true
+		}
+	}
+	return false
+}
+
+func hasHomopolymerRun(dna string, pos, maxRun int) bool {
+	lo := pos - maxRun
+	if lo < 0 {
+		lo = 0
+	}
+	hi := pos + 3 + maxRun
+	if hi > len(dna) {
+		hi = len(dna)
+	}
+	run := 1
+	for i := lo + 1; i < hi; i++ {
+		if dna[i] == dna[i-1] {
+			run++
+			if run > maxRun {
+				return true
+			}
+		} else {
+			run = 1
+		}
+	}
+	return false
+}
+
+func gcWindowOutOfRange(dna string, pos, window int, gcMin, gcMax float64) bool {
+	half := window / 2
+	lo := pos - half
+	if lo < 0 {
+		lo = 0
+	}
+	hi := lo + window
+	if hi > len(dna) {
+		hi = len(dna)
+		lo = hi - window
+		if lo < 0 {
+			lo = 0
+		}
+	}
+	if hi <= lo {
+		return false
+	}
+	gc := 0
+	for i := lo; i < hi; i++ {
+		if dna[i] == 'G' || dna[i] == 'C' {
+			gc++
+		}
+	}
+	frac := float64(gc) / float64(hi-lo)
+	if gcMin > 0 && frac < gcMin {
+		return true
+	}
+	if gcMax > 0 && frac > gcMax {
+		return true
+	}
+	return false
+}
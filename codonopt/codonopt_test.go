@@ -0,0 +1,98 @@
+package codonopt
+
+import (
+	"strings"
+	"testing"
+
+	"benchaid/bioseq"
+)
+
+func translates(t *testing.T, dna, wantProtein string) {
+	t.Helper()
+	seq, err := bioseq.New("test", dna, bioseq.DNA)
+	if err != nil {
+		t.Fatalf("bioseq.New(%q): %v", dna, err)
+	}
+	protein, err := seq.Translate(0, bioseq.StandardTable())
+	if err != nil {
+		t.Fatalf("Translate(%q): %v", dna, err)
+	}
+	if got := protein.String(); got != wantProtein {
+		t.Errorf("Translate(%q) = %q, want %q", dna, got, wantProtein)
+	}
+}
+
+func TestOptimizeRoundTripsThroughEveryStrategy(t *testing.T) {
+	protein := "MAD*"
+	table, ok := Builtin("ecoli")
+	if !ok {
+		t.Fatal("Builtin(ecoli) not registered")
+	}
+
+	for _, strategy := range []string{"", "most-frequent", "weighted-random", "cai-targeted"} {
+		dna := Optimize(protein, table, Options{Strategy: strategy})
+		if len(dna) != len(protein)*3 {
+			t.Errorf("strategy %q: len(dna) = %d, want %d", strategy, len(dna), len(protein)*3)
+		}
+		translates(t, dna, protein)
+	}
+}
+
+func TestOptimizeMostFrequentPicksHighestUsageCodon(t *testing.T) {
+	table, ok := Builtin("ecoli")
+	if !ok {
+		t.Fatal("Builtin(ecoli) not registered")
+	}
+	dna := Optimize("A", table, Options{Strategy: "most-frequent"})
+	if dna != "GCG" {
+		t.Errorf("Optimize(A, ecoli, most-frequent) = %q, want GCG (the biased preferred codon)", dna)
+	}
+}
+
+func TestBuiltinNormalizesOrganismKey(t *testing.T) {
+	want, ok := Builtin("ecoli")
+	if !ok {
+		t.Fatal("Builtin(ecoli) not registered")
+	}
+	got, ok := Builtin("E. coli K12")
+	if !ok {
+		t.Fatal("Builtin(\"E. coli K12\") not found")
+	}
+	if got.Name != want.Name {
+		t.Errorf("Builtin(\"E. coli K12\").Name = %q, want %q", got.Name, want.Name)
+	}
+	if _, ok := Builtin("not-a-real-organism"); ok {
+		t.Error("expected Builtin to fail for an unknown organism")
+	}
+}
+
+func TestCAIRejectsNonMultipleOfThree(t *testing.T) {
+	table, _ := Builtin("ecoli")
+	if _, err := CAI("ACGTA", table); err == nil {
+		t.Error("expected an error for a sequence whose length isn't a multiple of 3")
+	}
+}
+
+func TestCAIOfMostFrequentOutputIsHigh(t *testing.T) {
+	table, _ := Builtin("ecoli")
+	dna := Optimize("MADEGHIKLQRSTVWY", table, Options{Strategy: "most-frequent"})
+	cai, err := CAI(dna, table)
+	if err != nil {
+		t.Fatalf("CAI: %v", err)
+	}
+	if cai < 0.9 {
+		t.Errorf("CAI of an all-best-codon sequence = %v, want close to 1", cai)
+	}
+}
+
+func TestRefineRemovesAvoidSiteWithoutChangingProtein(t *testing.T) {
+	table, _ := Builtin("ecoli")
+	protein := "RRRR"
+	dna := Optimize(protein, table, Options{Strategy: "most-frequent"})
+
+	refined := Refine(dna, protein, table, Constraints{AvoidSites: []string{dna[:6]}})
+	if strings.Contains(refined, dna[:6]) {
+		t.Errorf("Refine left the avoid-site %q in the output %q", dna[:6], refined)
+	}
+	translates(t, refined, protein)
+}
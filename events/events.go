@@ -0,0 +1,122 @@
+// Package events defines the change-event types labbookCLI's watch
+// subcommands stream (new entries, appended HTML, registry updates, new
+// attachments) and a small Dispatcher for routing them, so the same typed
+// events can be embedded in other tools instead of re-parsing raw JSON.
+package events
+
+import (
+	"encoding/json"
+	"time"
+)
+
+// Type identifies the shape of an Event's Data.
+type Type string
+
+// The event types labbookCLI's watch subcommands know how to decode.
+const (
+	TypeEntryCreated       Type = "entry.created"
+	TypeEntryAppended      Type = "entry.appended"
+	TypeRegistryUpdated    Type = "registry.updated"
+	TypeAttachmentUploaded Type = "attachment.uploaded"
+)
+
+// Event is one change notification from the SSE or MQTT transport: an
+// envelope (ID, Type, Timestamp) wrapping a Data payload whose shape is
+// determined by Type.
+type Event struct {
+	ID        string          `json:"id"`
+	Type      Type            `json:"type"`
+	Timestamp time.Time       `json:"timestamp"`
+	Data      json.RawMessage `json:"data"`
+}
+
+// EntryCreated is Event.Data for TypeEntryCreated.
+type EntryCreated struct {
+	EntryID int      `json:"entryId"`
+	Title   string   `json:"title"`
+	Project string   `json:"project,omitempty"`
+	Tags    []string `json:"tags,omitempty"`
+}
+
+// EntryAppended is Event.Data for TypeEntryAppended.
+type EntryAppended struct {
+	EntryID    int    `json:"entryId"`
+	AppendHTML string `json:"appendHtml"`
+}
+
+// RegistryUpdated is Event.Data for TypeRegistryUpdated.
+type RegistryUpdated struct {
+	RegistryID int    `json:"registryId"`
+	Name       string `json:"name"`
+	Kind       string `json:"kind"`
+}
+
+// AttachmentUploaded is Event.Data for TypeAttachmentUploaded.
+type AttachmentUploaded struct {
+	AttachmentID int    `json:"attachmentId"`
+	FileName     string `json:"fileName"`
+	RegistryID   int    `json:"registryId,omitempty"`
+	EntryID      int    `json:"entryId,omitempty"`
+}
+
+// AsEntryCreated decodes e.Data as EntryCreated.
+func (e Event) AsEntryCreated() (EntryCreated, error) {
+	var v EntryCreated
+	err := json.Unmarshal(e.Data, &v)
+	return v, err
+}
+
+// AsEntryAppended decodes e.Data as EntryAppended.
+func (e Event) AsEntryAppended() (EntryAppended, error) {
+	var v EntryAppended
+	err := json.Unmarshal(e.Data, &v)
+	return v, err
+}
+
+// AsRegistryUpdated decodes e.Data as RegistryUpdated.
+func (e Event) AsRegistryUpdated() (RegistryUpdated, error) {
+	var v RegistryUpdated
+	err := json.Unmarshal(e.Data, &v)
+	return v, err
+}
+
+// AsAttachmentUploaded decodes e.Data as AttachmentUploaded.
+func (e Event) AsAttachmentUploaded() (AttachmentUploaded, error) {
+	var v AttachmentUploaded
+	err := json.Unmarshal(e.Data, &v)
+	return v, err
+}
+
+// Dispatcher fans a stream of Events out to handlers registered by Type (On)
+// or for every event regardless of Type (OnAny), so an embedding tool can
+// react to specific event kinds without a type switch at every call site.
+type Dispatcher struct {
+	handlers map[Type][]func(Event)
+	all      []func(Event)
+}
+
+// NewDispatcher returns an empty Dispatcher.
+func NewDispatcher() *Dispatcher {
+	return &Dispatcher{handlers: make(map[Type][]func(Event))}
+}
+
+// On registers fn to run for every dispatched Event of type t.
+func (d *Dispatcher) On(t Type, fn func(Event)) {
+	d.handlers[t] = append(d.handlers[t], fn)
+}
+
+// OnAny registers fn to run for every dispatched Event, regardless of type.
+func (d *Dispatcher) OnAny(fn func(Event)) {
+	d.all = append(d.all, fn)
+}
+
+// Dispatch runs every handler registered for e.Type, then every OnAny
+// handler, in registration order.
+func (d *Dispatcher) Dispatch(e Event) {
+	for _, fn := range d.handlers[e.Type] {
+		fn(e)
+	}
+	for _, fn := range d.all {
+		fn(e)
+	}
+}
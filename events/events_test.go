@@ -0,0 +1,31 @@
+package events
+
+import "testing"
+
+func TestEventDecodeHelpers(t *testing.T) {
+	e := Event{Type: TypeEntryCreated, Data: []byte(`{"entryId":7,"title":"Day 1","tags":["cryo"]}`)}
+	created, err := e.AsEntryCreated()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if created.EntryID != 7 || created.Title != "Day 1" || len(created.Tags) != 1 || created.Tags[0] != "cryo" {
+		t.Fatalf("unexpected decode: %+v", created)
+	}
+}
+
+func TestDispatcherRoutesByTypeAndAny(t *testing.T) {
+	d := NewDispatcher()
+	var created, any []Type
+	d.On(TypeEntryCreated, func(e Event) { created = append(created, e.Type) })
+	d.OnAny(func(e Event) { any = append(any, e.Type) })
+
+	d.Dispatch(Event{Type: TypeEntryCreated})
+	d.Dispatch(Event{Type: TypeRegistryUpdated})
+
+	if len(created) != 1 || created[0] != TypeEntryCreated {
+		t.Fatalf("expected one TypeEntryCreated dispatch, got %v", created)
+	}
+	if len(any) != 2 {
+		t.Fatalf("expected OnAny to see both events, got %v", any)
+	}
+}
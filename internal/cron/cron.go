@@ -0,0 +1,172 @@
+// Package cron parses standard 5-field cron expressions (plus the
+// @hourly/@daily/@weekly/@every shortcuts) into Schedules that compute their
+// own next fire time, for labbookCLI's `run` job scheduler.
+package cron
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Schedule computes the next fire time strictly after from.
+type Schedule interface {
+	Next(from time.Time) time.Time
+}
+
+// Parse parses a 5-field cron expression ("minute hour day-of-month month
+// day-of-week") or one of the shortcuts @hourly, @daily, @weekly, or
+// "@every <duration>" (duration in time.ParseDuration syntax, e.g. "30m").
+func Parse(expr string) (Schedule, error) {
+	expr = strings.TrimSpace(expr)
+	if expr == "" {
+		return nil, fmt.Errorf("cron: empty expression")
+	}
+	if strings.HasPrefix(expr, "@") {
+		return parseShortcut(expr)
+	}
+	fields := strings.Fields(expr)
+	if len(fields) != 5 {
+		return nil, fmt.Errorf("cron: expected 5 fields, got %d in %q", len(fields), expr)
+	}
+	minute, err := parseField(fields[0], 0, 59)
+	if err != nil {
+		return nil, fmt.Errorf("cron: minute: %w", err)
+	}
+	hour, err := parseField(fields[1], 0, 23)
+	if err != nil {
+		return nil, fmt.Errorf("cron: hour: %w", err)
+	}
+	dom, err := parseField(fields[2], 1, 31)
+	if err != nil {
+		return nil, fmt.Errorf("cron: day-of-month: %w", err)
+	}
+	month, err := parseField(fields[3], 1, 12)
+	if err != nil {
+		return nil, fmt.Errorf("cron: month: %w", err)
+	}
+	dow, err := parseField(fields[4], 0, 7)
+	if err != nil {
+		return nil, fmt.Errorf("cron: day-of-week: %w", err)
+	}
+	// Cron treats both 0 and 7 as Sunday.
+	if dow[7] {
+		dow[0] = true
+	}
+	return &fieldSchedule{minute: minute, hour: hour, dom: dom, month: month, dow: dow}, nil
+}
+
+func parseShortcut(expr string) (Schedule, error) {
+	switch expr {
+	case "@hourly":
+		return Parse("0 * * * *")
+	case "@daily":
+		return Parse("0 0 * * *")
+	case "@weekly":
+		return Parse("0 0 * * 0")
+	}
+	if rest, ok := strings.CutPrefix(expr, "@every "); ok {
+		d, err := time.ParseDuration(strings.TrimSpace(rest))
+		if err != nil {
+			return nil, fmt.Errorf("cron: @every: %w", err)
+		}
+		if d <= 0 {
+			return nil, fmt.Errorf("cron: @every duration must be positive, got %s", d)
+		}
+		return &everySchedule{interval: d}, nil
+	}
+	return nil, fmt.Errorf("cron: unrecognized shortcut %q", expr)
+}
+
+// fieldSet is a bitset over the field's valid range (index 0 is always
+// present even for 1-based fields like day-of-month; it's simply unused).
+type fieldSet [62]bool
+
+// parseField parses a single cron field: "*", a number, a comma-separated
+// list, a "lo-hi" range, or a "*/step" or "lo-hi/step" step expression.
+func parseField(field string, lo, hi int) (fieldSet, error) {
+	var set fieldSet
+	for _, part := range strings.Split(field, ",") {
+		rangeLo, rangeHi, step := lo, hi, 1
+		spec := part
+		if idx := strings.IndexByte(part, '/'); idx >= 0 {
+			spec = part[:idx]
+			n, err := strconv.Atoi(part[idx+1:])
+			if err != nil || n <= 0 {
+				return set, fmt.Errorf("invalid step in %q", part)
+			}
+			step = n
+		}
+		switch {
+		case spec == "*":
+			// rangeLo/rangeHi already cover the full range.
+		case strings.Contains(spec, "-"):
+			bounds := strings.SplitN(spec, "-", 2)
+			a, err1 := strconv.Atoi(bounds[0])
+			b, err2 := strconv.Atoi(bounds[1])
+			if err1 != nil || err2 != nil || a < lo || b > hi || a > b {
+				return set, fmt.Errorf("invalid range %q", spec)
+			}
+			rangeLo, rangeHi = a, b
+		default:
+			n, err := strconv.Atoi(spec)
+			if err != nil || n < lo || n > hi {
+				return set, fmt.Errorf("invalid value %q", spec)
+			}
+			rangeLo, rangeHi = n, n
+		}
+		for v := rangeLo; v <= rangeHi; v += step {
+			set[v] = true
+		}
+	}
+	return set, nil
+}
+
+// fieldSchedule fires at the next minute whose minute/hour/day-of-month/
+// month/day-of-week all match, scanning minute by minute. Per POSIX cron
+// semantics, if both day-of-month and day-of-week are restricted (not "*"),
+// a minute matches when EITHER field matches; otherwise both must match.
+type fieldSchedule struct {
+	minute, hour, dom, month, dow fieldSet
+}
+
+func (s *fieldSchedule) Next(from time.Time) time.Time {
+	domRestricted := !isFull(s.dom, 1, 31)
+	dowRestricted := !isFull(s.dow, 0, 6)
+
+	t := from.Truncate(time.Minute).Add(time.Minute)
+	// A five-year search horizon is far more than any real schedule needs
+	// and guards against an unsatisfiable field combination looping forever.
+	limit := t.AddDate(5, 0, 0)
+	for t.Before(limit) {
+		dayOK := s.dom[t.Day()] && s.month[int(t.Month())] && s.dow[int(t.Weekday())]
+		if domRestricted && dowRestricted {
+			dayOK = (s.dom[t.Day()] || s.dow[int(t.Weekday())]) && s.month[int(t.Month())]
+		}
+		if dayOK && s.hour[t.Hour()] && s.minute[t.Minute()] {
+			return t
+		}
+		t = t.Add(time.Minute)
+	}
+	return limit
+}
+
+func isFull(set fieldSet, lo, hi int) bool {
+	for v := lo; v <= hi; v++ {
+		if !set[v] {
+			return false
+		}
+	}
+	return true
+}
+
+// everySchedule fires every interval after from, anchored to from itself
+// (not to any fixed epoch), implementing "@every <duration>".
+type everySchedule struct {
+	interval time.Duration
+}
+
+func (s *everySchedule) Next(from time.Time) time.Time {
+	return from.Add(s.interval)
+}
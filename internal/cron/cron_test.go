@@ -0,0 +1,79 @@
+package cron
+
+import (
+	"testing"
+	"time"
+)
+
+func mustParse(t *testing.T, expr string) Schedule {
+	t.Helper()
+	s, err := Parse(expr)
+	if err != nil {
+		t.Fatalf("Parse(%q): %v", expr, err)
+	}
+	return s
+}
+
+func TestFieldScheduleNext(t *testing.T) {
+	s := mustParse(t, "0 2 * * *")
+	from := time.Date(2026, 7, 29, 10, 0, 0, 0, time.UTC)
+	got := s.Next(from)
+	want := time.Date(2026, 7, 30, 2, 0, 0, 0, time.UTC)
+	if !got.Equal(want) {
+		t.Fatalf("got %v, want %v", got, want)
+	}
+}
+
+func TestFieldScheduleStep(t *testing.T) {
+	s := mustParse(t, "*/15 * * * *")
+	from := time.Date(2026, 7, 29, 10, 3, 0, 0, time.UTC)
+	got := s.Next(from)
+	want := time.Date(2026, 7, 29, 10, 15, 0, 0, time.UTC)
+	if !got.Equal(want) {
+		t.Fatalf("got %v, want %v", got, want)
+	}
+}
+
+func TestShortcuts(t *testing.T) {
+	hourly := mustParse(t, "@hourly")
+	from := time.Date(2026, 7, 29, 10, 30, 0, 0, time.UTC)
+	want := time.Date(2026, 7, 29, 11, 0, 0, 0, time.UTC)
+	if got := hourly.Next(from); !got.Equal(want) {
+		t.Fatalf("@hourly: got %v, want %v", got, want)
+	}
+
+	weekly := mustParse(t, "@weekly")
+	got := weekly.Next(from)
+	if got.Weekday() != time.Sunday || got.Hour() != 0 || got.Minute() != 0 {
+		t.Fatalf("@weekly: unexpected next fire time %v", got)
+	}
+}
+
+func TestEveryShortcut(t *testing.T) {
+	s := mustParse(t, "@every 30m")
+	from := time.Date(2026, 7, 29, 10, 3, 0, 0, time.UTC)
+	want := from.Add(30 * time.Minute)
+	if got := s.Next(from); !got.Equal(want) {
+		t.Fatalf("got %v, want %v", got, want)
+	}
+}
+
+func TestDayOfMonthOrDayOfWeekIsOR(t *testing.T) {
+	// "1st of the month OR a Monday" at 00:00 - both fields restricted, so
+	// POSIX cron semantics OR them together rather than ANDing.
+	s := mustParse(t, "0 0 1 * 1")
+	from := time.Date(2026, 7, 6, 0, 0, 0, 0, time.UTC) // a Monday
+	got := s.Next(from)
+	want := time.Date(2026, 7, 13, 0, 0, 0, 0, time.UTC) // next Monday
+	if !got.Equal(want) {
+		t.Fatalf("got %v, want %v", got, want)
+	}
+}
+
+func TestParseErrors(t *testing.T) {
+	for _, expr := range []string{"", "* * *", "60 * * * *", "@bogus"} {
+		if _, err := Parse(expr); err == nil {
+			t.Fatalf("Parse(%q): expected error", expr)
+		}
+	}
+}
@@ -0,0 +1,143 @@
+// Package defline derives a human-readable display name for a registry
+// item from its structured metadata, the way NCBI's CreateDefLine builds
+// a FASTA header from a GenBank record's annotations. Each registry kind
+// has its own Formatter, keyed by the same normalized kind string
+// labbookCLI's registry commands use (see normalizeKind in
+// cmd/labbookCLI); Register lets a caller plug in a kind this package
+// doesn't already know about.
+package defline
+
+import (
+	"strconv"
+	"strings"
+)
+
+// Formatter builds a definition line from a registry item's metadata. It
+// should degrade gracefully on partial metadata, dropping any piece whose
+// fields are missing rather than leaving dangling separators.
+type Formatter func(meta map[string]interface{}) string
+
+var formatters = map[string]Formatter{
+	"plasmid":             plasmidDefline,
+	"protein preparation": proteinPreparationDefline,
+	"expression":          expressionDefline,
+	"primers":             primersDefline,
+	"cryo em grid":        cryoEMGridDefline,
+}
+
+// Register adds or replaces the Formatter used for kind.
+func Register(kind string, f Formatter) {
+	formatters[strings.ToLower(kind)] = f
+}
+
+// For returns the Formatter registered for kind, if any.
+func For(kind string) (Formatter, bool) {
+	f, ok := formatters[strings.ToLower(kind)]
+	return f, ok
+}
+
+// Generate returns the definition line kind's Formatter derives from meta,
+// or "" if kind has no registered Formatter.
+func Generate(kind string, meta map[string]interface{}) string {
+	f, ok := For(kind)
+	if !ok {
+		return ""
+	}
+	return f(meta)
+}
+
+// str returns meta[key] trimmed, or "" if it's absent or not a string.
+func str(meta map[string]interface{}, key string) string {
+	s, _ := meta[key].(string)
+	return strings.TrimSpace(s)
+}
+
+// numStr formats meta[key] (a float64, as decoded JSON numbers are) without
+// a trailing ".0", or "" if it's absent or zero.
+func numStr(meta map[string]interface{}, key string) string {
+	v, ok := meta[key].(float64)
+	if !ok || v == 0 {
+		return ""
+	}
+	return strconv.FormatFloat(v, 'f', -1, 64)
+}
+
+// join concatenates the non-empty parts with sep, skipping empty ones
+// rather than leaving a dangling separator.
+func join(sep string, parts ...string) string {
+	var nonEmpty []string
+	for _, p := range parts {
+		if p != "" {
+			nonEmpty = append(nonEmpty, p)
+		}
+	}
+	return strings.Join(nonEmpty, sep)
+}
+
+// plasmidDefline renders "<plasmidId> – <insert> in <backbone> [<resistance>]".
+func plasmidDefline(meta map[string]interface{}) string {
+	insertBackbone := join(" in ", str(meta, "insert"), str(meta, "backbone"))
+	line := join(" – ", str(meta, "plasmidId"), insertBackbone)
+	if resistance := str(meta, "resistance"); resistance != "" {
+		line = join(" ", line, "["+resistance+"]")
+	}
+	return line
+}
+
+// proteinPreparationDefline renders "<aliquotLabel> <species>/<expressionSystem>
+// @ <concentrationMgMl> mg/mL (<preppedOn>)".
+func proteinPreparationDefline(meta map[string]interface{}) string {
+	organism := join("/", str(meta, "species"), str(meta, "expressionSystem"))
+	line := join(" ", str(meta, "aliquotLabel"), organism)
+	if conc := numStr(meta, "concentrationMgMl"); conc != "" {
+		line = join(" ", line, "@ "+conc+" mg/mL")
+	}
+	if preppedOn := str(meta, "preppedOn"); preppedOn != "" {
+		line = join(" ", line, "("+preppedOn+")")
+	}
+	return line
+}
+
+// expressionDefline renders "<expressionStrain> + plasmid #<expressionPlasmidRefId>
+// harvested <harvestDate> (<totalVolumeL> L)".
+func expressionDefline(meta map[string]interface{}) string {
+	line := str(meta, "expressionStrain")
+	if refID, ok := meta["expressionPlasmidRefId"].(float64); ok && refID > 0 {
+		line = join(" + ", line, "plasmid #"+strconv.FormatFloat(refID, 'f', -1, 64))
+	}
+	if harvestDate := str(meta, "harvestDate"); harvestDate != "" {
+		line = join(" ", line, "harvested "+harvestDate)
+	}
+	if totalVolume := numStr(meta, "totalVolumeL"); totalVolume != "" {
+		line = join(" ", line, "("+totalVolume+" L)")
+	}
+	return line
+}
+
+// primersDefline renders "<primerId> <primerType> Tm=<primerTm>°C
+// (<primerLength> nt)".
+func primersDefline(meta map[string]interface{}) string {
+	line := join(" ", str(meta, "primerId"), str(meta, "primerType"))
+	if tm := numStr(meta, "primerTm"); tm != "" {
+		line = join(" ", line, "Tm="+tm+"°C")
+	}
+	if length := numStr(meta, "primerLength"); length != "" {
+		line = join(" ", line, "("+length+" nt)")
+	}
+	return line
+}
+
+// cryoEMGridDefline renders "<gridId> <gridType>/<gridMaterial> <gridMesh> –
+// <iceQuality>, <moviesCollected> movies".
+func cryoEMGridDefline(meta map[string]interface{}) string {
+	typeMaterial := join("/", str(meta, "gridType"), str(meta, "gridMaterial"))
+	line := join(" ", str(meta, "gridId"), typeMaterial, str(meta, "gridMeshSize"))
+	var tail string
+	if iceQuality := str(meta, "iceQuality"); iceQuality != "" {
+		tail = iceQuality
+	}
+	if movies := numStr(meta, "moviesCollected"); movies != "" {
+		tail = join(", ", tail, movies+" movies")
+	}
+	return join(" – ", line, tail)
+}
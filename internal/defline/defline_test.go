@@ -0,0 +1,104 @@
+package defline
+
+import "testing"
+
+func TestPlasmidDefline(t *testing.T) {
+	full := map[string]interface{}{
+		"plasmidId":  "pTEST001",
+		"insert":     "beta-lactamase",
+		"backbone":   "pUC19",
+		"resistance": "AmpR",
+	}
+	if got, want := Generate("plasmid", full), "pTEST001 – beta-lactamase in pUC19 [AmpR]"; got != want {
+		t.Errorf("Generate(plasmid, full) = %q, want %q", got, want)
+	}
+
+	partial := map[string]interface{}{"plasmidId": "pTEST002", "backbone": "pET28a"}
+	if got, want := Generate("plasmid", partial), "pTEST002 – pET28a"; got != want {
+		t.Errorf("Generate(plasmid, partial) = %q, want %q", got, want)
+	}
+
+	if got := Generate("plasmid", map[string]interface{}{}); got != "" {
+		t.Errorf("Generate(plasmid, empty) = %q, want \"\"", got)
+	}
+}
+
+func TestProteinPreparationDefline(t *testing.T) {
+	meta := map[string]interface{}{
+		"aliquotLabel":      "P1-A1",
+		"species":           "E. coli",
+		"expressionSystem":  "BL21(DE3)",
+		"concentrationMgMl": 4.5,
+		"preppedOn":         "2026-01-15",
+	}
+	want := "P1-A1 E. coli/BL21(DE3) @ 4.5 mg/mL (2026-01-15)"
+	if got := Generate("protein preparation", meta); got != want {
+		t.Errorf("Generate(protein preparation) = %q, want %q", got, want)
+	}
+
+	partial := map[string]interface{}{"aliquotLabel": "P1-A2"}
+	if got, want := Generate("protein preparation", partial), "P1-A2"; got != want {
+		t.Errorf("Generate(protein preparation, partial) = %q, want %q", got, want)
+	}
+}
+
+func TestExpressionDefline(t *testing.T) {
+	meta := map[string]interface{}{
+		"expressionStrain":       "BL21(DE3)",
+		"expressionPlasmidRefId": 42.0,
+		"harvestDate":            "2026-02-01",
+		"totalVolumeL":           2.0,
+	}
+	want := "BL21(DE3) + plasmid #42 harvested 2026-02-01 (2 L)"
+	if got := Generate("expression", meta); got != want {
+		t.Errorf("Generate(expression) = %q, want %q", got, want)
+	}
+}
+
+func TestPrimersDefline(t *testing.T) {
+	meta := map[string]interface{}{
+		"primerId":     "oJF001",
+		"primerType":   "forward",
+		"primerTm":     58.3,
+		"primerLength": 20.0,
+	}
+	want := "oJF001 forward Tm=58.3°C (20 nt)"
+	if got := Generate("primers", meta); got != want {
+		t.Errorf("Generate(primers) = %q, want %q", got, want)
+	}
+}
+
+func TestCryoEMGridDefline(t *testing.T) {
+	meta := map[string]interface{}{
+		"gridId":          "G001",
+		"gridType":        "Quantifoil",
+		"gridMaterial":    "Au",
+		"gridMeshSize":    "300",
+		"iceQuality":      "good",
+		"moviesCollected": 1200.0,
+	}
+	want := "G001 Quantifoil/Au 300 – good, 1200 movies"
+	if got := Generate("cryo em grid", meta); got != want {
+		t.Errorf("Generate(cryo em grid) = %q, want %q", got, want)
+	}
+
+	partial := map[string]interface{}{"gridId": "G002"}
+	if got, want := Generate("cryo em grid", partial), "G002"; got != want {
+		t.Errorf("Generate(cryo em grid, partial) = %q, want %q", got, want)
+	}
+}
+
+func TestGenerateUnknownKind(t *testing.T) {
+	if got := Generate("unknown", map[string]interface{}{"a": "b"}); got != "" {
+		t.Errorf("Generate(unknown) = %q, want \"\"", got)
+	}
+}
+
+func TestRegister(t *testing.T) {
+	Register("widget", func(meta map[string]interface{}) string {
+		return str(meta, "label")
+	})
+	if got, want := Generate("widget", map[string]interface{}{"label": "hi"}), "hi"; got != want {
+		t.Errorf("Generate(widget) = %q, want %q", got, want)
+	}
+}
@@ -0,0 +1,78 @@
+// Package html2text renders LabBook entry/template HTML (ContentHTML,
+// AppendHTML, and rendered template output) into readable plain text or
+// Markdown for terminal viewers, grep, LLM ingestion, and audit bundles,
+// without pulling in a heavy HTML-to-text dependency.
+package html2text
+
+import (
+	"strings"
+
+	"golang.org/x/net/html"
+	"golang.org/x/net/html/atom"
+)
+
+// Format selects the textual rendering produced by Convert.
+type Format string
+
+const (
+	// FormatText renders plain text: uppercased, underlined headings and
+	// "text (href)" links. Images still render as ![alt](src) and inline
+	// code keeps its backticks.
+	FormatText Format = "text"
+	// FormatMarkdown renders Markdown (headings, links, lists, code fences,
+	// pipe tables).
+	FormatMarkdown Format = "markdown"
+)
+
+// DefaultWidth is the word-wrap width used when Options.Width is zero and
+// the caller didn't explicitly request no wrapping.
+const DefaultWidth = 100
+
+// Options controls how Convert renders a document.
+type Options struct {
+	// Format is FormatText or FormatMarkdown. Defaults to FormatText.
+	Format Format
+	// Width word-wraps non-code paragraphs to this many columns. A zero
+	// value wraps at DefaultWidth; a negative value disables wrapping.
+	Width int
+	// Widgets is the entry's decoded Widgets field (typically
+	// []interface{} of maps with "id"/"widgetId" and "type" keys), used to
+	// substitute a compact textual representation for
+	// `<div data-widget="..." data-widget-id="...">` placeholders. May be
+	// nil, in which case placeholders render as a `[widget: type #id]`
+	// stub.
+	Widgets interface{}
+}
+
+// Convert parses src as an HTML fragment and renders it per opts.
+func Convert(src string, opts Options) (string, error) {
+	format := opts.Format
+	if format == "" {
+		format = FormatText
+	}
+	width := opts.Width
+	if width == 0 {
+		width = DefaultWidth
+	} else if width < 0 {
+		width = 0
+	}
+
+	nodes, err := html.ParseFragment(strings.NewReader(src), &html.Node{
+		Type:     html.ElementNode,
+		Data:     "body",
+		DataAtom: atom.Body,
+	})
+	if err != nil {
+		return "", err
+	}
+
+	r := &renderer{
+		format:  format,
+		width:   width,
+		widgets: indexWidgets(opts.Widgets),
+	}
+	for _, n := range nodes {
+		r.block(n, 0)
+	}
+	return strings.TrimRight(r.buf.String(), "\n") + "\n", nil
+}
@@ -0,0 +1,135 @@
+package html2text
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestConvertHeadingsAndParagraphs(t *testing.T) {
+	src := `<h1>Title</h1><p>First paragraph.</p><p>Second paragraph.</p>`
+
+	got, err := Convert(src, Options{Format: FormatMarkdown, Width: -1})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	want := "# Title\n\nFirst paragraph.\n\nSecond paragraph.\n"
+	if got != want {
+		t.Fatalf("markdown mismatch:\ngot:  %q\nwant: %q", got, want)
+	}
+
+	got, err = Convert(src, Options{Format: FormatText, Width: -1})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	want = "TITLE\n=====\n\nFirst paragraph.\n\nSecond paragraph.\n"
+	if got != want {
+		t.Fatalf("text mismatch:\ngot:  %q\nwant: %q", got, want)
+	}
+}
+
+func TestConvertLinksAndImages(t *testing.T) {
+	src := `<p>See <a href="https://example.com/x">the notebook</a> and <img src="grid.png" alt="grid">.</p>`
+
+	md, err := Convert(src, Options{Format: FormatMarkdown, Width: -1})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !strings.Contains(md, "[the notebook](https://example.com/x)") {
+		t.Fatalf("expected markdown link, got %q", md)
+	}
+	if !strings.Contains(md, "![grid](grid.png)") {
+		t.Fatalf("expected markdown image, got %q", md)
+	}
+
+	text, err := Convert(src, Options{Format: FormatText, Width: -1})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !strings.Contains(text, "the notebook (https://example.com/x)") {
+		t.Fatalf("expected text-style link, got %q", text)
+	}
+}
+
+func TestConvertLists(t *testing.T) {
+	src := `<ul><li>alpha</li><li>beta<ol><li>nested one</li></ol></li></ul>`
+
+	got, err := Convert(src, Options{Format: FormatText, Width: -1})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	want := "- alpha\n- beta\n  1. nested one\n"
+	if got != want {
+		t.Fatalf("list mismatch:\ngot:  %q\nwant: %q", got, want)
+	}
+}
+
+func TestConvertCodeBlock(t *testing.T) {
+	src := `<pre><code class="language-go">fmt.Println("hi")
+</code></pre>`
+
+	md, err := Convert(src, Options{Format: FormatMarkdown, Width: -1})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	want := "```go\nfmt.Println(\"hi\")\n```\n"
+	if md != want {
+		t.Fatalf("code block mismatch:\ngot:  %q\nwant: %q", md, want)
+	}
+}
+
+func TestConvertTable(t *testing.T) {
+	src := `<table><tr><th>Name</th><th>Kind</th></tr><tr><td>pET28</td><td>Plasmid</td></tr></table>`
+
+	md, err := Convert(src, Options{Format: FormatMarkdown, Width: -1})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	want := "| Name  | Kind    |\n" +
+		"| ----- | ------- |\n" +
+		"| pET28 | Plasmid |\n"
+	if md != want {
+		t.Fatalf("markdown table mismatch:\ngot:  %q\nwant: %q", md, want)
+	}
+}
+
+func TestConvertWrapsParagraphs(t *testing.T) {
+	src := `<p>one two three four five six seven eight nine ten</p>`
+
+	got, err := Convert(src, Options{Format: FormatText, Width: 20})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	want := "one two three four\nfive six seven eight\nnine ten\n"
+	if got != want {
+		t.Fatalf("wrap mismatch:\ngot:  %q\nwant: %q", got, want)
+	}
+}
+
+func TestConvertWidgetPlaceholderWithData(t *testing.T) {
+	src := `<div data-widget="sequence-viewer" data-widget-id="w1"></div>`
+	widgets := []interface{}{
+		map[string]interface{}{"id": "w1", "type": "sequence-viewer", "length": float64(204)},
+	}
+
+	got, err := Convert(src, Options{Format: FormatText, Width: -1, Widgets: widgets})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	want := "[widget: sequence-viewer #w1] length=204\n"
+	if got != want {
+		t.Fatalf("widget mismatch:\ngot:  %q\nwant: %q", got, want)
+	}
+}
+
+func TestConvertWidgetPlaceholderStub(t *testing.T) {
+	src := `<div data-widget="plasmid-map" data-widget-id="w9"></div>`
+
+	got, err := Convert(src, Options{Format: FormatText, Width: -1})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	want := "[widget: plasmid-map #w9]\n"
+	if got != want {
+		t.Fatalf("widget stub mismatch:\ngot:  %q\nwant: %q", got, want)
+	}
+}
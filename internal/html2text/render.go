@@ -0,0 +1,259 @@
+package html2text
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"golang.org/x/net/html"
+)
+
+// renderer walks a parsed node tree and accumulates rendered output in buf.
+// It is single-use: callers construct one per Convert call.
+type renderer struct {
+	format  Format
+	width   int
+	widgets map[string]widgetInfo
+	buf     strings.Builder
+}
+
+// block renders n and its siblings as block-level content at the given list
+// nesting depth, separating consecutive blocks with a blank line.
+func (r *renderer) block(n *html.Node, depth int) {
+	for ; n != nil; n = n.NextSibling {
+		switch n.Type {
+		case html.TextNode:
+			if text := strings.TrimSpace(n.Data); text != "" {
+				r.writeParagraph(text, depth)
+			}
+		case html.ElementNode:
+			r.element(n, depth)
+		case html.DocumentNode:
+			r.block(n.FirstChild, depth)
+		}
+	}
+}
+
+func (r *renderer) element(n *html.Node, depth int) {
+	switch n.Data {
+	case "p", "div":
+		if widget, ok := r.widgetPlaceholder(n); ok {
+			r.writeLine(widget, depth)
+			return
+		}
+		r.writeParagraph(r.inlineText(n), depth)
+	case "br":
+		r.buf.WriteString("\n")
+	case "h1", "h2", "h3", "h4", "h5", "h6":
+		level, _ := strconv.Atoi(strings.TrimPrefix(n.Data, "h"))
+		r.writeHeading(r.inlineText(n), level, depth)
+	case "ul", "ol":
+		r.writeList(n, depth, n.Data == "ol")
+	case "pre":
+		r.writeCodeBlock(preText(n), codeLang(n))
+	case "table":
+		r.writeTable(n, depth)
+	case "script", "style", "head":
+		// Not rendered.
+	default:
+		r.block(n.FirstChild, depth)
+	}
+}
+
+// writeParagraph word-wraps text (unless wrapping is disabled) and appends
+// it as its own paragraph, indented for the current list depth.
+func (r *renderer) writeParagraph(text string, depth int) {
+	text = strings.TrimSpace(text)
+	if text == "" {
+		return
+	}
+	indent := strings.Repeat("  ", depth)
+	for _, line := range wrapText(text, r.width, len(indent)) {
+		r.buf.WriteString(indent)
+		r.buf.WriteString(line)
+		r.buf.WriteString("\n")
+	}
+	r.buf.WriteString("\n")
+}
+
+func (r *renderer) writeLine(text string, depth int) {
+	r.buf.WriteString(strings.Repeat("  ", depth))
+	r.buf.WriteString(text)
+	r.buf.WriteString("\n\n")
+}
+
+func (r *renderer) writeHeading(text string, level int, depth int) {
+	text = strings.TrimSpace(text)
+	if text == "" {
+		return
+	}
+	indent := strings.Repeat("  ", depth)
+	if r.format == FormatMarkdown {
+		if level < 1 {
+			level = 1
+		}
+		if level > 6 {
+			level = 6
+		}
+		r.buf.WriteString(indent)
+		r.buf.WriteString(strings.Repeat("#", level))
+		r.buf.WriteString(" ")
+		r.buf.WriteString(text)
+		r.buf.WriteString("\n\n")
+		return
+	}
+	upper := strings.ToUpper(text)
+	r.buf.WriteString(indent)
+	r.buf.WriteString(upper)
+	r.buf.WriteString("\n")
+	r.buf.WriteString(indent)
+	r.buf.WriteString(strings.Repeat("=", len([]rune(upper))))
+	r.buf.WriteString("\n\n")
+}
+
+func (r *renderer) writeList(n *html.Node, depth int, ordered bool) {
+	index := 1
+	for item := n.FirstChild; item != nil; item = item.NextSibling {
+		if item.Type != html.ElementNode || item.Data != "li" {
+			continue
+		}
+		marker := "- "
+		if ordered {
+			marker = fmt.Sprintf("%d. ", index)
+			index++
+		}
+		indent := strings.Repeat("  ", depth)
+		text := r.inlineText(item)
+		lines := wrapText(text, r.width, len(indent)+len(marker))
+		for i, line := range lines {
+			r.buf.WriteString(indent)
+			if i == 0 {
+				r.buf.WriteString(marker)
+			} else {
+				r.buf.WriteString(strings.Repeat(" ", len(marker)))
+			}
+			r.buf.WriteString(line)
+			r.buf.WriteString("\n")
+		}
+		for child := item.FirstChild; child != nil; child = child.NextSibling {
+			if child.Type == html.ElementNode && (child.Data == "ul" || child.Data == "ol") {
+				r.writeList(child, depth+1, child.Data == "ol")
+			}
+		}
+	}
+	if depth == 0 {
+		r.buf.WriteString("\n")
+	}
+}
+
+func (r *renderer) writeCodeBlock(code, lang string) {
+	code = strings.TrimRight(code, "\n")
+	if r.format == FormatMarkdown {
+		r.buf.WriteString("```")
+		r.buf.WriteString(lang)
+		r.buf.WriteString("\n")
+		r.buf.WriteString(code)
+		r.buf.WriteString("\n```\n\n")
+		return
+	}
+	r.buf.WriteString(code)
+	r.buf.WriteString("\n\n")
+}
+
+// inlineText renders n's descendants as a single line of flowed inline
+// text, resolving <a>/<img>/<code> and widget placeholders along the way.
+func (r *renderer) inlineText(n *html.Node) string {
+	var b strings.Builder
+	r.inline(n.FirstChild, &b)
+	return collapseSpace(b.String())
+}
+
+func (r *renderer) inline(n *html.Node, b *strings.Builder) {
+	for ; n != nil; n = n.NextSibling {
+		switch n.Type {
+		case html.TextNode:
+			b.WriteString(n.Data)
+		case html.ElementNode:
+			if widget, ok := r.widgetPlaceholder(n); ok {
+				b.WriteString(" ")
+				b.WriteString(widget)
+				b.WriteString(" ")
+				continue
+			}
+			switch n.Data {
+			case "ul", "ol":
+				// Nested lists render as their own block in writeList, not
+				// folded into the parent <li>'s inline text.
+			case "br":
+				b.WriteString("\n")
+			case "a":
+				text := collapseSpace(r.inlineText(n))
+				href := attr(n, "href")
+				if href == "" {
+					b.WriteString(text)
+				} else if r.format == FormatMarkdown {
+					fmt.Fprintf(b, "[%s](%s)", text, href)
+				} else {
+					fmt.Fprintf(b, "%s (%s)", text, href)
+				}
+			case "img":
+				alt := attr(n, "alt")
+				src := attr(n, "src")
+				fmt.Fprintf(b, "![%s](%s)", alt, src)
+			case "code":
+				fmt.Fprintf(b, "`%s`", collapseSpace(r.inlineText(n)))
+			case "script", "style":
+				// Not rendered.
+			default:
+				r.inline(n.FirstChild, b)
+			}
+		}
+	}
+}
+
+func attr(n *html.Node, name string) string {
+	for _, a := range n.Attr {
+		if a.Key == name {
+			return a.Val
+		}
+	}
+	return ""
+}
+
+func preText(n *html.Node) string {
+	var b strings.Builder
+	var walk func(*html.Node)
+	walk = func(n *html.Node) {
+		for ; n != nil; n = n.NextSibling {
+			if n.Type == html.TextNode {
+				b.WriteString(n.Data)
+			}
+			walk(n.FirstChild)
+		}
+	}
+	walk(n.FirstChild)
+	return b.String()
+}
+
+// codeLang returns the fenced-block language hint for a <pre>, taken from a
+// nested <code class="language-xxx"> as GitHub-flavored Markdown does.
+func codeLang(n *html.Node) string {
+	for c := n.FirstChild; c != nil; c = c.NextSibling {
+		if c.Type == html.ElementNode && c.Data == "code" {
+			for _, a := range c.Attr {
+				if a.Key == "class" {
+					for _, class := range strings.Fields(a.Val) {
+						if strings.HasPrefix(class, "language-") {
+							return strings.TrimPrefix(class, "language-")
+						}
+					}
+				}
+			}
+		}
+	}
+	return ""
+}
+
+func collapseSpace(s string) string {
+	return strings.Join(strings.Fields(s), " ")
+}
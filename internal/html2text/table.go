@@ -0,0 +1,147 @@
+package html2text
+
+import (
+	"strings"
+
+	"golang.org/x/net/html"
+)
+
+// writeTable renders a <table> as an aligned text grid (FormatText) or a
+// pipe table (FormatMarkdown). Rowspan/colspan are ignored; every <tr> is
+// treated as a simple row of <th>/<td> cells.
+func (r *renderer) writeTable(n *html.Node, depth int) {
+	rows := tableRows(n)
+	if len(rows) == 0 {
+		return
+	}
+	indent := strings.Repeat("  ", depth)
+	widths := columnWidths(rows)
+
+	if r.format == FormatMarkdown {
+		r.writeMarkdownTable(rows, widths, indent)
+	} else {
+		r.writeTextTable(rows, widths, indent)
+	}
+	r.buf.WriteString("\n")
+}
+
+func (r *renderer) writeMarkdownTable(rows [][]string, widths []int, indent string) {
+	r.writeMarkdownRow(rows[0], widths, indent)
+	r.buf.WriteString(indent)
+	r.buf.WriteString("|")
+	for _, w := range widths {
+		r.buf.WriteString(" ")
+		r.buf.WriteString(strings.Repeat("-", w))
+		r.buf.WriteString(" |")
+	}
+	r.buf.WriteString("\n")
+	for _, row := range rows[1:] {
+		r.writeMarkdownRow(row, widths, indent)
+	}
+}
+
+func (r *renderer) writeMarkdownRow(row []string, widths []int, indent string) {
+	r.buf.WriteString(indent)
+	r.buf.WriteString("|")
+	for i, w := range widths {
+		cell := ""
+		if i < len(row) {
+			cell = row[i]
+		}
+		r.buf.WriteString(" ")
+		r.buf.WriteString(padRight(cell, w))
+		r.buf.WriteString(" |")
+	}
+	r.buf.WriteString("\n")
+}
+
+func (r *renderer) writeTextTable(rows [][]string, widths []int, indent string) {
+	sep := indent + "+"
+	for _, w := range widths {
+		sep += strings.Repeat("-", w+2) + "+"
+	}
+	r.buf.WriteString(sep)
+	r.buf.WriteString("\n")
+	for i, row := range rows {
+		r.buf.WriteString(indent)
+		r.buf.WriteString("|")
+		for col, w := range widths {
+			cell := ""
+			if col < len(row) {
+				cell = row[col]
+			}
+			r.buf.WriteString(" ")
+			r.buf.WriteString(padRight(cell, w))
+			r.buf.WriteString(" |")
+		}
+		r.buf.WriteString("\n")
+		if i == 0 {
+			r.buf.WriteString(sep)
+			r.buf.WriteString("\n")
+		}
+	}
+	r.buf.WriteString(sep)
+	r.buf.WriteString("\n")
+}
+
+func tableRows(table *html.Node) [][]string {
+	var rows [][]string
+	var walk func(*html.Node)
+	walk = func(n *html.Node) {
+		for ; n != nil; n = n.NextSibling {
+			if n.Type == html.ElementNode {
+				switch n.Data {
+				case "tr":
+					rows = append(rows, nil)
+					for cell := n.FirstChild; cell != nil; cell = cell.NextSibling {
+						if cell.Type == html.ElementNode && (cell.Data == "td" || cell.Data == "th") {
+							rows[len(rows)-1] = append(rows[len(rows)-1], collapseSpace(textContent(cell)))
+						}
+					}
+				default:
+					walk(n.FirstChild)
+				}
+			}
+		}
+	}
+	walk(table.FirstChild)
+	return rows
+}
+
+func textContent(n *html.Node) string {
+	var b strings.Builder
+	var walk func(*html.Node)
+	walk = func(n *html.Node) {
+		for ; n != nil; n = n.NextSibling {
+			if n.Type == html.TextNode {
+				b.WriteString(n.Data)
+			}
+			walk(n.FirstChild)
+		}
+	}
+	walk(n.FirstChild)
+	return b.String()
+}
+
+func columnWidths(rows [][]string) []int {
+	var widths []int
+	for _, row := range rows {
+		for i, cell := range row {
+			for len(widths) <= i {
+				widths = append(widths, 0)
+			}
+			if n := len([]rune(cell)); n > widths[i] {
+				widths[i] = n
+			}
+		}
+	}
+	return widths
+}
+
+func padRight(s string, width int) string {
+	n := len([]rune(s))
+	if n >= width {
+		return s
+	}
+	return s + strings.Repeat(" ", width-n)
+}
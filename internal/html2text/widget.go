@@ -0,0 +1,99 @@
+package html2text
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"golang.org/x/net/html"
+)
+
+// widgetInfo is the compact, type-specific summary substituted for a
+// `<div data-widget="..." data-widget-id="...">` placeholder when the
+// matching entry in Options.Widgets is found.
+type widgetInfo struct {
+	typ     string
+	id      string
+	summary string
+}
+
+// indexWidgets normalizes an entry's decoded Widgets field (typically
+// []interface{} of map[string]interface{} as produced by
+// json.Unmarshal into interface{}) into a lookup by widget ID.
+func indexWidgets(widgets interface{}) map[string]widgetInfo {
+	items, ok := widgets.([]interface{})
+	if !ok {
+		return nil
+	}
+	index := make(map[string]widgetInfo, len(items))
+	for _, item := range items {
+		m, ok := item.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		id := stringField(m, "id", "widgetId")
+		if id == "" {
+			continue
+		}
+		index[id] = widgetInfo{
+			typ:     stringField(m, "type", "widgetType"),
+			id:      id,
+			summary: summarizeWidget(m),
+		}
+	}
+	return index
+}
+
+// stringField returns the first of keys present in m, stringified.
+func stringField(m map[string]interface{}, keys ...string) string {
+	for _, k := range keys {
+		if v, ok := m[k]; ok {
+			return fmt.Sprintf("%v", v)
+		}
+	}
+	return ""
+}
+
+// summarizeWidget renders a widget's scalar fields (other than
+// id/widgetId/type/widgetType) as "key=value" pairs in stable, sorted key
+// order, so the placeholder gives a useful at-a-glance summary without
+// dumping the full widget JSON inline.
+func summarizeWidget(m map[string]interface{}) string {
+	skip := map[string]bool{"id": true, "widgetId": true, "type": true, "widgetType": true}
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		if !skip[k] {
+			keys = append(keys, k)
+		}
+	}
+	sort.Strings(keys)
+
+	var parts []string
+	for _, k := range keys {
+		switch v := m[k].(type) {
+		case map[string]interface{}, []interface{}:
+			continue
+		default:
+			parts = append(parts, fmt.Sprintf("%s=%v", k, v))
+		}
+	}
+	return strings.Join(parts, ", ")
+}
+
+// widgetPlaceholder reports whether n is a LabBook widget placeholder
+// (`data-widget`/`data-widget-id` attributes) and, if so, its rendered
+// substitution text.
+func (r *renderer) widgetPlaceholder(n *html.Node) (string, bool) {
+	widgetType := attr(n, "data-widget")
+	if widgetType == "" {
+		return "", false
+	}
+	widgetID := attr(n, "data-widget-id")
+	if info, ok := r.widgets[widgetID]; ok {
+		if info.summary == "" {
+			return fmt.Sprintf("[widget: %s #%s]", info.typ, info.id), true
+		}
+		return fmt.Sprintf("[widget: %s #%s] %s", info.typ, info.id, info.summary), true
+	}
+	return fmt.Sprintf("[widget: %s #%s]", widgetType, widgetID), true
+}
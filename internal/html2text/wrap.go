@@ -0,0 +1,39 @@
+package html2text
+
+import "strings"
+
+// wrapText greedily word-wraps text to width columns, accounting for a
+// leading prefix (indent or list marker) of prefixLen columns on every
+// line. width <= 0 disables wrapping and returns text as a single line.
+func wrapText(text string, width int, prefixLen int) []string {
+	words := strings.Fields(text)
+	if len(words) == 0 {
+		return nil
+	}
+	if width <= 0 || width <= prefixLen {
+		return []string{strings.Join(words, " ")}
+	}
+	limit := width - prefixLen
+
+	var lines []string
+	var cur strings.Builder
+	curLen := 0
+	for _, word := range words {
+		wordLen := len([]rune(word))
+		if curLen > 0 && curLen+1+wordLen > limit {
+			lines = append(lines, cur.String())
+			cur.Reset()
+			curLen = 0
+		}
+		if curLen > 0 {
+			cur.WriteString(" ")
+			curLen++
+		}
+		cur.WriteString(word)
+		curLen += wordLen
+	}
+	if curLen > 0 {
+		lines = append(lines, cur.String())
+	}
+	return lines
+}
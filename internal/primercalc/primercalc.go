@@ -0,0 +1,147 @@
+// Package primercalc computes the thermodynamic properties labbookCLI's
+// "Primers" registry kind tracks — length, GC%, molecular weight, and
+// melting temperature — directly from a primer's nucleotide sequence, so a
+// user supplying --primer-sequence doesn't also have to type the four or
+// five numbers that follow from it.
+package primercalc
+
+import (
+	"fmt"
+	"math"
+	"strings"
+)
+
+// nnParam holds the SantaLucia 1998 unified nearest-neighbor ΔH (kcal/mol)
+// and ΔS (cal/mol·K) for one dinucleotide step. Complementary steps (e.g.
+// AA/TT) share a single entry, keyed by whichever orientation is looked up.
+type nnParam struct {
+	dH, dS float64
+}
+
+// nnTable is the SantaLucia 1998 unified nearest-neighbor parameter set.
+var nnTable = map[string]nnParam{
+	"AA": {-7.9, -22.2}, "TT": {-7.9, -22.2},
+	"AT": {-7.2, -20.4},
+	"TA": {-7.2, -21.3},
+	"CA": {-8.5, -22.7}, "TG": {-8.5, -22.7},
+	"GT": {-8.4, -22.4}, "AC": {-8.4, -22.4},
+	"CT": {-7.8, -21.0}, "AG": {-7.8, -21.0},
+	"GA": {-8.2, -22.2}, "TC": {-8.2, -22.2},
+	"CG": {-10.6, -27.2},
+	"GC": {-9.8, -24.4},
+	"GG": {-8.0, -19.9}, "CC": {-8.0, -19.9},
+}
+
+// gasConstant is R in cal/mol·K, matching the units nnTable's ΔS is in.
+const gasConstant = 1.987
+
+// Compute derives length, GC%, molecular weight (Da), and Tm (°C) for the
+// ssDNA oligo seq, given the monovalent and divalent salt concentrations
+// (M) and the total strand concentration primerConcUM (µM) used for the Tm
+// calculation. seq is case-insensitive and must contain only A/C/G/T —
+// ambiguous IUPAC codes are rejected; callers that want to tolerate them
+// (e.g. labbookCLI's --allow-ambig) should skip calling Compute rather than
+// pass a sanitized sequence through it, since the composition and NN steps
+// would silently be wrong for the dropped bases.
+//
+// Tm follows SantaLucia (1998) nearest-neighbor thermodynamics, corrected
+// for salt per Owczarzy et al. (2008) using the equivalent monovalent
+// [Na+] for a mixed Mg2+/monovalent buffer.
+func Compute(seq string, saltMonovalentM, saltDivalentM, primerConcUM float64) (length int, gcPercent, mwDa, tmC float64, err error) {
+	seq = strings.ToUpper(strings.TrimSpace(seq))
+	if seq == "" {
+		return 0, 0, 0, 0, fmt.Errorf("primercalc: empty sequence")
+	}
+	var nA, nC, nG, nT int
+	for i, b := range seq {
+		switch b {
+		case 'A':
+			nA++
+		case 'C':
+			nC++
+		case 'G':
+			nG++
+		case 'T':
+			nT++
+		default:
+			return 0, 0, 0, 0, fmt.Errorf("primercalc: ambiguous or invalid base %q at position %d", b, i+1)
+		}
+	}
+	length = nA + nC + nG + nT
+	if length < 2 {
+		return 0, 0, 0, 0, fmt.Errorf("primercalc: sequence too short for nearest-neighbor Tm (need >= 2 bases)")
+	}
+	gcPercent = round2(100 * float64(nG+nC) / float64(length))
+	mwDa = round2(313.21*float64(nA) + 304.2*float64(nT) + 329.21*float64(nG) + 289.18*float64(nC) - 61.96)
+
+	tmC, err = meltingTempC(seq, saltMonovalentM, saltDivalentM, primerConcUM)
+	if err != nil {
+		return 0, 0, 0, 0, err
+	}
+	return length, gcPercent, mwDa, round2(tmC), nil
+}
+
+// meltingTempC sums nearest-neighbor ΔH/ΔS over seq's dinucleotide steps,
+// adds SantaLucia's initiation terms, solves for Tm at primerConcUM, and
+// applies the Owczarzy salt correction.
+func meltingTempC(seq string, saltMonovalentM, saltDivalentM, primerConcUM float64) (float64, error) {
+	if primerConcUM <= 0 {
+		return 0, fmt.Errorf("primercalc: primerConcUM must be > 0")
+	}
+	var dH, dS float64
+	for i := 0; i+1 < len(seq); i++ {
+		step := seq[i : i+2]
+		p, ok := nnTable[step]
+		if !ok {
+			return 0, fmt.Errorf("primercalc: no nearest-neighbor parameter for step %q", step)
+		}
+		dH += p.dH
+		dS += p.dS
+	}
+	// Initiation: a fixed term for every duplex, plus a terminal A/T
+	// penalty if either end of the primer is A or T.
+	dH += 0.1
+	dS += -2.8
+	if seq[0] == 'A' || seq[0] == 'T' || seq[len(seq)-1] == 'A' || seq[len(seq)-1] == 'T' {
+		dH += 2.3
+		dS += 4.1
+	}
+
+	ct := primerConcUM * 1e-6
+	tmK := dH * 1000 / (dS + gasConstant*math.Log(ct/4))
+
+	naEq := equivalentMonovalentNa(saltMonovalentM, saltDivalentM)
+	if naEq > 0 {
+		fGC := gcFraction(seq)
+		invTm := 1/tmK + (4.29*fGC-3.95)*1e-5*math.Log(naEq) + 9.4e-6*math.Log(naEq)*math.Log(naEq)
+		tmK = 1 / invTm
+	}
+	return tmK - 273.15, nil
+}
+
+func gcFraction(seq string) float64 {
+	var gc int
+	for _, b := range seq {
+		if b == 'G' || b == 'C' {
+			gc++
+		}
+	}
+	return float64(gc) / float64(len(seq))
+}
+
+// equivalentMonovalentNa converts a mixed Mg2+/monovalent buffer into an
+// equivalent [Na+] per Owczarzy et al. (2008): [Na+]eq = [Monovalent] +
+// 120*sqrt([Mg2+] - [dNTPs]), simplified here (no dNTP term) to
+// [Monovalent] + 120*sqrt([Mg2+]), which reduces to the plain monovalent
+// concentration when saltDivalentM is 0.
+func equivalentMonovalentNa(saltMonovalentM, saltDivalentM float64) float64 {
+	na := saltMonovalentM
+	if saltDivalentM > 0 {
+		na += 120 * math.Sqrt(saltDivalentM)
+	}
+	return na
+}
+
+func round2(v float64) float64 {
+	return math.Round(v*100) / 100
+}
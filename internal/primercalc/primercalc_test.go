@@ -0,0 +1,75 @@
+package primercalc
+
+import "testing"
+
+func TestCompute(t *testing.T) {
+	cases := []struct {
+		name                                         string
+		seq                                          string
+		saltMonovalentM, saltDivalentM, primerConcUM float64
+		wantLength                                   int
+		wantGC, wantMW, wantTm                       float64
+	}{
+		{
+			name: "no salt correction",
+			seq:  "GCGC", saltMonovalentM: 0, saltDivalentM: 0, primerConcUM: 0.25,
+			wantLength: 4, wantGC: 100, wantMW: 1174.82, wantTm: -3.82,
+		},
+		{
+			name: "monovalent salt correction",
+			seq:  "GCGC", saltMonovalentM: 0.05, saltDivalentM: 0, primerConcUM: 0.25,
+			wantLength: 4, wantGC: 100, wantMW: 1174.82, wantTm: -9.1,
+		},
+		{
+			name: "mixed monovalent/divalent salt",
+			seq:  "ACGTACGTACGT", saltMonovalentM: 0.05, saltDivalentM: 0.002, primerConcUM: 0.25,
+			wantLength: 12, wantGC: 50, wantMW: 3645.44, wantTm: 51.77,
+		},
+		{
+			name: "A/T-terminated 12-mer",
+			seq:  "AGCTTAGCATGC", saltMonovalentM: 0.05, saltDivalentM: 0, primerConcUM: 0.5,
+			wantLength: 12, wantGC: 50, wantMW: 3645.44, wantTm: 38.09,
+		},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			length, gc, mw, tm, err := Compute(tc.seq, tc.saltMonovalentM, tc.saltDivalentM, tc.primerConcUM)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if length != tc.wantLength {
+				t.Errorf("length = %d, want %d", length, tc.wantLength)
+			}
+			if gc != tc.wantGC {
+				t.Errorf("gcPercent = %v, want %v", gc, tc.wantGC)
+			}
+			if mw != tc.wantMW {
+				t.Errorf("mwDa = %v, want %v", mw, tc.wantMW)
+			}
+			if tm != tc.wantTm {
+				t.Errorf("tmC = %v, want %v", tm, tc.wantTm)
+			}
+		})
+	}
+}
+
+func TestComputeRejectsAmbiguousBases(t *testing.T) {
+	_, _, _, _, err := Compute("ACGTN", 0.05, 0, 0.25)
+	if err == nil {
+		t.Fatal("expected an error for an ambiguous base, got nil")
+	}
+}
+
+func TestComputeRejectsTooShortSequence(t *testing.T) {
+	_, _, _, _, err := Compute("A", 0.05, 0, 0.25)
+	if err == nil {
+		t.Fatal("expected an error for a 1-base sequence, got nil")
+	}
+}
+
+func TestComputeRejectsZeroConcentration(t *testing.T) {
+	_, _, _, _, err := Compute("ACGTACGT", 0.05, 0, 0)
+	if err == nil {
+		t.Fatal("expected an error for primerConcUM <= 0, got nil")
+	}
+}
@@ -0,0 +1,361 @@
+// Package render formats already-decoded values — typically a slice or
+// map a labbookCLI command unmarshaled from a server response — into one
+// of the --output formats labbookCLI commands are adopting: text (plain
+// json.Marshal, the default every command already produced before
+// --output existed), json (indented), jsonl (one compact object per
+// line, for piping a list into jq), yaml, or table (aligned columns,
+// optionally restricted to --columns). It also implements the small
+// JSONPath subset --output-filter accepts for post-filtering a result
+// independent of any server-side or RSQL filtering a command already did.
+package render
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Format is one of the --output values labbookCLI accepts.
+type Format string
+
+const (
+	Text  Format = "text"
+	JSON  Format = "json"
+	JSONL Format = "jsonl"
+	YAML  Format = "yaml"
+	Table Format = "table"
+)
+
+// ParseFormat validates --output's value, treating an empty string as
+// Text so commands that never pass --output keep their original
+// plain-JSON output.
+func ParseFormat(value string) (Format, error) {
+	switch Format(strings.ToLower(strings.TrimSpace(value))) {
+	case "":
+		return Text, nil
+	case Text:
+		return Text, nil
+	case JSON:
+		return JSON, nil
+	case JSONL:
+		return JSONL, nil
+	case YAML:
+		return YAML, nil
+	case Table:
+		return Table, nil
+	default:
+		return "", fmt.Errorf("unknown --output format %q (want text, json, jsonl, yaml, or table)", value)
+	}
+}
+
+// Render writes value to w in format. columns restricts Table's columns
+// (and their order) when non-empty, and is ignored by every other
+// format. value is typically a typed slice (e.g. []registryItem) or an
+// interface{} already decoded by encoding/json — jsonl and table
+// normalize it through a JSON round-trip first so both shapes work the
+// same way.
+func Render(w io.Writer, value interface{}, format Format, columns []string) error {
+	switch format {
+	case Text:
+		data, err := json.Marshal(value)
+		if err != nil {
+			return err
+		}
+		_, err = fmt.Fprintln(w, string(data))
+		return err
+	case JSON:
+		data, err := json.MarshalIndent(value, "", "  ")
+		if err != nil {
+			return err
+		}
+		_, err = fmt.Fprintln(w, string(data))
+		return err
+	case YAML:
+		data, err := yaml.Marshal(value)
+		if err != nil {
+			return err
+		}
+		_, err = w.Write(data)
+		return err
+	case JSONL:
+		generic, err := toGeneric(value)
+		if err != nil {
+			return err
+		}
+		return renderJSONL(w, generic)
+	case Table:
+		generic, err := toGeneric(value)
+		if err != nil {
+			return err
+		}
+		return renderTable(w, generic, columns)
+	default:
+		return fmt.Errorf("unknown output format %q", format)
+	}
+}
+
+// toGeneric round-trips value through encoding/json so callers that need
+// to inspect its shape (a slice, a map) can do so uniformly whether value
+// arrived as a typed Go value or as an interface{} from json.Unmarshal.
+func toGeneric(value interface{}) (interface{}, error) {
+	if _, ok := value.([]interface{}); ok {
+		return value, nil
+	}
+	if _, ok := value.(map[string]interface{}); ok {
+		return value, nil
+	}
+	data, err := json.Marshal(value)
+	if err != nil {
+		return nil, err
+	}
+	var generic interface{}
+	if err := json.Unmarshal(data, &generic); err != nil {
+		return nil, err
+	}
+	return generic, nil
+}
+
+func renderJSONL(w io.Writer, value interface{}) error {
+	items, ok := value.([]interface{})
+	if !ok {
+		data, err := json.Marshal(value)
+		if err != nil {
+			return err
+		}
+		_, err = fmt.Fprintln(w, string(data))
+		return err
+	}
+	for _, item := range items {
+		data, err := json.Marshal(item)
+		if err != nil {
+			return err
+		}
+		if _, err := fmt.Fprintln(w, string(data)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func renderTable(w io.Writer, value interface{}, columns []string) error {
+	items, ok := value.([]interface{})
+	if !ok {
+		if m, isMap := value.(map[string]interface{}); isMap {
+			items = []interface{}{m}
+		} else {
+			return fmt.Errorf("table output needs a list or object, got %T", value)
+		}
+	}
+
+	cols := columns
+	if len(cols) == 0 {
+		cols = inferColumns(items)
+	}
+	if len(cols) == 0 {
+		return nil
+	}
+
+	rows := make([][]string, 0, len(items))
+	for _, item := range items {
+		m, ok := item.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		row := make([]string, len(cols))
+		for i, col := range cols {
+			row[i] = formatCell(m[col])
+		}
+		rows = append(rows, row)
+	}
+
+	widths := make([]int, len(cols))
+	for i, c := range cols {
+		widths[i] = len(c)
+	}
+	for _, row := range rows {
+		for i, cell := range row {
+			if len(cell) > widths[i] {
+				widths[i] = len(cell)
+			}
+		}
+	}
+	writeRow := func(cells []string) error {
+		parts := make([]string, len(cells))
+		for i, cell := range cells {
+			parts[i] = cell + strings.Repeat(" ", widths[i]-len(cell))
+		}
+		_, err := fmt.Fprintln(w, strings.TrimRight(strings.Join(parts, "  "), " "))
+		return err
+	}
+	if err := writeRow(cols); err != nil {
+		return err
+	}
+	for _, row := range rows {
+		if err := writeRow(row); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func inferColumns(items []interface{}) []string {
+	if len(items) == 0 {
+		return nil
+	}
+	m, ok := items[0].(map[string]interface{})
+	if !ok {
+		return nil
+	}
+	cols := make([]string, 0, len(m))
+	for k := range m {
+		cols = append(cols, k)
+	}
+	sort.Strings(cols)
+	return cols
+}
+
+func formatCell(v interface{}) string {
+	switch t := v.(type) {
+	case nil:
+		return ""
+	case string:
+		return t
+	default:
+		data, err := json.Marshal(t)
+		if err != nil {
+			return fmt.Sprintf("%v", t)
+		}
+		return string(data)
+	}
+}
+
+var jsonPathPredicateRe = regexp.MustCompile(`^\[\?\(@\.([A-Za-z0-9_]+)(==|!=)(.+)\)\]$`)
+
+// FilterPath applies a small subset of JSONPath to value: root "$",
+// ".field" navigation (and, over an array, projection of that field from
+// every element), "[*]" as a no-op pass-through, and
+// "[?(@.field==\"x\")]" / "[?(@.field!=\"x\")]" equality predicates over
+// an array of objects. It's enough to cover labbookCLI's own --output-
+// filter examples (filter a list, then project a field) — not the full
+// JSONPath spec.
+func FilterPath(value interface{}, expr string) (interface{}, error) {
+	expr = strings.TrimSpace(expr)
+	if expr == "" || expr == "$" {
+		return value, nil
+	}
+	if !strings.HasPrefix(expr, "$") {
+		return nil, fmt.Errorf("jsonpath expression must start with $: %q", expr)
+	}
+
+	current, err := toGeneric(value)
+	if err != nil {
+		return nil, err
+	}
+
+	rest := expr[1:]
+	for len(rest) > 0 {
+		switch {
+		case strings.HasPrefix(rest, "."):
+			rest = rest[1:]
+			end := strings.IndexAny(rest, ".[")
+			field := rest
+			if end != -1 {
+				field = rest[:end]
+				rest = rest[end:]
+			} else {
+				rest = ""
+			}
+			if field == "" {
+				return nil, fmt.Errorf("jsonpath: empty field name in %q", expr)
+			}
+			current, err = navigateField(current, field)
+			if err != nil {
+				return nil, err
+			}
+		case strings.HasPrefix(rest, "["):
+			end := strings.Index(rest, "]")
+			if end == -1 {
+				return nil, fmt.Errorf("jsonpath: unterminated [ in %q", expr)
+			}
+			segment := rest[:end+1]
+			rest = rest[end+1:]
+			current, err = applyBracket(current, segment)
+			if err != nil {
+				return nil, err
+			}
+		default:
+			return nil, fmt.Errorf("jsonpath: unexpected token at %q", rest)
+		}
+	}
+	return current, nil
+}
+
+func navigateField(value interface{}, field string) (interface{}, error) {
+	switch v := value.(type) {
+	case map[string]interface{}:
+		return v[field], nil
+	case []interface{}:
+		projected := make([]interface{}, 0, len(v))
+		for _, item := range v {
+			m, ok := item.(map[string]interface{})
+			if !ok {
+				continue
+			}
+			projected = append(projected, m[field])
+		}
+		return projected, nil
+	default:
+		return nil, fmt.Errorf("jsonpath: cannot navigate field %q on %T", field, value)
+	}
+}
+
+func applyBracket(value interface{}, segment string) (interface{}, error) {
+	if segment == "[*]" {
+		return value, nil
+	}
+	m := jsonPathPredicateRe.FindStringSubmatch(segment)
+	if m == nil {
+		return nil, fmt.Errorf("jsonpath: unsupported bracket expression %q", segment)
+	}
+	field, op, rawValue := m[1], m[2], strings.TrimSpace(m[3])
+	want := parsePredicateValue(rawValue)
+
+	items, ok := value.([]interface{})
+	if !ok {
+		return nil, fmt.Errorf("jsonpath: %q requires an array, got %T", segment, value)
+	}
+	var filtered []interface{}
+	for _, item := range items {
+		m, ok := item.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		equal := fmt.Sprintf("%v", m[field]) == fmt.Sprintf("%v", want)
+		if (op == "==" && equal) || (op == "!=" && !equal) {
+			filtered = append(filtered, item)
+		}
+	}
+	return filtered, nil
+}
+
+func parsePredicateValue(raw string) interface{} {
+	if len(raw) >= 2 && (raw[0] == '"' || raw[0] == '\'') && raw[len(raw)-1] == raw[0] {
+		return raw[1 : len(raw)-1]
+	}
+	if n, err := strconv.ParseFloat(raw, 64); err == nil {
+		return n
+	}
+	if raw == "true" {
+		return true
+	}
+	if raw == "false" {
+		return false
+	}
+	return raw
+}
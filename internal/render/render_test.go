@@ -0,0 +1,89 @@
+package render
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+func TestParseFormat(t *testing.T) {
+	for _, value := range []string{"", "text", "JSON", "jsonl", "yaml", "Table"} {
+		if _, err := ParseFormat(value); err != nil {
+			t.Errorf("ParseFormat(%q) returned an error: %v", value, err)
+		}
+	}
+	if _, err := ParseFormat("csv"); err == nil {
+		t.Error("ParseFormat(\"csv\") expected an error, got nil")
+	}
+}
+
+func TestRenderJSONL(t *testing.T) {
+	items := []map[string]interface{}{
+		{"name": "pUC19", "kind": "Plasmid"},
+		{"name": "pET28a", "kind": "Plasmid"},
+	}
+	var buf bytes.Buffer
+	if err := Render(&buf, items, JSONL, nil); err != nil {
+		t.Fatalf("Render: %v", err)
+	}
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	if len(lines) != 2 {
+		t.Fatalf("expected 2 lines, got %d: %q", len(lines), buf.String())
+	}
+	if !strings.Contains(lines[0], "pUC19") || !strings.Contains(lines[1], "pET28a") {
+		t.Errorf("unexpected jsonl output: %q", buf.String())
+	}
+}
+
+func TestRenderTable(t *testing.T) {
+	items := []map[string]interface{}{
+		{"name": "pUC19", "kind": "Plasmid"},
+		{"name": "pET28a-long-name", "kind": "Plasmid"},
+	}
+	var buf bytes.Buffer
+	if err := Render(&buf, items, Table, []string{"name", "kind"}); err != nil {
+		t.Fatalf("Render: %v", err)
+	}
+	lines := strings.Split(strings.TrimRight(buf.String(), "\n"), "\n")
+	if len(lines) != 3 {
+		t.Fatalf("expected a header row plus 2 data rows, got %d: %q", len(lines), buf.String())
+	}
+	if !strings.HasPrefix(lines[0], "name") {
+		t.Errorf("expected header row to start with the name column, got %q", lines[0])
+	}
+	if len(lines[1]) != len(lines[2]) {
+		t.Errorf("expected aligned rows of equal width, got %q and %q", lines[1], lines[2])
+	}
+}
+
+func TestFilterPathPredicateAndProjection(t *testing.T) {
+	items := []map[string]interface{}{
+		{"name": "pUC19", "kind": "Plasmid"},
+		{"name": "Tris buffer", "kind": "Reagent"},
+	}
+	result, err := FilterPath(items, `$[?(@.kind=="Reagent")].name`)
+	if err != nil {
+		t.Fatalf("FilterPath: %v", err)
+	}
+	names, ok := result.([]interface{})
+	if !ok || len(names) != 1 || names[0] != "Tris buffer" {
+		t.Errorf("expected [\"Tris buffer\"], got %#v", result)
+	}
+}
+
+func TestFilterPathRootIsNoop(t *testing.T) {
+	items := []map[string]interface{}{{"name": "pUC19"}}
+	result, err := FilterPath(items, "$")
+	if err != nil {
+		t.Fatalf("FilterPath: %v", err)
+	}
+	if _, ok := result.([]map[string]interface{}); !ok {
+		t.Errorf("expected FilterPath(\"$\") to return value unchanged, got %#v", result)
+	}
+}
+
+func TestFilterPathRejectsBadExpression(t *testing.T) {
+	if _, err := FilterPath([]interface{}{}, "kind==Plasmid"); err == nil {
+		t.Error("expected an error for an expression missing the leading $, got nil")
+	}
+}
@@ -0,0 +1,221 @@
+package rsql
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Resolver looks up the value of a dotted field path (e.g.
+// "metadata.resistance") against whatever item Eval is being run for. ok is
+// false when the path doesn't resolve to anything, which Eval treats as a
+// non-match except for OpNE and OpOUT.
+type Resolver func(field string) (value interface{}, ok bool)
+
+// Eval evaluates node against resolve, returning whether the item it
+// resolves fields against matches the expression.
+func Eval(node Node, resolve Resolver) bool {
+	switch n := node.(type) {
+	case *AndNode:
+		return Eval(n.Left, resolve) && Eval(n.Right, resolve)
+	case *OrNode:
+		return Eval(n.Left, resolve) || Eval(n.Right, resolve)
+	case *CmpNode:
+		return evalCmp(n, resolve)
+	default:
+		return false
+	}
+}
+
+func evalCmp(n *CmpNode, resolve Resolver) bool {
+	got, ok := resolve(n.Field)
+	if !ok {
+		return n.Op == OpNE || n.Op == OpOUT
+	}
+	switch n.Op {
+	case OpEQ:
+		return len(n.Values) == 1 && valuesEqual(got, n.Values[0])
+	case OpNE:
+		return !(len(n.Values) == 1 && valuesEqual(got, n.Values[0]))
+	case OpIN:
+		return anyEqual(got, n.Values)
+	case OpOUT:
+		return !anyEqual(got, n.Values)
+	case OpLIKE:
+		return len(n.Values) == 1 && matchLike(fmt.Sprintf("%v", got), n.Values[0])
+	case OpGT, OpGE, OpLT, OpLE:
+		if len(n.Values) != 1 {
+			return false
+		}
+		cmp, ok := compareOrdered(got, n.Values[0])
+		if !ok {
+			return false
+		}
+		switch n.Op {
+		case OpGT:
+			return cmp > 0
+		case OpGE:
+			return cmp >= 0
+		case OpLT:
+			return cmp < 0
+		case OpLE:
+			return cmp <= 0
+		}
+	}
+	return false
+}
+
+func anyEqual(got interface{}, values []string) bool {
+	for _, v := range values {
+		if valuesEqual(got, v) {
+			return true
+		}
+	}
+	return false
+}
+
+// valuesEqual compares got (a resolved field value, typically a string,
+// float64, bool, or []interface{} from decoded JSON) against a raw RSQL
+// value string, preferring a numeric or case-insensitive string comparison
+// as appropriate, and matching if any element of a slice value equals v.
+func valuesEqual(got interface{}, v string) bool {
+	switch g := got.(type) {
+	case []interface{}:
+		for _, elem := range g {
+			if valuesEqual(elem, v) {
+				return true
+			}
+		}
+		return false
+	case []string:
+		for _, elem := range g {
+			if strings.EqualFold(elem, v) {
+				return true
+			}
+		}
+		return false
+	case float64:
+		f, err := strconv.ParseFloat(v, 64)
+		return err == nil && g == f
+	case bool:
+		b, err := strconv.ParseBool(v)
+		return err == nil && g == b
+	case nil:
+		return strings.EqualFold(v, "null") || v == ""
+	default:
+		return strings.EqualFold(fmt.Sprintf("%v", g), v)
+	}
+}
+
+// matchLike implements a simple SQL-LIKE-style match where '%' stands for
+// any run of characters; comparison is case-insensitive.
+func matchLike(s, pattern string) bool {
+	s = strings.ToLower(s)
+	pattern = strings.ToLower(pattern)
+	parts := strings.Split(pattern, "%")
+	if len(parts) == 1 {
+		return s == pattern
+	}
+	pos := 0
+	for i, part := range parts {
+		if part == "" {
+			continue
+		}
+		idx := strings.Index(s[pos:], part)
+		if idx < 0 {
+			return false
+		}
+		if i == 0 && idx != 0 {
+			return false
+		}
+		pos += idx + len(part)
+	}
+	lastPart := parts[len(parts)-1]
+	return lastPart == "" || strings.HasSuffix(s, lastPart)
+}
+
+// compareOrdered compares got against the RSQL value string v, trying a
+// numeric comparison first, then a timestamp comparison (RFC 3339 or a bare
+// "2006-01-02" date), and finally falling back to a lexicographic string
+// comparison. ok is false only when got itself can't be turned into any
+// comparable form.
+func compareOrdered(got interface{}, v string) (int, bool) {
+	if gf, ok := toFloat(got); ok {
+		if vf, err := strconv.ParseFloat(v, 64); err == nil {
+			return cmpFloat(gf, vf), true
+		}
+	}
+	if gt, ok := toTime(got); ok {
+		if vt, ok := parseTime(v); ok {
+			return cmpTime(gt, vt), true
+		}
+	}
+	gs, ok := toString(got)
+	if !ok {
+		return 0, false
+	}
+	return strings.Compare(gs, v), true
+}
+
+func toFloat(v interface{}) (float64, bool) {
+	switch g := v.(type) {
+	case float64:
+		return g, true
+	case int:
+		return float64(g), true
+	case string:
+		f, err := strconv.ParseFloat(g, 64)
+		return f, err == nil
+	}
+	return 0, false
+}
+
+func toTime(v interface{}) (time.Time, bool) {
+	switch g := v.(type) {
+	case time.Time:
+		return g, true
+	case string:
+		return parseTime(g)
+	}
+	return time.Time{}, false
+}
+
+func parseTime(s string) (time.Time, bool) {
+	for _, layout := range []string{time.RFC3339, "2006-01-02T15:04:05", "2006-01-02"} {
+		if t, err := time.Parse(layout, s); err == nil {
+			return t, true
+		}
+	}
+	return time.Time{}, false
+}
+
+func toString(v interface{}) (string, bool) {
+	switch v.(type) {
+	case string, float64, int, bool:
+		return fmt.Sprintf("%v", v), true
+	}
+	return "", false
+}
+
+func cmpFloat(a, b float64) int {
+	switch {
+	case a < b:
+		return -1
+	case a > b:
+		return 1
+	default:
+		return 0
+	}
+}
+
+func cmpTime(a, b time.Time) int {
+	switch {
+	case a.Before(b):
+		return -1
+	case a.After(b):
+		return 1
+	default:
+		return 0
+	}
+}
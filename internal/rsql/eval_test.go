@@ -0,0 +1,96 @@
+package rsql
+
+import "testing"
+
+// testItem is a stand-in for the registry/entry JSON items the CLI
+// evaluates filters against: a flat map keyed by the full dotted field path
+// (e.g. "metadata.resistance"), with "tags" as a list.
+type testItem map[string]interface{}
+
+func resolverFor(item testItem) Resolver {
+	return func(field string) (interface{}, bool) {
+		v, ok := item[field]
+		return v, ok
+	}
+}
+
+func mustParse(t *testing.T, expr string) Node {
+	t.Helper()
+	node, err := Parse(expr)
+	if err != nil {
+		t.Fatalf("Parse(%q): %v", expr, err)
+	}
+	return node
+}
+
+func TestEvalEquality(t *testing.T) {
+	node := mustParse(t, `kind==Plasmid`)
+	if !Eval(node, resolverFor(testItem{"kind": "Plasmid"})) {
+		t.Fatal("expected match")
+	}
+	if Eval(node, resolverFor(testItem{"kind": "Protein"})) {
+		t.Fatal("expected no match")
+	}
+}
+
+func TestEvalAndOr(t *testing.T) {
+	node := mustParse(t, `kind==Plasmid;tags=in=(cryo,em),project=="X"`)
+	// kind matches, tags doesn't -> AND fails, but project matches -> OR succeeds.
+	item := testItem{"kind": "Plasmid", "tags": []interface{}{"other"}, "project": "X"}
+	if !Eval(node, resolverFor(item)) {
+		t.Fatal("expected OR branch to match")
+	}
+	item2 := testItem{"kind": "Protein", "tags": []interface{}{"other"}, "project": "Y"}
+	if Eval(node, resolverFor(item2)) {
+		t.Fatal("expected no match")
+	}
+}
+
+func TestEvalInOutList(t *testing.T) {
+	in := mustParse(t, `metadata.resistance=in=(kan,amp)`)
+	out := mustParse(t, `metadata.resistance=out=(kan,amp)`)
+	item := testItem{"metadata.resistance": "amp"}
+	if !Eval(in, resolverFor(item)) {
+		t.Fatal("expected =in= match")
+	}
+	if Eval(out, resolverFor(item)) {
+		t.Fatal("expected =out= to exclude a listed value")
+	}
+	missing := testItem{}
+	if Eval(in, resolverFor(missing)) {
+		t.Fatal("expected =in= to not match a missing field")
+	}
+	if !Eval(out, resolverFor(missing)) {
+		t.Fatal("expected =out= to match a missing field")
+	}
+}
+
+func TestEvalNumericComparison(t *testing.T) {
+	gt := mustParse(t, `metadata.concentration=gt=5`)
+	if !Eval(gt, resolverFor(testItem{"metadata.concentration": float64(10)})) {
+		t.Fatal("expected 10 =gt= 5 to match")
+	}
+	if Eval(gt, resolverFor(testItem{"metadata.concentration": float64(2)})) {
+		t.Fatal("expected 2 =gt= 5 to not match")
+	}
+}
+
+func TestEvalDateComparison(t *testing.T) {
+	node := mustParse(t, `createdAt=gt=2024-01-01`)
+	if !Eval(node, resolverFor(testItem{"createdAt": "2024-06-15T00:00:00Z"})) {
+		t.Fatal("expected later date to match")
+	}
+	if Eval(node, resolverFor(testItem{"createdAt": "2023-01-01T00:00:00Z"})) {
+		t.Fatal("expected earlier date to not match")
+	}
+}
+
+func TestEvalLike(t *testing.T) {
+	node := mustParse(t, `name=like=pET%`)
+	if !Eval(node, resolverFor(testItem{"name": "pET28a"})) {
+		t.Fatal("expected prefix match")
+	}
+	if Eval(node, resolverFor(testItem{"name": "pUC19"})) {
+		t.Fatal("expected no match")
+	}
+}
@@ -0,0 +1,126 @@
+package rsql
+
+import "strings"
+
+type tokenKind int
+
+const (
+	tokEOF    tokenKind = iota
+	tokIdent            // bare field name or unquoted value atom
+	tokString           // single- or double-quoted value
+	tokOp               // ==, !=, =gt=, =lt=, =ge=, =le=, =in=, =out=, =like=
+	tokLParen
+	tokRParen
+	tokAnd // ;
+	tokOr  // ,
+)
+
+type token struct {
+	kind tokenKind
+	text string
+	pos  int
+}
+
+// operators lists every recognized operator literal. Checking each against
+// the remaining input as an exact prefix (rather than picking a "longest
+// match" order) is unambiguous here since no operator is itself a prefix of
+// another's characters at the same position except where the full literal
+// also matches, so plain iteration order doesn't matter.
+var operators = []string{"=like=", "=out=", "=in=", "=ge=", "=le=", "=gt=", "=lt=", "==", "!="}
+
+// identChar reports whether r may appear in a bare field name or unquoted
+// value: letters, digits, and the punctuation dotted paths and common atoms
+// use (., _, -, :, /, +, %).
+func identChar(r byte) bool {
+	switch {
+	case r >= 'a' && r <= 'z', r >= 'A' && r <= 'Z', r >= '0' && r <= '9':
+		return true
+	case r == '.' || r == '_' || r == '-' || r == ':' || r == '/' || r == '+' || r == '%':
+		return true
+	}
+	return false
+}
+
+// lex tokenizes an RSQL expression. It does not distinguish field names from
+// value atoms — both lex as tokIdent — since that depends on grammar
+// position, which the parser resolves.
+func lex(expr string) ([]token, error) {
+	var toks []token
+	i := 0
+	for i < len(expr) {
+		c := expr[i]
+		switch {
+		case c == ' ' || c == '\t' || c == '\n' || c == '\r':
+			i++
+		case c == '(':
+			toks = append(toks, token{tokLParen, "(", i})
+			i++
+		case c == ')':
+			toks = append(toks, token{tokRParen, ")", i})
+			i++
+		case c == ';':
+			toks = append(toks, token{tokAnd, ";", i})
+			i++
+		case c == ',':
+			toks = append(toks, token{tokOr, ",", i})
+			i++
+		case c == '\'' || c == '"':
+			s, n, err := lexQuoted(expr, i)
+			if err != nil {
+				return nil, err
+			}
+			toks = append(toks, token{tokString, s, i})
+			i = n
+		case c == '=' || c == '!':
+			op, ok := matchOperator(expr, i)
+			if !ok {
+				return nil, &ParseError{Pos: i, Msg: "unrecognized operator starting with " + string(c)}
+			}
+			toks = append(toks, token{tokOp, op, i})
+			i += len(op)
+		case identChar(c):
+			start := i
+			for i < len(expr) && identChar(expr[i]) {
+				i++
+			}
+			toks = append(toks, token{tokIdent, expr[start:i], start})
+		default:
+			return nil, &ParseError{Pos: i, Msg: "unexpected character " + string(c)}
+		}
+	}
+	toks = append(toks, token{tokEOF, "", len(expr)})
+	return toks, nil
+}
+
+func matchOperator(expr string, pos int) (string, bool) {
+	rest := expr[pos:]
+	for _, op := range operators {
+		if strings.HasPrefix(rest, op) {
+			return op, true
+		}
+	}
+	return "", false
+}
+
+// lexQuoted scans a quoted string starting at expr[start] (the opening
+// quote), returning its decoded content and the index just past the closing
+// quote. A doubled quote (two single quotes, or two double quotes) is an
+// escaped literal quote character.
+func lexQuoted(expr string, start int) (string, int, error) {
+	quote := expr[start]
+	var b strings.Builder
+	i := start + 1
+	for i < len(expr) {
+		if expr[i] == quote {
+			if i+1 < len(expr) && expr[i+1] == quote {
+				b.WriteByte(quote)
+				i += 2
+				continue
+			}
+			return b.String(), i + 1, nil
+		}
+		b.WriteByte(expr[i])
+		i++
+	}
+	return "", 0, &ParseError{Pos: start, Msg: "unterminated quoted value"}
+}
@@ -0,0 +1,135 @@
+package rsql
+
+// parser consumes the token stream produced by lex and builds an AST.
+// Precedence, loosest to tightest: OrNode (','), AndNode (';'), then a
+// parenthesized group or a single CmpNode.
+type parser struct {
+	tokens []token
+	pos    int
+}
+
+func (p *parser) peek() token {
+	return p.tokens[p.pos]
+}
+
+func (p *parser) next() token {
+	tok := p.tokens[p.pos]
+	if p.pos < len(p.tokens)-1 {
+		p.pos++
+	}
+	return tok
+}
+
+func (p *parser) expect(kind tokenKind, what string) (token, error) {
+	tok := p.next()
+	if tok.kind != kind {
+		return tok, &ParseError{Pos: tok.pos, Msg: "expected " + what + ", got " + describeToken(tok)}
+	}
+	return tok, nil
+}
+
+func describeToken(tok token) string {
+	if tok.kind == tokEOF {
+		return "end of expression"
+	}
+	return "\"" + tok.text + "\""
+}
+
+func (p *parser) parseOr() (Node, error) {
+	left, err := p.parseAnd()
+	if err != nil {
+		return nil, err
+	}
+	for p.peek().kind == tokOr {
+		p.next()
+		right, err := p.parseAnd()
+		if err != nil {
+			return nil, err
+		}
+		left = &OrNode{Left: left, Right: right}
+	}
+	return left, nil
+}
+
+func (p *parser) parseAnd() (Node, error) {
+	left, err := p.parsePrimary()
+	if err != nil {
+		return nil, err
+	}
+	for p.peek().kind == tokAnd {
+		p.next()
+		right, err := p.parsePrimary()
+		if err != nil {
+			return nil, err
+		}
+		left = &AndNode{Left: left, Right: right}
+	}
+	return left, nil
+}
+
+func (p *parser) parsePrimary() (Node, error) {
+	if p.peek().kind == tokLParen {
+		p.next()
+		node, err := p.parseOr()
+		if err != nil {
+			return nil, err
+		}
+		if _, err := p.expect(tokRParen, "')'"); err != nil {
+			return nil, err
+		}
+		return node, nil
+	}
+	return p.parseComparison()
+}
+
+func (p *parser) parseComparison() (Node, error) {
+	field, err := p.expect(tokIdent, "a field name")
+	if err != nil {
+		return nil, err
+	}
+	opTok, err := p.expect(tokOp, "a comparison operator")
+	if err != nil {
+		return nil, err
+	}
+	values, err := p.parseValues()
+	if err != nil {
+		return nil, err
+	}
+	return &CmpNode{Field: field.text, Op: Op(opTok.text), Values: values}, nil
+}
+
+func (p *parser) parseValues() ([]string, error) {
+	if p.peek().kind != tokLParen {
+		v, err := p.parseValue()
+		if err != nil {
+			return nil, err
+		}
+		return []string{v}, nil
+	}
+	p.next() // '('
+	var values []string
+	for {
+		v, err := p.parseValue()
+		if err != nil {
+			return nil, err
+		}
+		values = append(values, v)
+		if p.peek().kind == tokOr {
+			p.next()
+			continue
+		}
+		break
+	}
+	if _, err := p.expect(tokRParen, "')'"); err != nil {
+		return nil, err
+	}
+	return values, nil
+}
+
+func (p *parser) parseValue() (string, error) {
+	tok := p.next()
+	if tok.kind != tokIdent && tok.kind != tokString {
+		return "", &ParseError{Pos: tok.pos, Msg: "expected a value, got " + describeToken(tok)}
+	}
+	return tok.text, nil
+}
@@ -0,0 +1,125 @@
+package rsql
+
+import "testing"
+
+func TestParseSimpleComparison(t *testing.T) {
+	node, err := Parse(`kind==Plasmid`)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	cmp, ok := node.(*CmpNode)
+	if !ok {
+		t.Fatalf("expected *CmpNode, got %T", node)
+	}
+	if cmp.Field != "kind" || cmp.Op != OpEQ || len(cmp.Values) != 1 || cmp.Values[0] != "Plasmid" {
+		t.Fatalf("unexpected node: %+v", cmp)
+	}
+}
+
+func TestParseAndBindsTighterThanOr(t *testing.T) {
+	// "a;b,c" must parse as (a AND b) OR c, not a AND (b OR c).
+	node, err := Parse(`kind==Plasmid;project==X,tag==cryo`)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	or, ok := node.(*OrNode)
+	if !ok {
+		t.Fatalf("expected top-level *OrNode, got %T", node)
+	}
+	and, ok := or.Left.(*AndNode)
+	if !ok {
+		t.Fatalf("expected *AndNode on the left of OR, got %T", or.Left)
+	}
+	if left, ok := and.Left.(*CmpNode); !ok || left.Field != "kind" {
+		t.Fatalf("unexpected AND left operand: %+v", and.Left)
+	}
+	if right, ok := and.Right.(*CmpNode); !ok || right.Field != "project" {
+		t.Fatalf("unexpected AND right operand: %+v", and.Right)
+	}
+	if right, ok := or.Right.(*CmpNode); !ok || right.Field != "tag" {
+		t.Fatalf("unexpected OR right operand: %+v", or.Right)
+	}
+}
+
+func TestParseParenGroupOverridesPrecedence(t *testing.T) {
+	// With explicit grouping, "a;(b,c)" is a AND (b OR c).
+	node, err := Parse(`kind==Plasmid;(project==X,project==Y)`)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	and, ok := node.(*AndNode)
+	if !ok {
+		t.Fatalf("expected top-level *AndNode, got %T", node)
+	}
+	if _, ok := and.Right.(*OrNode); !ok {
+		t.Fatalf("expected grouped OR on the right of AND, got %T", and.Right)
+	}
+}
+
+func TestParseInList(t *testing.T) {
+	node, err := Parse(`metadata.resistance=in=(kan,amp)`)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	cmp := node.(*CmpNode)
+	if cmp.Field != "metadata.resistance" || cmp.Op != OpIN {
+		t.Fatalf("unexpected node: %+v", cmp)
+	}
+	if len(cmp.Values) != 2 || cmp.Values[0] != "kan" || cmp.Values[1] != "amp" {
+		t.Fatalf("unexpected values: %v", cmp.Values)
+	}
+}
+
+func TestParseQuotedValueWithComma(t *testing.T) {
+	// A comma inside a quoted value must not be treated as the OR operator.
+	node, err := Parse(`project=="Smith, J."`)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	cmp := node.(*CmpNode)
+	if len(cmp.Values) != 1 || cmp.Values[0] != "Smith, J." {
+		t.Fatalf("unexpected values: %v", cmp.Values)
+	}
+}
+
+func TestParseAllOperators(t *testing.T) {
+	cases := []struct {
+		expr string
+		op   Op
+	}{
+		{"a==1", OpEQ},
+		{"a!=1", OpNE},
+		{"a=gt=1", OpGT},
+		{"a=lt=1", OpLT},
+		{"a=ge=1", OpGE},
+		{"a=le=1", OpLE},
+		{"a=like=1", OpLIKE},
+	}
+	for _, tc := range cases {
+		node, err := Parse(tc.expr)
+		if err != nil {
+			t.Fatalf("%s: unexpected error: %v", tc.expr, err)
+		}
+		cmp := node.(*CmpNode)
+		if cmp.Op != tc.op {
+			t.Fatalf("%s: expected op %s, got %s", tc.expr, tc.op, cmp.Op)
+		}
+	}
+}
+
+func TestParseErrors(t *testing.T) {
+	cases := []string{
+		"",
+		"kind==",
+		"==Plasmid",
+		"kind=Plasmid",
+		"kind==Plasmid;",
+		"(kind==Plasmid",
+		`kind=="unterminated`,
+	}
+	for _, expr := range cases {
+		if _, err := Parse(expr); err == nil {
+			t.Fatalf("%q: expected an error", expr)
+		}
+	}
+}
@@ -0,0 +1,88 @@
+// Package rsql implements a small, dependency-free parser and evaluator
+// for an RSQL/FIQL-style filter expression, e.g.
+//
+//	kind==Plasmid;metadata.resistance=in=(kan,amp);createdAt=gt=2024-01-01
+//
+// It is used by labbookCLI to let scripted callers express ad-hoc queries
+// against registry/entry metadata that the server's fixed set of query
+// parameters doesn't cover.
+package rsql
+
+import "fmt"
+
+// Op is an RSQL comparison operator.
+type Op string
+
+// The operators recognized by Parse.
+const (
+	OpEQ   Op = "=="
+	OpNE   Op = "!="
+	OpGT   Op = "=gt="
+	OpLT   Op = "=lt="
+	OpGE   Op = "=ge="
+	OpLE   Op = "=le="
+	OpIN   Op = "=in="
+	OpOUT  Op = "=out="
+	OpLIKE Op = "=like="
+)
+
+// Node is one term of a parsed RSQL expression: an AndNode, OrNode, or
+// CmpNode. String reserializes it back into RSQL text (see CmpNode.String).
+type Node interface {
+	node()
+	String() string
+}
+
+// AndNode is the RSQL ';' operator: both sides must hold.
+type AndNode struct {
+	Left, Right Node
+}
+
+// OrNode is the RSQL ',' operator: either side may hold. OrNode binds more
+// loosely than AndNode, so "a;b,c" parses as (a;b),c.
+type OrNode struct {
+	Left, Right Node
+}
+
+// CmpNode is a single field comparison, e.g. "metadata.resistance=in=(kan,amp)".
+// Values holds a single value, or the entries of a parenthesized list. Parse
+// accepts a list after any operator; Eval matches a list only for OpIN and
+// OpOUT, and treats every other operator with more than one value as a
+// non-match.
+type CmpNode struct {
+	Field  string
+	Op     Op
+	Values []string
+}
+
+func (*AndNode) node() {}
+func (*OrNode) node()  {}
+func (*CmpNode) node() {}
+
+// Parse parses an RSQL expression into an AST.
+func Parse(expr string) (Node, error) {
+	toks, err := lex(expr)
+	if err != nil {
+		return nil, err
+	}
+	p := &parser{tokens: toks}
+	node, err := p.parseOr()
+	if err != nil {
+		return nil, err
+	}
+	if tok := p.peek(); tok.kind != tokEOF {
+		return nil, &ParseError{Pos: tok.pos, Msg: "unexpected trailing input: " + tok.text}
+	}
+	return node, nil
+}
+
+// ParseError reports a lexing or parsing failure together with the byte
+// offset into the original expression where it was detected.
+type ParseError struct {
+	Pos int
+	Msg string
+}
+
+func (e *ParseError) Error() string {
+	return fmt.Sprintf("rsql: %s (at offset %d)", e.Msg, e.Pos)
+}
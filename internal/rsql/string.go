@@ -0,0 +1,59 @@
+package rsql
+
+import "strings"
+
+// String reserializes the node back into RSQL text, e.g. for forwarding a
+// reduced expression on to a server as a single "filter" query parameter.
+// It is not guaranteed to reproduce the original expression verbatim (value
+// quoting style and redundant parens aren't preserved), only an equivalent
+// one.
+func (n *CmpNode) String() string {
+	var b strings.Builder
+	b.WriteString(n.Field)
+	b.WriteString(string(n.Op))
+	if len(n.Values) == 1 {
+		b.WriteString(quoteValue(n.Values[0]))
+		return b.String()
+	}
+	b.WriteByte('(')
+	for i, v := range n.Values {
+		if i > 0 {
+			b.WriteByte(',')
+		}
+		b.WriteString(quoteValue(v))
+	}
+	b.WriteByte(')')
+	return b.String()
+}
+
+func (n *OrNode) String() string {
+	return n.Left.String() + "," + n.Right.String()
+}
+
+func (n *AndNode) String() string {
+	return andOperand(n.Left) + ";" + andOperand(n.Right)
+}
+
+// andOperand parenthesizes n when it's an OrNode, since ',' binds looser
+// than ';' and would otherwise change meaning once embedded in an AND chain.
+func andOperand(n Node) string {
+	s := n.String()
+	if _, ok := n.(*OrNode); ok {
+		return "(" + s + ")"
+	}
+	return s
+}
+
+// quoteValue single-quotes v (doubling embedded quotes) when it contains
+// characters that would otherwise be lexed as structure rather than value
+// content.
+func quoteValue(v string) string {
+	plain := v != ""
+	for i := 0; i < len(v) && plain; i++ {
+		plain = identChar(v[i])
+	}
+	if plain {
+		return v
+	}
+	return "'" + strings.ReplaceAll(v, "'", "''") + "'"
+}
@@ -0,0 +1,49 @@
+package seqio
+
+import (
+	"fmt"
+	"strings"
+)
+
+// FastaRecord is one ">id description\nSEQUENCE..." entry.
+type FastaRecord struct {
+	ID          string
+	Description string
+	Sequence    string
+}
+
+// ParseFasta parses one or more FASTA records from data.
+func ParseFasta(data string) ([]FastaRecord, error) {
+	var records []FastaRecord
+	var cur *FastaRecord
+	var seq strings.Builder
+
+	flush := func() {
+		if cur != nil {
+			cur.Sequence = strings.ToUpper(seq.String())
+			records = append(records, *cur)
+		}
+		seq.Reset()
+	}
+
+	for _, line := range strings.Split(data, "\n") {
+		line = strings.TrimRight(line, "\r")
+		if strings.TrimSpace(line) == "" {
+			continue
+		}
+		if strings.HasPrefix(line, ">") {
+			flush()
+			header := strings.TrimPrefix(line, ">")
+			id, description, _ := strings.Cut(header, " ")
+			cur = &FastaRecord{ID: id, Description: strings.TrimSpace(description)}
+			continue
+		}
+		seq.WriteString(strings.TrimSpace(line))
+	}
+	flush()
+
+	if len(records) == 0 {
+		return nil, fmt.Errorf("seqio: no FASTA records found")
+	}
+	return records, nil
+}
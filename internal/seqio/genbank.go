@@ -0,0 +1,228 @@
+// Package seqio provides minimal parsers for the sequence file formats
+// labbookCLI's "registry import" needs to read: GenBank flat files and
+// FASTA. Neither parser aims to be a complete implementation of its
+// format — each covers exactly the sections a plasmid/primer registry
+// import needs (LOCUS/DEFINITION/ACCESSION/KEYWORDS/FEATURES/ORIGIN for
+// GenBank; header + sequence for FASTA). Other GenBank sections are
+// skipped, and ParseGenBank errors on a record that doesn't start with a
+// LOCUS line. FASTA sequence lines before the first header are dropped.
+package seqio
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+// Feature is one FEATURES table entry (e.g. a CDS, misc_feature, or
+// rep_origin), with its qualifiers (/gene=, /product=, /note=, ...) keyed
+// by name; the order of different qualifiers is not kept. A qualifier
+// repeated within one feature keeps every value in file order; Qualifier
+// returns just the first.
+type Feature struct {
+	Type       string
+	Location   string
+	Qualifiers map[string][]string
+}
+
+// Qualifier returns the first value recorded for key, or "" if the
+// feature has no such qualifier.
+func (f Feature) Qualifier(key string) string {
+	if vs := f.Qualifiers[key]; len(vs) > 0 {
+		return vs[0]
+	}
+	return ""
+}
+
+// GenBankRecord is one LOCUS...// entry of a (possibly multi-record)
+// GenBank flat file.
+type GenBankRecord struct {
+	Locus      string
+	Definition string
+	Accession  string
+	Keywords   string
+	Features   []Feature
+	// Sequence is the ORIGIN block's bases, joined and upper-cased.
+	Sequence string
+}
+
+// ParseGenBank parses one or more "LOCUS ... // " records concatenated in
+// data, in the order they appear.
+func ParseGenBank(data string) ([]GenBankRecord, error) {
+	lines := strings.Split(data, "\n")
+	var records []GenBankRecord
+
+	i := 0
+	for i < len(lines) && strings.TrimSpace(lines[i]) == "" {
+		i++
+	}
+	for i < len(lines) {
+		if strings.TrimSpace(lines[i]) == "" {
+			i++
+			continue
+		}
+		if !strings.HasPrefix(lines[i], "LOCUS") {
+			return nil, fmt.Errorf("seqio: expected a LOCUS line, got %q", lines[i])
+		}
+		rec, next, err := parseOneRecord(lines, i)
+		if err != nil {
+			return nil, err
+		}
+		records = append(records, rec)
+		i = next
+	}
+	if len(records) == 0 {
+		return nil, fmt.Errorf("seqio: no LOCUS records found")
+	}
+	return records, nil
+}
+
+func parseOneRecord(lines []string, start int) (GenBankRecord, int, error) {
+	rec := GenBankRecord{}
+	if fields := strings.Fields(lines[start]); len(fields) >= 2 {
+		rec.Locus = fields[1]
+	}
+
+	var curFeature *Feature
+	curQualKey := ""
+	inOrigin := false
+	var seq strings.Builder
+
+	i := start + 1
+	for ; i < len(lines); i++ {
+		line := lines[i]
+		trimmed := strings.TrimSpace(line)
+
+		if trimmed == "//" {
+			i++
+			break
+		}
+		flushFeature := func() {
+			if curFeature != nil {
+				rec.Features = append(rec.Features, *curFeature)
+				curFeature = nil
+			}
+			curQualKey = ""
+		}
+
+		switch {
+		case strings.HasPrefix(line, "DEFINITION"):
+			rec.Definition = strings.TrimSuffix(strings.TrimSpace(strings.TrimPrefix(line, "DEFINITION")), ".")
+			flushFeature()
+		case strings.HasPrefix(line, "ACCESSION"):
+			rec.Accession = strings.TrimSpace(strings.TrimPrefix(line, "ACCESSION"))
+			flushFeature()
+		case strings.HasPrefix(line, "KEYWORDS"):
+			rec.Keywords = strings.TrimSpace(strings.TrimPrefix(line, "KEYWORDS"))
+			flushFeature()
+		case strings.HasPrefix(line, "FEATURES"):
+			flushFeature()
+		case strings.HasPrefix(line, "ORIGIN"):
+			inOrigin = true
+			flushFeature()
+		case inOrigin:
+			seq.WriteString(originBases(line))
+		case isFeatureLine(line):
+			flushFeature()
+			fields := strings.Fields(trimmed)
+			f := Feature{Qualifiers: map[string][]string{}}
+			if len(fields) > 0 {
+				f.Type = fields[0]
+			}
+			if len(fields) > 1 {
+				f.Location = fields[1]
+			}
+			curFeature = &f
+			curQualKey = ""
+		case curFeature != nil && strings.HasPrefix(trimmed, "/"):
+			key, value, _ := strings.Cut(strings.TrimPrefix(trimmed, "/"), "=")
+			value = strings.Trim(value, `"`)
+			curFeature.Qualifiers[key] = append(curFeature.Qualifiers[key], value)
+			curQualKey = key
+		case curFeature != nil && curQualKey != "" && trimmed != "":
+			// A qualifier value wrapped onto a continuation line.
+			vs := curFeature.Qualifiers[curQualKey]
+			if len(vs) > 0 {
+				vs[len(vs)-1] = strings.TrimSuffix(vs[len(vs)-1]+" "+trimmed, `"`)
+				curFeature.Qualifiers[curQualKey] = vs
+			}
+		}
+	}
+	if curFeature != nil {
+		rec.Features = append(rec.Features, *curFeature)
+	}
+	rec.Sequence = seq.String()
+	return rec, i, nil
+}
+
+// isFeatureLine reports whether line starts a new FEATURES table entry:
+// indented by exactly 5 columns (GenBank's fixed column layout) with a
+// feature key in column 6, as opposed to a qualifier line (indented to
+// column 22) or a continuation line.
+func isFeatureLine(line string) bool {
+	if len(line) < 6 {
+		return false
+	}
+	for _, r := range line[:5] {
+		if r != ' ' {
+			return false
+		}
+	}
+	return line[5] != ' '
+}
+
+// ExtractRegion returns the nucleotides that location selects out of
+// sequence (reverse-complementing if location is wrapped in
+// complement(...)). It supports exactly the location forms a registry
+// import's CDS lookup needs — a plain "start..end" range, optionally
+// wrapped in complement(...), with partial-range markers (<, >) ignored —
+// and errors on anything more exotic (join, order, remote references), which
+// the caller should treat as "translation not attempted" rather than guess.
+func ExtractRegion(sequence, location string) (string, error) {
+	loc := strings.TrimSpace(location)
+	complement := false
+	if strings.HasPrefix(loc, "complement(") && strings.HasSuffix(loc, ")") {
+		complement = true
+		loc = strings.TrimSuffix(strings.TrimPrefix(loc, "complement("), ")")
+	}
+	startField, endField, ok := strings.Cut(loc, "..")
+	if !ok {
+		return "", fmt.Errorf("seqio: unsupported location %q", location)
+	}
+	start, err1 := strconv.Atoi(strings.Trim(startField, "<>"))
+	end, err2 := strconv.Atoi(strings.Trim(endField, "<>"))
+	if err1 != nil || err2 != nil || start < 1 || end > len(sequence) || start > end {
+		return "", fmt.Errorf("seqio: invalid location %q for a %d-base sequence", location, len(sequence))
+	}
+	region := sequence[start-1 : end]
+	if complement {
+		region = reverseComplement(region)
+	}
+	return region, nil
+}
+
+var genbankComplement = map[byte]byte{'A': 'T', 'T': 'A', 'C': 'G', 'G': 'C', 'N': 'N'}
+
+func reverseComplement(seq string) string {
+	out := make([]byte, len(seq))
+	for i := 0; i < len(seq); i++ {
+		out[len(seq)-1-i] = genbankComplement[seq[i]]
+	}
+	return string(out)
+}
+
+func originBases(line string) string {
+	var b strings.Builder
+	for _, field := range strings.Fields(line) {
+		if _, err := strconv.Atoi(field); err == nil {
+			continue
+		}
+		for _, r := range field {
+			if unicode.IsLetter(r) {
+				b.WriteRune(unicode.ToUpper(r))
+			}
+		}
+	}
+	return b.String()
+}
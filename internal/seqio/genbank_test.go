@@ -0,0 +1,110 @@
+package seqio
+
+import "testing"
+
+const sampleGenBank = `LOCUS       pTEST001                 45 bp    DNA     circular SYN 01-JAN-2024
+DEFINITION  Test plasmid pTEST001, complete sequence.
+ACCESSION   TEST001
+KEYWORDS    .
+FEATURES             Location/Qualifiers
+     CDS             1..33
+                     /gene="bla"
+                     /product="beta-lactamase"
+                     /note="AmpR"
+                     /transl_table=11
+     rep_origin      34..45
+                     /note="ColE1 origin"
+ORIGIN
+        1 atggctaaag atgttgaagc ggcataa ggtaagggtt tga
+//
+`
+
+func TestParseGenBank(t *testing.T) {
+	records, err := ParseGenBank(sampleGenBank)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(records) != 1 {
+		t.Fatalf("got %d records, want 1", len(records))
+	}
+	rec := records[0]
+	if rec.Locus != "pTEST001" {
+		t.Errorf("Locus = %q, want pTEST001", rec.Locus)
+	}
+	if rec.Definition != "Test plasmid pTEST001, complete sequence" {
+		t.Errorf("Definition = %q", rec.Definition)
+	}
+	if rec.Accession != "TEST001" {
+		t.Errorf("Accession = %q", rec.Accession)
+	}
+	if len(rec.Features) != 2 {
+		t.Fatalf("got %d features, want 2", len(rec.Features))
+	}
+	cds := rec.Features[0]
+	if cds.Type != "CDS" || cds.Location != "1..33" {
+		t.Errorf("CDS = %+v", cds)
+	}
+	if cds.Qualifier("gene") != "bla" {
+		t.Errorf("gene = %q, want bla", cds.Qualifier("gene"))
+	}
+	if cds.Qualifier("note") != "AmpR" {
+		t.Errorf("note = %q, want AmpR", cds.Qualifier("note"))
+	}
+	origin := rec.Features[1]
+	if origin.Type != "rep_origin" {
+		t.Errorf("origin.Type = %q, want rep_origin", origin.Type)
+	}
+	wantSeq := "ATGGCTAAAGATGTTGAAGCGGCATAAGGTAAGGGTTTGA"
+	if rec.Sequence != wantSeq {
+		t.Errorf("Sequence = %q, want %q", rec.Sequence, wantSeq)
+	}
+}
+
+func TestTranslate(t *testing.T) {
+	aa, err := Translate("ATGGCTAAAGATGTTGAAGCGGCATAA", "11")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	want := "MAKDVEAA"
+	if aa != want {
+		t.Errorf("Translate() = %q, want %q", aa, want)
+	}
+}
+
+func TestExtractRegion(t *testing.T) {
+	seq := "ATGGCTAAAGATGTTGAAGCGGCATAAGGTAAGGGTTTGA"
+	region, err := ExtractRegion(seq, "1..27")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if region != "ATGGCTAAAGATGTTGAAGCGGCATAA" {
+		t.Errorf("region = %q", region)
+	}
+	rc, err := ExtractRegion("ATGG", "complement(1..4)")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if rc != "CCAT" {
+		t.Errorf("complement region = %q, want CCAT", rc)
+	}
+	if _, err := ExtractRegion(seq, "join(1..10,20..30)"); err == nil {
+		t.Error("expected an error for an unsupported join(...) location")
+	}
+}
+
+func TestParseFasta(t *testing.T) {
+	data := ">primerA forward primer\nACGTACGT\nACGT\n>primerB reverse primer\nTTTTGGGG\n"
+	records, err := ParseFasta(data)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(records) != 2 {
+		t.Fatalf("got %d records, want 2", len(records))
+	}
+	if records[0].ID != "primerA" || records[0].Sequence != "ACGTACGTACGT" {
+		t.Errorf("records[0] = %+v", records[0])
+	}
+	if records[1].ID != "primerB" || records[1].Description != "reverse primer" {
+		t.Errorf("records[1] = %+v", records[1])
+	}
+}
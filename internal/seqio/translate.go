@@ -0,0 +1,55 @@
+package seqio
+
+import (
+	"fmt"
+	"strings"
+)
+
+// standardCodonTable is NCBI genetic code table 1 (the standard code).
+// Table 11 (bacterial/archaeal/plant plastid), the other table GenBank
+// CDS features commonly declare via /transl_table=, assigns the same
+// amino acid to every sense codon as table 1 and differs only in which
+// codons are valid translation starts — irrelevant once a CDS's reading
+// frame is already given by its location, so Translate honors
+// /transl_table= only to the extent of accepting it; the codon table
+// itself doesn't change.
+var standardCodonTable = map[string]byte{
+	"TTT": 'F', "TTC": 'F', "TTA": 'L', "TTG": 'L',
+	"CTT": 'L', "CTC": 'L', "CTA": 'L', "CTG": 'L',
+	"ATT": 'I', "ATC": 'I', "ATA": 'I', "ATG": 'M',
+	"GTT": 'V', "GTC": 'V', "GTA": 'V', "GTG": 'V',
+	"TCT": 'S', "TCC": 'S', "TCA": 'S', "TCG": 'S',
+	"CCT": 'P', "CCC": 'P', "CCA": 'P', "CCG": 'P',
+	"ACT": 'T', "ACC": 'T', "ACA": 'T', "ACG": 'T',
+	"GCT": 'A', "GCC": 'A', "GCA": 'A', "GCG": 'A',
+	"TAT": 'Y', "TAC": 'Y', "TAA": '*', "TAG": '*',
+	"CAT": 'H', "CAC": 'H', "CAA": 'Q', "CAG": 'Q',
+	"AAT": 'N', "AAC": 'N', "AAA": 'K', "AAG": 'K',
+	"GAT": 'D', "GAC": 'D', "GAA": 'E', "GAG": 'E',
+	"TGT": 'C', "TGC": 'C', "TGA": '*', "TGG": 'W',
+	"CGT": 'R', "CGC": 'R', "CGA": 'R', "CGG": 'R',
+	"AGT": 'S', "AGC": 'S', "AGA": 'R', "AGG": 'R',
+	"GGT": 'G', "GGC": 'G', "GGA": 'G', "GGG": 'G',
+}
+
+// Translate translates a CDS nucleotide sequence (in frame, 5'→3', A/C/G/T
+// only) into its one-letter amino acid sequence using the standard genetic
+// code, stopping at (and excluding) the first in-frame stop codon.
+// translTable is the CDS's /transl_table= qualifier, if any — see
+// standardCodonTable's doc comment for why it doesn't affect the result.
+func Translate(nucleotide string, translTable string) (string, error) {
+	seq := strings.ToUpper(nucleotide)
+	var aa strings.Builder
+	for i := 0; i+3 <= len(seq); i += 3 {
+		codon := seq[i : i+3]
+		residue, ok := standardCodonTable[codon]
+		if !ok {
+			return "", fmt.Errorf("seqio: invalid codon %q at nucleotide position %d", codon, i+1)
+		}
+		if residue == '*' {
+			break
+		}
+		aa.WriteByte(residue)
+	}
+	return aa.String(), nil
+}
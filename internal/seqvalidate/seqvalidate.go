@@ -0,0 +1,394 @@
+// Package seqvalidate sanity-checks the nucleotide and amino-acid
+// sequences labbookCLI's registry kinds store as free-text metadata
+// (sequenceAA on Plasmid, primerSequence on Primers) before they're sent
+// to the server, and re-checks them on demand for items that already
+// exist. It reports problems as a flat list of Findings rather than
+// failing fast, so a caller can choose to print every issue, POST anyway,
+// or refuse to POST — the --strict/non-strict split labbookCLI's
+// registry create and registry validate commands make.
+package seqvalidate
+
+import (
+	"fmt"
+	"math"
+	"strings"
+
+	"benchaid/internal/seqio"
+)
+
+// Severity distinguishes a problem serious enough to block a --strict
+// submission (SeverityError) from one worth the user's attention but not
+// worth failing over (SeverityWarning).
+type Severity string
+
+const (
+	SeverityError   Severity = "error"
+	SeverityWarning Severity = "warning"
+)
+
+// Finding is one validation result, JSON-tagged so callers can emit it as
+// a JSON-lines stream (one Finding per line) for both interactive use and
+// CI.
+type Finding struct {
+	Field    string   `json:"field"`
+	Code     string   `json:"code"`
+	Severity Severity `json:"severity"`
+	Message  string   `json:"message"`
+}
+
+// standardAAResidues is the 20 standard amino acid one-letter codes.
+const standardAAResidues = "ACDEFGHIKLMNPQRSTVWY"
+
+// Options configures how strict each check is. A zero Options is not
+// ready to use — call DefaultOptions and override individual fields.
+type Options struct {
+	// AllowedExtraAA lists one-letter codes tolerated in sequenceAA
+	// beyond the 20 standard residues. Defaults to "*XUO": "*" for a
+	// trailing stop, "X" for an unresolved residue, "U"/"O" for the rare
+	// selenocysteine/pyrrolysine residues some expression systems encode.
+	AllowedExtraAA string
+	// MWTolerancePercent is how far a stored molecularWeightDa may
+	// diverge from the computed monoisotopic MW before it's flagged.
+	MWTolerancePercent float64
+	// AllowAmbiguousNT tolerates IUPAC ambiguity codes (R, Y, S, W, K, M,
+	// B, D, H, V) in nucleotide sequences in addition to A/C/G/T/U/N.
+	AllowAmbiguousNT bool
+	// GeneticCode names the codon table. CheckTranslation does not read
+	// it and always translates with the standard code (NCBI table 1) — see
+	// seqio.Translate's doc comment for why table 11 doesn't need a
+	// separate entry.
+	GeneticCode string
+}
+
+// DefaultOptions returns the tolerances registry create/validate use
+// absent any overriding flags.
+func DefaultOptions() Options {
+	return Options{
+		AllowedExtraAA:     "*XUO",
+		MWTolerancePercent: 1.0,
+		AllowAmbiguousNT:   false,
+		GeneticCode:        "standard",
+	}
+}
+
+// ValidateProteinSequence checks seq against the allowed AA alphabet
+// (standardAAResidues plus opts.AllowedExtraAA) and flags an internal
+// stop codon — a "*" anywhere but the last position, which almost always
+// means the wrong reading frame or a truncated ORF was entered.
+func ValidateProteinSequence(seq string, opts Options) []Finding {
+	var findings []Finding
+	seq = strings.ToUpper(strings.TrimSpace(seq))
+	if seq == "" {
+		return nil
+	}
+	allowed := standardAAResidues + strings.ToUpper(opts.AllowedExtraAA)
+	for i, r := range seq {
+		if !strings.ContainsRune(allowed, r) {
+			findings = append(findings, Finding{
+				Field:    "sequenceAA",
+				Code:     "invalid-residue",
+				Severity: SeverityError,
+				Message:  fmt.Sprintf("invalid amino acid letter %q at position %d", r, i+1),
+			})
+		}
+		if r == '*' && i != len(seq)-1 {
+			findings = append(findings, Finding{
+				Field:    "sequenceAA",
+				Code:     "internal-stop",
+				Severity: SeverityError,
+				Message:  fmt.Sprintf("internal stop codon (*) at position %d", i+1),
+			})
+		}
+	}
+	return findings
+}
+
+// monoisotopicResidueMass is the monoisotopic mass (Da) each amino acid
+// contributes as a peptide-bonded residue (i.e. after loss of water),
+// keyed by one-letter code. U (selenocysteine) and O (pyrrolysine) are
+// included since ValidateProteinSequence allows them by default; X and a
+// non-terminal "*" have no defined mass and are rejected by
+// ComputeMonoisotopicMW instead of silently skipped, since either would
+// make the computed total meaningless (a terminal "*" is skipped).
+var monoisotopicResidueMass = map[byte]float64{
+	'G': 57.02146, 'A': 71.03711, 'S': 87.03203, 'P': 97.05276,
+	'V': 99.06841, 'T': 101.04768, 'C': 103.00919, 'L': 113.08406,
+	'I': 113.08406, 'N': 114.04293, 'D': 115.02694, 'Q': 128.05858,
+	'K': 128.09496, 'E': 129.04259, 'M': 131.04049, 'H': 137.05891,
+	'F': 147.06841, 'R': 156.10111, 'Y': 163.06333, 'W': 186.07931,
+	'U': 150.95364, 'O': 237.14773,
+}
+
+// waterMonoisotopicMass is added once per sequence, accounting for the
+// terminal -H and -OH a peptide's residue masses don't otherwise include.
+const waterMonoisotopicMass = 18.01056
+
+// ComputeMonoisotopicMW sums monoisotopicResidueMass over seq plus
+// waterMonoisotopicMass. It errors on any residue without a defined mass
+// (X, a non-terminal "*", or anything ValidateProteinSequence would
+// already have flagged as invalid) rather than guessing.
+func ComputeMonoisotopicMW(seq string) (float64, error) {
+	seq = strings.ToUpper(strings.TrimSpace(seq))
+	if seq == "" {
+		return 0, fmt.Errorf("seqvalidate: empty sequence")
+	}
+	total := waterMonoisotopicMass
+	for i := 0; i < len(seq); i++ {
+		b := seq[i]
+		if b == '*' && i == len(seq)-1 {
+			continue
+		}
+		mass, ok := monoisotopicResidueMass[b]
+		if !ok {
+			return 0, fmt.Errorf("seqvalidate: no defined mass for residue %q at position %d", b, i+1)
+		}
+		total += mass
+	}
+	return total, nil
+}
+
+// CheckMolecularWeight flags storedMWDa if it diverges from seq's
+// computed monoisotopic MW by more than opts.MWTolerancePercent. A seq
+// ComputeMonoisotopicMW can't score (an X or internal stop) is reported
+// as a separate finding rather than silently skipping the MW check.
+func CheckMolecularWeight(seq string, storedMWDa float64, opts Options) []Finding {
+	if strings.TrimSpace(seq) == "" || storedMWDa <= 0 {
+		return nil
+	}
+	computed, err := ComputeMonoisotopicMW(seq)
+	if err != nil {
+		return []Finding{{
+			Field:    "molecularWeightDa",
+			Code:     "mw-not-computable",
+			Severity: SeverityWarning,
+			Message:  fmt.Sprintf("can't verify molecularWeightDa: %v", err),
+		}}
+	}
+	tolerance := opts.MWTolerancePercent
+	if tolerance <= 0 {
+		tolerance = DefaultOptions().MWTolerancePercent
+	}
+	diffPercent := math.Abs(storedMWDa-computed) / computed * 100
+	if diffPercent > tolerance {
+		return []Finding{{
+			Field:    "molecularWeightDa",
+			Code:     "mw-mismatch",
+			Severity: SeverityError,
+			Message:  fmt.Sprintf("stored molecularWeightDa %.2f disagrees with computed %.2f by %.2f%% (tolerance %.2f%%)", storedMWDa, computed, diffPercent, tolerance),
+		}}
+	}
+	return nil
+}
+
+// nucleotideAllowed is the non-ambiguous nucleotide alphabet this package
+// accepts: A/C/G/T (DNA), U (RNA, or a DNA/RNA mix some imports produce),
+// and N (fully ambiguous). IUPAC's partial-ambiguity codes are accepted
+// only when opts.AllowAmbiguousNT is set.
+const nucleotideAllowed = "ACGTUN"
+const nucleotideAmbiguous = "RYSWKMBDHV"
+
+// ValidateNucleotideSequence checks seq's alphabet and flags two common
+// synthesis/design problems: homopolymer runs of 5 or more identical
+// bases (hard for some synthesis chemistries and a common PCR slippage
+// site) and a self-complementary window of 8nt or more (hairpin
+// potential, since it can base-pair with itself elsewhere in the same
+// strand).
+func ValidateNucleotideSequence(seq string, opts Options) []Finding {
+	var findings []Finding
+	seq = strings.ToUpper(strings.TrimSpace(seq))
+	if seq == "" {
+		return nil
+	}
+	allowed := nucleotideAllowed
+	if opts.AllowAmbiguousNT {
+		allowed += nucleotideAmbiguous
+	}
+	for i, r := range seq {
+		if !strings.ContainsRune(allowed, r) {
+			findings = append(findings, Finding{
+				Field:    "sequence",
+				Code:     "invalid-base",
+				Severity: SeverityError,
+				Message:  fmt.Sprintf("invalid nucleotide base %q at position %d", r, i+1),
+			})
+		}
+	}
+
+	run := 1
+	for i := 1; i < len(seq); i++ {
+		if seq[i] == seq[i-1] {
+			run++
+		} else {
+			run = 1
+		}
+		if run == 5 {
+			findings = append(findings, Finding{
+				Field:    "sequence",
+				Code:     "homopolymer-run",
+				Severity: SeverityWarning,
+				Message:  fmt.Sprintf("run of %d identical bases (%q) ending at position %d", run, seq[i], i+1),
+			})
+		}
+	}
+
+	if hairpinPos, ok := findHairpinWindow(seq, 8); ok {
+		findings = append(findings, Finding{
+			Field:    "sequence",
+			Code:     "hairpin-potential",
+			Severity: SeverityWarning,
+			Message:  fmt.Sprintf("self-complementary %d nt window at position %d suggests hairpin potential", 8, hairpinPos+1),
+		})
+	}
+	return findings
+}
+
+// findHairpinWindow reports the first position whose window-length
+// window is self-complementary with some other, non-overlapping window
+// later in seq — a simple proxy for secondary-structure potential, not a
+// full free-energy fold.
+func findHairpinWindow(seq string, window int) (int, bool) {
+	if len(seq) < window*2 {
+		return 0, false
+	}
+	for i := 0; i+window <= len(seq); i++ {
+		rc := reverseComplement(seq[i : i+window])
+		for j := i + window; j+window <= len(seq); j++ {
+			if seq[j:j+window] == rc {
+				return i, true
+			}
+		}
+	}
+	return 0, false
+}
+
+func reverseComplement(seq string) string {
+	out := make([]byte, len(seq))
+	for i := 0; i < len(seq); i++ {
+		out[len(seq)-1-i] = complementBase(seq[i])
+	}
+	return string(out)
+}
+
+func complementBase(b byte) byte {
+	switch b {
+	case 'A':
+		return 'T'
+	case 'T', 'U':
+		return 'A'
+	case 'C':
+		return 'G'
+	case 'G':
+		return 'C'
+	default:
+		return 'N'
+	}
+}
+
+// gcContentPercent returns the fraction of G/C bases in seq, as a
+// percentage.
+func gcContentPercent(seq string) float64 {
+	var gc int
+	for _, b := range seq {
+		if b == 'G' || b == 'C' {
+			gc++
+		}
+	}
+	if len(seq) == 0 {
+		return 0
+	}
+	return 100 * float64(gc) / float64(len(seq))
+}
+
+// ValidatePrimerSequence runs ValidateNucleotideSequence and additionally
+// warns when seq's GC content falls outside the 40-60% range most primer
+// design guides recommend for reliable annealing.
+func ValidatePrimerSequence(seq string, opts Options) []Finding {
+	findings := ValidateNucleotideSequence(seq, opts)
+	trimmed := strings.ToUpper(strings.TrimSpace(seq))
+	if trimmed == "" {
+		return findings
+	}
+	if gc := gcContentPercent(trimmed); gc < 40 || gc > 60 {
+		findings = append(findings, Finding{
+			Field:    "primerSequence",
+			Code:     "gc-out-of-range",
+			Severity: SeverityWarning,
+			Message:  fmt.Sprintf("GC content %.1f%% outside the recommended 40-60%% range for primers", gc),
+		})
+	}
+	return findings
+}
+
+// CheckTranslation translates nucleotide with the configured genetic
+// code and flags a mismatch against aaSeq (a trailing stop on either side
+// is ignored, since ValidateProteinSequence and seqio.Translate disagree
+// on whether to keep it).
+func CheckTranslation(nucleotide, aaSeq string, opts Options) []Finding {
+	nucleotide = strings.TrimSpace(nucleotide)
+	aaSeq = strings.TrimSpace(aaSeq)
+	if nucleotide == "" || aaSeq == "" {
+		return nil
+	}
+	translated, err := seqio.Translate(nucleotide, "")
+	if err != nil {
+		return []Finding{{
+			Field:    "sequenceNT",
+			Code:     "translation-failed",
+			Severity: SeverityError,
+			Message:  fmt.Sprintf("can't translate sequenceNT: %v", err),
+		}}
+	}
+	wantAA := strings.ToUpper(strings.TrimSuffix(strings.ToUpper(aaSeq), "*"))
+	if translated != wantAA {
+		return []Finding{{
+			Field:    "sequenceAA",
+			Code:     "translation-mismatch",
+			Severity: SeverityError,
+			Message:  fmt.Sprintf("translated sequenceNT (%s) doesn't match stored sequenceAA (%s)", translated, wantAA),
+		}}
+	}
+	return nil
+}
+
+// ValidateMetadata runs every check whose relevant fields are present in
+// meta, regardless of registry kind: sequenceAA (alphabet, internal
+// stop, MW-vs-molecularWeightDa), primerSequence (alphabet, homopolymer
+// runs, hairpin potential, GC range), sequenceNT (alphabet, homopolymer
+// runs, hairpin potential), and sequenceNT-vs-sequenceAA translation
+// agreement when both are present. It's the single entry point both
+// parseRegistryPayloadWithID/parseRegistryPatchPayloadWithID and
+// `registry validate` call against a (possibly partial) metadata map.
+func ValidateMetadata(meta map[string]interface{}, opts Options) []Finding {
+	var findings []Finding
+	sequenceAA, _ := meta["sequenceAA"].(string)
+	primerSequence, _ := meta["primerSequence"].(string)
+	sequenceNT, _ := meta["sequenceNT"].(string)
+
+	if strings.TrimSpace(sequenceAA) != "" {
+		findings = append(findings, ValidateProteinSequence(sequenceAA, opts)...)
+		if mw, ok := meta["molecularWeightDa"].(float64); ok {
+			findings = append(findings, CheckMolecularWeight(sequenceAA, mw, opts)...)
+		}
+	}
+	if strings.TrimSpace(primerSequence) != "" {
+		findings = append(findings, ValidatePrimerSequence(primerSequence, opts)...)
+	}
+	if strings.TrimSpace(sequenceNT) != "" {
+		findings = append(findings, ValidateNucleotideSequence(sequenceNT, opts)...)
+		if strings.TrimSpace(sequenceAA) != "" {
+			findings = append(findings, CheckTranslation(sequenceNT, sequenceAA, opts)...)
+		}
+	}
+	return findings
+}
+
+// HasErrors reports whether any Finding in findings is SeverityError,
+// the condition registry create --strict refuses to POST over.
+func HasErrors(findings []Finding) bool {
+	for _, f := range findings {
+		if f.Severity == SeverityError {
+			return true
+		}
+	}
+	return false
+}
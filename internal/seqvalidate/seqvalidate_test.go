@@ -0,0 +1,125 @@
+package seqvalidate
+
+import "testing"
+
+func TestValidateProteinSequence(t *testing.T) {
+	opts := DefaultOptions()
+
+	if findings := ValidateProteinSequence("MAGWSTKLVX*", opts); len(findings) != 0 {
+		t.Errorf("valid sequence with trailing stop produced findings: %+v", findings)
+	}
+
+	findings := ValidateProteinSequence("MAG*WST", opts)
+	if len(findings) != 1 || findings[0].Code != "internal-stop" {
+		t.Errorf("expected a single internal-stop finding, got %+v", findings)
+	}
+
+	findings = ValidateProteinSequence("MAGBST", opts)
+	if len(findings) != 1 || findings[0].Code != "invalid-residue" {
+		t.Errorf("expected a single invalid-residue finding for B, got %+v", findings)
+	}
+}
+
+func TestComputeMonoisotopicMW(t *testing.T) {
+	mw, err := ComputeMonoisotopicMW("AG")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	want := 71.03711 + 57.02146 + waterMonoisotopicMass
+	if mw != want {
+		t.Errorf("mw = %v, want %v", mw, want)
+	}
+
+	if _, err := ComputeMonoisotopicMW("AX"); err == nil {
+		t.Fatal("expected an error for an X residue, got nil")
+	}
+}
+
+func TestCheckMolecularWeight(t *testing.T) {
+	mw, err := ComputeMonoisotopicMW("AG")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if findings := CheckMolecularWeight("AG", mw, DefaultOptions()); len(findings) != 0 {
+		t.Errorf("matching MW produced findings: %+v", findings)
+	}
+
+	findings := CheckMolecularWeight("AG", mw*2, DefaultOptions())
+	if len(findings) != 1 || findings[0].Code != "mw-mismatch" {
+		t.Errorf("expected a single mw-mismatch finding, got %+v", findings)
+	}
+}
+
+func TestValidateNucleotideSequence(t *testing.T) {
+	opts := DefaultOptions()
+
+	if findings := ValidateNucleotideSequence("ACGTACGTACGT", opts); len(findings) != 0 {
+		t.Errorf("clean sequence produced findings: %+v", findings)
+	}
+
+	findings := ValidateNucleotideSequence("ACGTAAAAAGGT", opts)
+	if len(findings) != 1 || findings[0].Code != "homopolymer-run" {
+		t.Errorf("expected a single homopolymer-run finding, got %+v", findings)
+	}
+
+	findings = ValidateNucleotideSequence("ACGTACGBACGT", opts)
+	if len(findings) != 1 || findings[0].Code != "invalid-base" {
+		t.Errorf("expected a single invalid-base finding for B, got %+v", findings)
+	}
+
+	findings = ValidateNucleotideSequence("ACGTACGBACGT", Options{AllowAmbiguousNT: true})
+	if len(findings) != 0 {
+		t.Errorf("ambiguous base allowed under AllowAmbiguousNT produced findings: %+v", findings)
+	}
+
+	findings = ValidateNucleotideSequence("ACGTACGTACGTACGT", opts)
+	var gotHairpin bool
+	for _, f := range findings {
+		if f.Code == "hairpin-potential" {
+			gotHairpin = true
+		}
+	}
+	if !gotHairpin {
+		t.Errorf("expected a hairpin-potential finding for a self-complementary repeat, got %+v", findings)
+	}
+}
+
+func TestValidatePrimerSequence(t *testing.T) {
+	findings := ValidatePrimerSequence("ATATATATATATATAT", DefaultOptions())
+	var gotGC bool
+	for _, f := range findings {
+		if f.Code == "gc-out-of-range" {
+			gotGC = true
+		}
+	}
+	if !gotGC {
+		t.Errorf("expected a gc-out-of-range finding for a 0%% GC primer, got %+v", findings)
+	}
+
+	if findings := ValidatePrimerSequence("ACGTACGTACGTACGA", DefaultOptions()); len(findings) != 0 {
+		t.Errorf("50%% GC primer with no other problems produced findings: %+v", findings)
+	}
+}
+
+func TestCheckTranslation(t *testing.T) {
+	if findings := CheckTranslation("ATGGCTGGTTAA", "MAG", DefaultOptions()); len(findings) != 0 {
+		t.Errorf("matching translation produced findings: %+v", findings)
+	}
+
+	findings := CheckTranslation("ATGGCTGGTTAA", "MAGG", DefaultOptions())
+	if len(findings) != 1 || findings[0].Code != "translation-mismatch" {
+		t.Errorf("expected a single translation-mismatch finding, got %+v", findings)
+	}
+}
+
+func TestValidateMetadata(t *testing.T) {
+	meta := map[string]interface{}{
+		"sequenceNT": "ATGGCTGGTTAA",
+		"sequenceAA": "MAGG",
+	}
+	findings := ValidateMetadata(meta, DefaultOptions())
+	if len(findings) != 1 || findings[0].Code != "translation-mismatch" {
+		t.Errorf("expected a single translation-mismatch finding, got %+v", findings)
+	}
+}
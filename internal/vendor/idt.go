@@ -0,0 +1,170 @@
+package vendor
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+)
+
+// IDTClient places gBlocks Gene Fragment orders through IDT's Synthetic
+// Biology Ordering API. IDT's ordering flow is a single submit-and-price
+// step rather than Twist's construct/score/quote/order pipeline, so
+// CreateConstruct and WaitForScoring just hold the sequence locally (IDT
+// only screens it for synthesizability once CreateQuote submits the real
+// request), and WaitForQuote is a no-op since IDT returns a priced quote
+// synchronously.
+//
+// NOTE: IDT's public API surface has changed over the years; the request
+// shapes below follow IDT's documented ordering API as of this writing and
+// should be re-verified against current IDT API docs before being pointed
+// at production.
+type IDTClient struct {
+	baseURL string
+	token   string // OAuth2 bearer token (client-credentials flow)
+	http    *http.Client
+
+	mu      sync.Mutex
+	pending map[string]ConstructSpec
+}
+
+// NewIDTClient builds an IDTClient. token is a bearer token already issued
+// by IDT's OAuth2 client-credentials flow.
+func NewIDTClient(baseURL, token string) *IDTClient {
+	if baseURL == "" {
+		baseURL = "https://www.idtdna.com"
+	}
+	return &IDTClient{
+		baseURL: strings.TrimRight(baseURL, "/"),
+		token:   token,
+		http:    &http.Client{},
+		pending: map[string]ConstructSpec{},
+	}
+}
+
+func (c *IDTClient) Name() string { return "idt" }
+
+func (c *IDTClient) CreateConstruct(ctx context.Context, spec ConstructSpec, idempotencyKey string) (string, error) {
+	id := "idt-pending-" + randomID(8)
+	c.mu.Lock()
+	c.pending[id] = spec
+	c.mu.Unlock()
+	return id, nil
+}
+
+func (c *IDTClient) WaitForScoring(ctx context.Context, constructID string, timeout, interval time.Duration) error {
+	c.mu.Lock()
+	_, ok := c.pending[constructID]
+	c.mu.Unlock()
+	if !ok {
+		return fmt.Errorf("idt: unknown construct %s", constructID)
+	}
+	return nil
+}
+
+func (c *IDTClient) CreateQuote(ctx context.Context, spec OrderSpec, idempotencyKey string) (string, error) {
+	sequences := make([]map[string]interface{}, len(spec.ConstructIDs))
+	c.mu.Lock()
+	for i, id := range spec.ConstructIDs {
+		cs, ok := c.pending[id]
+		if !ok {
+			c.mu.Unlock()
+			return "", fmt.Errorf("idt: unknown construct %s", id)
+		}
+		sequences[i] = map[string]interface{}{"Name": cs.Name, "Seq": cs.Sequence}
+	}
+	c.mu.Unlock()
+
+	payload, err := json.Marshal(map[string]interface{}{"Sequences": sequences})
+	if err != nil {
+		return "", err
+	}
+	resp, err := c.request(ctx, http.MethodPost, "/restapi/v1/synthesis/gblocks/quote", payload, idempotencyKey)
+	if err != nil {
+		return "", err
+	}
+	var out struct {
+		QuoteID string `json:"QuoteId"`
+	}
+	if err := json.Unmarshal(resp, &out); err != nil {
+		return "", err
+	}
+	if out.QuoteID == "" {
+		return "", errors.New("idt: missing quote id in response")
+	}
+	return out.QuoteID, nil
+}
+
+func (c *IDTClient) WaitForQuote(ctx context.Context, quoteID string, timeout, interval time.Duration) error {
+	return nil
+}
+
+func (c *IDTClient) CreateOrder(ctx context.Context, quoteID string, spec OrderSpec, idempotencyKey string) (string, error) {
+	payload, err := json.Marshal(map[string]interface{}{
+		"QuoteId": quoteID,
+		"ShipTo": map[string]string{
+			"FirstName": spec.Shipment.FirstName,
+			"LastName":  spec.Shipment.LastName,
+			"Phone":     spec.Shipment.Phone,
+		},
+		"PoNumber": spec.POReference,
+	})
+	if err != nil {
+		return "", err
+	}
+	resp, err := c.request(ctx, http.MethodPost, "/restapi/v1/synthesis/gblocks/order", payload, idempotencyKey)
+	if err != nil {
+		return "", err
+	}
+	var out struct {
+		OrderID string `json:"OrderId"`
+	}
+	if err := json.Unmarshal(resp, &out); err != nil {
+		return "", err
+	}
+	if out.OrderID == "" {
+		return "", errors.New("idt: missing order id in response")
+	}
+	return out.OrderID, nil
+}
+
+// request sends one HTTP call using ctx's deadline. Unlike TwistClient,
+// this doesn't retry transient failures or honor Retry-After.
+func (c *IDTClient) request(ctx context.Context, method, path string, body []byte, idempotencyKey string) ([]byte, error) {
+	reqURL := c.baseURL + path
+	var reader io.Reader
+	if body != nil {
+		reader = bytes.NewReader(body)
+	}
+	req, err := http.NewRequestWithContext(ctx, method, reqURL, reader)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Authorization", "Bearer "+c.token)
+	req.Header.Set("Accept", "application/json")
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	if idempotencyKey != "" {
+		req.Header.Set("Idempotency-Key", idempotencyKey)
+	}
+	resp, err := c.http.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	respBody, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return nil, fmt.Errorf("idt api error (%d): %s", resp.StatusCode, strings.TrimSpace(string(respBody)))
+	}
+	return respBody, nil
+}
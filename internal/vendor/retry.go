@@ -0,0 +1,79 @@
+package vendor
+
+import (
+	"context"
+	"errors"
+	"math/rand"
+	"net"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+const (
+	retryBase  = 500 * time.Millisecond
+	retryCap   = 30 * time.Second
+	maxRetries = 5
+)
+
+// nextBackoff computes a decorrelated-jitter backoff delay: a random value
+// between retryBase and 3x the previous delay, capped at retryCap. This
+// spreads out retries from many concurrent callers better than a plain
+// exponential backoff, which would have them all retry in lockstep.
+func nextBackoff(prev time.Duration) time.Duration {
+	if prev <= 0 {
+		prev = retryBase
+	}
+	ceiling := prev * 3
+	if ceiling > retryCap {
+		ceiling = retryCap
+	}
+	if ceiling <= retryBase {
+		return retryBase
+	}
+	return retryBase + time.Duration(rand.Int63n(int64(ceiling-retryBase)))
+}
+
+// isRetryable reports whether a request failed in a way worth retrying:
+// HTTP 429/5xx, or a net.Error that timed out.
+func isRetryable(statusCode int, err error) bool {
+	if statusCode == http.StatusTooManyRequests || statusCode >= 500 {
+		return true
+	}
+	var netErr net.Error
+	if errors.As(err, &netErr) && netErr.Timeout() {
+		return true
+	}
+	return false
+}
+
+// retryAfter parses a Retry-After header (either delay-seconds or an
+// HTTP-date) into a duration.
+func retryAfter(h http.Header) (time.Duration, bool) {
+	v := h.Get("Retry-After")
+	if v == "" {
+		return 0, false
+	}
+	if secs, err := strconv.Atoi(v); err == nil {
+		return time.Duration(secs) * time.Second, true
+	}
+	if t, err := http.ParseTime(v); err == nil {
+		if d := time.Until(t); d > 0 {
+			return d, true
+		}
+	}
+	return 0, false
+}
+
+// sleepOrDone waits for d or ctx's cancellation, whichever comes first, so
+// a long poll can return promptly on SIGINT instead of finishing its sleep.
+func sleepOrDone(ctx context.Context, d time.Duration) error {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	select {
+	case <-timer.C:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
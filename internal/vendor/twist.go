@@ -0,0 +1,348 @@
+package vendor
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// TwistClient drives Twist Bioscience's gene-synthesis ordering API:
+// construct creation, async scoring, quoting, and order placement.
+type TwistClient struct {
+	baseURL string
+	email   string
+	token   string
+	http    *http.Client
+}
+
+// NewTwistClient builds a TwistClient. email is the Twist account email
+// used as the path parameter on every endpoint; token is the
+// X-End-User-Token API token. Per-call deadlines come from the context
+// passed to each method, so the underlying http.Client carries no fixed
+// Timeout.
+func NewTwistClient(baseURL, email, token string) *TwistClient {
+	return &TwistClient{
+		baseURL: strings.TrimRight(baseURL, "/"),
+		email:   email,
+		token:   token,
+		http:    &http.Client{},
+	}
+}
+
+func (c *TwistClient) Name() string { return "twist" }
+
+type twistConstructRequest struct {
+	Sequences         []string `json:"sequences"`
+	Name              string   `json:"name"`
+	Type              string   `json:"type"`
+	VectorMESUID      string   `json:"vector_mes_uid,omitempty"`
+	InsertionPointMES string   `json:"insertion_point_mes_uid,omitempty"`
+	AdaptersOn        *bool    `json:"adapters_on,omitempty"`
+}
+
+type twistConstructResponse struct {
+	ID string `json:"id"`
+}
+
+func (c *TwistClient) CreateConstruct(ctx context.Context, spec ConstructSpec, idempotencyKey string) (string, error) {
+	constructType := "NON_CLONED_GENE"
+	if spec.Cloned {
+		constructType = "CLONED_GENE"
+	}
+	payload, err := json.Marshal(twistConstructRequest{
+		Sequences:         []string{spec.Sequence},
+		Name:              spec.Name,
+		Type:              constructType,
+		VectorMESUID:      spec.VectorID,
+		InsertionPointMES: spec.InsertionPointID,
+		AdaptersOn:        spec.AdaptersOn,
+	})
+	if err != nil {
+		return "", err
+	}
+	path := fmt.Sprintf("/v1/users/%s/constructs/", pathEscape(c.email))
+	resp, err := c.request(ctx, http.MethodPost, path, payload, idempotencyKey)
+	if err != nil {
+		return "", err
+	}
+	var out twistConstructResponse
+	if err := json.Unmarshal(resp, &out); err != nil {
+		return "", err
+	}
+	if out.ID == "" {
+		return "", errors.New("twist: missing construct id in response")
+	}
+	return out.ID, nil
+}
+
+type twistConstructStatus struct {
+	ID        string `json:"id"`
+	Scored    bool   `json:"scored"`
+	ScoreData struct {
+		Issues []map[string]interface{} `json:"issues"`
+	} `json:"score_data"`
+}
+
+func (c *TwistClient) WaitForScoring(ctx context.Context, constructID string, timeout, interval time.Duration) error {
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+	for {
+		status, err := c.fetchConstructStatus(ctx, constructID)
+		if err != nil {
+			return err
+		}
+		if status.Scored {
+			if len(status.ScoreData.Issues) > 0 {
+				return fmt.Errorf("twist: scoring issues returned: %v", status.ScoreData.Issues)
+			}
+			return nil
+		}
+		if err := sleepOrDone(ctx, interval); err != nil {
+			return scoringWaitErr(err)
+		}
+	}
+}
+
+func scoringWaitErr(err error) error {
+	if errors.Is(err, context.DeadlineExceeded) {
+		return errors.New("twist: scoring timeout exceeded")
+	}
+	return err
+}
+
+func (c *TwistClient) fetchConstructStatus(ctx context.Context, id string) (twistConstructStatus, error) {
+	path := fmt.Sprintf("/v1/users/%s/constructs/describe/?id__in=%s&scored=true", pathEscape(c.email), queryEscape(id))
+	raw, err := c.getJSON(ctx, path)
+	if err != nil {
+		return twistConstructStatus{}, err
+	}
+	var items []twistConstructStatus
+	if err := json.Unmarshal(raw, &items); err != nil {
+		return twistConstructStatus{}, err
+	}
+	if len(items) == 0 {
+		return twistConstructStatus{}, errors.New("twist: no construct status returned")
+	}
+	return items[0], nil
+}
+
+func (c *TwistClient) CreateQuote(ctx context.Context, spec OrderSpec, idempotencyKey string) (string, error) {
+	externalID := spec.ExternalID
+	if externalID == "" {
+		externalID = "twist-" + randomID(6)
+	}
+	constructs := make([]map[string]interface{}, len(spec.ConstructIDs))
+	for i, id := range spec.ConstructIDs {
+		constructs[i] = map[string]interface{}{
+			"id":    id,
+			"index": i + 1,
+		}
+	}
+	payload := map[string]interface{}{
+		"external_id": externalID,
+		"shipment": map[string]string{
+			"first_name":           spec.Shipment.FirstName,
+			"last_name":            spec.Shipment.LastName,
+			"phone":                spec.Shipment.Phone,
+			"recipient_address_id": spec.Shipment.RecipientAddressID,
+		},
+		"containers": []map[string]interface{}{
+			{
+				"constructs": constructs,
+			},
+		},
+		"order_sub_product_type": spec.OrderSubProduct,
+	}
+	if spec.Project != "" {
+		payload["ecommerce_project_name"] = spec.Project
+	}
+	if len(spec.OrderSettings) > 0 {
+		payload["order_settings"] = spec.OrderSettings
+	}
+
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return "", err
+	}
+	path := fmt.Sprintf("/v1/users/%s/quotes/", pathEscape(c.email))
+	resp, err := c.request(ctx, http.MethodPost, path, body, idempotencyKey)
+	if err != nil {
+		return "", err
+	}
+	var out map[string]interface{}
+	if err := json.Unmarshal(resp, &out); err != nil {
+		return "", err
+	}
+	id, _ := out["id"].(string)
+	if id == "" {
+		return "", errors.New("twist: missing quote id in response")
+	}
+	return id, nil
+}
+
+func (c *TwistClient) WaitForQuote(ctx context.Context, quoteID string, timeout, interval time.Duration) error {
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+	for {
+		status, err := c.fetchQuoteStatus(ctx, quoteID)
+		if err != nil {
+			return err
+		}
+		if status == "SUCCESS" {
+			return nil
+		}
+		if status == "FAILED" {
+			return errors.New("twist: quote failed")
+		}
+		if err := sleepOrDone(ctx, interval); err != nil {
+			if errors.Is(err, context.DeadlineExceeded) {
+				return errors.New("twist: quote timeout exceeded")
+			}
+			return err
+		}
+	}
+}
+
+func (c *TwistClient) fetchQuoteStatus(ctx context.Context, id string) (string, error) {
+	path := fmt.Sprintf("/v1/users/%s/quotes/%s/", pathEscape(c.email), pathEscape(id))
+	raw, err := c.getJSON(ctx, path)
+	if err != nil {
+		return "", err
+	}
+	var out struct {
+		StatusInfo struct {
+			Status string `json:"status"`
+		} `json:"status_info"`
+	}
+	if err := json.Unmarshal(raw, &out); err != nil {
+		return "", err
+	}
+	if out.StatusInfo.Status == "" {
+		return "", errors.New("twist: missing quote status")
+	}
+	return out.StatusInfo.Status, nil
+}
+
+func (c *TwistClient) CreateOrder(ctx context.Context, quoteID string, spec OrderSpec, idempotencyKey string) (string, error) {
+	payload := map[string]interface{}{
+		"quote_id": quoteID,
+	}
+	if spec.NoPO {
+		payload["payment_flow"] = "NO_PO"
+		payload["payment_method_id"] = nil
+	} else {
+		payload["payment_method_id"] = spec.PaymentMethodID
+		if spec.POReference != "" {
+			payload["po_reference"] = spec.POReference
+		}
+	}
+
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return "", err
+	}
+	path := fmt.Sprintf("/v1/users/%s/orders/", pathEscape(c.email))
+	resp, err := c.request(ctx, http.MethodPost, path, body, idempotencyKey)
+	if err != nil {
+		return "", err
+	}
+	var out map[string]interface{}
+	if err := json.Unmarshal(resp, &out); err != nil {
+		return "", err
+	}
+	id, _ := out["id"].(string)
+	if id == "" {
+		return "", errors.New("twist: missing order id in response")
+	}
+	return id, nil
+}
+
+// VectorsList fetches the caller's registered Twist vectors. This is a
+// Twist-specific listing with no analogue in the generic Client interface,
+// so it's exposed only on the concrete TwistClient.
+func (c *TwistClient) VectorsList(ctx context.Context) ([]byte, error) {
+	return c.getJSON(ctx, fmt.Sprintf("/v1/users/%s/vectors/", pathEscape(c.email)))
+}
+
+func (c *TwistClient) getJSON(ctx context.Context, path string) ([]byte, error) {
+	return c.request(ctx, http.MethodGet, path, nil, "")
+}
+
+// request sends one HTTP call, retrying transient failures (429/5xx, or a
+// timed-out net.Error) with decorrelated-jitter backoff, honoring a
+// Retry-After header when Twist sends one. It gives up once ctx is done
+// (deadline or SIGINT cancellation) or maxRetries is exhausted.
+func (c *TwistClient) request(ctx context.Context, method, path string, body []byte, idempotencyKey string) ([]byte, error) {
+	var backoff time.Duration
+	for attempt := 0; ; attempt++ {
+		respBody, status, header, err := c.doRequestOnce(ctx, method, path, body, idempotencyKey)
+		if err == nil && status >= 200 && status < 300 {
+			return respBody, nil
+		}
+		var callErr error
+		if err != nil {
+			callErr = err
+		} else {
+			callErr = fmt.Errorf("twist api error (%d): %s", status, strings.TrimSpace(string(respBody)))
+		}
+		if attempt >= maxRetries || !isRetryable(status, err) {
+			return nil, callErr
+		}
+		delay, ok := retryAfter(header)
+		if !ok {
+			backoff = nextBackoff(backoff)
+			delay = backoff
+		}
+		if waitErr := sleepOrDone(ctx, delay); waitErr != nil {
+			return nil, waitErr
+		}
+	}
+}
+
+func (c *TwistClient) doRequestOnce(ctx context.Context, method, path string, body []byte, idempotencyKey string) ([]byte, int, http.Header, error) {
+	reqURL := c.baseURL + path
+	var reader io.Reader
+	if body != nil {
+		reader = bytes.NewReader(body)
+	}
+	req, err := http.NewRequestWithContext(ctx, method, reqURL, reader)
+	if err != nil {
+		return nil, 0, nil, err
+	}
+	req.Header.Set("X-End-User-Token", c.token)
+	req.Header.Set("Accept", "application/json")
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	if idempotencyKey != "" {
+		req.Header.Set("Idempotency-Key", idempotencyKey)
+	}
+	resp, err := c.http.Do(req)
+	if err != nil {
+		return nil, 0, nil, err
+	}
+	defer resp.Body.Close()
+	respBody, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, resp.StatusCode, resp.Header, err
+	}
+	return respBody, resp.StatusCode, resp.Header, nil
+}
+
+func pathEscape(val string) string  { return url.PathEscape(val) }
+func queryEscape(val string) string { return url.QueryEscape(val) }
+
+func randomID(bytesLen int) string {
+	buf := make([]byte, bytesLen)
+	_, _ = rand.Read(buf)
+	return hex.EncodeToString(buf)
+}
@@ -0,0 +1,76 @@
+// Package vendor abstracts the gene-synthesis vendors twist_order can place
+// orders with behind one Client interface, so the CLI's sequence handling,
+// plate layout, and journaling logic don't need to know which vendor is on
+// the other end of the wire.
+package vendor
+
+import (
+	"context"
+	"time"
+)
+
+// Shipment is the recipient information every vendor's order submission
+// needs.
+type Shipment struct {
+	FirstName          string
+	LastName           string
+	Phone              string
+	RecipientAddressID string
+}
+
+// ConstructSpec describes one sequence to synthesize, in vendor-agnostic
+// terms. VectorID/InsertionPointID/AdaptersOn are Twist-specific clonal-gene
+// fields; vendors that don't have an equivalent concept ignore them.
+type ConstructSpec struct {
+	Name             string
+	Sequence         string
+	Cloned           bool
+	VectorID         string
+	InsertionPointID string
+	AdaptersOn       *bool
+}
+
+// OrderSpec describes the quote/order a set of already-created constructs
+// should be placed under. OrderSubProduct and OrderSettings carry Twist's
+// product-configuration vocabulary (order_sub_product_type/order_settings);
+// vendors with a different product model translate or ignore them rather
+// than forcing a lowest-common-denominator settings schema on every vendor.
+type OrderSpec struct {
+	ExternalID      string
+	Project         string
+	Shipment        Shipment
+	ConstructIDs    []string
+	OrderSubProduct string
+	OrderSettings   []map[string]interface{}
+	PaymentMethodID string
+	POReference     string
+	NoPO            bool
+}
+
+// Client is one gene-synthesis vendor's ordering API, reduced to the
+// create-construct -> score -> quote -> order pipeline twist_order drives.
+// A vendor without a given stage (e.g. no separate async quote step)
+// implements it as a pass-through; see each vendor's doc comment. Every
+// method takes a context so a caller can bound an individual call with a
+// deadline or cancel the whole pipeline (e.g. on SIGINT) without leaking
+// the goroutine blocked in a long poll.
+type Client interface {
+	// Name identifies the vendor for log output and the journal.
+	Name() string
+	// CreateConstruct submits one sequence for synthesis and returns its
+	// vendor-assigned construct ID. idempotencyKey, if non-empty, is
+	// passed through so a retried call with the same key doesn't create a
+	// duplicate.
+	CreateConstruct(ctx context.Context, spec ConstructSpec, idempotencyKey string) (string, error)
+	// WaitForScoring blocks until the vendor has finished evaluating
+	// constructID for synthesizability, or returns an error on timeout,
+	// context cancellation, or a hard rejection.
+	WaitForScoring(ctx context.Context, constructID string, timeout, interval time.Duration) error
+	// CreateQuote prices the given constructs and returns a quote ID.
+	CreateQuote(ctx context.Context, spec OrderSpec, idempotencyKey string) (string, error)
+	// WaitForQuote blocks until the quote is ready to order from.
+	WaitForQuote(ctx context.Context, quoteID string, timeout, interval time.Duration) error
+	// CreateOrder places the order against quoteID and returns the
+	// vendor's order ID.
+	CreateOrder(ctx context.Context, quoteID string, spec OrderSpec, idempotencyKey string) (string, error)
+}
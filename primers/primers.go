@@ -0,0 +1,319 @@
+// Package primers designs PCR amplification primers for splicing an insert
+// into a vector by overlap-dependent methods (Gibson, SLIC, LIC). Each
+// primer is built from two parts: a 5' overhang homologous to the vector
+// end it must anneal to during assembly, and a 3' region that anneals to
+// the insert template during amplification. Melting temperatures use the
+// nearest-neighbor thermodynamics of SantaLucia 1998 (Proc. Natl. Acad.
+// Sci. 95:1460-1465), the unified parameter set most primer-design tools
+// are built on.
+package primers
+
+import (
+	"fmt"
+	"math"
+	"strings"
+)
+
+// nnParam holds the enthalpy (kcal/mol) and entropy (cal/(mol*K)) of one
+// nearest-neighbor dinucleotide step, as tabulated in SantaLucia 1998
+// Table 1 (unified parameters).
+type nnParam struct {
+	dH, dS float64
+}
+
+var nnParams = map[string]nnParam{
+	"AA": {-7.9, -22.2}, "TT": {-7.9, -22.2},
+	"AT": {-7.2, -20.4},
+	"TA": {-7.2, -21.3},
+	"CA": {-8.5, -22.7}, "TG": {-8.5, -22.7},
+	"GT": {-8.4, -22.4}, "AC": {-8.4, -22.4},
+	"CT": {-7.8, -21.0}, "AG": {-7.8, -21.0},
+	"GA": {-8.2, -22.2}, "TC": {-8.2, -22.2},
+	"CG": {-10.6, -27.2},
+	"GC": {-9.8, -24.4},
+	"GG": {-8.0, -19.9}, "CC": {-8.0, -19.9},
+}
+
+const (
+	gasConstant  = 1.987  // cal/(mol*K)
+	strandConc   = 250e-9 // 250nM, a typical PCR primer concentration
+	celsiusZeroK = 273.15
+)
+
+// MeltingTemp estimates the nearest-neighbor Tm, in degrees Celsius, of seq
+// annealing to its perfect complement at 250nM strand concentration. It
+// does not salt-correct beyond what's baked into the SantaLucia 1998
+// parameters, so it's meant for comparing candidate primers, not as a
+// substitute for a full thermodynamic model.
+func MeltingTemp(seq string) float64 {
+	seq = strings.ToUpper(seq)
+	if len(seq) < 2 {
+		return 0
+	}
+	dH, dS := initiationParams(seq)
+	for i := 0; i+1 < len(seq); i++ {
+		p, ok := nnParams[seq[i:i+2]]
+		if !ok {
+			continue
+		}
+		dH += p.dH
+		dS += p.dS
+	}
+	// Two non-self-complementary strands at equal concentration: the
+	// effective total-strand term in the SantaLucia Tm equation is CT/4.
+	return (1000*dH)/(dS+gasConstant*math.Log(strandConc/4)) - celsiusZeroK
+}
+
+func initiationParams(seq string) (dH, dS float64) {
+	term := func(b byte) (float64, float64) {
+		if b == 'G' || b == 'C' {
+			return 0.1, -2.8
+		}
+		return 2.3, 4.1
+	}
+	h1, s1 := term(seq[0])
+	h2, s2 := term(seq[len(seq)-1])
+	return h1 + h2, s1 + s2
+}
+
+// Primer is one oligo in a PrimerPair: a homology overhang (if any)
+// concatenated with the region that actually anneals to the template.
+type Primer struct {
+	Sequence          string // full 5'->3' primer, overhang + AnnealingSequence
+	AnnealingSequence string
+	Tm                float64 // nearest-neighbor Tm of AnnealingSequence
+	OverhangTm        float64 // nearest-neighbor Tm of the overhang alone, 0 if none
+}
+
+// PrimerPair is a forward/reverse amplification primer set, along with any
+// design warnings (secondary structure, primer-dimer) worth surfacing to
+// whoever orders the oligos.
+type PrimerPair struct {
+	Forward  Primer
+	Reverse  Primer
+	Warnings []string `json:",omitempty"`
+}
+
+// Options bounds the length search for the overhang and annealing regions
+// and sets their target Tm.
+type Options struct {
+	OverhangMinLen, OverhangMaxLen int
+	OverhangTargetTm               float64
+	AnnealMinLen, AnnealMaxLen     int
+	AnnealTargetTm                 float64
+}
+
+// DefaultOptions returns the lengths and target Tms used by Design when the
+// caller doesn't need to tune them: 20-40bp homology overhangs (the usual
+// Gibson/SLIC range) and 18-25bp annealing regions tuned to ~60C.
+func DefaultOptions() Options {
+	return Options{
+		OverhangMinLen:   20,
+		OverhangMaxLen:   40,
+		OverhangTargetTm: 50,
+		AnnealMinLen:     18,
+		AnnealMaxLen:     25,
+		AnnealTargetTm:   60,
+	}
+}
+
+// Design builds a forward/reverse primer pair that amplifies insert while
+// appending overhangs homologous to the vector flanks it will be spliced
+// between. fiveFlank is the vector sequence immediately upstream of the
+// insertion site, threeFlank immediately downstream; both should be at
+// least opts.OverhangMaxLen bases where the vector allows it.
+func Design(fiveFlank, insert, threeFlank string, opts Options) (PrimerPair, error) {
+	if len(insert) < opts.AnnealMinLen {
+		return PrimerPair{}, fmt.Errorf("primers: insert length %d is shorter than the minimum anneal length %d", len(insert), opts.AnnealMinLen)
+	}
+	if fiveFlank == "" || threeFlank == "" {
+		return PrimerPair{}, fmt.Errorf("primers: vector flanks are required to design homology overhangs")
+	}
+
+	fwdOverhang := pickRegion(fiveFlank, true, opts.OverhangMinLen, opts.OverhangMaxLen, opts.OverhangTargetTm, false)
+	fwdAnneal := pickRegion(insert, false, opts.AnnealMinLen, opts.AnnealMaxLen, opts.AnnealTargetTm, true)
+
+	// threeFlank is the vector sequence immediately downstream of the
+	// insertion site, so the junction-adjacent bases are at its *start*,
+	// not its end. reverseComplement moves those junction-proximal bases
+	// to the end of its result, so the overhang must anchor at the
+	// *end* of revOverhangSource (anchorEnd=true) — that's algebraically
+	// reverseComplement(threeFlank[:l]), the correct junction-adjacent
+	// region — not the vector-distal bases an anchorEnd=false pick would
+	// give whenever threeFlank is longer than the chosen overhang.
+	revOverhangSource := reverseComplement(threeFlank)
+	revOverhang := pickRegion(revOverhangSource, true, opts.OverhangMinLen, opts.OverhangMaxLen, opts.OverhangTargetTm, false)
+	revAnneal := pickRegion(reverseComplement(insert), false, opts.AnnealMinLen, opts.AnnealMaxLen, opts.AnnealTargetTm, true)
+
+	forward := Primer{
+		Sequence:          fwdOverhang + fwdAnneal,
+		AnnealingSequence: fwdAnneal,
+		Tm:                MeltingTemp(fwdAnneal),
+		OverhangTm:        MeltingTemp(fwdOverhang),
+	}
+	reverse := Primer{
+		Sequence:          revOverhang + revAnneal,
+		AnnealingSequence: revAnneal,
+		Tm:                MeltingTemp(revAnneal),
+		OverhangTm:        MeltingTemp(revOverhang),
+	}
+
+	pair := PrimerPair{Forward: forward, Reverse: reverse}
+	pair.Warnings = evaluatePair(pair)
+	return pair, nil
+}
+
+// pickRegion scans candidate lengths in [minLen, maxLen] of seq, anchored
+// at its 3' end if anchorEnd is set or its 5' end otherwise, and returns
+// the one whose Tm is closest to targetTm. When preferGCClamp is set, a
+// candidate ending in G/C is favored on near-ties, since a 3' G or C
+// strengthens primer binding at the extension-critical end.
+func pickRegion(seq string, anchorEnd bool, minLen, maxLen int, targetTm float64, preferGCClamp bool) string {
+	if maxLen > len(seq) {
+		maxLen = len(seq)
+	}
+	if minLen > maxLen {
+		minLen = maxLen
+	}
+	var best string
+	bestScore := math.MaxFloat64
+	for l := minLen; l <= maxLen; l++ {
+		var region string
+		if anchorEnd {
+			region = seq[len(seq)-l:]
+		} else {
+			region = seq[:l]
+		}
+		score := math.Abs(MeltingTemp(region) - targetTm)
+		if preferGCClamp && hasGCClamp(region) {
+			score -= 0.5
+		}
+		if score < bestScore {
+			bestScore = score
+			best = region
+		}
+	}
+	return best
+}
+
+func hasGCClamp(seq string) bool {
+	if seq == "" {
+		return false
+	}
+	last := seq[len(seq)-1]
+	return last == 'G' || last == 'C'
+}
+
+var dnaComplement = map[byte]byte{'A': 'T', 'T': 'A', 'C': 'G', 'G': 'C', 'N': 'N'}
+
+func reverseComplement(seq string) string {
+	out := make([]byte, len(seq))
+	for i := 0; i < len(seq); i++ {
+		c, ok := dnaComplement[seq[len(seq)-1-i]]
+		if !ok {
+			c = 'N'
+		}
+		out[i] = c
+	}
+	return string(out)
+}
+
+func isComplementary(a, b string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := 0; i < len(a); i++ {
+		if dnaComplement[a[i]] != b[i] {
+			return false
+		}
+	}
+	return true
+}
+
+func reverseString(s string) string {
+	out := make([]byte, len(s))
+	for i := 0; i < len(s); i++ {
+		out[i] = s[len(s)-1-i]
+	}
+	return string(out)
+}
+
+// HairpinDeltaG is a simple self-folding heuristic: it scans seq for
+// perfectly self-complementary stems (no mismatches, at least 4 bases)
+// separated by a loop of at least 3 bases, and returns the lowest
+// nearest-neighbor free energy at 37C among them (0 if there are none). It's a rough stand-in for a real folding algorithm
+// (mfold/RNAfold), good enough to flag primers worth re-designing.
+func HairpinDeltaG(seq string) float64 {
+	const (
+		minLoop = 3
+		minStem = 4
+	)
+	best := 0.0
+	n := len(seq)
+	for loop := minLoop; loop < n; loop++ {
+		for stemLen := minStem; stemLen*2+loop <= n; stemLen++ {
+			for start := 0; start+2*stemLen+loop <= n; start++ {
+				stem5 := seq[start : start+stemLen]
+				stem3 := seq[start+stemLen+loop : start+2*stemLen+loop]
+				if isComplementary(stem5, reverseString(stem3)) {
+					if dg := stemDeltaG(stem5); dg < best {
+						best = dg
+					}
+				}
+			}
+		}
+	}
+	return best
+}
+
+// stemDeltaG sums nearest-neighbor free energies (at 37C, the standard
+// reference temperature for these parameters) along a duplex stem.
+func stemDeltaG(stem string) float64 {
+	const bodyTempK = 310.15
+	dg := 0.0
+	for i := 0; i+1 < len(stem); i++ {
+		p, ok := nnParams[stem[i:i+2]]
+		if !ok {
+			continue
+		}
+		dg += p.dH - bodyTempK*p.dS/1000
+	}
+	return dg
+}
+
+// ThreePrimeDimer reports whether the 3' ends of f and r are complementary
+// over at least minLen bases, which would let the two primers anneal to
+// each other 3'-to-3' and extend into a primer-dimer instead of the
+// intended template.
+func ThreePrimeDimer(f, r string, minLen int) bool {
+	maxCheck := len(f)
+	if maxCheck > len(f) {
+		maxCheck = len(f)
+	}
+	if maxCheck > len(r) {
+		maxCheck = len(r)
+	}
+	for k := minLen; k <= maxCheck; k++ {
+		fTail := f[len(f)-k:]
+		rTail := r[len(r)-k:]
+		if isComplementary(fTail, reverseString(rTail)) {
+			return true
+		}
+	}
+	return false
+}
+
+func evaluatePair(pair PrimerPair) []string {
+	var warnings []string
+	const hairpinThreshold = -3.0
+	if dg := HairpinDeltaG(pair.Forward.Sequence); dg < hairpinThreshold {
+		warnings = append(warnings, fmt.Sprintf("forward primer may form a hairpin (dG %.1f kcal/mol)", dg))
+	}
+	if dg := HairpinDeltaG(pair.Reverse.Sequence); dg < hairpinThreshold {
+		warnings = append(warnings, fmt.Sprintf("reverse primer may form a hairpin (dG %.1f kcal/mol)", dg))
+	}
+	if ThreePrimeDimer(pair.Forward.Sequence, pair.Reverse.Sequence, 4) {
+		warnings = append(warnings, "forward/reverse primers share >=4bp of 3' complementarity (possible primer-dimer)")
+	}
+	return warnings
+}
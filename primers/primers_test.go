@@ -0,0 +1,69 @@
+package primers
+
+import "testing"
+
+func TestMeltingTempKnownSequence(t *testing.T) {
+	// A short, clearly non-empty seq should give a plausible Tm rather
+	// than NaN/zero; only a loose range is checked, not an exact value of
+	// the SantaLucia 1998 computation.
+	tm := MeltingTemp("GCGCGCGCGC")
+	if tm < 40 || tm > 90 {
+		t.Errorf("MeltingTemp(GCGCGCGCGC) = %v, want a plausible Tm in [40, 90]", tm)
+	}
+	if got := MeltingTemp("A"); got != 0 {
+		t.Errorf("MeltingTemp of a single base = %v, want 0", got)
+	}
+}
+
+func TestDesignOverhangsAnchorAtJunction(t *testing.T) {
+	// fiveFlank's junction-adjacent bases are at its end; threeFlank's are
+	// at its start. Both flanks here are longer than OverhangMaxLen, so a
+	// wrongly-anchored pick would differ from these junction-adjacent
+	// regions.
+	fiveFlank := "AAAAAAAAAAAAAAAAAAAAAAAAAAAAAA" + "GGGGCCCCGGGGCCCCGGGG" // distal + junction-adjacent 20bp
+	threeFlank := "CCCCGGGGCCCCGGGGCCCC" + "TTTTTTTTTTTTTTTTTTTTTTTTTTTTTT" // junction-adjacent 20bp + distal
+	insert := "ATGGCTAGCATGGCTAGCATGGCTAGCATGGCTAGC"
+
+	opts := Options{
+		OverhangMinLen:   20,
+		OverhangMaxLen:   20,
+		OverhangTargetTm: 50,
+		AnnealMinLen:     18,
+		AnnealMaxLen:     18,
+		AnnealTargetTm:   60,
+	}
+
+	pair, err := Design(fiveFlank, insert, threeFlank, opts)
+	if err != nil {
+		t.Fatalf("Design: %v", err)
+	}
+
+	wantFwdOverhang := fiveFlank[len(fiveFlank)-20:]
+	if got := pair.Forward.Sequence[:20]; got != wantFwdOverhang {
+		t.Errorf("forward overhang = %q, want the fiveFlank's junction-adjacent %q", got, wantFwdOverhang)
+	}
+
+	// The reverse overhang must be homologous to the junction-adjacent
+	// bases of threeFlank — i.e. reverseComplement(threeFlank[:20]) — not
+	// the vector-distal end.
+	wantRevOverhang := reverseComplement(threeFlank[:20])
+	if got := pair.Reverse.Sequence[:20]; got != wantRevOverhang {
+		t.Errorf("reverse overhang = %q, want reverseComplement(threeFlank[:20]) = %q", got, wantRevOverhang)
+	}
+}
+
+func TestDesignRejectsShortInsertOrMissingFlanks(t *testing.T) {
+	opts := DefaultOptions()
+	if _, err := Design("ACGT", "AC", "ACGT", opts); err == nil {
+		t.Error("expected an error for an insert shorter than AnnealMinLen")
+	}
+	if _, err := Design("", "ATGGCTAGCATGGCTAGCATGGCTAGC", "ACGT", opts); err == nil {
+		t.Error("expected an error for a missing fiveFlank")
+	}
+}
+
+func TestReverseComplement(t *testing.T) {
+	if got := reverseComplement("ACGTN"); got != "NACGT" {
+		t.Errorf("reverseComplement(ACGTN) = %q, want NACGT", got)
+	}
+}
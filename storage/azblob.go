@@ -0,0 +1,150 @@
+package storage
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/to"
+	"github.com/Azure/azure-sdk-for-go/sdk/azidentity"
+	"github.com/Azure/azure-sdk-for-go/sdk/storage/azblob"
+	"github.com/Azure/azure-sdk-for-go/sdk/storage/azblob/bloberror"
+	"github.com/Azure/azure-sdk-for-go/sdk/storage/azblob/sas"
+	"github.com/Azure/azure-sdk-for-go/sdk/storage/azblob/service"
+)
+
+// azblobBlob is the azblob:// backend: an Azure Storage account +
+// container, authenticating via DefaultAzureCredential (environment,
+// workload/managed identity, or a cached `az login` session).
+type azblobBlob struct {
+	client    *azblob.Client
+	container string
+	prefix    string
+}
+
+func newAzblobBlob(account, container, prefix string) (*azblobBlob, error) {
+	if account == "" || container == "" {
+		return nil, fmt.Errorf("storage: azblob URL must be azblob://account/container/prefix")
+	}
+	cred, err := azidentity.NewDefaultAzureCredential(nil)
+	if err != nil {
+		return nil, fmt.Errorf("storage: azure credentials: %w", err)
+	}
+	serviceURL := fmt.Sprintf("https://%s.blob.core.windows.net/", account)
+	client, err := azblob.NewClient(serviceURL, cred, nil)
+	if err != nil {
+		return nil, err
+	}
+	return &azblobBlob{client: client, container: container, prefix: prefix}, nil
+}
+
+func (a *azblobBlob) fullKey(key string) string {
+	if a.prefix == "" {
+		return key
+	}
+	return strings.TrimSuffix(a.prefix, "/") + "/" + strings.TrimPrefix(key, "/")
+}
+
+func (a *azblobBlob) Put(ctx context.Context, key string, r io.Reader, meta map[string]string) (string, string, error) {
+	fullKey := a.fullKey(key)
+	metaPtrs := make(map[string]*string, len(meta))
+	for k, v := range meta {
+		metaPtrs[k] = to.Ptr(v)
+	}
+	resp, err := a.client.UploadStream(ctx, a.container, fullKey, r, &azblob.UploadStreamOptions{
+		Metadata: metaPtrs,
+	})
+	if err != nil {
+		return "", "", err
+	}
+	etag := ""
+	if resp.ETag != nil {
+		etag = string(*resp.ETag)
+	}
+	url := fmt.Sprintf("%s/%s/%s", strings.TrimSuffix(a.client.URL(), "/"), a.container, fullKey)
+	return url, etag, nil
+}
+
+func (a *azblobBlob) Get(ctx context.Context, key string) (io.ReadCloser, BlobMeta, error) {
+	resp, err := a.client.DownloadStream(ctx, a.container, a.fullKey(key), nil)
+	if err != nil {
+		return nil, BlobMeta{}, err
+	}
+	values := map[string]string{}
+	for k, v := range resp.Metadata {
+		if v != nil {
+			values[k] = *v
+		}
+	}
+	meta := BlobMeta{Values: values}
+	if resp.ContentLength != nil {
+		meta.Size = *resp.ContentLength
+	}
+	if resp.LastModified != nil {
+		meta.LastModified = *resp.LastModified
+	}
+	return resp.Body, meta, nil
+}
+
+// Presign signs a read-only SAS URL via a user delegation key rather than
+// blobClient.GetSASURL, which requires a SharedKeyCredential this backend
+// never holds (it authenticates with DefaultAzureCredential, an AAD token
+// credential) and would fail unconditionally with
+// bloberror.MissingSharedKeyCredential.
+func (a *azblobBlob) Presign(ctx context.Context, key string, ttl time.Duration) (string, error) {
+	svcClient := a.client.ServiceClient()
+	now := time.Now().UTC().Add(-10 * time.Second)
+	expiry := now.Add(ttl)
+	udc, err := svcClient.GetUserDelegationCredential(ctx, service.KeyInfo{
+		Start:  to.Ptr(now.Format(sas.TimeFormat)),
+		Expiry: to.Ptr(expiry.Format(sas.TimeFormat)),
+	}, nil)
+	if err != nil {
+		return "", fmt.Errorf("storage: get user delegation credential: %w", err)
+	}
+
+	qp, err := sas.BlobSignatureValues{
+		Protocol:      sas.ProtocolHTTPS,
+		StartTime:     now,
+		ExpiryTime:    expiry,
+		Permissions:   to.Ptr(sas.BlobPermissions{Read: true}).String(),
+		ContainerName: a.container,
+		BlobName:      a.fullKey(key),
+	}.SignWithUserDelegation(udc)
+	if err != nil {
+		return "", fmt.Errorf("storage: sign user delegation SAS: %w", err)
+	}
+
+	blobURL := fmt.Sprintf("%s/%s/%s", strings.TrimSuffix(a.client.URL(), "/"), a.container, a.fullKey(key))
+	return blobURL + "?" + qp.Encode(), nil
+}
+
+func (a *azblobBlob) Delete(ctx context.Context, key string) error {
+	_, err := a.client.DeleteBlob(ctx, a.container, a.fullKey(key), nil)
+	if bloberror.HasCode(err, bloberror.BlobNotFound) {
+		return nil
+	}
+	return err
+}
+
+func (a *azblobBlob) List(ctx context.Context, prefix string) ([]string, error) {
+	var keys []string
+	fullPrefix := a.fullKey(prefix)
+	pager := a.client.NewListBlobsFlatPager(a.container, &azblob.ListBlobsFlatOptions{
+		Prefix: &fullPrefix,
+	})
+	for pager.More() {
+		page, err := pager.NextPage(ctx)
+		if err != nil {
+			return nil, err
+		}
+		for _, item := range page.Segment.BlobItems {
+			if item.Name != nil {
+				keys = append(keys, strings.TrimPrefix(*item.Name, a.prefix+"/"))
+			}
+		}
+	}
+	return keys, nil
+}
@@ -0,0 +1,148 @@
+package storage
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+)
+
+// fileBlob is the file:// backend: a local directory tree, for air-gapped
+// mirrors and for exercising the Blob interface without cloud credentials.
+// Metadata is stored alongside each object as "<key>.meta.json" since the
+// local filesystem has no native concept of object metadata.
+type fileBlob struct {
+	baseDir string
+}
+
+func newFileBlob(baseDir string) (*fileBlob, error) {
+	if strings.TrimSpace(baseDir) == "" {
+		return nil, fmt.Errorf("storage: file backend requires a non-empty path")
+	}
+	if err := os.MkdirAll(baseDir, 0755); err != nil {
+		return nil, err
+	}
+	return &fileBlob{baseDir: baseDir}, nil
+}
+
+func (f *fileBlob) resolve(key string) (string, error) {
+	clean := filepath.Clean("/" + key)
+	return filepath.Join(f.baseDir, clean), nil
+}
+
+func (f *fileBlob) Put(ctx context.Context, key string, r io.Reader, meta map[string]string) (string, string, error) {
+	path, err := f.resolve(key)
+	if err != nil {
+		return "", "", err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return "", "", err
+	}
+	out, err := os.Create(path)
+	if err != nil {
+		return "", "", err
+	}
+	defer out.Close()
+	if _, err := io.Copy(out, r); err != nil {
+		return "", "", err
+	}
+	if len(meta) > 0 {
+		metaBytes, err := json.Marshal(meta)
+		if err != nil {
+			return "", "", err
+		}
+		if err := os.WriteFile(path+".meta.json", metaBytes, 0644); err != nil {
+			return "", "", err
+		}
+	}
+	info, err := out.Stat()
+	if err != nil {
+		return "", "", err
+	}
+	return "file://" + path, fmt.Sprintf("%d-%d", info.Size(), info.ModTime().UnixNano()), nil
+}
+
+func (f *fileBlob) Get(ctx context.Context, key string) (io.ReadCloser, BlobMeta, error) {
+	path, err := f.resolve(key)
+	if err != nil {
+		return nil, BlobMeta{}, err
+	}
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, BlobMeta{}, err
+	}
+	info, err := file.Stat()
+	if err != nil {
+		file.Close()
+		return nil, BlobMeta{}, err
+	}
+	values := map[string]string{}
+	if metaBytes, err := os.ReadFile(path + ".meta.json"); err == nil {
+		_ = json.Unmarshal(metaBytes, &values)
+	}
+	return file, BlobMeta{Values: values, Size: info.Size(), LastModified: info.ModTime()}, nil
+}
+
+func (f *fileBlob) Presign(ctx context.Context, key string, ttl time.Duration) (string, error) {
+	path, err := f.resolve(key)
+	if err != nil {
+		return "", err
+	}
+	// There is no server to hand out time-limited URLs for a local
+	// directory; the absolute path is the only "URL" a local mirror has.
+	return "file://" + path, nil
+}
+
+func (f *fileBlob) Delete(ctx context.Context, key string) error {
+	path, err := f.resolve(key)
+	if err != nil {
+		return err
+	}
+	_ = os.Remove(path + ".meta.json")
+	if err := os.Remove(path); err != nil && !os.IsNotExist(err) {
+		return err
+	}
+	return nil
+}
+
+func (f *fileBlob) List(ctx context.Context, prefix string) ([]string, error) {
+	root, err := f.resolve(prefix)
+	if err != nil {
+		return nil, err
+	}
+	var keys []string
+	walkRoot := root
+	if info, err := os.Stat(root); err != nil || !info.IsDir() {
+		walkRoot = filepath.Dir(root)
+	}
+	err = filepath.WalkDir(walkRoot, func(path string, d os.DirEntry, err error) error {
+		if err != nil {
+			if os.IsNotExist(err) {
+				return nil
+			}
+			return err
+		}
+		if d.IsDir() || strings.HasSuffix(path, ".meta.json") {
+			return nil
+		}
+		rel, err := filepath.Rel(f.baseDir, path)
+		if err != nil {
+			return err
+		}
+		key := filepath.ToSlash(rel)
+		if strings.HasPrefix(key, prefix) {
+			keys = append(keys, key)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	sort.Strings(keys)
+	return keys, nil
+}
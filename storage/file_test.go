@@ -0,0 +1,108 @@
+package storage
+
+import (
+	"context"
+	"io"
+	"strings"
+	"testing"
+)
+
+func TestFileBlobPutGetList(t *testing.T) {
+	dir := t.TempDir()
+	blob, err := newFileBlob(dir)
+	if err != nil {
+		t.Fatalf("newFileBlob: %v", err)
+	}
+	ctx := context.Background()
+
+	url, _, err := blob.Put(ctx, "attachments/42/grid.png", strings.NewReader("pngbytes"), map[string]string{"contentType": "image/png"})
+	if err != nil {
+		t.Fatalf("Put: %v", err)
+	}
+	if !strings.HasSuffix(url, "attachments/42/grid.png") {
+		t.Fatalf("unexpected URL: %q", url)
+	}
+
+	r, meta, err := blob.Get(ctx, "attachments/42/grid.png")
+	if err != nil {
+		t.Fatalf("Get: %v", err)
+	}
+	defer r.Close()
+	data, err := io.ReadAll(r)
+	if err != nil {
+		t.Fatalf("reading body: %v", err)
+	}
+	if string(data) != "pngbytes" {
+		t.Fatalf("unexpected body: %q", data)
+	}
+	if meta.Values["contentType"] != "image/png" {
+		t.Fatalf("unexpected metadata: %+v", meta.Values)
+	}
+
+	keys, err := blob.List(ctx, "attachments/42")
+	if err != nil {
+		t.Fatalf("List: %v", err)
+	}
+	if len(keys) != 1 || keys[0] != "attachments/42/grid.png" {
+		t.Fatalf("unexpected keys: %v", keys)
+	}
+
+	if err := blob.Delete(ctx, "attachments/42/grid.png"); err != nil {
+		t.Fatalf("Delete: %v", err)
+	}
+	if keys, err := blob.List(ctx, "attachments/42"); err != nil || len(keys) != 0 {
+		t.Fatalf("expected no keys after delete, got %v, err %v", keys, err)
+	}
+}
+
+func TestMirrorUploadAndVerify(t *testing.T) {
+	dir := t.TempDir()
+	blob, err := newFileBlob(dir)
+	if err != nil {
+		t.Fatalf("newFileBlob: %v", err)
+	}
+	ctx := context.Background()
+
+	sidecar, err := MirrorUpload(ctx, blob, "attachments/7/plasmid.gb", 7, strings.NewReader("gbrecord"), nil)
+	if err != nil {
+		t.Fatalf("MirrorUpload: %v", err)
+	}
+	if sidecar.SHA256 == "" || sidecar.AttachmentID != 7 {
+		t.Fatalf("unexpected sidecar: %+v", sidecar)
+	}
+
+	matches, _, err := VerifyObject(ctx, blob, "attachments/7/plasmid.gb", sidecar.SHA256)
+	if err != nil {
+		t.Fatalf("VerifyObject: %v", err)
+	}
+	if !matches {
+		t.Fatalf("expected hash to match after upload")
+	}
+
+	matches, _, err = VerifyObject(ctx, blob, "attachments/7/plasmid.gb", "deadbeef")
+	if err != nil {
+		t.Fatalf("VerifyObject: %v", err)
+	}
+	if matches {
+		t.Fatalf("expected mismatch against a wrong hash")
+	}
+}
+
+func TestSidecarRoundTrip(t *testing.T) {
+	dir := t.TempDir()
+	s := Sidecar{AttachmentID: 3, SHA256: "abc123", BackendURL: "s3://bucket/key"}
+	if err := WriteSidecar(dir, s); err != nil {
+		t.Fatalf("WriteSidecar: %v", err)
+	}
+	got, ok, err := ReadSidecar(dir, 3)
+	if err != nil || !ok {
+		t.Fatalf("ReadSidecar: ok=%v err=%v", ok, err)
+	}
+	if got.SHA256 != "abc123" || got.BackendURL != "s3://bucket/key" {
+		t.Fatalf("unexpected sidecar: %+v", got)
+	}
+
+	if _, ok, err := ReadSidecar(dir, 99); err != nil || ok {
+		t.Fatalf("expected ok=false for missing sidecar, got ok=%v err=%v", ok, err)
+	}
+}
@@ -0,0 +1,114 @@
+package storage
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// Sidecar is the mirror-mode record written after a successful server-side
+// upload and a background copy to a configured Blob backend, so
+// `attachments download` can fall back to the mirror when the server is
+// unreachable and `attachments verify` can detect drift.
+type Sidecar struct {
+	AttachmentID int       `json:"attachmentId"`
+	SHA256       string    `json:"sha256"`
+	BackendURL   string    `json:"backendUrl"`
+	UploadedAt   time.Time `json:"uploadedAt"`
+}
+
+func sidecarPath(dir string, attachmentID int) string {
+	return filepath.Join(dir, fmt.Sprintf("%d.sidecar.json", attachmentID))
+}
+
+// WriteSidecar records s under dir, creating dir if necessary.
+func WriteSidecar(dir string, s Sidecar) error {
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return err
+	}
+	data, err := json.MarshalIndent(s, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(sidecarPath(dir, s.AttachmentID), data, 0644)
+}
+
+// ReadSidecar loads the sidecar for attachmentID from dir, reporting
+// ok=false (with a nil error) if none has been recorded yet.
+func ReadSidecar(dir string, attachmentID int) (Sidecar, bool, error) {
+	data, err := os.ReadFile(sidecarPath(dir, attachmentID))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return Sidecar{}, false, nil
+		}
+		return Sidecar{}, false, err
+	}
+	var s Sidecar
+	if err := json.Unmarshal(data, &s); err != nil {
+		return Sidecar{}, false, err
+	}
+	return s, true, nil
+}
+
+// ListSidecars returns every sidecar recorded under dir.
+func ListSidecars(dir string) ([]Sidecar, error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	var sidecars []Sidecar
+	for _, entry := range entries {
+		data, err := os.ReadFile(filepath.Join(dir, entry.Name()))
+		if err != nil {
+			continue
+		}
+		var s Sidecar
+		if err := json.Unmarshal(data, &s); err == nil {
+			sidecars = append(sidecars, s)
+		}
+	}
+	return sidecars, nil
+}
+
+// MirrorUpload copies r to blob under key, computing its SHA-256 as it
+// streams, and returns a Sidecar ready for WriteSidecar. This is the
+// mirror-mode path: it runs after the server already has the bytes, so the
+// CLI can afford to hash the whole stream in one pass.
+func MirrorUpload(ctx context.Context, blob Blob, key string, attachmentID int, r io.Reader, meta map[string]string) (Sidecar, error) {
+	h := sha256.New()
+	backendURL, _, err := blob.Put(ctx, key, io.TeeReader(r, h), meta)
+	if err != nil {
+		return Sidecar{}, err
+	}
+	return Sidecar{
+		AttachmentID: attachmentID,
+		SHA256:       hex.EncodeToString(h.Sum(nil)),
+		BackendURL:   backendURL,
+		UploadedAt:   time.Now(),
+	}, nil
+}
+
+// VerifyObject re-downloads key from blob and recomputes its SHA-256,
+// reporting whether it matches wantSHA256.
+func VerifyObject(ctx context.Context, blob Blob, key, wantSHA256 string) (matches bool, gotSHA256 string, err error) {
+	r, _, err := blob.Get(ctx, key)
+	if err != nil {
+		return false, "", err
+	}
+	defer r.Close()
+	h := sha256.New()
+	if _, err := io.Copy(h, r); err != nil {
+		return false, "", err
+	}
+	got := hex.EncodeToString(h.Sum(nil))
+	return got == wantSHA256, got, nil
+}
@@ -0,0 +1,79 @@
+// Package storage is a pluggable object-storage abstraction for attachment
+// downloads/uploads: a Blob interface with s3, azblob, gs, and local file://
+// implementations, selected by URL scheme so labs running LabBook alongside
+// S3/Azure Blob/GCS (or an air-gapped mirror) can bypass the server's
+// /api/uploads proxy for attachment bytes.
+package storage
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// BlobMeta is the metadata associated with a stored object: arbitrary
+// caller-supplied key/value pairs (e.g. content type, original file name)
+// plus the size and last-modified time reported by the backend.
+type BlobMeta struct {
+	Values       map[string]string
+	Size         int64
+	LastModified time.Time
+}
+
+// Blob is a minimal object-storage client: put/get/presign/delete/list
+// against a single bucket-or-container-and-prefix, implemented for s3,
+// azblob, gs, and local file:// backends.
+type Blob interface {
+	// Put uploads r under key, returning the backend's canonical URL for
+	// the object and, where the backend supports it, an ETag.
+	Put(ctx context.Context, key string, r io.Reader, meta map[string]string) (blobURL, etag string, err error)
+	// Get opens key for reading along with its metadata. Callers must
+	// close the returned ReadCloser.
+	Get(ctx context.Context, key string) (io.ReadCloser, BlobMeta, error)
+	// Presign returns a time-limited URL for downloading key directly from
+	// the backend, bypassing the CLI/server entirely.
+	Presign(ctx context.Context, key string, ttl time.Duration) (string, error)
+	// Delete removes key. It is not an error if key does not exist.
+	Delete(ctx context.Context, key string) error
+	// List returns every key with the given prefix.
+	List(ctx context.Context, prefix string) ([]string, error)
+}
+
+// Open parses rawURL (as read from LABBOOK_STORAGE_URL or --storage-backend)
+// and returns the Blob implementation for its scheme:
+//
+//	s3://bucket/prefix          (aws-sdk-go-v2, default credential chain)
+//	azblob://account/container/prefix
+//	gs://bucket/prefix          (Application Default Credentials)
+//	file:///absolute/path       (local filesystem, for air-gapped mirrors)
+func Open(rawURL string) (Blob, error) {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return nil, fmt.Errorf("storage: invalid URL %q: %w", rawURL, err)
+	}
+	prefix := strings.TrimPrefix(u.Path, "/")
+	switch u.Scheme {
+	case "s3":
+		return newS3Blob(u.Host, prefix)
+	case "azblob":
+		parts := strings.SplitN(prefix, "/", 2)
+		if u.Host == "" || len(parts) == 0 || parts[0] == "" {
+			return nil, fmt.Errorf("storage: azblob URL must be azblob://account/container/prefix, got %q", rawURL)
+		}
+		container := parts[0]
+		containerPrefix := ""
+		if len(parts) == 2 {
+			containerPrefix = parts[1]
+		}
+		return newAzblobBlob(u.Host, container, containerPrefix)
+	case "gs":
+		return newGCSBlob(u.Host, prefix)
+	case "file":
+		return newFileBlob(u.Path)
+	default:
+		return nil, fmt.Errorf("storage: unsupported backend scheme %q (want s3, azblob, gs, or file)", u.Scheme)
+	}
+}